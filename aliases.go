@@ -0,0 +1,130 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// An alias is an exported type alias declaration, type Name = Target.
+type alias struct {
+	key    string // Import path and name, as in io/fs.FileInfo.
+	target string // Key of the type it names.
+	rhs    string // The type as written, such as fs.FileInfo.
+	pos    token.Position
+	doc    *ast.CommentGroup
+}
+
+// aliasesOf prints the exported type aliases in GOROOT, the module and
+// GOPATH that denote the type, given as pkg.Type, directly or through
+// other aliases, with the first sentence of their documentation.
+func (s *session) aliasesOf(arg string) {
+	if !strings.Contains(arg, ".") {
+		usage()
+	}
+	pkg, name := split(arg)
+	dirs := s.pathsFor(goRoot, "")
+	dirs = append(dirs, s.searchDirs()...)
+	var aliases []alias
+	for _, dir := range dirs {
+		aliases = append(aliases, aliasesIn(dir)...)
+	}
+	// The keys of the type and of the aliases found so far that denote it.
+	denote := func(key string) bool {
+		dot := strings.LastIndex(key, ".")
+		p, n := key[:dot], key[dot+1:]
+		return (p == pkg || importedName(p) == pkg) && equalFold(n, name)
+	}
+	found := make(map[string]bool)
+	printed := false
+	for more := true; more; {
+		more = false
+		for _, a := range aliases {
+			if found[a.key] || !found[a.target] && !denote(a.target) {
+				continue
+			}
+			found[a.key], more, printed = true, true, true
+			fmt.Fprintf(s.out, "%s:%d: %s = %s\n", a.pos.Filename, a.pos.Line, a.key, a.rhs)
+			if syn := synopsis(a.doc); syn != "" {
+				fmt.Fprintf(s.out, "\t%s\n", syn)
+			}
+		}
+	}
+	if !printed {
+		fmt.Fprintf(os.Stderr, "doc: -aliases-of: no exported alias denotes %s\n", arg)
+		exit(1)
+	}
+}
+
+// aliasesIn returns the exported type aliases declared in the non-test Go
+// files of the directory whose types are named types, possibly instantiated.
+func aliasesIn(dir string) []alias {
+	fset := token.NewFileSet()
+	notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+	pkgs, _ := parseDir(fset, dir, notTest, parser.ParseComments) // Ignore the error.
+	from := importPath(dir)
+	var aliases []alias
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			imports := make(map[string]string) // Local name to import path.
+			for _, imp := range file.Imports {
+				p, err := strconv.Unquote(imp.Path.Value)
+				if err != nil {
+					continue
+				}
+				imports[importName(imp)] = p
+			}
+			for _, decl := range file.Decls {
+				decl, ok := decl.(*ast.GenDecl)
+				if !ok || decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					spec := spec.(*ast.TypeSpec)
+					if !spec.Assign.IsValid() || !spec.Name.IsExported() {
+						continue
+					}
+					target := ""
+					typ := spec.Type
+					if index, ok := typ.(*ast.IndexExpr); ok {
+						typ = index.X
+					} else if index, ok := typ.(*ast.IndexListExpr); ok {
+						typ = index.X
+					}
+					switch typ := typ.(type) {
+					case *ast.Ident:
+						target = from + "." + typ.Name
+					case *ast.SelectorExpr:
+						if x, ok := typ.X.(*ast.Ident); ok && imports[x.Name] != "" {
+							target = imports[x.Name] + "." + typ.Sel.Name
+						}
+					}
+					if target == "" {
+						continue
+					}
+					doc := spec.Doc
+					if doc == nil && !decl.Lparen.IsValid() {
+						doc = decl.Doc
+					}
+					aliases = append(aliases, alias{
+						key:    from + "." + spec.Name.Name,
+						target: target,
+						rhs:    types.ExprString(spec.Type),
+						pos:    fset.Position(spec.Name.Pos()),
+						doc:    doc,
+					})
+				}
+			}
+		}
+	}
+	return aliases
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAliasesOfMajorVersion(t *testing.T) {
+	files := maps.Clone(testModule)
+	files["compat/compat.go"] = `package compat
+
+import "example.com/m/v2"
+
+// Settings is the old name for Config.
+type Settings = m.Config
+`
+	writeModule(t, files)
+	var out bytes.Buffer
+	newSession(&out).aliasesOf("m.Config")
+	// The module path ends in /v2, but the package is named m.
+	for _, want := range []string{
+		"example.com/m/v2.Alias = Config\n",
+		"example.com/m/v2/compat.Settings = m.Config\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("-aliases-of m.Config printed\n%s\nwant %q", out.String(), want)
+		}
+	}
+}
+
+func TestAliasesOf(t *testing.T) {
+	fakeGOROOT(t, map[string]string{
+		"src/io/io.go":       "package io\n\n// Reader reads.\ntype Reader interface{ Read([]byte) (int, error) }\n",
+		"src/io/fs/fs.go":    "package fs\n\n// FileInfo describes a file.\ntype FileInfo interface{ Name() string }\n",
+		"src/os/types.go":    "package os\n\nimport \"io/fs\"\n\n// A FileInfo describes a file.\ntype FileInfo = fs.FileInfo\n",
+		"src/os/os_test.go":  "package os\n\nimport \"io/fs\"\n\ntype TestInfo = fs.FileInfo\n",
+		"src/os/internal.go": "package os\n\nimport \"io/fs\"\n\ntype fileInfo = fs.FileInfo\n",
+	})
+	old := goPaths
+	goPaths = nil
+	defer func() { goPaths = old }()
+	writeModule(t, map[string]string{
+		"go.mod": "module example.com/al\n\ngo 1.22\n",
+		"compat/compat.go": `package compat
+
+import (
+	"io"
+	"os"
+	"example.com/al/list"
+)
+
+// Info is the old name of os.FileInfo.
+type Info = os.FileInfo
+
+// Source is an io.Reader.
+type Source = io.Reader
+
+// Ints is a list of ints.
+type Ints = list.List[int]
+`,
+		"list/list.go": "package list\n\n// List is a list.\ntype List[T any] []T\n",
+	})
+	tests := []struct {
+		arg    string
+		want   []string // Aliases printed, without positions.
+		status int
+	}{
+		// An alias of an alias denotes the type too.
+		{arg: "fs.FileInfo", want: []string{"os.FileInfo = fs.FileInfo", "example.com/al/compat.Info = os.FileInfo"}},
+		{arg: "os.FileInfo", want: []string{"example.com/al/compat.Info = os.FileInfo"}},
+		{arg: "io.reader", want: []string{"example.com/al/compat.Source = io.Reader"}},
+		{arg: "list.List", want: []string{"example.com/al/compat.Ints = list.List[int]"}},
+		{arg: "io.Writer", status: 1},
+		{arg: "Writer", status: 2},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).aliasesOf(test.arg) })
+		if status != test.status {
+			t.Errorf("-aliases-of %s exited with %d, want %d", test.arg, status, test.status)
+			continue
+		}
+		var got []string
+		for _, line := range strings.Split(out.String(), "\n") {
+			if _, decl, ok := strings.Cut(line, ": "); ok && !strings.HasPrefix(line, "\t") {
+				got = append(got, decl)
+			}
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("-aliases-of %s printed\n%s\nwant %q", test.arg, out.String(), test.want)
+		}
+	}
+	// Each alias is followed by its synopsis.
+	var out bytes.Buffer
+	newSession(&out).aliasesOf("io.Reader")
+	if !strings.Contains(out.String(), "\tSource is an io.Reader.\n") {
+		t.Errorf("-aliases-of io.Reader printed\n%s\nwant the synopsis of Source", out.String())
+	}
+}
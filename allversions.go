@@ -0,0 +1,102 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// allVersions prints the documentation of the named symbol in the package
+// with the import path in every version of its module in the module cache,
+// oldest first, each labeled module@version. A version whose documentation
+// is that of the one before it is just said to be the same. Positions and
+// URLs, which differ from version to version, are not printed.
+func (s *session) allVersions(pkgPath, name string) {
+	modulePath, versions := cachedVersions(pkgPath)
+	if len(versions) == 0 {
+		fmt.Fprintf(os.Stderr, "doc: -all-versions: no module in the module cache provides %s\n", pkgPath)
+		exit(1)
+	}
+	s.showURL, s.showSrc = false, false
+	rel := strings.TrimPrefix(strings.TrimPrefix(pkgPath, modulePath), "/")
+	escaped, _ := module.EscapePath(modulePath) // Checked by cachedVersions.
+	saved := s.out
+	defer func() { s.out = saved }()
+	prev, prevVersion := "", ""
+	found := false
+	for i, version := range versions {
+		var b bytes.Buffer
+		s.out = &b
+		s.printed = false
+		dir := filepath.Join(moduleCacheDir(), escaped+"@"+version, filepath.FromSlash(rel))
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			s.lookInDirectory(dir, "", name)
+		}
+		if i > 0 {
+			fmt.Fprintln(saved)
+		}
+		label := modulePath + "@" + version
+		switch text := b.String(); {
+		case text == "":
+			fmt.Fprintf(saved, "%s: no %s.%s\n", label, path.Base(pkgPath), name)
+		case text == prev:
+			fmt.Fprintf(saved, "%s: same as %s\n", label, prevVersion)
+		default:
+			fmt.Fprintf(saved, "%s\n%s\n", label, strings.TrimRight(text, "\n"))
+			found = true
+		}
+		prev, prevVersion = b.String(), version
+	}
+	if !found {
+		exit(1)
+	}
+}
+
+// cachedVersions returns the path of the module providing the package with
+// the import path, the longest for which the module cache holds a version,
+// and the versions it holds, oldest first.
+func cachedVersions(pkgPath string) (modulePath string, versions []string) {
+	cache := moduleCacheDir()
+	if cache == "" {
+		return "", nil
+	}
+	for modulePath = pkgPath; modulePath != "." && modulePath != "/"; modulePath = path.Dir(modulePath) {
+		escaped, err := module.EscapePath(modulePath)
+		if err != nil {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(cache, escaped) + "@*")
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.IsDir() {
+				versions = append(versions, m[strings.LastIndex(m, "@")+1:])
+			}
+		}
+		if len(versions) > 0 {
+			semver.Sort(versions)
+			return modulePath, versions
+		}
+	}
+	return "", nil
+}
+
+// splitImportSymbol splits an argument such as example.com/a/b.Name into the
+// import path and the symbol, at the first dot after the last slash.
+func splitImportSymbol(arg string) (pkgPath, name string, ok bool) {
+	slash := strings.LastIndex(arg, "/")
+	dot := strings.Index(arg[slash+1:], ".")
+	if dot < 0 {
+		return "", "", false
+	}
+	dot += slash + 1
+	return arg[:dot], arg[dot+1:], true
+}
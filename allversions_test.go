@@ -0,0 +1,77 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAllVersions(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	defaultFlags(t)
+	write := func(name, text string) {
+		name = filepath.Join(cache, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(text), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := "package x\n\n// Do does.\nfunc Do() {}\n"
+	write("example.com/!lib@v1.0.0/x/x.go", "package x\n")
+	write("example.com/!lib@v1.1.0/x/x.go", old)
+	write("example.com/!lib@v1.2.0/x/x.go", old)
+	write("example.com/!lib@v1.10.0/x/x.go", "package x\n\n// Do does, with options.\nfunc Do(opts ...string) {}\n")
+	write("example.com/!lib/y@v0.1.0/x/x.go", "package x\n") // Another module.
+
+	var out bytes.Buffer
+	status := exitStatus(func() { newSession(&out).allVersions("example.com/Lib/x", "Do") })
+	want := `example.com/Lib@v1.0.0: no x.Do
+
+example.com/Lib@v1.1.0
+// Do does.
+func Do()
+
+example.com/Lib@v1.2.0: same as v1.1.0
+
+example.com/Lib@v1.10.0
+// Do does, with options.
+func Do(opts ...string)
+`
+	if status != 0 || out.String() != want {
+		t.Errorf("-all-versions printed\n%s\nand exited with %d, want\n%s", out.String(), status, want)
+	}
+
+	out.Reset()
+	if status := exitStatus(func() { newSession(&out).allVersions("example.com/Lib/x", "Nothing") }); status != 1 {
+		t.Errorf("-all-versions of a missing symbol exited with %d, want 1", status)
+	}
+	if status := exitStatus(func() { newSession(&out).allVersions("example.com/none", "Do") }); status != 1 {
+		t.Errorf("-all-versions of an uncached module exited with %d, want 1", status)
+	}
+}
+
+func TestSplitImportSymbol(t *testing.T) {
+	tests := []struct {
+		arg, pkgPath, name string
+		ok                 bool
+	}{
+		{"example.com/a/b.Name", "example.com/a/b", "Name", true},
+		{"example.com/a/b.Type.Method", "example.com/a/b", "Type.Method", true},
+		{"strings.Cut", "strings", "Cut", true},
+		{"example.com/a/b", "", "", false},
+	}
+	for _, test := range tests {
+		pkgPath, name, ok := splitImportSymbol(test.arg)
+		if pkgPath != test.pkgPath || name != test.name || ok != test.ok {
+			t.Errorf("splitImportSymbol(%q) = %q, %q, %t; want %q, %q, %t", test.arg, pkgPath, name, ok, test.pkgPath, test.name, test.ok)
+		}
+	}
+}
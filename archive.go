@@ -0,0 +1,178 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// lookInArchive looks for the named exported identifier in the packages
+// named pkg, or in every package if pkg is empty, in the archive, which is
+// searched instead of the module, GOROOT and GOPATH. Files in the archive
+// are named in the output by the archive's name followed by their path
+// within it, and are subject to the limits on downloaded source.
+func (s *session) lookInArchive(archive, pkg, name string) {
+	fsys, closer, err := openArchive(archive)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -archive: %s\n", err)
+		exit(2)
+	}
+	defer closer.Close()
+	fs.WalkDir(fsys, ".", func(dir string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if dir != "." && strings.HasPrefix(path.Base(dir), ".") {
+			return fs.SkipDir
+		}
+		if pkg == "" || path.Base(dir) == pkg {
+			s.lookInArchiveDir(fsys, archive, dir, pkg, name)
+		}
+		return nil
+	})
+}
+
+// lookInArchiveDir looks in the package (if any) in the directory of the
+// archive's file system for the named exported identifier.
+func (s *session) lookInArchiveDir(fsys fs.FS, archive, dir, pkgName, name string) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return
+	}
+	fset := token.NewFileSet()
+	pkgs := make(map[string]*ast.Package)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		fileName := filepath.Join(archive, filepath.FromSlash(dir), e.Name())
+		info, err := e.Info()
+		if err == nil && info.Size() > int64(maxFileSize) {
+			err = fmt.Errorf("%s: file larger than %d bytes", fileName, maxFileSize)
+		}
+		var src []byte
+		if err == nil {
+			src, err = fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		}
+		if err == nil {
+			src, err = decodeSource(fileName, src)
+		}
+		var file *ast.File
+		if err == nil {
+			file, err = parseUntrusted(fset, fileName, src)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: skipping %s\n", err)
+			continue
+		}
+		s.sources[fileName] = src
+		pkg := pkgs[file.Name.Name]
+		if pkg == nil {
+			pkg = &ast.Package{Name: file.Name.Name, Files: make(map[string]*ast.File)}
+			pkgs[file.Name.Name] = pkg
+		}
+		pkg.Files[fileName] = file
+	}
+	for _, pkg := range choosePackages(filepath.Join(archive, filepath.FromSlash(dir)), pkgs) {
+		s.doPackage(pkg, fset, pkgName, name)
+	}
+}
+
+// openArchive returns the contents of the zip file, or of the tar file,
+// which may be compressed with gzip, as a file system, and what to close
+// when done with it. Only the Go files of a tar file are kept, repacked
+// into a zip file in memory, and those larger than the limit on the size
+// of a file are dropped with a notice. Like a module zip file, the archive
+// may unpack to at most maxZipSize bytes.
+func openArchive(name string) (fs.FS, io.Closer, error) {
+	if strings.HasSuffix(name, ".zip") {
+		r, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		var size uint64
+		for _, zf := range r.File {
+			if size += zf.UncompressedSize64; size > uint64(maxZipSize) {
+				r.Close()
+				return nil, nil, fmt.Errorf("%s: unpacks to more than %d bytes", name, maxZipSize)
+			}
+		}
+		return r, r, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".tgz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", name, err)
+		}
+		r = gz
+	}
+	files := make(map[string][]byte) // A later entry for a name replaces an earlier one.
+	var size int64
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", name, err)
+		}
+		fileName := path.Clean(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || !strings.HasSuffix(fileName, ".go") || !fs.ValidPath(fileName) {
+			continue
+		}
+		if hdr.Size > int64(maxFileSize) {
+			fmt.Fprintf(os.Stderr, "doc: skipping %s: file larger than %d bytes\n", path.Join(name, fileName), maxFileSize)
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, int64(maxFileSize)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", name, err)
+		}
+		if size += int64(len(data)); size > maxZipSize {
+			return nil, nil, fmt.Errorf("%s: unpacks to more than %d bytes", name, maxZipSize)
+		}
+		files[fileName] = data
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, fileName := range slices.Sorted(maps.Keys(files)) {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: fileName, Method: zip.Store})
+		if err == nil {
+			_, err = w.Write(files[fileName])
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %s", name, err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %s", name, err)
+	}
+	return zr, io.NopCloser(nil), nil
+}
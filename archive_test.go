@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestArchive writes the files, keyed by slash-separated name, to an
+// archive with the given name, a zip file or a tar file compressed with
+// gzip, in a temporary directory, and returns its path.
+func writeTestArchive(t *testing.T, name string, files map[string]string) string {
+	t.Helper()
+	name = filepath.Join(t.TempDir(), name)
+	fd, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	if filepath.Ext(name) == ".zip" {
+		z := zip.NewWriter(fd)
+		for file, data := range files {
+			w, err := z.Create(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write([]byte(data))
+		}
+		if err := z.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+	gz := gzip.NewWriter(fd)
+	tw := tar.NewWriter(gz)
+	for file, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: file, Mode: 0666, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(data))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestOpenArchiveLimit(t *testing.T) {
+	defer func(size int64) { maxZipSize = size }(maxZipSize)
+	maxZipSize = 100
+	files := make(map[string]string)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		files["p/"+name+".go"] = "package p\n\n// " + strings.Repeat("x", 20) + "\n"
+	}
+	for _, name := range []string{"src.zip", "src.tar.gz"} {
+		// Each file is within the limit, but not all of them.
+		if _, _, err := openArchive(writeTestArchive(t, name, files)); err == nil {
+			t.Errorf("openArchive(%s) of 4 files of %d bytes: no error", name, len(files["p/a.go"]))
+		}
+		small := map[string]string{"p/a.go": files["p/a.go"]}
+		fsys, closer, err := openArchive(writeTestArchive(t, name, small))
+		if err != nil {
+			t.Errorf("openArchive(%s) of 1 file: %v", name, err)
+			continue
+		}
+		if _, err := fsys.Open("p/a.go"); err != nil {
+			t.Errorf("openArchive(%s): %v", name, err)
+		}
+		closer.Close()
+	}
+}
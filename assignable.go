@@ -0,0 +1,155 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// setAssignable parses the function type given by -assignable-to, such as
+// func(http.ResponseWriter, *http.Request).
+func (s *session) setAssignable(typ string) {
+	x, err := parser.ParseExpr(typ)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -assignable-to: %s\n", err)
+		exit(2)
+	}
+	fn, ok := x.(*ast.FuncType)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "doc: -assignable-to: %s is not a function type\n", typ)
+		exit(2)
+	}
+	s.assignableKey = (&typeNamer{}).signatureKey(fn)
+}
+
+// assignableMatches reports whether the declaration in node is, if
+// -assignable-to is set, a function, or a method of an exported type whose
+// method value, without its receiver, is assignable to the function type:
+// whether its signature is identical. Types are compared as written, with each named
+// type qualified by the name of its package, so types that are the same
+// only through an alias do not match, apart from the predeclared aliases
+// byte, rune and any. Generic functions never match.
+func (f *File) assignableMatches(node ast.Node) bool {
+	if f.s.assignableKey == "" {
+		return true
+	}
+	fn, ok := node.(*ast.FuncDecl)
+	if !ok || fn.Type.TypeParams != nil || fn.Recv != nil && !ast.IsExported(recvTypeName(fn)) {
+		return false
+	}
+	n := &typeNamer{pkg: f.file.Name.Name, imports: make(map[string]string)}
+	for _, imp := range f.file.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		n.imports[importName(imp)] = importedName(p)
+	}
+	return n.signatureKey(fn.Type) == f.s.assignableKey
+}
+
+// A typeNamer writes types in a canonical form, to compare types written
+// in different files.
+type typeNamer struct {
+	pkg     string            // Name of the package declaring unqualified types; empty for the query.
+	imports map[string]string // Name of each imported package, by the name the file uses.
+}
+
+// predeclaredAliases maps the predeclared aliases to the types they denote.
+var predeclaredAliases = map[string]string{"byte": "uint8", "rune": "int32", "any": "interface{}"}
+
+// signatureKey returns the parameter and result types of the function type.
+func (n *typeNamer) signatureKey(fn *ast.FuncType) string {
+	return "func(" + n.listKey(fn.Params) + ") (" + n.listKey(fn.Results) + ")"
+}
+
+// listKey returns the types of the fields of the list, one for each name.
+func (n *typeNamer) listKey(list *ast.FieldList) string {
+	if list == nil {
+		return ""
+	}
+	var typs []string
+	for _, field := range list.List {
+		count := max(len(field.Names), 1)
+		for i := 0; i < count; i++ {
+			typs = append(typs, n.key(field.Type))
+		}
+	}
+	return strings.Join(typs, ", ")
+}
+
+// key returns the type in canonical form.
+func (n *typeNamer) key(x ast.Expr) string {
+	switch x := x.(type) {
+	case *ast.ParenExpr:
+		return n.key(x.X)
+	case *ast.Ident:
+		if obj, ok := types.Universe.Lookup(x.Name).(*types.TypeName); ok {
+			if alias, ok := predeclaredAliases[obj.Name()]; ok {
+				return alias
+			}
+			return obj.Name()
+		}
+		if n.pkg != "" {
+			return n.pkg + "." + x.Name
+		}
+		return x.Name
+	case *ast.SelectorExpr:
+		if id, ok := x.X.(*ast.Ident); ok {
+			if name, ok := n.imports[id.Name]; ok {
+				return name + "." + x.Sel.Name
+			}
+		}
+	case *ast.StarExpr:
+		return "*" + n.key(x.X)
+	case *ast.Ellipsis:
+		return "..." + n.key(x.Elt)
+	case *ast.ArrayType:
+		if x.Len == nil {
+			return "[]" + n.key(x.Elt)
+		}
+		return "[" + types.ExprString(x.Len) + "]" + n.key(x.Elt)
+	case *ast.MapType:
+		return "map[" + n.key(x.Key) + "]" + n.key(x.Value)
+	case *ast.ChanType:
+		switch x.Dir {
+		case ast.SEND:
+			return "chan<- " + n.key(x.Value)
+		case ast.RECV:
+			return "<-chan " + n.key(x.Value)
+		}
+		return "chan " + n.key(x.Value)
+	case *ast.FuncType:
+		return n.signatureKey(x)
+	case *ast.InterfaceType:
+		if x.Methods == nil || len(x.Methods.List) == 0 {
+			return "interface{}"
+		}
+	}
+	return types.ExprString(x)
+}
+
+// majorVersion matches the last element of an import path that is a major
+// version, or that ends in one as in gopkg.in/yaml.v3.
+var majorVersion = regexp.MustCompile(`(^|\.)v[0-9]+$`)
+
+// packageNameOf returns the name a package with the import path is most
+// likely declared with: its last element, without a major version.
+func packageNameOf(pkgPath string) string {
+	base := path.Base(pkgPath)
+	switch m := majorVersion.FindStringIndex(base); {
+	case m == nil:
+	case m[0] > 0:
+		base = base[:m[0]]
+	case path.Dir(pkgPath) != ".":
+		base = path.Base(path.Dir(pkgPath))
+	}
+	return base
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestAssignableTo(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/as\n\ngo 1.22\n",
+		"web/web.go": `package web
+
+import "net/http"
+
+// Handle handles.
+func Handle(w http.ResponseWriter, r *http.Request) {}
+
+// Server serves.
+type Server struct{}
+
+// ServeHTTP serves.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {}
+
+type server struct{}
+
+// ServeHTTP is on an unexported type.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+// Generic is generic.
+func Generic[T any](w http.ResponseWriter, r *http.Request) {}
+
+// Wrong takes too much.
+func Wrong(w http.ResponseWriter, r *http.Request, n int) {}
+
+// Sum sums.
+func Sum(a, b int) (int, error) { return 0, nil }
+
+// Write writes.
+func Write(p []byte, v any) {}
+`,
+		"web/other.go": `package web
+
+import h "net/http"
+
+// Other is renamed.
+func Other(w h.ResponseWriter, r *h.Request) {}
+`,
+		"util/util.go": `package util
+
+import "net/http"
+
+// Wrap wraps.
+func Wrap(w http.ResponseWriter, r *http.Request) {}
+
+// Total totals.
+func Total(x, y int) (int, error) { return 0, nil }
+`,
+	})
+	tests := []struct {
+		args []string
+		want string // The outline of what is printed.
+	}{
+		{[]string{"func(http.ResponseWriter, *http.Request)", "web"}, "Functions\n#Other\n#Handle\nTypes\n#Server.ServeHTTP\n"},
+		{[]string{"func(http.ResponseWriter, *http.Request)"}, "#Wrap\n#Other\n#Handle\n#Server.ServeHTTP\n"},
+		{[]string{"func(int, int) (int, error)"}, "#Total\n#Sum\n"},
+		{[]string{"func(x int, y int) (n int, err error)", "web"}, "Functions\n#Sum\n"},
+		{[]string{"func([]uint8, interface{})", "web"}, "Functions\n#Write\n"},
+		{[]string{"func(int) (int, error)", "web"}, ""},
+	}
+	for _, test := range tests {
+		args := append([]string{"-local", "-assignable-to"}, test.args...)
+		out, stderr, status := runDoc(t, dir, args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", args, status, stderr)
+			continue
+		}
+		if got := outline(out); got != test.want {
+			t.Errorf("doc %q listed\n%s\nwant\n%s", args, got, test.want)
+		}
+	}
+	for _, typ := range []string{"int", "func(", "struct{}"} {
+		if _, _, status := runDoc(t, dir, "-local", "-assignable-to", typ); status != 2 {
+			t.Errorf("doc -assignable-to %q exited with %d, want 2", typ, status)
+		}
+	}
+}
+
+func TestPackageNameOf(t *testing.T) {
+	tests := []struct {
+		pkgPath, want string
+	}{
+		{"net/http", "http"},
+		{"example.com/mod/v2", "mod"},
+		{"gopkg.in/yaml.v3", "yaml"},
+		{"v2", "v2"},
+		{"example.com/env", "env"},
+	}
+	for _, test := range tests {
+		if got := packageNameOf(test.pkgPath); got != test.want {
+			t.Errorf("packageNameOf(%q) = %q, want %q", test.pkgPath, got, test.want)
+		}
+	}
+}
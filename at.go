@@ -0,0 +1,284 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// lookAt prints the documentation of the identifier at the position, given
+// as file.go:line:column with the column counted in bytes from 1, after
+// type-checking the package holding the file. It is what an editor shows
+// when the cursor rests on a name: for a use, the declaration it refers to,
+// in whatever package; for a local variable, its type.
+func (s *session) lookAt(arg string) {
+	name, line, col, ok := splitPosition(arg)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "doc: -at: want file.go:line:column, not %s\n", arg)
+		exit(2)
+	}
+	name, err := filepath.Abs(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -at: %s\n", err)
+		exit(2)
+	}
+	fset := token.NewFileSet()
+	pkg, astFile := parseEnclosing(fset, name)
+	if astFile == nil {
+		fmt.Fprintf(os.Stderr, "doc: -at: cannot parse %s\n", name)
+		exit(1)
+	}
+	tokFile := fset.File(astFile.Pos())
+	if line < 1 || line > tokFile.LineCount() {
+		fmt.Fprintf(os.Stderr, "doc: -at: %s has no line %d\n", name, line)
+		exit(2)
+	}
+	pos := tokFile.LineStart(line) + token.Pos(col-1)
+	var id *ast.Ident
+	ast.Inspect(astFile, func(n ast.Node) bool {
+		if n == nil || pos < n.Pos() || n.End() <= pos {
+			return false
+		}
+		if n, ok := n.(*ast.Ident); ok {
+			id = n
+		}
+		return true
+	})
+	if id == nil {
+		fmt.Fprintf(os.Stderr, "doc: -at: no identifier at %s\n", arg)
+		exit(1)
+	}
+	typesPkg, info := typeCheck(fset, pkg)
+	obj := info.Uses[id]
+	if obj == nil {
+		obj = info.Defs[id]
+	}
+	if obj == nil {
+		fmt.Fprintf(os.Stderr, "doc: -at: nothing known of %s at %s\n", id.Name, arg)
+		exit(1)
+	}
+	qualifier := func(p *types.Package) string {
+		if p == typesPkg {
+			return ""
+		}
+		return p.Name()
+	}
+	fmt.Fprintf(s.out, "%s\n\n", types.ObjectString(obj, qualifier))
+
+	var files []*File
+	for fileName, astFile := range pkg.Files {
+		file := s.newFile(fset, fileName, obj.Name(), astFile)
+		file.doPrint = true
+		file.types = typesPkg
+		file.objs = info.Defs
+		files = append(files, file)
+	}
+	switch obj := obj.(type) {
+	case *types.PkgName:
+		s.pkgDoc = true
+		s.lookInPackage(obj.Imported().Path(), filepath.Dir(name), "")
+	case *types.Var:
+		if obj.IsField() {
+			for sel, selection := range info.Selections {
+				if sel.Sel != id {
+					continue
+				}
+				owner := fieldOwner(selection.Recv(), selection.Index())
+				if owner == nil {
+					break
+				}
+				if file, decl, fieldID := findField(s, owner, obj.Name(), files); decl != nil {
+					file.printNode(decl, fieldID, file.declURL(typeKind, owner.Name()+"."+fieldID.Name, file.fset.Position(fieldID.Pos()).Line))
+				}
+			}
+			return
+		}
+		s.printObject(obj, files)
+	case *types.Func:
+		if obj.Type().(*types.Signature).Recv() == nil {
+			s.printObject(obj, files)
+			return
+		}
+		file, decl := findFunc(s, obj, files)
+		if decl != nil {
+			file.printFunc(decl)
+		}
+	default:
+		s.printObject(obj, files)
+	}
+}
+
+// printObject prints the declaration of the object if it is declared at the
+// top level of a package, the universe included. In the package of the
+// files, the type-checked package holding the position being examined, it
+// is found by position; other packages are searched for its name, which
+// must be exported there.
+func (s *session) printObject(obj types.Object, files []*File) {
+	switch {
+	case obj.Pkg() == nil:
+		if obj.Parent() != types.Universe {
+			return
+		}
+		// The universe is documented, in lower case, by package builtin.
+		fset := token.NewFileSet()
+		pkgs, _ := parseDir(fset, filepath.Join(goRoot, "src", "builtin"), nil, parser.ParseComments)
+		for _, pkg := range pkgs {
+			for fileName, astFile := range pkg.Files {
+				node, id := findDecl(astFile, func(id *ast.Ident) bool { return id.Name == obj.Name() })
+				if node != nil {
+					file := s.newFile(fset, fileName, obj.Name(), astFile)
+					file.doPrint = true
+					file.printDecl(node, id)
+					return
+				}
+			}
+		}
+	case obj.Parent() != obj.Pkg().Scope():
+		// A local, whose type has been printed.
+	case obj.Pkg() == files[0].types:
+		for _, file := range files {
+			node, id := findDecl(file.file, func(id *ast.Ident) bool { return id.Pos() == obj.Pos() })
+			if node != nil {
+				file.printDecl(node, id)
+				return
+			}
+		}
+	default:
+		s.methods = false // Only package-level names are wanted.
+		s.lookInPackage(obj.Pkg().Path(), filesDir(files), obj.Name())
+	}
+}
+
+// lookInPackage prints the documentation of the name, or of the package if
+// the name is empty, in the package with the import path as imported by a
+// file in srcDir. It exits if the package's source cannot be found or
+// documents nothing by the name.
+func (s *session) lookInPackage(pkgPath, srcDir, name string) {
+	dir := dirForImport(pkgPath, srcDir)
+	if dir == "" {
+		fmt.Fprintf(os.Stderr, "doc: -at: cannot find the source of package %s\n", pkgPath)
+		exit(1)
+	}
+	s.lookInDirectory(dir, "", name)
+	if !s.printed {
+		if name == "" {
+			fmt.Fprintf(os.Stderr, "doc: -at: no documentation for package %s in %s\n", pkgPath, dir)
+		} else {
+			fmt.Fprintf(os.Stderr, "doc: -at: no documentation for %s.%s in %s\n", pkgPath, name, dir)
+		}
+		exit(1)
+	}
+}
+
+// printDecl prints the top-level declaration, named by the identifier, as
+// Visit would, but whether or not the name is exported.
+func (f *File) printDecl(node ast.Node, id *ast.Ident) {
+	if fn, ok := node.(*ast.FuncDecl); ok {
+		f.printFunc(fn)
+		return
+	}
+	f.printNode(node, id, f.nameURL(node, id))
+}
+
+// printFunc prints the declaration of the function or method, with its body
+// only if -full is set.
+func (f *File) printFunc(decl *ast.FuncDecl) {
+	body := decl.Body
+	if !f.s.full {
+		decl.Body = nil // Do not print the function body.
+	}
+	if decl.Recv != nil {
+		f.printNode(decl, decl.Name, f.methodURL(decl))
+	} else {
+		f.printNode(decl, decl.Name, f.nameURL(decl, decl.Name))
+	}
+	decl.Body = body
+}
+
+// findDecl returns the top-level declaration in the file of the first name
+// that satisfies match, as Visit would print it, and the identifier naming
+// it, or nil.
+func findDecl(file *ast.File, match func(*ast.Ident) bool) (ast.Node, *ast.Ident) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil && match(decl.Name) {
+				return decl, decl.Name
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				var names []*ast.Ident
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					names = spec.Names
+				case *ast.TypeSpec:
+					names = []*ast.Ident{spec.Name}
+				}
+				for _, id := range names {
+					if !match(id) {
+						continue
+					}
+					if _, ok := spec.(*ast.TypeSpec); ok && decl.Lparen.IsValid() {
+						return spec, id // As Visit does; see the comment there.
+					}
+					return decl, id
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// parseEnclosing parses the package in the directory of the named file
+// that holds the file, returning the package and the file. The file is
+// parsed even if it has syntax errors, as the one being edited may.
+func parseEnclosing(fset *token.FileSet, name string) (*ast.Package, *ast.File) {
+	pkgs, _ := parseDir(fset, filepath.Dir(name), nil, parser.ParseComments) // Ignore the error.
+	for _, pkg := range pkgs {
+		if astFile := pkg.Files[name]; astFile != nil {
+			return pkg, astFile
+		}
+	}
+	src, err := readSource(name)
+	if err != nil {
+		return nil, nil
+	}
+	astFile, _ := parseFile(fset, name, src, parser.ParseComments)
+	if astFile == nil || astFile.Name == nil {
+		return nil, nil
+	}
+	pkg := pkgs[astFile.Name.Name]
+	if pkg == nil {
+		pkg = &ast.Package{Name: astFile.Name.Name, Files: make(map[string]*ast.File)}
+	}
+	pkg.Files[name] = astFile
+	return pkg, astFile
+}
+
+// splitPosition splits a position of the form file:line:column.
+func splitPosition(arg string) (name string, line, col int, ok bool) {
+	i := strings.LastIndex(arg, ":")
+	if i < 0 {
+		return "", 0, 0, false
+	}
+	j := strings.LastIndex(arg[:i], ":")
+	if j < 0 {
+		return "", 0, 0, false
+	}
+	line, lerr := strconv.Atoi(arg[j+1 : i])
+	col, cerr := strconv.Atoi(arg[i+1:])
+	if lerr != nil || cerr != nil || col < 1 {
+		return "", 0, 0, false
+	}
+	return arg[:j], line, col, true
+}
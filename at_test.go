@@ -0,0 +1,56 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLookAtModuleImport(t *testing.T) {
+	defaultFlags(t)
+	dir := writeModule(t, testModule)
+	var out bytes.Buffer
+	// Line 7 of auth.go is "type Token struct{ C m.Config }".
+	newSession(&out).lookAt(filepath.Join(dir, "internal", "auth", "auth.go") + ":7:24")
+	for _, want := range []string{"type m.Config struct{Name string}", "// Config configures things."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("-at m.Config printed\n%s\nwant it to contain %q", out.String(), want)
+		}
+	}
+}
+
+func FuzzSplitPosition(f *testing.F) {
+	for _, arg := range []string{"a.go:7:24", "a.go:7", "C:\\a.go:1:1", "a.go:1:0", "a.go:-1:+2", "::"} {
+		f.Add(arg)
+	}
+	f.Fuzz(func(t *testing.T, arg string) {
+		name, line, col, ok := splitPosition(arg)
+		if !ok {
+			return
+		}
+		if col < 1 {
+			t.Fatalf("splitPosition(%q) column %d", arg, col)
+		}
+		if name2, line2, col2, ok := splitPosition(fmt.Sprintf("%s:%d:%d", name, line, col)); !ok || name2 != name || line2 != line || col2 != col {
+			t.Fatalf("splitPosition(%q) = %q, %d, %d, which does not split again the same", arg, name, line, col)
+		}
+	})
+}
+
+func TestLookAtDependency(t *testing.T) {
+	defaultFlags(t)
+	dir := writeModule(t, depModule(t))
+	var out bytes.Buffer
+	newSession(&out).lookAt(filepath.Join(dir, "main.go") + ":10:21")
+	for _, want := range []string{"func semver.Compare(v string, w string) int", "// Compare returns an integer comparing two versions", "https://pkg.go.dev/golang.org/x/mod/semver#Compare"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("-at semver.Compare printed\n%s\nwant it to contain %q", out.String(), want)
+		}
+	}
+}
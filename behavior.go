@@ -0,0 +1,158 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/types"
+	"strings"
+)
+
+// behavior prints what the body of fn reveals that its comment may not:
+// the explicit calls to panic and the sentinel errors it returns.
+// Function literals are skipped, as they may run elsewhere.
+func (f *File) behavior(fn *ast.FuncDecl) {
+	if fn.Body == nil {
+		return
+	}
+	var panics, errs []string
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if id, ok := n.Fun.(*ast.Ident); ok && id.Name == "panic" && len(n.Args) == 1 {
+				if _, ok := f.uses[id].(*types.Builtin); ok || f.uses[id] == nil {
+					panics = appendUnique(panics, f.exprString(n.Args[0]))
+				}
+			}
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				if name := f.sentinel(result); name != "" {
+					errs = appendUnique(errs, name)
+				}
+			}
+		}
+		return true
+	})
+	if len(panics) == 0 && len(errs) == 0 {
+		return
+	}
+	w := f.output(nodeKind(fn))
+	if len(panics) > 0 {
+		fmt.Fprintf(w, "May panic: %s\n", strings.Join(panics, ", "))
+	}
+	if len(errs) > 0 {
+		fmt.Fprintf(w, "Returns errors: %s\n", strings.Join(errs, ", "))
+	}
+	fmt.Fprintln(w)
+}
+
+// sentinel returns the name of the package-level error variable the
+// expression refers to, or the empty string.
+func (f *File) sentinel(expr ast.Expr) string {
+	var id *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return ""
+	}
+	v, ok := f.uses[id].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return ""
+	}
+	if !types.Implements(v.Type(), types.Universe.Lookup("error").Type().Underlying().(*types.Interface)) {
+		return ""
+	}
+	return f.exprString(expr)
+}
+
+// exprString returns the source form of the expression, abbreviated if long.
+func (f *File) exprString(expr ast.Expr) string {
+	var b bytes.Buffer
+	printer.Fprint(&b, f.fset, expr)
+	s := b.String()
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + " ..."
+	}
+	if len(s) > 60 {
+		s = s[:60] + "..."
+	}
+	return s
+}
+
+// appendUnique appends s to list if it is not already present.
+func appendUnique(list []string, s string) []string {
+	for _, t := range list {
+		if t == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// concurrency prints a summary of the calling contract of fn as it concerns
+// concurrency: whether it starts goroutines, takes a context.Context, or
+// accepts or returns channels.
+func (f *File) concurrency(fn *ast.FuncDecl) {
+	var notes []string
+	if fn.Body != nil {
+		goroutines := false
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			if _, ok := node.(*ast.GoStmt); ok {
+				goroutines = true
+			}
+			return !goroutines
+		})
+		if goroutines {
+			notes = append(notes, "starts goroutines")
+		}
+	}
+	if obj, ok := f.objs[fn.Name].(*types.Func); ok {
+		sig := obj.Type().(*types.Signature)
+		qual := func(p *types.Package) string {
+			if p == obj.Pkg() {
+				return ""
+			}
+			return p.Name()
+		}
+		for i := 0; i < sig.Params().Len(); i++ {
+			param := sig.Params().At(i)
+			if isContext(param.Type()) {
+				notes = append(notes, "takes a context.Context")
+			}
+			if isChan(param.Type()) {
+				notes = append(notes, fmt.Sprintf("accepts channel %s %s", param.Name(), types.TypeString(param.Type(), qual)))
+			}
+		}
+		for i := 0; i < sig.Results().Len(); i++ {
+			if typ := sig.Results().At(i).Type(); isChan(typ) {
+				notes = append(notes, fmt.Sprintf("returns channel %s", types.TypeString(typ, qual)))
+			}
+		}
+	}
+	if len(notes) == 0 {
+		return
+	}
+	fmt.Fprintf(f.output(nodeKind(fn)), "Concurrency: %s\n\n", strings.Join(notes, "; "))
+}
+
+// isContext reports whether the type is context.Context.
+func isContext(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// isChan reports whether the type is a channel.
+func isChan(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Chan)
+	return ok
+}
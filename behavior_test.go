@@ -0,0 +1,124 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// behaviorModule is a module whose functions panic, return sentinel
+// errors, start goroutines and pass contexts and channels.
+var behaviorModule = map[string]string{
+	"go.mod": "module example.com/b\n\ngo 1.22\n",
+	"b.go": `package b
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrEmpty reports an empty input.
+var ErrEmpty = errors.New("empty")
+
+// Parse parses s.
+func Parse(s string) (int, error) {
+	if s == "" {
+		return 0, ErrEmpty
+	}
+	if s == "eof" {
+		return 0, io.EOF
+	}
+	if s == "?" {
+		panic("unreachable: " + s)
+	}
+	if s == "!" {
+		panic(ErrEmpty)
+	}
+	if s == "again" {
+		return 0, ErrEmpty
+	}
+	err := errors.New("local")
+	return 0, err
+}
+
+// Later panics only in a function literal.
+func Later() func() {
+	return func() { panic("later") }
+}
+
+// Start starts work.
+func Start(ctx context.Context, in <-chan int) chan error {
+	go func() {}()
+	return nil
+}
+
+// Wait waits.
+func Wait(done chan struct{}) {}
+
+// Read reads.
+func Read() error { return io.EOF }
+
+// Plain does nothing.
+func Plain() {}
+`,
+}
+
+func TestBehavior(t *testing.T) {
+	dir := writeModule(t, behaviorModule)
+	defaultFlags(t)
+	setFlag(t, behaviorFlag, true)
+	tests := []struct {
+		name string
+		want string // The notes printed, or empty if none.
+	}{
+		{"Parse", "May panic: \"unreachable: \" + s, ErrEmpty\nReturns errors: ErrEmpty, io.EOF\n\n"},
+		{"Read", "Returns errors: io.EOF\n\n"},
+		{"Later", ""},
+		{"Plain", ""},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		newSession(&out).lookInDirectory(dir, "", test.name)
+		notes := ""
+		if i := strings.Index(out.String(), "May panic: "); i >= 0 {
+			notes = out.String()[i:]
+		} else if i := strings.Index(out.String(), "Returns errors: "); i >= 0 {
+			notes = out.String()[i:]
+		}
+		if !strings.HasPrefix(notes, test.want) || test.want == "" && notes != "" {
+			t.Errorf("-behavior of %s printed\n%s\nwant notes\n%s", test.name, out.String(), test.want)
+		}
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	dir := writeModule(t, behaviorModule)
+	defaultFlags(t)
+	setFlag(t, concurrencyFlag, true)
+	tests := []struct {
+		name string
+		want string // The note printed, or empty if none.
+	}{
+		{"Start", "Concurrency: starts goroutines; takes a context.Context; accepts channel in <-chan int; returns channel chan error\n"},
+		{"Wait", "Concurrency: accepts channel done chan struct{}\n"},
+		{"Later", ""}, // It returns a function, not a channel.
+		{"Plain", ""},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		newSession(&out).lookInDirectory(dir, "", test.name)
+		note := ""
+		if i := strings.Index(out.String(), "Concurrency: "); i >= 0 {
+			note, _, _ = strings.Cut(out.String()[i:], "\n")
+			note += "\n"
+		}
+		if note != test.want {
+			t.Errorf("-concurrency of %s printed\n%s\nwant %q", test.name, out.String(), test.want)
+		}
+	}
+}
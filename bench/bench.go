@@ -0,0 +1,222 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Bench times doc searching a synthetic module, so that changes meant to
+// make doc faster can show what they gain and later changes can be checked
+// not to lose it. It writes a module of generated packages to a temporary
+// directory, runs a fixed set of queries against it with doc -local, and
+// prints the median time of each.
+//
+// Usage:
+//	bench [flags]
+//
+// Flags
+//	-doc path
+// names the doc binary to time; the default is doc, found in $PATH.
+// Flags
+//	-pkgs n -files n -decls n
+// set the size of the module: the number of packages, of files in each
+// package, and of declarations of each kind in each file.
+// Flag
+//	-runs n
+// sets how many times each query is run.
+// Flag
+//	-o file
+// writes the timings to the file as well, one query per line, for use as
+// a baseline.
+// Flag
+//	-baseline file
+// compares the timings to those in a file written with -o, and exits with
+// status 1 if any query is slower by more than the -tolerance, a fraction.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	docFlag       = flag.String("doc", "doc", "doc binary to time")
+	pkgsFlag      = flag.Int("pkgs", 200, "number of packages in the module")
+	filesFlag     = flag.Int("files", 10, "number of files in each package")
+	declsFlag     = flag.Int("decls", 20, "number of declarations of each kind in each file")
+	runsFlag      = flag.Int("runs", 5, "number of times to run each query")
+	outFlag       = flag.String("o", "", "file to write the timings to")
+	baselineFlag  = flag.String("baseline", "", "file of timings, written with -o, to compare against")
+	toleranceFlag = flag.Float64("tolerance", 0.1, "fraction by which a query may be slower than the baseline")
+)
+
+// The queries, as arguments to doc, each run with -local in the module.
+// They cover a qualified name, an unqualified one found by walking every
+// package, a regular expression, a whole package, and a name that is not there.
+var queries = [][]string{
+	{"pkg0.Func0"},
+	{"func3"},
+	{"-r", "Type1.*"},
+	{"pkg1.Type0"},
+	{"nosuchname"},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: bench [flags]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 0 || *runsFlag < 1 {
+		usage()
+	}
+	var err error
+	dir, err = os.MkdirTemp("", "docbench")
+	if err != nil {
+		fatalf("%s", err)
+	}
+	if err := generate(dir, *pkgsFlag, *filesFlag, *declsFlag); err != nil {
+		fatalf("%s", err)
+	}
+
+	var out bytes.Buffer
+	timings := make(map[string]time.Duration)
+	fmt.Fprintf(&out, "# %d packages, %d files, %d declarations\n", *pkgsFlag, *filesFlag, *declsFlag)
+	for _, args := range queries {
+		name := strings.Join(args, " ")
+		d, err := timeQuery(dir, args, *runsFlag)
+		if err != nil {
+			fatalf("doc %s: %s", name, err)
+		}
+		timings[name] = d
+		fmt.Fprintf(&out, "%s\t%d\n", name, d.Nanoseconds())
+	}
+	for _, args := range queries {
+		name := strings.Join(args, " ")
+		fmt.Printf("%-20s %v\n", name, timings[name].Round(time.Microsecond))
+	}
+	if *outFlag != "" {
+		if err := os.WriteFile(*outFlag, out.Bytes(), 0644); err != nil {
+			fatalf("%s", err)
+		}
+	}
+	os.RemoveAll(dir)
+	if *baselineFlag != "" && !compare(*baselineFlag, timings) {
+		os.Exit(1)
+	}
+}
+
+// dir is the temporary directory holding the generated module.
+var dir string
+
+// fatalf reports the error, removes the generated module and exits.
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "bench: "+format+"\n", args...)
+	if dir != "" {
+		os.RemoveAll(dir)
+	}
+	os.Exit(1)
+}
+
+// generate writes a module to root holding the packages pkg0, pkg1, ...,
+// each with the files, each with the number of constants, variables,
+// functions and types, with doc comments, and a method for each type.
+func generate(root string, pkgs, files, decls int) error {
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/bench\n"), 0644); err != nil {
+		return err
+	}
+	for p := 0; p < pkgs; p++ {
+		pkgDir := filepath.Join(root, fmt.Sprintf("pkg%d", p))
+		if err := os.Mkdir(pkgDir, 0755); err != nil {
+			return err
+		}
+		for f := 0; f < files; f++ {
+			var b bytes.Buffer
+			if f == 0 {
+				fmt.Fprintf(&b, "// Package pkg%d is generated for timing doc.\n", p)
+			}
+			fmt.Fprintf(&b, "package pkg%d\n\n", p)
+			for d := 0; d < decls; d++ {
+				// Names are unique within the package but repeat across packages.
+				n := f*decls + d
+				fmt.Fprintf(&b, "// Const%d is a constant.\nconst Const%d = %d\n\n", n, n, n)
+				fmt.Fprintf(&b, "// Var%d is a variable.\nvar Var%d = \"%d\"\n\n", n, n, n)
+				fmt.Fprintf(&b, "// Func%d returns its argument.\nfunc Func%d(x int) int { return x + %d }\n\n", n, n, n)
+				fmt.Fprintf(&b, "// Type%d is a type.\ntype Type%d struct{ N int }\n\n", n, n)
+				fmt.Fprintf(&b, "// Get returns N.\nfunc (t *Type%d) Get() int { return t.N }\n\n", n)
+			}
+			name := filepath.Join(pkgDir, fmt.Sprintf("file%d.go", f))
+			if err := os.WriteFile(name, b.Bytes(), 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// timeQuery runs doc -local with the arguments in root the number of times
+// and returns the median of the times taken.
+func timeQuery(root string, args []string, runs int) (time.Duration, error) {
+	var times []time.Duration
+	for i := 0; i < runs; i++ {
+		cmd := exec.Command(*docFlag, append([]string{"-local"}, args...)...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(), "XDG_CACHE_HOME="+filepath.Join(root, ".cache"))
+		start := time.Now()
+		// doc exits with status 1 when nothing is found, which is not a failure here.
+		if err := cmd.Run(); err != nil {
+			if _, ok := err.(*exec.ExitError); !ok {
+				return 0, err
+			}
+		}
+		times = append(times, time.Since(start))
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	return times[len(times)/2], nil
+}
+
+// compare reports, for each query in the baseline file, how its time has
+// changed, and returns false if any is slower by more than the tolerance.
+func compare(baseline string, timings map[string]time.Duration) bool {
+	f, err := os.Open(baseline)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bench: %s\n", err)
+		return false
+	}
+	defer f.Close()
+	ok := true
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		tab := strings.LastIndex(line, "\t")
+		if tab < 0 {
+			continue
+		}
+		name := line[:tab]
+		ns, err := strconv.ParseInt(line[tab+1:], 10, 64)
+		now, found := timings[name]
+		if err != nil || !found || ns <= 0 {
+			continue
+		}
+		change := float64(now.Nanoseconds()-ns) / float64(ns)
+		verdict := ""
+		if change > *toleranceFlag {
+			verdict = " SLOWER"
+			ok = false
+		}
+		fmt.Printf("%-20s %+.1f%%%s\n", name, 100*change, verdict)
+	}
+	return ok
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+	if err := generate(root, 3, 2, 4); err != nil {
+		t.Fatal(err)
+	}
+	for p := 0; p < 3; p++ {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%d", p))
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pkgs) != 1 {
+			t.Fatalf("%s holds %d packages, want 1", dir, len(pkgs))
+		}
+		for _, pkg := range pkgs {
+			decls := 0
+			for _, file := range pkg.Files {
+				decls += len(file.Decls)
+			}
+			if len(pkg.Files) != 2 || decls != 2*4*5 {
+				t.Errorf("%s has %d files and %d declarations, want 2 and %d", dir, len(pkg.Files), decls, 2*4*5)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "go.mod")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := filepath.Join(t.TempDir(), "baseline")
+	text := "# doc timings\nqualified\t1000000\nwalk all\t2000000\ngone\t1000000\nbad\tx\n"
+	if err := os.WriteFile(baseline, []byte(text), 0666); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		timings map[string]time.Duration
+		ok      bool
+	}{
+		{map[string]time.Duration{"qualified": 1050 * time.Microsecond, "walk all": 1500 * time.Microsecond}, true},
+		{map[string]time.Duration{"qualified": 1200 * time.Microsecond, "walk all": 2 * time.Millisecond}, false},
+		{map[string]time.Duration{"bad": time.Hour, "new": time.Hour}, true}, // Neither is in the baseline.
+	}
+	for _, test := range tests {
+		if got := compare(baseline, test.timings); got != test.ok {
+			t.Errorf("compare with %v = %t, want %t", test.timings, got, test.ok)
+		}
+	}
+	if compare(filepath.Join(t.TempDir(), "missing"), nil) {
+		t.Errorf("compare with a missing baseline = true, want false")
+	}
+}
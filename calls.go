@@ -0,0 +1,191 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A funcTarget is a function or method named on the command line,
+// found in a package.
+type funcTarget struct {
+	s     *session
+	fset  *token.FileSet
+	pkg   *ast.Package
+	files []*File
+	info  *types.Info
+	decl  *ast.FuncDecl
+	obj   *types.Func
+}
+
+// findTargets returns the functions in the packages in the directories
+// with the name, which may be Type.Method. Case is ignored.
+func (s *session) findTargets(dirs []string, name string) []funcTarget {
+	recv, method := "", name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		recv, method = name[:i], name[i+1:]
+	}
+	var targets []funcTarget
+	for _, dir := range dirs {
+		fset := token.NewFileSet()
+		pkgs, _ := parseDir(fset, dir, nil, parser.ParseComments) // Ignore the error.
+		for _, pkg := range pkgs {
+			var files []*File
+			var decls []*ast.FuncDecl
+			for fileName, astFile := range pkg.Files {
+				file := s.newFile(fset, fileName, name, astFile)
+				file.doPrint = true
+				files = append(files, file)
+				for _, decl := range astFile.Decls {
+					decl, ok := decl.(*ast.FuncDecl)
+					if ok && decl.Name.IsExported() && equalFold(decl.Name.Name, method) && equalFold(recvTypeName(decl), recv) {
+						decls = append(decls, decl)
+					}
+				}
+			}
+			if len(decls) == 0 {
+				continue
+			}
+			typesPkg, info := typeCheck(fset, pkg)
+			for _, file := range files {
+				file.types = typesPkg
+			}
+			for _, decl := range decls {
+				obj, _ := info.Defs[decl.Name].(*types.Func)
+				targets = append(targets, funcTarget{s, fset, pkg, files, info, decl, obj})
+			}
+		}
+	}
+	if len(targets) == 0 {
+		fmt.Fprintf(os.Stderr, "doc: no function %s\n", name)
+		exit(1)
+	}
+	return targets
+}
+
+// calls lists the functions called by the named function, following calls
+// within its package to the depth set by -depth.
+func (s *session) calls(dirs []string, name string) {
+	for _, t := range s.findTargets(dirs, name) {
+		fmt.Fprintf(s.out, "%s calls:\n", funcName(t.pkg.Name, t.decl))
+		t.printCalls(t.decl, *depthFlag, "\t", make(map[*types.Func]bool))
+		fmt.Fprintln(s.out)
+	}
+}
+
+// printCalls prints the functions called in the body of fn, each with its position
+// and synopsis, and recurs into those declared in the same package.
+func (t *funcTarget) printCalls(fn *ast.FuncDecl, depth int, indent string, seen map[*types.Func]bool) {
+	if fn.Body == nil || depth <= 0 {
+		return
+	}
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		id := calleeIdent(call.Fun)
+		if id == nil {
+			return true
+		}
+		obj, ok := t.info.Uses[id].(*types.Func)
+		if !ok || seen[obj] {
+			return true
+		}
+		seen[obj] = true
+		file, decl := findFunc(t.s, obj, t.files)
+		if decl == nil {
+			fmt.Fprintf(t.s.out, "%s%s\n", indent, obj.FullName())
+			return true
+		}
+		pkgName := file.file.Name.Name
+		fmt.Fprintf(t.s.out, "%s%s\t%s\n", indent, funcName(pkgName, decl), position(file.fset, decl.Name.Pos()))
+		if s := synopsis(decl.Doc); s != "" {
+			fmt.Fprintf(t.s.out, "%s\t%s\n", indent, s)
+		}
+		if file.fset == t.fset {
+			t.printCalls(decl, depth-1, indent+"\t", seen)
+		}
+		return true
+	})
+}
+
+// callers lists the functions that call the named function: those in its own
+// package, found through the type checker, and those elsewhere in GOROOT,
+// GOPATH and the current module that refer to it by its qualified name.
+// Methods are only found within the package.
+func (s *session) callers(dirs []string, name string) {
+	all := append(s.pathsFor(goRoot, ""), s.searchDirs()...)
+	for _, t := range s.findTargets(dirs, name) {
+		fmt.Fprintf(s.out, "%s is called by:\n", funcName(t.pkg.Name, t.decl))
+		pkgPath := importPath(filepath.Dir(t.fset.Position(t.decl.Pos()).Filename))
+		if t.obj != nil {
+			for _, file := range t.files {
+				for _, decl := range file.file.Decls {
+					caller, ok := decl.(*ast.FuncDecl)
+					if !ok {
+						continue
+					}
+					ast.Inspect(caller, func(node ast.Node) bool {
+						if id, ok := node.(*ast.Ident); ok && t.info.Uses[id] == t.obj {
+							t.printCaller(pkgPath, caller, t.fset.Position(id.Pos()))
+						}
+						return true
+					})
+				}
+			}
+		}
+		if t.decl.Recv != nil {
+			fmt.Fprintln(s.out)
+			continue
+		}
+		walkReferences(all, parser.ParseComments, func(ref reference) {
+			if ref.path == pkgPath && ref.name == t.decl.Name.Name && ref.fn != nil && ref.from != pkgPath {
+				t.printCaller(ref.from, ref.fn, ref.pos)
+			}
+		})
+		fmt.Fprintln(s.out)
+	}
+}
+
+// printCaller prints the function holding a call, qualified by the import path
+// of its package, the position of the call, and the function's synopsis.
+func (t *funcTarget) printCaller(pkgPath string, fn *ast.FuncDecl, pos token.Position) {
+	fmt.Fprintf(t.s.out, "\t%s\t%s:%d\n", funcName(pkgPath, fn), pos.Filename, pos.Line)
+	if s := synopsis(fn.Doc); s != "" {
+		fmt.Fprintf(t.s.out, "\t\t%s\n", s)
+	}
+}
+
+// funcName returns the qualified name of the function or method, such as
+// bytes.Buffer.Write.
+func funcName(pkg string, fn *ast.FuncDecl) string {
+	if recv := recvTypeName(fn); recv != "" {
+		return pkg + "." + recv + "." + fn.Name.Name
+	}
+	return pkg + "." + fn.Name.Name
+}
+
+// position returns the file:line form of the position.
+func position(fset *token.FileSet, pos token.Pos) string {
+	p := fset.Position(pos)
+	return fmt.Sprintf("%s:%d", p.Filename, p.Line)
+}
+
+// synopsis returns the first sentence of the comment.
+func synopsis(comment *ast.CommentGroup) string {
+	if comment == nil {
+		return ""
+	}
+	return new(doc.Package).Synopsis(comment.Text())
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// callModule is a module in which package app calls functions and methods
+// of its own and of package store.
+var callModule = map[string]string{
+	"go.mod": "module example.com/app\n\ngo 1.22\n",
+	"app/app.go": `package app
+
+import "example.com/app/store"
+
+// Serve serves a request.
+func Serve(name string) string {
+	key := normalize(name)
+	return store.Get(key)
+}
+
+// normalize cleans up a name.
+func normalize(name string) string {
+	return trim(name)
+}
+
+// trim trims a name.
+func trim(name string) string { return name }
+
+// Handler handles requests.
+type Handler struct{}
+
+// Handle serves with the handler.
+func (h *Handler) Handle(name string) string { return Serve(name) + h.Log() }
+
+// Log logs.
+func (h *Handler) Log() string { return "" }
+`,
+	"store/store.go": `package store
+
+// Get gets the value for the key.
+func Get(key string) string { return "" }
+
+// Preload gets the default key.
+func Preload() { Get("default") }
+`,
+}
+
+func TestCalls(t *testing.T) {
+	dir := writeModule(t, callModule)
+	fakeGOROOT(t, nil) // So that -callers need not search the standard library.
+	oldDepth := *depthFlag
+	t.Cleanup(func() { *depthFlag = oldDepth })
+	tests := []struct {
+		callers bool
+		pkg     string
+		name    string
+		depth   int
+		want    string // With the module directory as $DIR.
+	}{
+		{
+			pkg: "app", name: "Serve", depth: 1,
+			want: `app.Serve calls:
+	app.normalize	$DIR/app/app.go:12
+		normalize cleans up a name.
+	store.Get	$DIR/store/store.go:4
+		Get gets the value for the key.
+
+`,
+		},
+		{
+			pkg: "app", name: "serve", depth: 3,
+			want: `app.Serve calls:
+	app.normalize	$DIR/app/app.go:12
+		normalize cleans up a name.
+		app.trim	$DIR/app/app.go:17
+			trim trims a name.
+	store.Get	$DIR/store/store.go:4
+		Get gets the value for the key.
+
+`,
+		},
+		{
+			pkg: "app", name: "Handler.Handle", depth: 1,
+			want: `app.Handler.Handle calls:
+	app.Serve	$DIR/app/app.go:6
+		Serve serves a request.
+	app.Handler.Log	$DIR/app/app.go:26
+		Log logs.
+
+`,
+		},
+		{
+			callers: true, pkg: "store", name: "Get",
+			want: `store.Get is called by:
+	example.com/app/store.Preload	$DIR/store/store.go:7
+		Preload gets the default key.
+	example.com/app/app.Serve	$DIR/app/app.go:8
+		Serve serves a request.
+
+`,
+		},
+		{
+			callers: true, pkg: "app", name: "Handler.Log",
+			want: `app.Handler.Log is called by:
+	example.com/app/app.Handler.Handle	$DIR/app/app.go:23
+		Handle serves with the handler.
+
+`,
+		},
+	}
+	for _, test := range tests {
+		*depthFlag = test.depth
+		var out bytes.Buffer
+		s := newSession(&out)
+		dirs := s.packageDirs(test.pkg)
+		if test.callers {
+			s.callers(dirs, test.name)
+		} else {
+			s.calls(dirs, test.name)
+		}
+		if got := strings.ReplaceAll(out.String(), dir, "$DIR"); got != test.want {
+			t.Errorf("callers %t, %s.%s, depth %d printed\n%s\nwant\n%s", test.callers, test.pkg, test.name, test.depth, got, test.want)
+		}
+	}
+	if status := exitStatus(func() { newSession(&bytes.Buffer{}).calls(nil, "Missing") }); status != 1 {
+		t.Errorf("-calls of a missing function exited with %d, want 1", status)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// chatLimit is the most characters -chat prints. Longer messages are
+// refused or split by chat tools such as Slack.
+const chatLimit = 4000
+
+// chatFragment returns, for -chat, the documentation of the declaration of
+// id in node as chat tools show it: the declaration in a code block, then the
+// doc comment, with headings in bold.
+func (f *File) chatFragment(node ast.Node, id *ast.Ident, url string) string {
+	name := id.Name
+	if fn, ok := node.(*ast.FuncDecl); ok && fn.Recv != nil {
+		name = recvTypeName(fn) + "." + name
+	}
+	f.chatLink(name, url)
+	return chatDoc(f.bareDeclaration(node), docComment(node))
+}
+
+// chatLink records, if it is the first, the link to the full documentation
+// of the named symbol, or of the package if the name is empty: its page on
+// pkg.go.dev, or the given URL for a package with no import path.
+func (f *File) chatLink(name, url string) {
+	if f.s.chatMore != "" {
+		return
+	}
+	f.s.chatMore = strings.TrimSpace(url)
+	if pkgPath := importPath(filepath.Dir(f.name)); !filepath.IsAbs(filepath.FromSlash(pkgPath)) {
+		f.s.chatMore = "https://pkg.go.dev/" + pkgPath
+		if name != "" {
+			f.s.chatMore += "#" + name
+		}
+	}
+}
+
+// chatDoc returns the declaration, in a code block, and the doc comment
+// marked up for chat tools.
+func chatDoc(decl string, doc *ast.CommentGroup) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "```\n%s\n```\n", decl)
+	if doc != nil {
+		for i, block := range parseDoc(doc).Content {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			chatBlock(&b, block)
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// chatBlock writes a block of a doc comment, followed by a newline. Chat tools
+// have no headings, so a heading is shown in bold; code is fenced, not indented.
+func chatBlock(b *strings.Builder, block comment.Block) {
+	switch block := block.(type) {
+	case *comment.Paragraph:
+		fmt.Fprintf(b, "%s\n", chatText(block.Text))
+	case *comment.Heading:
+		fmt.Fprintf(b, "*%s*\n", chatText(block.Text))
+	case *comment.Code:
+		fmt.Fprintf(b, "```\n%s```\n", block.Text)
+	case *comment.List:
+		for _, item := range block.Items {
+			bullet := "•"
+			if item.Number != "" {
+				bullet = item.Number + "."
+			}
+			for j, c := range item.Content {
+				if p, ok := c.(*comment.Paragraph); ok {
+					if j == 0 {
+						fmt.Fprintf(b, "%s %s\n", bullet, chatText(p.Text))
+					} else {
+						fmt.Fprintf(b, "  %s\n", chatText(p.Text))
+					}
+				}
+			}
+		}
+	}
+}
+
+// chatText returns the text of a paragraph on one line, for the chat tool
+// to wrap. Doc links become code, which is how identifiers read in chat.
+func chatText(text []comment.Text) string {
+	var b strings.Builder
+	for _, t := range text {
+		switch t := t.(type) {
+		case comment.Plain:
+			b.WriteString(string(t))
+		case comment.Italic:
+			fmt.Fprintf(&b, "_%s_", string(t))
+		case *comment.Link:
+			inner := chatText(t.Text)
+			if inner == t.URL {
+				b.WriteString(t.URL)
+			} else {
+				fmt.Fprintf(&b, "%s (%s)", inner, t.URL)
+			}
+		case *comment.DocLink:
+			fmt.Fprintf(&b, "`%s`", chatText(t.Text))
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// chatOutput, for -chat, collects the output so it can be cut to chatLimit
+// characters. The returned function writes it to the session's writer.
+func (s *session) chatOutput() func() {
+	out := s.out
+	buf := new(bytes.Buffer)
+	s.out = buf
+	return func() {
+		io.WriteString(out, chatTruncate(buf.String(), s.chatMore))
+	}
+}
+
+// chatTruncate returns the text, without trailing blank lines, cut at a line
+// boundary to fit in chatLimit characters if need be, in which case it
+// ends by linking to the full documentation at url.
+func chatTruncate(text, url string) string {
+	text = strings.TrimRight(text, "\n") + "\n"
+	if utf8.RuneCountInString(text) <= chatLimit {
+		return text
+	}
+	more := "…\n"
+	if url != "" {
+		more = "…\nView more: " + url + "\n"
+	}
+	const fence = "```\n"
+	n := chatLimit - utf8.RuneCountInString(more) - len(fence)
+	for i := range text {
+		if n == 0 {
+			text = text[:i]
+			break
+		}
+		n--
+	}
+	if i := strings.LastIndex(text, "\n"); i >= 0 {
+		text = text[:i+1]
+	}
+	if strings.Count("\n"+text, "\n```")%2 == 1 {
+		text += fence // Close the open code block.
+	}
+	return text + more
+}
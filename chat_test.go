@@ -0,0 +1,107 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChatTruncate(t *testing.T) {
+	const url = "https://pkg.go.dev/fmt"
+	if got, want := chatTruncate("short\n\n\n", url), "short\n"; got != want {
+		t.Errorf("chatTruncate of a short text = %q, want %q", got, want)
+	}
+	line := strings.Repeat("x", 99) + "\n"
+	wide := strings.Repeat("日", 99) + "\n"
+	tests := []struct {
+		name string
+		text string
+		url  string
+	}{
+		{"long", strings.Repeat(line, 100), url},
+		{"long without a URL", strings.Repeat(line, 100), ""},
+		{"multibyte", strings.Repeat(wide, 100), url},
+		{"open code block", "```\n" + strings.Repeat(line, 100) + "```\n", url},
+	}
+	for _, test := range tests {
+		got := chatTruncate(test.text, test.url)
+		if n := utf8.RuneCountInString(got); n > chatLimit {
+			t.Errorf("%s: chatTruncate returned %d characters, more than %d", test.name, n, chatLimit)
+		}
+		more := "…\n"
+		if test.url != "" {
+			more += "View more: " + test.url + "\n"
+		}
+		kept, ok := strings.CutSuffix(got, more)
+		if !ok {
+			t.Errorf("%s: chatTruncate returned %q..., not ending in %q", test.name, got[:40], more)
+			continue
+		}
+		kept = strings.TrimSuffix(kept, "```\n")
+		if !strings.HasPrefix(test.text, kept) || !strings.HasSuffix(kept, "\n") {
+			t.Errorf("%s: chatTruncate did not cut the text at a line boundary", test.name)
+		}
+		if strings.Count(got, "```")%2 != 0 {
+			t.Errorf("%s: chatTruncate left a code block open", test.name)
+		}
+	}
+}
+
+func TestChat(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/ch\n\ngo 1.22\n",
+		"shop/shop.go": `// Package shop sells.
+package shop
+
+// Cart holds items.
+//
+// # Use
+//
+// Call [strings.Cut] on _names_, see https://go.dev:
+//
+//	c := Cart{}
+//
+// Steps:
+//
+//  1. First.
+//  2. Second.
+type Cart struct{}
+
+// Add adds to the [Cart].
+func (c *Cart) Add() {}
+`,
+		"shop/long.go": "package shop\n\n" + strings.Repeat("// Long is long.\n//\n", 300) + "func Long() {}\n",
+	})
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"shop.cart"}, "```\ntype Cart struct{}\n```\n" +
+			"Cart holds items.\n\n*Use*\n\n" +
+			"Call `strings.Cut` on _names_, see https://go.dev:\n\n" +
+			"```\nc := Cart{}\n```\n\n" +
+			"Steps:\n\n1. First.\n2. Second.\n"},
+		{[]string{"shop.add"}, "```\nfunc (c *Cart) Add()\n```\nAdd adds to the `Cart`.\n"},
+		{[]string{"-pkg", "shop"}, "```\npackage shop\n```\nPackage shop sells.\n"},
+	}
+	for _, test := range tests {
+		args := append([]string{"-chat"}, test.args...)
+		out, stderr, status := runDoc(t, dir, args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", args, status, stderr)
+			continue
+		}
+		if out != test.want {
+			t.Errorf("doc %q printed\n%s\nwant\n%s", args, out, test.want)
+		}
+	}
+	// Long output ends by linking to the full documentation.
+	out, _, _ := runDoc(t, dir, "-chat", "shop.long")
+	if want := "…\nView more: https://pkg.go.dev/example.com/ch/shop#Long\n"; !strings.HasSuffix(out, want) {
+		t.Errorf("doc -chat shop.long printed\n%s\nwant it to end %q", out, want)
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// cheatsheet prints, for each package with the name, a compact listing of
+// its exported functions, one line each, grouped by task, followed by its
+// types with their methods. A function mentioned in a section of the
+// package comment, under a heading, is listed under that heading;
+// otherwise functions whose names begin with the same word, such as
+// ParseInt and ParseBool, are listed together, and the rest under Other.
+func (s *session) cheatsheet(pkg string) {
+	found := false
+	for _, dir := range s.packageDirs(pkg) {
+		fset := token.NewFileSet()
+		notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+		pkgs, _ := parseDir(fset, dir, notTest, parser.ParseComments) // Ignore the error.
+		for _, p := range pkgs {
+			if p.Name == "main" {
+				continue
+			}
+			if found {
+				fmt.Fprintln(s.out)
+			}
+			found = true
+			s.cheatsheetPackage(fset, dir, p)
+		}
+	}
+	if !found {
+		fmt.Fprintf(os.Stderr, "doc: -cheatsheet: no package %s\n", pkg)
+		exit(1)
+	}
+}
+
+// cheatsheetPackage prints the cheat sheet of the package in the directory.
+func (s *session) cheatsheetPackage(fset *token.FileSet, dir string, pkg *ast.Package) {
+	var names []string
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var (
+		pkgDoc  *ast.CommentGroup
+		funcs   []*ast.FuncDecl
+		types   []*ast.TypeSpec
+		methods = make(map[string][]*ast.FuncDecl) // By receiver type.
+		files   = make(map[ast.Node]*File)
+	)
+	for _, name := range names {
+		astFile := pkg.Files[name]
+		file := s.newFile(fset, name, "", astFile)
+		if pkgDoc == nil {
+			pkgDoc = astFile.Doc
+		}
+		for _, decl := range astFile.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+				files[decl] = file
+				if recv := recvTypeName(decl); recv != "" {
+					methods[recv] = append(methods[recv], decl)
+				} else {
+					funcs = append(funcs, decl)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok && spec.Name.IsExported() {
+						types = append(types, spec)
+						files[spec] = file
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(funcs, func(i, j int) bool { return lessFold(funcs[i].Name.Name, funcs[j].Name.Name) })
+	sort.Slice(types, func(i, j int) bool { return lessFold(types[i].Name.Name, types[j].Name.Name) })
+
+	fmt.Fprintf(s.out, "package %s // import %q\n", pkg.Name, importPath(dir))
+	if syn := synopsis(pkgDoc); syn != "" {
+		fmt.Fprintf(s.out, "%s\n", syn)
+	}
+	fmt.Fprintln(s.out)
+	for _, group := range taskGroups(pkgDoc, funcs) {
+		fmt.Fprintf(s.out, "%s\n", group.name)
+		for _, fn := range group.funcs {
+			fmt.Fprintf(s.out, "\t%s\n", files[fn].signature(fn, fn.Name))
+		}
+	}
+	if len(types) > 0 {
+		fmt.Fprintf(s.out, "Types\n")
+		for _, spec := range types {
+			fmt.Fprintf(s.out, "\t%s\n", files[spec].signature(spec, spec.Name))
+			ms := methods[spec.Name.Name]
+			sort.Slice(ms, func(i, j int) bool { return lessFold(ms[i].Name.Name, ms[j].Name.Name) })
+			for _, fn := range ms {
+				fmt.Fprintf(s.out, "\t\t%s\n", files[fn].signature(fn, fn.Name))
+			}
+		}
+	}
+}
+
+// A taskGroup is a heading of a cheat sheet and the functions under it.
+type taskGroup struct {
+	name  string
+	funcs []*ast.FuncDecl
+}
+
+// taskGroups divides the functions, which are sorted by name, into groups
+// by task: first one for each section of the package comment that mentions
+// any, in order, then one for each first word of their names shared by
+// two or more, in order, and last Other.
+func taskGroups(pkgDoc *ast.CommentGroup, funcs []*ast.FuncDecl) []taskGroup {
+	byName := make(map[string]*ast.FuncDecl)
+	for _, fn := range funcs {
+		byName[fn.Name.Name] = fn
+	}
+	var groups []taskGroup
+	placed := make(map[*ast.FuncDecl]bool)
+	for _, section := range docSections(pkgDoc) {
+		group := taskGroup{name: section.heading}
+		for _, name := range section.words {
+			if fn := byName[name]; fn != nil && !placed[fn] {
+				placed[fn] = true
+				group.funcs = append(group.funcs, fn)
+			}
+		}
+		if len(group.funcs) > 0 {
+			sort.Slice(group.funcs, func(i, j int) bool { return lessFold(group.funcs[i].Name.Name, group.funcs[j].Name.Name) })
+			groups = append(groups, group)
+		}
+	}
+	byWord := make(map[string][]*ast.FuncDecl)
+	var words []string
+	for _, fn := range funcs {
+		if placed[fn] {
+			continue
+		}
+		word := leadingWord(fn.Name.Name)
+		if byWord[word] == nil {
+			words = append(words, word)
+		}
+		byWord[word] = append(byWord[word], fn)
+	}
+	sort.Slice(words, func(i, j int) bool { return lessFold(words[i], words[j]) })
+	other := taskGroup{name: "Other"}
+	for _, word := range words {
+		if len(byWord[word]) < 2 {
+			other.funcs = append(other.funcs, byWord[word]...)
+			continue
+		}
+		groups = append(groups, taskGroup{word, byWord[word]})
+	}
+	if len(other.funcs) > 0 {
+		sort.Slice(other.funcs, func(i, j int) bool { return lessFold(other.funcs[i].Name.Name, other.funcs[j].Name.Name) })
+		groups = append(groups, other)
+	}
+	return groups
+}
+
+// A docSection is a heading of a package comment and the words of the
+// text that follows it, up to the next heading.
+type docSection struct {
+	heading string
+	words   []string
+}
+
+// docSections returns the sections of the package comment.
+func docSections(pkgDoc *ast.CommentGroup) []docSection {
+	if pkgDoc == nil {
+		return nil
+	}
+	var sections []docSection
+	for _, block := range new(comment.Parser).Parse(pkgDoc.Text()).Content {
+		if h, ok := block.(*comment.Heading); ok {
+			sections = append(sections, docSection{heading: chatText(h.Text)})
+			continue
+		}
+		if len(sections) == 0 {
+			continue
+		}
+		var text string
+		switch block := block.(type) {
+		case *comment.Paragraph:
+			text = chatText(block.Text)
+		case *comment.Code:
+			text = block.Text
+		case *comment.List:
+			for _, item := range block.Items {
+				for _, c := range item.Content {
+					if p, ok := c.(*comment.Paragraph); ok {
+						text += chatText(p.Text) + " "
+					}
+				}
+			}
+		}
+		words := strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' })
+		sections[len(sections)-1].words = append(sections[len(sections)-1].words, words...)
+	}
+	return sections
+}
+
+// leadingWord returns the first word of the mixed-caps name: Parse in
+// ParseInt, HTML in HTMLEscape.
+func leadingWord(name string) string {
+	runes := []rune(name)
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		if unicode.IsUpper(runes[i-1]) {
+			// Within an initialism, which ends before a capital followed by lower case.
+			if i+1 < len(runes) && unicode.IsLower(runes[i+1]) && i > 1 {
+				return string(runes[:i])
+			}
+			continue
+		}
+		return string(runes[:i])
+	}
+	return name
+}
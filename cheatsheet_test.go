@@ -0,0 +1,77 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheatsheet(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/c\n\ngo 1.22\n",
+		"conv/conv.go": `// Package conv converts values.
+//
+// # Quoting
+//
+// Use Quote, or QuoteRune for a rune.
+package conv
+
+func ParseInt(s string) int   { return 0 }
+func ParseBool(s string) bool { return false }
+func Quote(s string) string   { return s }
+func QuoteRune(r rune) string { return "" }
+func QuoteToASCII(s string) string { return s }
+func Itoa(i int) string       { return "" }
+
+// A NumError records a failed conversion.
+type NumError struct{ Func string }
+
+func (e *NumError) Error() string { return "" }
+func (e *NumError) Unwrap() error { return nil }
+`,
+	})
+	var out bytes.Buffer
+	newSession(&out).cheatsheet(filepath.Join(dir, "conv"))
+	want := `package conv // import "example.com/c/conv"
+Package conv converts values.
+
+Quoting
+	func Quote(s string) string
+	func QuoteRune(r rune) string
+Parse
+	func ParseBool(s string) bool
+	func ParseInt(s string) int
+Other
+	func Itoa(i int) string
+	func QuoteToASCII(s string) string
+Types
+	type NumError struct{ ... }
+		func (e *NumError) Error() string
+		func (e *NumError) Unwrap() error
+`
+	if out.String() != want {
+		t.Errorf("-cheatsheet printed\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestLeadingWord(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"ParseInt", "Parse"},
+		{"HTMLEscape", "HTML"},
+		{"Itoa", "Itoa"},
+		{"URL", "URL"},
+		{"NewReaderSize", "New"},
+		{"IPv4", "IPv4"},
+	}
+	for _, test := range tests {
+		if got := leadingWord(test.name); got != test.want {
+			t.Errorf("leadingWord(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/gcexportdata"
+)
+
+// exportData holds the importers shared by all type checking, one for each
+// module, so the export data of a package is loaded once however many
+// packages of the module being checked import it, and the packages it
+// returns are the same objects for all.
+var exportData = &importers{byRoot: make(map[string]*sharedImporter)}
+
+// importers keeps a sharedImporter for each module root. Two modules may
+// select different versions of a package they both import, and a package
+// read from export data is known only by its import path, so one module's
+// import must not be satisfied by what was read for another.
+type importers struct {
+	mu     sync.Mutex
+	byRoot map[string]*sharedImporter // By module root, or "" outside any module.
+}
+
+// forDir returns the importer for packages in the directory, that of the
+// module holding it; the empty string stands for the current directory.
+func (im *importers) forDir(dir string) *sharedImporter {
+	root := moduleRootOf(dir)
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	s := im.byRoot[root]
+	if s == nil {
+		s = &sharedImporter{
+			fset:     token.NewFileSet(),
+			packages: make(map[string]*types.Package),
+		}
+		im.byRoot[root] = s
+	}
+	return s
+}
+
+// A sharedImporter serializes reading export data into its packages, so one
+// importer may serve type checks running in parallel; finding the export
+// data, the slow part, is done outside the lock. The export data of the
+// standard library is kept between runs in doc's cache directory, as asking
+// the go command for it takes a good fraction of a second a package. That
+// of other packages is found by the go command from the directory of the
+// importing file, so an import resolves as in a build of that file's module.
+type sharedImporter struct {
+	mu       sync.Mutex
+	fset     *token.FileSet
+	packages map[string]*types.Package // By import path; filled by gcexportdata.Read.
+}
+
+func (s *sharedImporter) Import(path string) (*types.Package, error) {
+	return s.ImportFrom(path, "", 0)
+}
+
+func (s *sharedImporter) ImportFrom(path, srcDir string, mode types.ImportMode) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if pkg := s.lookup(path); pkg != nil {
+		return pkg, nil
+	}
+	var data []byte
+	var err error
+	if dir := filepath.Join(runtime.GOROOT(), "src", path); isDir(dir) {
+		data, err = stdExportData(path, dir)
+	} else {
+		data, path, err = moduleExportData(path, srcDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pkg := s.packages[path]; pkg != nil && pkg.Complete() {
+		return pkg, nil // Read meanwhile by another check.
+	}
+	return gcexportdata.Read(bytes.NewReader(data), s.fset, s.packages, path)
+}
+
+// lookup returns the package with the import path if its export data has
+// been read, or nil.
+func (s *sharedImporter) lookup(path string) *types.Package {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pkg := s.packages[path]; pkg != nil && pkg.Complete() {
+		return pkg
+	}
+	return nil
+}
+
+// isDir reports whether the name is that of a directory.
+func isDir(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.IsDir()
+}
+
+// moduleExportData returns the export data of the package outside the
+// standard library with the import path as imported by a file in srcDir,
+// and the package's resolved import path, which vendoring may change.
+// The go command builds the package if it must.
+func moduleExportData(path, srcDir string) ([]byte, string, error) {
+	file, resolved := gcexportdata.Find(path, srcDir)
+	if file == "" {
+		return nil, "", fmt.Errorf("no export data for %q", path)
+	}
+	data, err := readExportData(path, file)
+	return data, resolved, err
+}
+
+// stdExportData returns the export data of the standard library package
+// with the import path, whose source is in dir, from the cache if it is there.
+func stdExportData(path, dir string) ([]byte, error) {
+	name := exportCachePath(path, dir)
+	if data, err := os.ReadFile(name); err == nil {
+		stats.cacheHits.Add(1)
+		return data, nil
+	}
+	file, _ := gcexportdata.Find(path, "")
+	if file == "" {
+		return nil, fmt.Errorf("no export data for %q", path)
+	}
+	data, err := readExportData(path, file)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" && os.MkdirAll(filepath.Dir(name), 0777) == nil {
+		os.WriteFile(name, data, 0666) // Failure to write the cache is not an error.
+	}
+	return data, nil
+}
+
+// readExportData returns the export data of the package with the import
+// path from the compiled file.
+func readExportData(path, file string) ([]byte, error) {
+	fd, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	r, err := gcexportdata.NewReader(fd)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return io.ReadAll(r)
+}
+
+// exportCachePath returns the name of the file caching the export data of
+// the package, named for a hash of its contents, as seen in the names, sizes
+// and modification times of the files of its directory, and of the release
+// of Go and the build configuration it was compiled for. As the standard
+// library changes only with the release, that of its dependencies need not
+// be considered.
+func exportCachePath(path, dir string) string {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s/%s cgo=%t %s\n", gorootVersion(), build.Default.GOOS, build.Default.GOARCH, build.Default.CgoEnabled, path)
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && !info.IsDir() {
+			fmt.Fprintf(h, "%s %d %d\n", info.Name(), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return filepath.Join(cache, "doc", "export", hex.EncodeToString(h.Sum(nil)[:16]))
+}
+
+// A checkUnit is a package to type-check and, once checked, the result.
+type checkUnit struct {
+	fset  *token.FileSet
+	pkg   *ast.Package
+	types *types.Package
+	info  *types.Info
+}
+
+// typeCheckAll type-checks the packages, as many at once as there are
+// processors to run them. The packages need not be in dependency order:
+// what each imports comes from export data, not from the others. If
+// checking a package panics, the first such panic is raised again here,
+// once the rest are checked, for recoverCrash to report.
+func typeCheckAll(pkgs []*checkUnit) {
+	work := make(chan *checkUnit)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		panicked any
+	)
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range work {
+				if r := p.check(); r != nil {
+					mu.Lock()
+					if panicked == nil {
+						panicked = r
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, p := range pkgs {
+		work <- p
+	}
+	close(work)
+	wg.Wait()
+	if panicked != nil {
+		panic(panicked)
+	}
+}
+
+// check type-checks the unit, returning nil or, if checking panicked, the
+// panic forwarded for main's goroutine.
+func (p *checkUnit) check() (panicked any) {
+	defer func() {
+		if r := recover(); r != nil {
+			var dir string
+			for name := range p.pkg.Files {
+				dir = filepath.Dir(name)
+				break
+			}
+			panicked = forwardPanic(r, dir)
+		}
+	}()
+	p.types, p.info = typeCheck(p.fset, p.pkg)
+	return nil
+}
+
+// maxTypeErrors is the most errors kept from type-checking a package.
+const maxTypeErrors = 100
+
+// typeUnavailable returns why the type declared by the spec is incompletely
+// known, so its method set may lack methods, or the empty string if it is
+// known. The usual reason is that a package it embeds a type from could not
+// be imported; such a failure is reported at the import, not at the use.
+func (f *File) typeUnavailable(spec *ast.TypeSpec) string {
+	obj := f.objs[spec.Name]
+	if obj == nil || !hasInvalidPart(obj.Type()) {
+		return ""
+	}
+	for _, e := range f.typeErrs {
+		if spec.Pos() <= e.Pos && e.Pos < spec.End() {
+			return firstLine(e.Msg)
+		}
+	}
+	var reason string
+	ast.Inspect(spec.Type, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || reason != "" {
+			return reason == ""
+		}
+		x, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		for _, imp := range f.file.Imports {
+			for _, e := range f.typeErrs {
+				if importName(imp) == x.Name && imp.Pos() <= e.Pos && e.Pos < imp.End() {
+					reason = firstLine(e.Msg)
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if reason == "" && len(f.typeErrs) > 0 {
+		reason = firstLine(f.typeErrs[0].Msg)
+	}
+	if reason == "" {
+		reason = "type checking failed"
+	}
+	return reason
+}
+
+// hasInvalidPart reports whether a field or embedded type of the struct or
+// interface type is invalid.
+func hasInvalidPart(typ types.Type) bool {
+	invalid := func(t types.Type) bool {
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		return t == types.Typ[types.Invalid]
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if invalid(t.Field(i).Type()) {
+				return true
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			if invalid(t.EmbeddedType(i)) {
+				return true
+			}
+		}
+	case *types.Basic:
+		return t.Kind() == types.Invalid
+	}
+	return false
+}
+
+// firstLine returns the first line of an error message, shortening that of a
+// failed import, which goes on to list every directory searched.
+func firstLine(msg string) string {
+	msg, _, _ = strings.Cut(msg, "\n")
+	if strings.HasPrefix(msg, "could not import ") {
+		msg, _, _ = strings.Cut(msg, " (")
+	}
+	return msg
+}
+
+// importName returns the name by which the file refers to the imported
+// package: the one the import gives, or else the one the package declares.
+func importName(imp *ast.ImportSpec) string {
+	if imp.Name != nil {
+		return imp.Name.Name
+	}
+	importPath, _ := strconv.Unquote(imp.Path.Value)
+	return importedName(importPath)
+}
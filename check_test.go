@@ -0,0 +1,183 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestImportPerModule checks that two modules importing different copies of
+// the same package each see their own.
+func TestImportPerModule(t *testing.T) {
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOWORK", "off")
+	dir := writeModule(t, map[string]string{
+		"a/go.mod":    "module example.com/a\n\nrequire example.com/dep v1.0.0\n\nreplace example.com/dep => ../depa\n",
+		"a/a.go":      "package a\n\nimport \"example.com/dep\"\n\nvar V = dep.A\n",
+		"depa/go.mod": "module example.com/dep\n",
+		"depa/dep.go": "package dep\n\nconst A = 1\n",
+		"b/go.mod":    "module example.com/b\n\nrequire example.com/dep v1.0.0\n\nreplace example.com/dep => ../depb\n",
+		"b/b.go":      "package b\n\nimport \"example.com/dep\"\n\nvar V = dep.B\n",
+		"depb/go.mod": "module example.com/dep\n",
+		"depb/dep.go": "package dep\n\nconst B = \"b\"\n",
+	})
+	tests := []struct {
+		dir, typ string
+	}{
+		{"a", "int"},
+		{"b", "string"},
+		{"a", "int"},
+	}
+	for _, test := range tests {
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, filepath.Join(dir, test.dir), nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, errs := typeCheckErrors(fset, pkgs[test.dir])
+		if len(errs) > 0 {
+			t.Errorf("checking %s: %v", test.dir, errs[0])
+			continue
+		}
+		typesPkg, _ := typeCheck(fset, pkgs[test.dir])
+		if got := typesPkg.Scope().Lookup("V").Type().String(); got != test.typ {
+			t.Errorf("%s.V has type %s, want %s", test.dir, got, test.typ)
+		}
+	}
+}
+
+func TestExportCachePath(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(file, []byte("package a\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	first := exportCachePath("a", dir)
+	if first == "" {
+		t.Fatal("exportCachePath returned no name")
+	}
+	if again := exportCachePath("a", dir); again != first {
+		t.Errorf("exportCachePath changed from %s to %s for an unchanged package", first, again)
+	}
+	if other := exportCachePath("b", dir); other == first {
+		t.Errorf("exportCachePath is %s for two import paths", first)
+	}
+	// A change to a file of the package changes the name.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if changed := exportCachePath("a", dir); changed == first {
+		t.Errorf("exportCachePath is %s after a file changed", first)
+	}
+	if name := exportCachePath("a", filepath.Join(dir, "missing")); name != "" {
+		t.Errorf("exportCachePath of a missing directory = %s, want none", name)
+	}
+}
+
+func TestStdExportDataCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := filepath.Join(goRoot, "src", "unicode", "utf8")
+	first, err := stdExportData("unicode/utf8", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(exportCachePath("unicode/utf8", dir)); err != nil {
+		t.Fatalf("export data was not cached: %v", err)
+	}
+	hits := stats.cacheHits.Load()
+	second, err := stdExportData("unicode/utf8", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.cacheHits.Load() != hits+1 {
+		t.Errorf("the second read of the export data was not from the cache")
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("the cached export data differs from that read first")
+	}
+}
+
+func TestFirstLine(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"undefined: Undefined", "undefined: Undefined"},
+		{"first\nsecond", "first"},
+		{`could not import example.com/dep (no required module provides package "example.com/dep")`, "could not import example.com/dep"},
+		{"cannot use x (variable of type int) as string value", "cannot use x (variable of type int) as string value"},
+	}
+	for _, test := range tests {
+		if got := firstLine(test.msg); got != test.want {
+			t.Errorf("firstLine(%q) = %q, want %q", test.msg, got, test.want)
+		}
+	}
+}
+
+func TestTypeUnavailable(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/tu\n\ngo 1.22\n",
+		"p/p.go": `package p
+
+import "example.com/missing/dep"
+
+// Wrapper embeds a missing type.
+type Wrapper struct {
+	dep.Thing
+	N int
+}
+
+// Broken has an undefined field type.
+type Broken struct {
+	X Undefined
+}
+
+// Fine is fine.
+type Fine struct{ N int }
+
+// String stringifies.
+func (Fine) String() string { return "" }
+`,
+	})
+	tests := []struct {
+		arg  string
+		want string // The note, if any.
+	}{
+		{"p.wrapper", "(type information unavailable: could not import example.com/missing/dep)"},
+		{"p.broken", "(type information unavailable: undefined: Undefined)"},
+		{"p.fine", ""},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.arg)
+		if status != 0 {
+			t.Errorf("doc %s exited with %d; stderr:\n%s", test.arg, status, stderr)
+			continue
+		}
+		got := ""
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, "(type information unavailable") {
+				got = line
+			}
+		}
+		if got != test.want {
+			t.Errorf("doc %s printed\n%s\nwant the note %q", test.arg, out, test.want)
+		}
+	}
+	// The declaration and the methods that are known are printed all the same.
+	out, _, _ := runDoc(t, dir, "p.fine")
+	if !strings.Contains(out, "func (Fine) String() string") {
+		t.Errorf("doc p.fine printed\n%s\nwant its String method", out)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// clipboardCommands lists, for each system, the commands that copy their
+// standard input to the clipboard, in order of preference. On Linux the
+// first is for Wayland, the others for X.
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux":   {{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}},
+}
+
+// copyToClipboard copies the text, as -clip asks, without leading or
+// trailing blank lines, to the system clipboard using the first command for
+// the system that is installed.
+func copyToClipboard(text *bytes.Buffer) {
+	if text.Len() == 0 {
+		return
+	}
+	for _, args := range clipboardCommands[runtime.GOOS] {
+		if args[0] == "wl-copy" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			continue
+		}
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = bytes.NewReader(bytes.TrimSpace(text.Bytes()))
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "doc: -clip: %s: %s\n", args[0], err)
+			exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "doc: -clip: no clipboard command found for %s\n", runtime.GOOS)
+	exit(1)
+}
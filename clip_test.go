@@ -0,0 +1,45 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCopyToClipboard(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the fake clipboard command is a shell script for Linux")
+	}
+	bin := t.TempDir()
+	clipped := filepath.Join(t.TempDir(), "clipboard")
+	script := "#!/bin/sh\nexec /bin/cat > " + clipped + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "xsel"), []byte(script), 0777); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	t.Setenv("WAYLAND_DISPLAY", "")
+	if status := exitStatus(func() { copyToClipboard(bytes.NewBufferString("\n\nfunc Cut()\n\n")) }); status != 0 {
+		t.Fatalf("copyToClipboard exited with %d", status)
+	}
+	if data, err := os.ReadFile(clipped); err != nil || string(data) != "func Cut()" {
+		t.Errorf("clipboard holds %q, %v; want %q", data, err, "func Cut()")
+	}
+
+	// Nothing to copy runs nothing.
+	os.Remove(clipped)
+	copyToClipboard(new(bytes.Buffer))
+	if _, err := os.Stat(clipped); err == nil {
+		t.Errorf("copyToClipboard of nothing ran the command")
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if status := exitStatus(func() { copyToClipboard(bytes.NewBufferString("x")) }); status != 1 {
+		t.Errorf("copyToClipboard with no command exited with %d, want 1", status)
+	}
+}
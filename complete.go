@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// complete prints, one a line, the completions of the prefix of an
+// argument, for shell and editor completion: the names of packages if the
+// prefix holds no dot, and otherwise the exported symbols, and methods as
+// Type.Method, of the packages named before the dot. As elsewhere, case is
+// ignored; closer matches in case, then shorter ones, come first.
+func (s *session) complete(prefix string) {
+	var candidates []string
+	if pkg, name, ok := strings.Cut(prefix, "."); ok {
+		seen := make(map[string]bool)
+		for _, dir := range s.packageDirs(pkg) {
+			for _, file := range s.parsePackageFiles(dir) {
+				for _, sym := range completionNames(file) {
+					if hasPrefixFold(sym, name) && !seen[sym] {
+						seen[sym] = true
+						candidates = append(candidates, pkg+"."+sym)
+					}
+				}
+			}
+		}
+	} else {
+		seen := make(map[string]bool)
+		dirs := s.pathsFor(goRoot, "")
+		dirs = append(dirs, s.searchDirs()...)
+		for _, dir := range dirs {
+			name := filepath.Base(dir)
+			if hasPrefixFold(name, prefix) && !seen[name] && hasGoFiles(dir) {
+				seen[name] = true
+				candidates = append(candidates, name)
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if ea, eb := strings.HasPrefix(a, prefix), strings.HasPrefix(b, prefix); ea != eb {
+			return ea
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return lessFold(a, b)
+	})
+	for _, c := range candidates {
+		fmt.Fprintln(s.out, c)
+	}
+}
+
+// completionNames returns the exported package-level names declared in the
+// file, with methods of exported types as Type.Method.
+func completionNames(file *ast.File) []string {
+	var names []string
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			recv := recvTypeName(decl)
+			switch {
+			case !decl.Name.IsExported():
+			case decl.Recv == nil:
+				names = append(names, decl.Name.Name)
+			case token.IsExported(recv):
+				names = append(names, recv+"."+decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.Name.IsExported() {
+						names = append(names, spec.Name.Name)
+					}
+				case *ast.ValueSpec:
+					for _, id := range spec.Names {
+						if id.IsExported() {
+							names = append(names, id.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// hasPrefixFold reports whether s begins with prefix, ignoring case as foldName does.
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(foldName(s), foldName(prefix))
+}
+
+// hasGoFiles reports whether the directory holds a Go source file.
+func hasGoFiles(dir string) bool {
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+			return true
+		}
+	}
+	return false
+}
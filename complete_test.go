@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	fakeGOROOT(t, map[string]string{
+		"src/strings/strings.go": "package strings\n\nfunc Cut(s, sep string) (string, string, bool)\n",
+		"src/strconv/atoi.go":    "package strconv\n\nfunc Atoi(s string) (int, error)\n",
+	})
+	old := goPaths
+	goPaths = nil
+	defer func() { goPaths = old }()
+	writeModule(t, map[string]string{
+		"go.mod": "module example.com/c\n\ngo 1.22\n",
+		"shop/shop.go": `package shop
+
+// Cart holds items.
+type Cart struct{}
+
+// Add adds.
+func (c *Cart) Add(item string) {}
+
+func (c *Cart) reset() {}
+
+// CartSize is the size of a cart.
+const CartSize = 10
+
+// Checkout checks out.
+func Checkout() {}
+
+func cartless() {}
+
+type basket struct{}
+
+// Add adds.
+func (b *basket) Add() {}
+`,
+		"Shopper/shopper.go": "package shopper\n",
+		"shopdocs/README":    "no Go here\n",
+	})
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"str", []string{"strconv", "strings"}},
+		{"shop", []string{"shop", "Shopper"}},
+		{"SHOP", []string{"shop", "Shopper"}},
+		{"shop.Cart", []string{"shop.Cart", "shop.Cart.Add", "shop.CartSize"}},
+		{"shop.c", []string{"shop.Cart", "shop.Cart.Add", "shop.CartSize", "shop.Checkout"}},
+		{"shop.", []string{"shop.Cart", "shop.Cart.Add", "shop.CartSize", "shop.Checkout"}},
+		{"strings.c", []string{"strings.Cut"}},
+		{"shop.z", nil},
+		{"zzz", nil},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		newSession(&out).complete(test.prefix)
+		got := strings.Fields(out.String())
+		if !slices.Equal(got, test.want) {
+			t.Errorf("complete %q = %q, want %q", test.prefix, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// config holds the settings from the configuration file, named by $DOCCONFIG
+// or else doc/config in the user's configuration directory. Each line of the
+// file holds a key followed by its value, separated by white space; text
+// after a # is a comment. A key may appear more than once.
+var config = readConfig()
+
+// configPath returns the name of the configuration file.
+func configPath() string {
+	if name := os.Getenv("DOCCONFIG"); name != "" {
+		return name
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "doc", "config")
+}
+
+// readConfig reads the configuration file. A missing file is an empty configuration.
+func readConfig() map[string][]string {
+	config := make(map[string][]string)
+	name := configPath()
+	if name == "" {
+		return config
+	}
+	fd, err := os.Open(name)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+		}
+		return config
+	}
+	defer fd.Close()
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		config[fields[0]] = append(config[fields[0]], strings.Join(fields[1:], " "))
+	}
+	return config
+}
+
+// configValue returns the last value set for the key, or the empty string.
+func configValue(key string) string {
+	values := config[key]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// configFloat returns the value for the key as a number, or def if it is not set.
+func configFloat(key string, def float64) float64 {
+	s := configValue(key)
+	if s == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: config: %s: %s\n", key, err)
+		return def
+	}
+	return f
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+)
+
+// A namedType is a type found by findType, with the files declaring it.
+type namedType struct {
+	obj   *types.TypeName
+	path  string // Import path of its package, which typeCheck does not record.
+	files []*ast.File
+}
+
+// findType returns the exported type given as pkg.Type, from the first
+// package, nearest first, that declares it.
+func (s *session) findType(arg string) (namedType, bool) {
+	if !strings.Contains(arg, ".") {
+		usage()
+	}
+	pkg, name := split(arg)
+	for _, dir := range s.paths(pkg) {
+		fset := token.NewFileSet()
+		notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+		pkgs, _ := parseDir(fset, dir, notTest, parser.ParseComments) // Ignore the error.
+		for _, astPkg := range pkgs {
+			typesPkg, _ := typeCheck(fset, astPkg)
+			if typesPkg == nil {
+				continue
+			}
+			if tn := lookupType(typesPkg.Scope(), name); tn != nil {
+				var files []*ast.File
+				for _, file := range astPkg.Files {
+					files = append(files, file)
+				}
+				return namedType{tn, importPath(dir), files}, true
+			}
+		}
+	}
+	return namedType{}, false
+}
+
+// convertible reports whether a value of the type given as pkg.Type can be
+// assigned or converted to the other, following the rules of the spec, and
+// compares their fields, with their documentation, if they are structs.
+//
+// The packages are type checked separately, so types are compared by their
+// fully qualified names rather than by identity.
+func (s *session) convertible(argA, argB string) {
+	a, okA := s.findType(argA)
+	b, okB := s.findType(argB)
+	for _, t := range []struct {
+		arg string
+		ok  bool
+	}{{argA, okA}, {argB, okB}} {
+		if !t.ok {
+			fmt.Fprintf(os.Stderr, "doc: -convertible: no type %s\n", t.arg)
+			exit(1)
+		}
+	}
+	nameA, nameB := a.typeString(a.obj.Type()), b.typeString(b.obj.Type())
+	fmt.Fprintf(s.out, "%s and %s\n", nameA, nameB)
+	switch {
+	case a.typeString(types.Unalias(a.obj.Type())) == b.typeString(types.Unalias(b.obj.Type())):
+		fmt.Fprintf(s.out, "assignable: yes, they are the same type\n")
+	default:
+		fmt.Fprintf(s.out, "assignable: no, they are different defined types\n")
+	}
+	underA, underB := a.obj.Type().Underlying(), b.obj.Type().Underlying()
+	switch {
+	case a.identicalIgnoringTags(b, underA, underB):
+		fmt.Fprintf(s.out, "convertible: yes, as %s(x), since their underlying types are identical, ignoring tags\n", b.obj.Name())
+	case isNumeric(underA) && isNumeric(underB):
+		fmt.Fprintf(s.out, "convertible: yes, as %s(x), since both are numeric\n", b.obj.Name())
+	default:
+		fmt.Fprintf(s.out, "convertible: no, their underlying types differ\n")
+	}
+	stA, okA := underA.(*types.Struct)
+	stB, okB := underB.(*types.Struct)
+	if !okA || !okB {
+		return
+	}
+	fmt.Fprintf(s.out, "\nfields:\n")
+	docsA, docsB := fieldDocs(a), fieldDocs(b)
+	var names []string
+	fieldsA, fieldsB := structFields(stA), structFields(stB)
+	for i := 0; i < stA.NumFields(); i++ {
+		names = append(names, stA.Field(i).Name())
+	}
+	for i := 0; i < stB.NumFields(); i++ {
+		if fieldsA[stB.Field(i).Name()] == nil {
+			names = append(names, stB.Field(i).Name())
+		}
+	}
+	for _, name := range names {
+		fa, fb := fieldsA[name], fieldsB[name]
+		switch {
+		case fb == nil:
+			fmt.Fprintf(s.out, "\t%s %s\tonly in %s\n", name, a.typeString(fa.Type()), nameA)
+		case fa == nil:
+			fmt.Fprintf(s.out, "\t%s %s\tonly in %s\n", name, b.typeString(fb.Type()), nameB)
+		case a.typeString(fa.Type()) != b.typeString(fb.Type()):
+			fmt.Fprintf(s.out, "\t%s\ttype differs: %s, %s\n", name, a.typeString(fa.Type()), b.typeString(fb.Type()))
+		case stA.Tag(fieldIndex(stA, name)) != stB.Tag(fieldIndex(stB, name)):
+			fmt.Fprintf(s.out, "\t%s %s\ttags differ: %s, %s\n", name, a.typeString(fa.Type()), tag(stA, name), tag(stB, name))
+		default:
+			fmt.Fprintf(s.out, "\t%s %s\tsame\n", name, a.typeString(fa.Type()))
+		}
+		docA, docB := synopsis(docsA[name]), synopsis(docsB[name])
+		switch {
+		case docA == docB && docA != "":
+			fmt.Fprintf(s.out, "\t\t%s\n", docA)
+		case docA != docB:
+			if docA != "" {
+				fmt.Fprintf(s.out, "\t\t%s: %s\n", nameA, docA)
+			}
+			if docB != "" {
+				fmt.Fprintf(s.out, "\t\t%s: %s\n", nameB, docB)
+			}
+		}
+	}
+}
+
+// typeString returns the type, which appears in the package of t, with
+// names qualified by import path, as in example.com/pkg.T.
+func (t namedType) typeString(typ types.Type) string {
+	return types.TypeString(typ, func(p *types.Package) string {
+		if p == t.obj.Pkg() {
+			return t.path
+		}
+		return p.Path()
+	})
+}
+
+// identicalIgnoringTags reports whether the types, x appearing in the
+// package of t and y in that of u, would be identical if their struct
+// tags were removed, comparing named types by name.
+func (t namedType) identicalIgnoringTags(u namedType, x, y types.Type) bool {
+	sx, okX := x.(*types.Struct)
+	sy, okY := y.(*types.Struct)
+	if !okX || !okY {
+		return t.typeString(x) == u.typeString(y)
+	}
+	if sx.NumFields() != sy.NumFields() {
+		return false
+	}
+	for i := 0; i < sx.NumFields(); i++ {
+		fx, fy := sx.Field(i), sy.Field(i)
+		if fx.Name() != fy.Name() || fx.Embedded() != fy.Embedded() || !t.identicalIgnoringTags(u, fx.Type(), fy.Type()) {
+			return false
+		}
+		// Unexported fields of different packages are never identical.
+		if !fx.Exported() && t.path != u.path {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumeric reports whether the type is an integer, floating-point or complex type.
+func isNumeric(typ types.Type) bool {
+	basic, ok := typ.(*types.Basic)
+	return ok && basic.Info()&types.IsNumeric != 0
+}
+
+// tag returns the tag of the named field of the struct, as written in source.
+func tag(st *types.Struct, name string) string {
+	if tag := st.Tag(fieldIndex(st, name)); tag != "" {
+		return "`" + tag + "`"
+	}
+	return "none"
+}
+
+// structFields returns the fields of the struct by name.
+func structFields(st *types.Struct) map[string]*types.Var {
+	fields := make(map[string]*types.Var)
+	for i := 0; i < st.NumFields(); i++ {
+		fields[st.Field(i).Name()] = st.Field(i)
+	}
+	return fields
+}
+
+// fieldIndex returns the index of the named field of the struct.
+func fieldIndex(st *types.Struct, name string) int {
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// fieldDocs returns the doc comments, or else line comments, of the fields
+// of the struct type, by name. For an alias of a type of the same package,
+// they are those of the type it denotes.
+func fieldDocs(t namedType) map[string]*ast.CommentGroup {
+	name := t.obj.Name()
+	if named, ok := types.Unalias(t.obj.Type()).(*types.Named); ok && named.Obj().Pkg() == t.obj.Pkg() {
+		name = named.Obj().Name()
+	}
+	docs := make(map[string]*ast.CommentGroup)
+	for _, file := range t.files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok || spec.Name.Name != name {
+				return true
+			}
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				for _, field := range st.Fields.List {
+					doc := field.Doc
+					if doc == nil {
+						doc = field.Comment
+					}
+					for _, id := range field.Names {
+						docs[id.Name] = doc
+					}
+					if len(field.Names) == 0 {
+						docs[types.ExprString(embeddedName(field.Type))] = doc
+					}
+				}
+			}
+			return false
+		})
+	}
+	return docs
+}
+
+// embeddedName returns the identifier naming the embedded field of the type.
+func embeddedName(typ ast.Expr) ast.Expr {
+	switch t := typ.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel
+	case *ast.IndexExpr:
+		return embeddedName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedName(t.X)
+	}
+	return typ
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// convertModule holds parallel types for -convertible.
+var convertModule = map[string]string{
+	"go.mod": "module example.com/cv\n\ngo 1.22\n",
+	"a/a.go": `package a
+
+// User is a user.
+type User struct {
+	// Name is the name.
+	Name string ` + "`json:\"name\"`" + `
+	// Age is in years.
+	Age int
+	ID  int64
+}
+
+// Same is User.
+type Same = User
+
+// Celsius is a temperature.
+type Celsius float64
+
+// Point is a point.
+type Point struct{ X, Y int }
+`,
+	"b/b.go": `package b
+
+// Person is a person.
+type Person struct {
+	// Name is the full name.
+	Name  string
+	// Age is in years.
+	Age   int
+	ID    int32
+	Email string
+}
+
+// Row is a row.
+type Row struct {
+	// Name is the name.
+	Name string ` + "`db:\"name\"`" + `
+	// Age is in years.
+	Age int
+	ID  int64
+}
+
+// Fahrenheit is a temperature.
+type Fahrenheit int
+
+// Label is text.
+type Label string
+`,
+}
+
+func TestConvertible(t *testing.T) {
+	fakeGOROOT(t, nil)
+	old := goPaths
+	goPaths = nil
+	defer func() { goPaths = old }()
+	writeModule(t, convertModule)
+	tests := []struct {
+		a, b   string
+		want   string
+		status int
+	}{
+		{
+			a: "a.User", b: "b.Row",
+			want: `example.com/cv/a.User and example.com/cv/b.Row
+assignable: no, they are different defined types
+convertible: yes, as Row(x), since their underlying types are identical, ignoring tags
+
+fields:
+	Name string	tags differ: ` + "`json:\"name\"`, `db:\"name\"`" + `
+		Name is the name.
+	Age int	same
+		Age is in years.
+	ID int64	same
+`,
+		},
+		{
+			// An alias shares the documentation of the type it denotes.
+			a: "a.User", b: "a.Same",
+			want: `example.com/cv/a.User and example.com/cv/a.Same
+assignable: yes, they are the same type
+convertible: yes, as Same(x), since their underlying types are identical, ignoring tags
+
+fields:
+	Name string	same
+		Name is the name.
+	Age int	same
+		Age is in years.
+	ID int64	same
+`,
+		},
+		{
+			a: "a.User", b: "b.Person",
+			want: "example.com/cv/a.User and example.com/cv/b.Person\n" +
+				"assignable: no, they are different defined types\n" +
+				"convertible: no, their underlying types differ\n" +
+				"\n" +
+				"fields:\n" +
+				"\tName string\ttags differ: `json:\"name\"`, none\n" +
+				"\t\texample.com/cv/a.User: Name is the name.\n" +
+				"\t\texample.com/cv/b.Person: Name is the full name.\n" +
+				"\tAge int\tsame\n" +
+				"\t\tAge is in years.\n" +
+				"\tID\ttype differs: int64, int32\n" +
+				"\tEmail string\tonly in example.com/cv/b.Person\n",
+		},
+		{
+			a: "a.Celsius", b: "b.Fahrenheit",
+			want: "example.com/cv/a.Celsius and example.com/cv/b.Fahrenheit\n" +
+				"assignable: no, they are different defined types\n" +
+				"convertible: yes, as Fahrenheit(x), since both are numeric\n",
+		},
+		{
+			a: "a.Point", b: "b.Label",
+			want: "example.com/cv/a.Point and example.com/cv/b.Label\n" +
+				"assignable: no, they are different defined types\n" +
+				"convertible: no, their underlying types differ\n",
+		},
+		{a: "a.User", b: "b.Nothing", status: 1},
+		{a: "User", b: "b.Row", status: 2},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).convertible(test.a, test.b) })
+		if status != test.status {
+			t.Errorf("-convertible %s %s exited with %d, want %d", test.a, test.b, status, test.status)
+			continue
+		}
+		if status == 0 && out.String() != test.want {
+			t.Errorf("-convertible %s %s printed\n%s\nwant\n%s", test.a, test.b, out.String(), test.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// coverProfiles returns the coverage profiles for the package in the
+// directory: those of the file named by -cover or, with -cover-run, those
+// written by running the package's tests.
+func (s *session) coverProfiles(dir string) []*cover.Profile {
+	key := dir
+	if !*coverRunFlag {
+		key = *coverFlag
+	}
+	if profiles, ok := s.coverage[key]; ok {
+		return profiles
+	}
+	file := *coverFlag
+	if *coverRunFlag {
+		tmp, err := os.CreateTemp("", "doc-cover-*.out")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: -cover-run: %v\n", err)
+			exit(2)
+		}
+		tmp.Close()
+		defer os.Remove(tmp.Name())
+		file = tmp.Name()
+		cmd := exec.Command("go", "test", "-coverprofile="+file, ".")
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "doc: -cover-run: go test in %s: %v\n%s", dir, err, out)
+		}
+	}
+	profiles, err := cover.ParseProfiles(file)
+	if err != nil && !*coverRunFlag {
+		fmt.Fprintf(os.Stderr, "doc: -cover: %v\n", err)
+		exit(2)
+	}
+	s.coverage[key] = profiles
+	return profiles
+}
+
+// coverage prints a line giving the percentage of the statements of fn
+// that the coverage profile records as run.
+func (f *File) coverage(fn *ast.FuncDecl) {
+	if fn.Body == nil {
+		return
+	}
+	dir := filepath.Dir(f.name)
+	name := path.Join(importPath(dir), filepath.Base(f.name))
+	var profile *cover.Profile
+	for _, p := range f.s.coverProfiles(dir) {
+		if p.FileName == name || strings.HasSuffix(p.FileName, "/"+filepath.Base(dir)+"/"+filepath.Base(f.name)) {
+			profile = p
+			break
+		}
+	}
+	w := f.output(nodeKind(fn))
+	if profile == nil {
+		fmt.Fprintf(w, "Coverage: not in the profile\n\n")
+		return
+	}
+	start, end := f.fset.Position(fn.Body.Pos()), f.fset.Position(fn.Body.End())
+	total, covered := 0, 0
+	for _, b := range profile.Blocks {
+		if b.StartLine < start.Line || b.EndLine > end.Line {
+			continue
+		}
+		total += b.NumStmt
+		if b.Count > 0 {
+			covered += b.NumStmt
+		}
+	}
+	if total == 0 {
+		fmt.Fprintf(w, "Coverage: no statements\n\n")
+		return
+	}
+	fmt.Fprintf(w, "Coverage: %.1f%% of %d statements\n\n", 100*float64(covered)/float64(total), total)
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// calcProfile is a coverage profile of calcModule in which the test runs
+// Add and the first statement of Fact.
+const calcProfile = `mode: set
+example.com/calc/calc.go:5.26,5.40 1 1
+example.com/calc/calc.go:9.2,9.12 1 1
+example.com/calc/calc.go:10.3,11.1 1 0
+example.com/calc/calc.go:12.2,12.22 1 0
+example.com/calc/calc.go:16.26,16.36 1 0
+`
+
+func TestCover(t *testing.T) {
+	dir := writeModule(t, calcModule)
+	profile := filepath.Join(t.TempDir(), "cover.out")
+	if err := os.WriteFile(profile, []byte(calcProfile), 0666); err != nil {
+		t.Fatal(err)
+	}
+	defaultFlags(t)
+	oldCover := *coverFlag
+	t.Cleanup(func() { *coverFlag = oldCover })
+	tests := []struct {
+		name string
+		want string // Empty if there is no coverage line.
+	}{
+		{"Add", "Coverage: 100.0% of 1 statements\n"},
+		{"Fact", "Coverage: 33.3% of 3 statements\n"},
+		{"Same", "Coverage: 0.0% of 1 statements\n"},
+		{"Deref", "Coverage: no statements\n"},
+		{"Decl", ""},
+	}
+	for _, run := range []bool{false, true} {
+		*coverFlag = profile
+		if run {
+			*coverFlag = ""
+			setFlag(t, coverRunFlag, true)
+			t.Setenv("GOFLAGS", "-mod=mod")
+			t.Setenv("GOWORK", "off")
+			// Running the test covers Add alone.
+			tests[1].want = "Coverage: 0.0% of 3 statements\n"
+			tests[3].want = "Coverage: 0.0% of 1 statements\n"
+		}
+		s := newSession(nil)
+		for _, test := range tests {
+			var out bytes.Buffer
+			s.out = &out
+			s.lookInDirectory(dir, "", test.name)
+			if test.want == "" {
+				if strings.Contains(out.String(), "Coverage:") {
+					t.Errorf("-cover (run %t) of %s printed\n%s\nwant no coverage", run, test.name, out.String())
+				}
+				continue
+			}
+			if !strings.Contains(out.String(), test.want) {
+				t.Errorf("-cover (run %t) of %s printed\n%s\nwant it to contain %q", run, test.name, out.String(), test.want)
+			}
+		}
+		if len(s.coverage) != 1 {
+			t.Errorf("-cover (run %t) read %d profiles, want 1", run, len(s.coverage))
+		}
+	}
+}
+
+func TestCoverNotInProfile(t *testing.T) {
+	dir := writeModule(t, calcModule)
+	profile := filepath.Join(t.TempDir(), "cover.out")
+	if err := os.WriteFile(profile, []byte("mode: set\nexample.com/other/other.go:1.1,2.2 1 1\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	defaultFlags(t)
+	oldCover := *coverFlag
+	t.Cleanup(func() { *coverFlag = oldCover })
+	*coverFlag = profile
+	var out bytes.Buffer
+	newSession(&out).lookInDirectory(dir, "", "Add")
+	if want := "Coverage: not in the profile\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("-cover with a profile of another package printed\n%s\nwant it to contain %q", out.String(), want)
+	}
+
+	*coverFlag = filepath.Join(t.TempDir(), "missing.out")
+	if status := exitStatus(func() { newSession(&out).lookInDirectory(dir, "", "Add") }); status != 2 {
+		t.Errorf("-cover with a missing profile exited with %d, want 2", status)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+)
+
+// recoverCrash, deferred by main, turns a panic into a report that says
+// what doc was asked and what it was looking at when it failed, with the
+// part of the stack that matters, so a malformed tree yields something
+// worth filing rather than a raw trace mid-search. A panic on another
+// goroutine reaches it forwarded by forwardPanic, and the panic of exit
+// ends doc with exit's status, once main's deferred calls have run. As it
+// runs last, it also ends doc with the status a deferred call that failed
+// set in s.status, if that is greater.
+func (s *session) recoverCrash() {
+	r := recover()
+	if r == nil {
+		if s.status != 0 {
+			os.Exit(s.status)
+		}
+		return
+	}
+	if code, ok := r.(exitCode); ok {
+		os.Exit(max(int(code), s.status))
+	}
+	stack, examining := debug.Stack(), s.examining
+	if p, ok := r.(forwardedPanic); ok {
+		r, stack, examining = p.value, p.stack, p.examining
+	}
+	fmt.Fprintf(os.Stderr, "doc: internal error: %v\n", r)
+	fmt.Fprintf(os.Stderr, "query: doc %s\n", strings.Join(os.Args[1:], " "))
+	var flags []string
+	flag.Visit(func(f *flag.Flag) {
+		flags = append(flags, fmt.Sprintf("-%s=%s", f.Name, f.Value))
+	})
+	if len(flags) > 0 {
+		fmt.Fprintf(os.Stderr, "flags: %s\n", strings.Join(flags, " "))
+	}
+	if examining != "" {
+		fmt.Fprintf(os.Stderr, "while examining: %s\n", examining)
+	}
+	fmt.Fprintf(os.Stderr, "%s\n", trimStack(stack, 10))
+	fmt.Fprintf(os.Stderr, "Please report this, with the lines above, at https://github.com/robpike/doc/issues.\n")
+	os.Exit(2)
+}
+
+// exitCode is the panic with which exit unwinds main.
+type exitCode int
+
+// exitUnwinds is set once main has deferred recoverCrash, after which exit
+// can unwind main rather than end the process at once.
+var exitUnwinds bool
+
+// exit ends doc with the status code. Once recoverCrash is deferred, it
+// does so by panicking, so that main's other deferred calls, which print
+// -time's statistics and copy the output for -clip and -chat, still run;
+// os.Exit would skip them.
+func exit(code int) {
+	if !exitUnwinds {
+		os.Exit(code)
+	}
+	panic(exitCode(code))
+}
+
+// A forwardedPanic is a panic recovered on a goroutine other than main's
+// and panicked with again on main's, with what it needs for the report
+// of recoverCrash.
+type forwardedPanic struct {
+	value     any
+	stack     []byte // Where it panicked, on its own goroutine.
+	examining string
+}
+
+// forwardPanic returns the value recovered from a panic on a goroutine
+// other than main's, which was examining the named directory or file, as
+// the value with which main's goroutine should panic to report it.
+func forwardPanic(r any, examining string) any {
+	if _, ok := r.(exitCode); ok {
+		return r
+	}
+	return forwardedPanic{value: r, stack: debug.Stack(), examining: examining}
+}
+
+// trimStack returns at most n frames of the stack trace, starting with the
+// one that panicked, without the frames of the panic and its recovery.
+func trimStack(stack []byte, n int) string {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic(") {
+			lines = lines[i+2:] // Each frame is two lines: function and position.
+			break
+		}
+	}
+	if len(lines) > 2*n {
+		lines = lines[:2*n]
+	}
+	return strings.Join(lines, "\n")
+}
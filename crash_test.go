@@ -0,0 +1,30 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"testing"
+)
+
+func TestTypeCheckAllForwardsPanic(t *testing.T) {
+	name := filepath.Join("dir", "bad.go")
+	units := []*checkUnit{{
+		fset: token.NewFileSet(),
+		pkg:  &ast.Package{Name: "bad", Files: map[string]*ast.File{name: nil}}, // Checking a nil file panics.
+	}}
+	defer func() {
+		p, ok := recover().(forwardedPanic)
+		if !ok {
+			t.Fatalf("typeCheckAll did not forward the panic of its worker")
+		}
+		if p.examining != "dir" || len(p.stack) == 0 {
+			t.Errorf("forwarded panic examining %q with %d bytes of stack, want dir and the stack", p.examining, len(p.stack))
+		}
+	}()
+	typeCheckAll(units)
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// useColor reports whether diffs are colored: when standard output is a
+// terminal and neither NO_COLOR nor -screen-reader is set.
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" || *screenReaderFlag {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// An edit is a line of a diff: kept (' '), deleted ('-') or added ('+').
+type edit struct {
+	op   byte
+	text string
+}
+
+// diffLines returns the edits, from a longest common subsequence, that
+// turn the lines of a into those of b. Doc comments are short, so the
+// quadratic table is no burden.
+func diffLines(a, b []string) []edit {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', a[i]})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j]})
+			j++
+		}
+	}
+	return edits
+}
+
+// textDiff returns the difference between the old and new text, headed by
+// a hunk header naming the symbol: a unified diff or, if width is positive,
+// the two side by side in that many columns. It is colored, as the theme
+// says, if color is set.
+func textDiff(symbol, old, new string, width int, color bool) string {
+	edits := diffLines(strings.Split(strings.TrimSuffix(old, "\n"), "\n"), strings.Split(strings.TrimSuffix(new, "\n"), "\n"))
+	palette := currentTheme()
+	paint := func(code, text string) string {
+		if !color {
+			return text
+		}
+		return code + text + colorReset
+	}
+	var b strings.Builder
+	header := paint(palette.header, "@@ "+symbol+" @@")
+	b.WriteString(header + "\n")
+	if width > 0 {
+		sideBySide(&b, edits, width, palette, paint)
+		return b.String()
+	}
+	// Show each change with the unchanged lines near it, marking the gaps.
+	near := make([]bool, len(edits))
+	for i, e := range edits {
+		if e.op == ' ' {
+			continue
+		}
+		for k := max(0, i-diffContext); k <= min(len(edits)-1, i+diffContext); k++ {
+			near[k] = true
+		}
+	}
+	for i, e := range edits {
+		if !near[i] {
+			continue
+		}
+		if i > 0 && !near[i-1] && b.Len() > len(header)+1 {
+			// The next hunk, after a gap.
+			b.WriteString(header + "\n")
+		}
+		switch e.op {
+		case '-':
+			b.WriteString(paint(palette.del, "-"+e.text) + "\n")
+		case '+':
+			b.WriteString(paint(palette.add, "+"+e.text) + "\n")
+		default:
+			b.WriteString(" " + e.text + "\n")
+		}
+	}
+	return b.String()
+}
+
+// sideBySide writes the edits as two columns, old on the left and new on
+// the right, marked between them as sdiff does: | for a changed line,
+// < for a deleted one and > for an added one. The left column is measured
+// as a terminal shows it, so wide characters and tabs keep it aligned.
+func sideBySide(b *strings.Builder, edits []edit, width int, palette theme, paint func(code, text string) string) {
+	col := max((width-3)/2, 1)
+	cell := func(text string) string { return fitWidth(text, col) }
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			fmt.Fprintf(b, "%s   %s\n", cell(edits[i].text), edits[i].text)
+			i++
+			continue
+		}
+		// Pair a run of deleted lines with the run of added lines after it.
+		var dels, adds []string
+		for ; i < len(edits) && edits[i].op == '-'; i++ {
+			dels = append(dels, edits[i].text)
+		}
+		for ; i < len(edits) && edits[i].op == '+'; i++ {
+			adds = append(adds, edits[i].text)
+		}
+		for k := 0; k < max(len(dels), len(adds)); k++ {
+			switch {
+			case k < len(dels) && k < len(adds):
+				fmt.Fprintf(b, "%s | %s\n", paint(palette.del, cell(dels[k])), paint(palette.add, adds[k]))
+			case k < len(dels):
+				fmt.Fprintf(b, "%s <\n", paint(palette.del, cell(dels[k])))
+			default:
+				fmt.Fprintf(b, "%s > %s\n", cell(""), paint(palette.add, adds[k]))
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := func(s string) string { return strings.Join(strings.Split(s, ""), "\n") + "\n" }
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{
+			name: "a change at the start",
+			old:  lines("abcdefghij"),
+			new:  lines("Abcdefghij"),
+			want: []string{"@@ F @@", "-a", "+A", " b", " c", " d"},
+		},
+		{
+			name: "a change in the middle",
+			old:  lines("abcdefghij"),
+			new:  lines("abcdeFghij"),
+			want: []string{"@@ F @@", " c", " d", " e", "-f", "+F", " g", " h", " i"},
+		},
+		{
+			name: "a change at the end",
+			old:  lines("abcdefghij"),
+			new:  lines("abcdefghiJ"),
+			want: []string{"@@ F @@", " g", " h", " i", "-j", "+J"},
+		},
+		{
+			name: "two hunks",
+			old:  lines("abcdefghijklmn"),
+			new:  lines("AbcdefghijklmN"),
+			want: []string{"@@ F @@", "-a", "+A", " b", " c", " d", "@@ F @@", " k", " l", " m", "-n", "+N"},
+		},
+		{
+			name: "hunks joined by a short gap",
+			old:  lines("abcdefghij"),
+			new:  lines("AbcdefgHij"),
+			want: []string{"@@ F @@", "-a", "+A", " b", " c", " d", " e", " f", " g", "-h", "+H", " i", " j"},
+		},
+	}
+	for _, test := range tests {
+		got := textDiff("F", test.old, test.new, 0, false)
+		if want := strings.Join(test.want, "\n") + "\n"; got != want {
+			t.Errorf("%s: textDiff =\n%s\nwant\n%s", test.name, got, want)
+		}
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	old := "same\n日本語テキスト\n\tgone\nkeep\n"
+	new := "same\nnew\nkeep\nadded\n"
+	// In 23 columns, each side has 10, with 3 between.
+	want := []string{
+		"@@ F @@",
+		"same         same",
+		"日本語テ…  | new", // テキ would straddle the edge.
+		"        g… <", // The tab reaches column 8.
+		"keep         keep",
+		"           > added",
+	}
+	got := textDiff("F", old, new, 23, false)
+	if got != strings.Join(want, "\n")+"\n" {
+		t.Errorf("textDiff side by side =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	// A scratch directory, in no module or GOPATH.
+	dir := t.TempDir()
+	scratch := filepath.Join(dir, "scratch")
+	if err := os.Mkdir(scratch, 0777); err != nil {
+		t.Fatal(err)
+	}
+	src := "// Package scratch is an experiment.\npackage scratch\n\n// Parse parses.\nfunc Parse() {}\n"
+	if err := os.WriteFile(filepath.Join(scratch, "s.go"), []byte(src), 0666); err != nil {
+		t.Fatal(err)
+	}
+	pos := filepath.Join(scratch, "s.go")
+	// The results have no URLs, for the directory has no import path.
+	parse := pos + ":5:\n// Parse parses.\nfunc Parse()\n\n"
+	tests := []struct {
+		args   []string
+		want   string
+		status int
+	}{
+		{args: []string{"-dir", "scratch", "Parse"}, want: parse},
+		{args: []string{"-dir", "./scratch", "parse"}, want: parse},
+		{args: []string{"-dir", scratch, "Parse"}, want: parse},
+		{args: []string{"./scratch", "Parse"}, want: parse},
+		{args: []string{"-pkg", "-dir", "scratch"}, want: pos + ":1:\npackage scratch\nPackage scratch is an experiment.\n\n\n"},
+		{args: []string{"-dir", "scratch", "Nothing"}},
+		{args: []string{"-dir", "scratch"}, status: 2},
+		{args: []string{"-dir", "scratch", "a", "b"}, status: 2},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != test.status {
+			t.Errorf("doc %q exited with %d, want %d; stderr:\n%s", test.args, status, test.status, stderr)
+			continue
+		}
+		if status == 0 && out != test.want {
+			t.Errorf("doc %q printed\n%s\nwant\n%s", test.args, out, test.want)
+		}
+	}
+}
+
+func TestDirInModule(t *testing.T) {
+	dir := writeModule(t, testModule)
+	// A directory of the module keeps its URLs.
+	out, stderr, status := runDoc(t, dir, "-url", "-dir", filepath.Join("internal", "auth"), "Make")
+	if status != 0 {
+		t.Fatalf("doc -dir exited with %d; stderr:\n%s", status, stderr)
+	}
+	if want := "https://pkg.go.dev/example.com/m/v2/internal/auth#Make\n"; !strings.HasPrefix(out, want) {
+		t.Errorf("doc -url -dir printed %q, want it to begin %q", out, want)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A dirCache remembers the subdirectories of the directories walked, so a
+// search of a tree on a network file system need not stat every entry of
+// every directory each time. Within the cache's time to live, an entry is
+// trusted outright; after it, one stat of the directory checks that its
+// modification time, which changes when entries are added, removed or
+// renamed, is the one recorded, and only if not is the directory read again.
+// The cache is the process's, shared by the sessions it runs in turn.
+type dirCache struct {
+	ttl   time.Duration
+	mu    sync.Mutex
+	dirs  map[string]*dirEntry
+	dirty bool
+}
+
+// A dirEntry is what the cache knows of a directory.
+type dirEntry struct {
+	ModTime int64    // Modification time, in nanoseconds since the epoch: the fingerprint.
+	Checked int64    // When the fingerprint was last checked, in seconds since the epoch.
+	Subdirs []string // Names of the subdirectories, in lexical order.
+}
+
+// walkCache is the directory cache, or nil if it is off: the time to live is set
+// in seconds in the configuration file as dircache.ttl, and 0, the
+// default, turns the cache off.
+var walkCache = openDirCache()
+
+// dirCachePath returns the name of the file holding the directory cache.
+func dirCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "doc", "dirs.json")
+}
+
+func openDirCache() *dirCache {
+	ttl := time.Duration(configFloat("dircache.ttl", 0) * float64(time.Second))
+	if ttl <= 0 {
+		return nil
+	}
+	c := &dirCache{ttl: ttl, dirs: make(map[string]*dirEntry)}
+	if data, err := os.ReadFile(dirCachePath()); err == nil {
+		json.Unmarshal(data, &c.dirs) // A damaged cache is rebuilt.
+	}
+	return c
+}
+
+// walk calls fn, as walkDirs does for the session, for each directory of
+// the tree at dir, whose root is root, reporting whether to continue.
+func (c *dirCache) walk(s *session, root, dir, pkg string, fn func(dir string) bool) bool {
+	stats.dirs.Add(1)
+	// No .hg or other dot nonsense please, though the root may be in a dot directory.
+	if dir != root && strings.HasPrefix(filepath.Base(dir), ".") {
+		return true
+	}
+	skip, prune := s.skipDir(dir)
+	if prune || s.skipGenerated(dir, pkg) {
+		return true
+	}
+	subdirs, ok := c.subdirs(dir)
+	if !ok {
+		return true
+	}
+	if !skip && (pkg == "" || filepath.Base(dir) == pkg) && !fn(dir) {
+		return false
+	}
+	for _, sub := range subdirs {
+		if !c.walk(s, root, filepath.Join(dir, sub), pkg, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// subdirs returns the names of the subdirectories of the directory, from
+// the cache if the entry there is fresh or its fingerprint still matches,
+// and whether the directory exists.
+func (c *dirCache) subdirs(dir string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	e := c.dirs[dir]
+	if e != nil && now.Sub(time.Unix(e.Checked, 0)) < c.ttl {
+		stats.cacheHits.Add(1)
+		return e.Subdirs, true
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		if e != nil {
+			delete(c.dirs, dir)
+			c.dirty = true
+		}
+		return nil, false
+	}
+	c.dirty = true
+	if e != nil && info.ModTime().UnixNano() == e.ModTime {
+		stats.cacheHits.Add(1)
+		e.Checked = now.Unix()
+		return e.Subdirs, true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		delete(c.dirs, dir)
+		return nil, false
+	}
+	old := e
+	e = &dirEntry{ModTime: info.ModTime().UnixNano(), Checked: now.Unix()}
+	for _, entry := range entries {
+		if entry.IsDir() { // Not a symbolic link, as with filepath.Walk.
+			e.Subdirs = append(e.Subdirs, entry.Name())
+		}
+	}
+	c.dirs[dir] = e
+	if old != nil {
+		for _, sub := range old.Subdirs {
+			if !slices.Contains(e.Subdirs, sub) {
+				c.forget(filepath.Join(dir, sub))
+			}
+		}
+	}
+	return e.Subdirs, true
+}
+
+// forget removes the directory, which is gone, and those beneath it from the
+// cache, which would otherwise keep them for good, as they are never walked.
+func (c *dirCache) forget(dir string) {
+	for name := range c.dirs {
+		if name == dir || strings.HasPrefix(name, dir+slash) {
+			delete(c.dirs, name)
+		}
+	}
+}
+
+// save writes the cache, if it has changed, replacing the file whole so a
+// concurrent doc never reads a partial one.
+func (c *dirCache) save() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	name := dirCachePath()
+	if !c.dirty || name == "" {
+		return
+	}
+	data, err := json.Marshal(c.dirs)
+	if err != nil || os.MkdirAll(filepath.Dir(name), 0700) != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), "dirs")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(tmp.Name(), name) != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	c.dirty = false
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// mkdirs makes the slash-separated directories under root.
+func mkdirs(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// walked returns the directories, relative to root, for which walkDirs
+// calls its function, walking from start.
+func walked(t *testing.T, root, start, pkg string) []string {
+	t.Helper()
+	var dirs []string
+	newSession(io.Discard).walkDirs(start, pkg, func(dir string) bool {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dirs = append(dirs, filepath.ToSlash(rel))
+		return true
+	})
+	return dirs
+}
+
+func TestDirCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	mkdirs(t, root, "a/x", "b/x", "c", ".hidden/x")
+	old := walkCache
+	defer func() { walkCache = old }()
+	walkCache = &dirCache{ttl: time.Hour, dirs: make(map[string]*dirEntry)}
+
+	steps := []struct {
+		change func()
+		want   []string
+	}{
+		{func() {}, []string{"a/x", "b/x"}},
+		{func() { mkdirs(t, root, "d/x") }, []string{"a/x", "b/x"}},   // Trusted within the time to live.
+		{func() { walkCache.ttl = 0 }, []string{"a/x", "b/x", "d/x"}}, // Root's fingerprint changed.
+		{func() { os.RemoveAll(filepath.Join(root, "a")) }, []string{"b/x", "d/x"}},
+		{func() { mkdirs(t, root, "b/x/x") }, []string{"b/x", "b/x/x", "d/x"}},
+	}
+	for i, step := range steps {
+		step.change()
+		if got := walked(t, root, root, "x"); !slices.Equal(got, step.want) {
+			t.Errorf("step %d: walked %q, want %q", i, got, step.want)
+		}
+	}
+	for _, dir := range []string{"a", "a/x"} {
+		if _, ok := walkCache.dirs[filepath.Join(root, filepath.FromSlash(dir))]; ok {
+			t.Errorf("the cache still holds the removed directory %s", dir)
+		}
+	}
+
+	// The cache is saved, and read again by the next doc.
+	config["dircache.ttl"] = []string{"600"}
+	defer delete(config, "dircache.ttl")
+	reopened := openDirCache()
+	if reopened == nil || reopened.ttl != 600*time.Second {
+		t.Fatalf("openDirCache = %+v, want a cache with a time to live of 600s", reopened)
+	}
+	if e := reopened.dirs[filepath.Join(root, "b", "x")]; e == nil || !slices.Equal(e.Subdirs, []string{"x"}) {
+		t.Errorf("reopened cache holds %+v for b/x, want its subdirectory x", e)
+	}
+	delete(config, "dircache.ttl")
+	if c := openDirCache(); c != nil {
+		t.Errorf("openDirCache with no dircache.ttl = %+v, want nil", c)
+	}
+}
+
+func TestSymlinkedRoot(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	mkdirs(t, dir, "real/a/x", "real/b")
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(filepath.Join(dir, "real"), link); err != nil {
+		t.Skip(err)
+	}
+	old := walkCache
+	defer func() { walkCache = old }()
+	for _, cache := range []*dirCache{nil, {ttl: time.Hour, dirs: make(map[string]*dirEntry)}} {
+		walkCache = cache
+		// Reported under the link's name.
+		if got, want := walked(t, dir, link, ""), []string{"link", "link/a", "link/a/x", "link/b"}; !slices.Equal(got, want) {
+			t.Errorf("with cache %t, walked %q, want %q", cache != nil, got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// listGenerate prints, with their positions, the //go:generate directives
+// in the Go files, tests included, of the directories. Like go generate,
+// it looks at the text of each line, not at the parsed file.
+func (s *session) listGenerate(dirs []string) {
+	for _, dir := range dirs {
+		names, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+		for _, name := range names {
+			scanLines(name, func(line int, text string) {
+				if strings.HasPrefix(text, "//go:generate ") {
+					fmt.Fprintf(s.out, "%s:%d: %s\n", name, line, text)
+				}
+			})
+		}
+	}
+}
+
+// listDirectives prints, grouped by file, the directives in the Go files
+// of the directories that change how the compiler, linker or cgo treats
+// them: //go: lines such as //go:nosplit and //go:linkname, //export and
+// the old // +build, and the preamble of each import "C", with its #cgo
+// lines.
+func (s *session) listDirectives(dirs []string) {
+	for _, dir := range dirs {
+		names, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+		for _, name := range names {
+			var lines []string
+			scanLines(name, func(line int, text string) {
+				if isDirective(text) {
+					lines = append(lines, fmt.Sprintf("\t%d: %s\n", line, text))
+				}
+			})
+			lines = append(lines, cgoPreambles(name)...)
+			if len(lines) == 0 {
+				continue
+			}
+			sortByLine(lines)
+			fmt.Fprintf(s.out, "%s\n", name)
+			for _, line := range lines {
+				fmt.Fprint(s.out, line)
+			}
+		}
+	}
+}
+
+// isDirective reports whether the line is a directive comment.
+func isDirective(text string) bool {
+	switch {
+	case strings.HasPrefix(text, "//go:"):
+		return len(text) > len("//go:") && text[len("//go:")] != ' '
+	case strings.HasPrefix(text, "//export "), strings.HasPrefix(text, "// +build "):
+		return true
+	}
+	return false
+}
+
+// cgoPreambles returns, in the form of listDirectives, the position and
+// length of the preamble of each import "C" in the file, and its #cgo lines.
+func cgoPreambles(name string) []string {
+	fset := token.NewFileSet()
+	file, err := parseFile(fset, name, nil, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+	var lines []string
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.ImportSpec)
+			if path, _ := strconv.Unquote(spec.Path.Value); path != "C" {
+				continue
+			}
+			doc := spec.Doc
+			if doc == nil && !decl.Lparen.IsValid() {
+				doc = decl.Doc
+			}
+			if doc == nil {
+				continue
+			}
+			start, end := fset.Position(doc.Pos()).Line, fset.Position(doc.End()).Line
+			lines = append(lines, fmt.Sprintf("\t%d: cgo preamble, %d lines\n", start, end-start+1))
+			for _, c := range doc.List {
+				for i, text := range strings.Split(c.Text, "\n") {
+					text = strings.TrimSpace(strings.TrimPrefix(text, "//"))
+					if strings.HasPrefix(text, "#cgo ") {
+						lines = append(lines, fmt.Sprintf("\t%d: %s\n", fset.Position(c.Pos()).Line+i, text))
+					}
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// sortByLine sorts the lines of listDirectives by their line numbers.
+func sortByLine(lines []string) {
+	number := func(s string) int {
+		n, _ := strconv.Atoi(strings.TrimSpace(s[:strings.Index(s, ":")]))
+		return n
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return number(lines[i]) < number(lines[j]) })
+}
+
+// scanLines calls fn for each line of the file, numbered from 1.
+func scanLines(name string, fn func(line int, text string)) {
+	fd, err := os.Open(name)
+	if err != nil {
+		return
+	}
+	defer fd.Close()
+	scanner := bufio.NewScanner(fd)
+	for line := 1; scanner.Scan(); line++ {
+		fn(line, scanner.Text())
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// directivesModule holds a package with directives of every kind.
+var directivesModule = map[string]string{
+	"go.mod": "module example.com/dv\n\ngo 1.22\n",
+	"gen/gen.go": `//go:build linux
+
+// Package gen generates.
+package gen
+
+//go:generate stringer -type=Kind
+// go:generate not a directive
+
+/*
+#cgo LDFLAGS: -lm
+#include <math.h>
+*/
+import "C"
+
+// Kind is a kind.
+type Kind int
+
+//go:noescape
+func f()
+
+//go: not a directive
+
+//export Exported
+func Exported() {}
+`,
+	"gen/gen_test.go": "package gen\n\n//go:generate go run gen.go\n",
+	"gen/plain.go":    "package gen\n",
+}
+
+func TestGenerate(t *testing.T) {
+	dir := writeModule(t, directivesModule)
+	gen := filepath.Join(dir, "gen")
+	tests := []struct {
+		args   []string
+		want   string
+		status int
+	}{
+		{
+			args: []string{"-generate", "gen"},
+			want: filepath.Join(gen, "gen.go") + ":6: //go:generate stringer -type=Kind\n" +
+				filepath.Join(gen, "gen_test.go") + ":3: //go:generate go run gen.go\n",
+		},
+		{args: []string{"-generate", "nosuch"}},
+		{args: []string{"-generate"}, status: 2},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != test.status {
+			t.Errorf("doc %q exited with %d, want %d; stderr:\n%s", test.args, status, test.status, stderr)
+			continue
+		}
+		if status == 0 && out != test.want {
+			t.Errorf("doc %q printed\n%s\nwant\n%s", test.args, out, test.want)
+		}
+	}
+}
+
+func TestIsDirective(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"//go:noescape", true},
+		{"//go:linkname f runtime.f", true},
+		{"//go:build linux", true},
+		{"//export Exported", true},
+		{"// +build linux", true},
+		{"//go:", false},
+		{"//go: not a directive", false},
+		{"// go:noescape", false},
+		{"//exported", false},
+		{"// Export exports.", false},
+	}
+	for _, test := range tests {
+		if got := isDirective(test.text); got != test.want {
+			t.Errorf("isDirective(%q) = %t, want %t", test.text, got, test.want)
+		}
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	dir := writeModule(t, directivesModule)
+	gen := filepath.Join(dir, "gen")
+	// Files without directives, such as plain.go, are not listed.
+	want := filepath.Join(gen, "gen.go") + "\n" +
+		"\t1: //go:build linux\n" +
+		"\t6: //go:generate stringer -type=Kind\n" +
+		"\t9: cgo preamble, 4 lines\n" +
+		"\t10: #cgo LDFLAGS: -lm\n" +
+		"\t18: //go:noescape\n" +
+		"\t23: //export Exported\n" +
+		filepath.Join(gen, "gen_test.go") + "\n" +
+		"\t3: //go:generate go run gen.go\n"
+	out, stderr, status := runDoc(t, dir, "-directives", "gen")
+	if status != 0 {
+		t.Fatalf("doc -directives gen exited with %d; stderr:\n%s", status, stderr)
+	}
+	if out != want {
+		t.Errorf("doc -directives gen printed\n%s\nwant\n%s", out, want)
+	}
+}
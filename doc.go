@@ -11,7 +11,10 @@
 // the source. It has a more Go-like UI than godoc. It can also search for
 // symbols by looking in all packages, and case is ignored. For instance:
 //	doc isupper
-// will find unicode.IsUpper.
+// will find unicode.IsUpper. Case is folded as Unicode does, with the
+// Turkish dotted and dotless I matching i and ß matching ss, so
+//	doc strasse
+// finds Straße too. A regular expression ignores case only rune by rune.
 //
 // The -pkg flag retrieves package-level doc comments only.
 //
@@ -21,8 +24,57 @@
 //	doc name       # "doc isupper" (finds unicode.IsUpper)
 //	doc -pkg pkg   # "doc fmt"
 //
+// Packages are looked for first in the module containing the current
+// directory, then in GOROOT, then in GOPATH; once a match is found, farther
+// places are not searched. For a plain pkg.name, without a regular
+// expression, the search stops at the first package that declares the name.
+//
 // The pkg is the last element of the package path;
-// no slashes (ast.Node not go/ast.Node).
+// no slashes (ast.Node not go/ast.Node). A pkg that begins with . or /
+// is instead the directory holding the package, as in
+//	doc ./internal/auth Token
+//	doc . Handler
+//
+// If the package cannot be found but is one of a few well-known packages
+// that have moved, such as golang.org/x/net/context, now context, or
+// github.com/golang/protobuf, now google.golang.org/protobuf, doc says
+// where it went. More moves can be listed in the configuration file:
+//	moved github.com/oldorg/lib github.com/neworg/lib
+//
+// When the name is a type alias, the documentation for the aliased type
+// is printed after that of the alias.
+//
+// A symbol that not every program can use is marked by a line before its
+// documentation: "test only" if it is declared in a _test.go file, "in a
+// command" if its package is main, and "internal to path" if an internal
+// element of its import path restricts it to the packages under path.
+// Likewise a symbol of a deprecated module is marked with the module's
+// deprecation message, which usually names its successor, and one of a
+// retracted version of a module with the reason for the retraction. These
+// come from the go.mod file of the module's latest version, asked of the
+// module proxy for a module in the module cache that GOPRIVATE does not
+// match, and otherwise from the module's own go.mod file.
+//
+// A symbol may be given a stability tier, such as stable, beta or
+// experimental, by a line of its doc comment, or that of its type or its
+// package, such as
+//	// Stability: experimental
+// or by lines of the configuration file naming packages, or trees of them
+// as in GOPRIVATE, and optionally a symbol:
+//	stability example.com/sdk/preview experimental
+//	stability example.com/sdk Client.Watch beta
+// The tier is printed before its documentation, as in
+//	stability: experimental
+//
+// A name of several identifiers separated by dots selects a field or method
+// by following the types of fields, across packages if need be:
+//	doc http.Server.TLSConfig.MinVersion
+// prints the documentation of the MinVersion field of tls.Config.
+// A method promoted from an embedded field is found through the field, and
+// reported with the type that declares it and the path that promotes it:
+//	doc -m bufio.ReadWriter Read
+// prints the documentation of the Read method of bufio.Reader, which
+// ReadWriter promotes through its embedded *Reader.
 //
 // The name may also be a regular expression to select which names
 // to match. In regular expression searches, case is ignored and
@@ -38,10 +90,642 @@
 // Flags
 //	-doc -src -url
 // restrict printing to the documentation, source path, or godoc URL.
+// The links may instead be to another kind of server, chosen in the
+// configuration file with url.scheme: pkgsite, classic-godoc, or, to link
+// to the source, gitea or sourcegraph. The line url.host names the server,
+// for one that is self-hosted, and url.branch the branch the source
+// schemes link to:
+//	url.scheme classic-godoc
+//	url.host http://godoc.internal:6060
+// Sourcegraph links name the revision of the source, such as the version
+// of a module in the module cache, or else the one set by url.rev.
+// By default the links are to pkg.go.dev. A url.host on godoc.org or
+// golang.org, the sites pkg.go.dev replaced, is taken to be pkg.go.dev,
+// with a warning.
 // Flag
 //	-r
 // takes a single argument (no package), a name or regular expression
 // to search for in all packages.
+// When a regular expression is matched against a named package, the
+// results are listed in sections: constants, variables, functions and
+// types, with each type followed by its methods.
+// Flag
+//	-explain
+// takes a single argument, a Go expression, and prints the documentation
+// of each function and method it calls, in the order they are called:
+//	doc -explain 'json.NewDecoder(r).Decode(&v)'
+// Flag
+//	-method-of pkg.Type
+// takes a single argument, an interface method such as io.Writer.Write,
+// and prints the method of the type, possibly promoted from an embedded
+// field, that satisfies it:
+//	doc -method-of bytes.Buffer io.Writer.Write
+// Flags
+//	-tests -bench -fuzz
+// take a package and list its tests, benchmarks or fuzz targets, with their
+// doc comments, from its test files. Each is followed by the subtests it
+// runs with t.Run (or b.Run) and a string literal for the name, as go test
+// would name them.
+// Flags
+//	-calls -callers
+// list, instead of the documentation for the named function or method
+// (pkg.Type.Method), the functions it calls or the functions that call it.
+// Calls are followed -depth levels within the package.
+// Flag
+//	-unused
+// takes an optional directory or pattern (default the current module)
+// such as ./... and lists the exported package-level symbols there that
+// no other package in the module or GOPATH refers to:
+//	doc -unused ./...
+// Flag
+//	-collisions
+// takes the same optional pattern and lists the exported package-level
+// names declared in more than one package there, such as several types
+// named Config or functions named New, each with where it is declared and
+// the synopsis of its doc comment.
+// Flag
+//	-lsif
+// takes the same optional pattern and writes, for code intelligence
+// platforms, an index in the Language Server Index Format (LSIF), as lines
+// of JSON, of the exported declarations there: for each, its definition,
+// a hover holding its declaration and doc comment in Markdown, a gomod
+// moniker import/path:Name, and the references to it, through qualified
+// identifiers, from the other packages indexed:
+//	doc -lsif ./... > dump.lsif
+// SCIP, which is encoded as protocol buffers, is not written.
+// Flag
+//	-overview pkg
+// prints a summary of the package, where to start reading an unfamiliar
+// one, rather than all of it: its synopsis, the exported types most used
+// in the package and the packages below it, each with the functions that
+// construct it, and then the most used of its other functions. Uses are
+// counted from the source, without type checking.
+// Flag
+//	-cheatsheet pkg
+// prints a compact listing of the package's functions, one signature to a
+// line, for printing or pinning, grouped by task: under the headings of the
+// sections of the package comment that mention them, and otherwise by the
+// first word of their names, as for Parse, Format and Quote in strconv;
+// then its types, each with its methods.
+// Flag
+//	-ptr
+// lists, for a matched type T, the methods of *T that are not methods of T,
+// and the interfaces that only *T satisfies.
+// Flag
+//	-behavior
+// appends to a function's documentation the explicit panics and the
+// sentinel errors found in its body.
+// Flag
+//	-concurrency
+// adds a line noting whether a function starts goroutines, takes a
+// context.Context, or accepts or returns channels.
+// Flag
+//	-risk
+// adds a line noting whether a function's body uses package unsafe and how
+// often it refers to package reflect, or, with -pkg, how many files of the
+// package import unsafe: a quick signal when judging a dependency.
+// Flag
+//	-perf
+// builds the package of each function matched with go build -gcflags=-m=2
+// and appends, as Performance notes, whether the compiler can inline the
+// function, and at what cost, and which of its parameters escape.
+// Flags
+//	-cover profile
+//	-cover-run
+// add to each function the percentage of its statements run, from the
+// coverage profile written by go test -coverprofile or, with -cover-run,
+// by running the tests of the function's package.
+// Flag
+//	-snippet
+// adds to each function a snippet of code that calls it, with zero values
+// for the arguments, variables for the results and a check of the error,
+// to start from when writing the call.
+// Flag
+//	-license
+// adds the license governing each result, from the SPDX identifier in the
+// file or the LICENSE file of its module, and the file's copyright line.
+// Flag
+//	-verbose-doc
+// adds to each result its examples, the functions that construct it, the
+// types in its signature and the symbols its comment links to, each with
+// a synopsis.
+// Flag
+//	-follow
+// prints, for an undocumented function whose body is a single call,
+// the documentation of the function it calls.
+// Flag
+//	-inherit-doc
+// prints, for an undocumented method, the documentation of the interface
+// method it implements, from an interface of its own package or of one it
+// imports, attributed as in
+//	(documented on io.Reader.Read)
+// Flag
+//	-rank
+// orders the results by a score that favors exact matches, the standard
+// library, short import paths and packages imported by many others, and
+// disfavors those of deprecated modules and retracted versions.
+// The weights of these terms may be set in the configuration file,
+// $DOCCONFIG or doc/config in the user's configuration directory,
+// with lines such as
+//	weight.popularity 2
+//	weight.deprecated 10
+// Flag
+//	-why
+// explains each result in a line before its documentation: whether its
+// name is the one asked for, the same ignoring case, or matches the regular
+// expression; whether -filter and -stable-only admitted it; and the root
+// it was found in: std, module, workspace or vendored. With -rank, each
+// package's results are preceded by its score and the weighted terms that
+// make it up, for tuning the weights:
+//	why: io scores 25.38: exactcase +10.00, stdlib +5.00, pathlength -1.00 (path elements: 1), ...
+// Flag
+//	-C n
+// prints n lines of source on either side of each declaration's name,
+// numbered like the output of grep -n -C n.
+// Flag
+//	-raw
+// prints doc comments and declarations exactly as they appear in the
+// source, comment markers and all.
+// Flag
+//	-lang code
+// prints doc comments translated into the language, where translations
+// are available, such as from a doc.<code>.json file in the package's
+// directory mapping symbol names (Type.Method for methods, empty for the
+// package) to text. Otherwise the original is printed.
+// Flag
+//	-local
+// restricts the search to the packages of the module containing the
+// current directory.
+// Flag
+//	-all-roots
+// searches GOROOT and GOPATH even when a closer package matched, and prints
+// every package that declares a plain pkg.name, not just the first.
+// Flag
+//	-manifest go.mod [-all] [-force] -o dir
+// Write the documentation of each direct dependency required by the go.mod
+// file, at the version the build uses, to a text file in dir named by the
+// package's import path. The version is the one minimal version selection
+// picks over the pruned module graph, as go list -m all reports it, which
+// may be newer than go.mod's; if the go command cannot list the graph
+// without the network, go.mod's is used, with a warning. With -all, every
+// package of each dependency is written, not just the one at the module
+// root. Versions missing from the module cache are fetched from the module
+// proxy, so the result is a bundle of documentation that can be read
+// without a network. Beside each file, one with .stamp appended records the
+// module version it documents; a file whose stamp names the version wanted
+// is left alone, and without -all its module is not even fetched, so a run
+// that was interrupted, or one after a few dependencies changed, redoes
+// only what is out of date; -force rewrites everything.
+//
+// Source fetched from a module proxy, by -manifest and -stale, is not
+// trusted: files larger than 10MB, nested more than 1000 deep, or taking
+// longer than 10 seconds to parse are skipped with a notice. The limits may
+// be set in the configuration file as limit.filesize (in bytes),
+// limit.nesting, and limit.parsetime (in seconds). Modules are fetched from
+// the proxies of GOPROXY, in turn, but never directly from version control.
+// Before it is parsed, a module is checked against its hash in go.sum or,
+// if not listed there, in the checksum database, whose answer is verified
+// against the database's signed tree; GOSUMDB, GONOSUMDB and GOPRIVATE are
+// followed as the go command follows them. A module whose hash cannot be
+// had or does not match is not documented.
+//
+// When no package is named, so every package is searched, files larger than
+// 5MB, usually generated code, are skipped with a notice. The size may be set
+// in the configuration file as limit.searchfilesize, in bytes, or 0 for no limit.
+//
+// Regular expressions match in time linear in the length of the name, so
+// no pattern can make matching run away, but a pattern longer than 1000
+// bytes, or one whose compiled program has more than 10000 instructions,
+// as repetitions of repeated alternatives like
+// ((a|b){30}(c|d){30}(e|f){30}(g|h){30}){30} have, is refused. The
+// limits may be set in the configuration file as limit.regexplen and
+// limit.regexpsize.
+//
+// Walking a tree on a network file system, such as a GOPATH mounted over
+// NFS, is slow, as every entry of every directory is examined. Setting
+//	dircache.ttl 600
+// in the configuration file keeps the subdirectories of each directory
+// walked in doc's cache directory. For the time to live, in seconds, they
+// are trusted; after that, a directory is read again only if its
+// modification time has changed.
+//
+// The export data of the standard library, from which type checking learns
+// what imported packages declare, is kept in doc's cache directory for each
+// release of Go, so the go command is asked for it only once.
+//
+// Responses from the network, to -manifest, -stale, -repo, -update and
+// checks against the checksum database, are kept in doc's cache directory
+// and fetched again only if the server reports, by ETag or modification
+// time, that they have changed. Failed requests are retried, waiting twice
+// as long each time, or as long as the server asks up to a minute, and no
+// more than 4 requests are made a second. A request, including reading the
+// response, is given up after 5 minutes. The number of retries, the rate
+// and the time allowed a request, in seconds, may be set in the
+// configuration file as http.retries, http.rate and http.timeout, where a
+// rate of 0 is no limit.
+// Flag
+//	-rename-impact pkg.Name
+// List every reference to pkg.Name, in its own package and from the other
+// packages of the module and GOPATH, that would need updating if it were
+// renamed, grouped by module with counts.
+// Flag
+//	-lint [pattern]
+// Check the doc comments of the current module, or of the packages in the
+// pattern, and report doc links such as [Reader.Read] that refer to no
+// declaration, method or field, and comments that begin with the name of an
+// identifier other than the one they document. Both are usually left behind
+// when a symbol is renamed or deleted.
+// Flag
+//	-stale [version]
+// Compare the doc comments of the current module with those of its latest
+// published version, or the version given, as fetched from the module proxy
+// and shown on pkg.go.dev. Each exported symbol whose documentation differs,
+// that is not yet published, or that has since been removed is reported,
+// which helps decide when it is time to tag a release. Changed documentation
+// is shown as a unified diff, colored on a terminal unless NO_COLOR is set,
+// or with -width n side by side in n columns. The colors are those of the
+// theme set in the configuration file, light, dark or high-contrast, all
+// drawn from the 16 colors every terminal has:
+//	theme dark
+// Flag
+//	-update [channel]
+// Install, with go install, the newest release of the module doc was built
+// from, as listed by the module proxies of GOPROXY, if it is newer than the
+// running binary. The channel is
+// stable, the default, which ignores pre-releases, or latest; it may also
+// be set in the configuration file as update.channel. Versions retracted by
+// the go.mod file of the module's latest version are never installed.
+// Flag
+//	-offline
+// Never use the network. What -manifest, -repo and -stale need must already
+// be in the module cache or among the responses doc has cached, or for -repo
+// a clone in doc's cache; -update just reports the version running.
+// Flag
+//	-version
+// Print the version of doc, the revision and settings it was built with,
+// and the GOROOT it searches with that GOROOT's version of Go, which is
+// worth including in a report of a package found or missed.
+// Flag
+//	-out targets
+// Write the results to each of a comma-separated list of targets: stdout,
+// the default, for the usual output, and json=file, to write the results
+// to the file as JSON: an object whose schemaVersion field gives the
+// version of the format and whose results field holds an array of objects
+// holding each declaration's name, kind, package, position, URL, printed
+// text, the category of its root (see -roots), its visibility: exported,
+// internal, test or main, and whether its module is deprecated or retracted
+// and its stability tier. Thus
+//	doc -out stdout,json=/tmp/results.json strings Index
+// prints the results and saves them for later use by a script.
+// Within a schema version fields may be added, so readers should ignore
+// fields they do not know; any other change brings a new version.
+// For programs that handle many results, such as indexers, the target
+// gob=file writes the same results to the file in the gob encoding, as
+// each is found rather than all at the end: first a struct holding the
+// int SchemaVersion, then a struct for each result with the fields, named
+// as in the JSON, that the reader wants:
+//	doc -out gob=/tmp/results.gob -r '.*'
+// Flag
+//	-snapshot file.json
+// writes the results to the file, as -out json=file does, to be compared
+// with those of a later run:
+//	doc -snapshot before.json -r 'New.*'
+// Flag
+//	-compare file.json
+// runs the query and, instead of printing the results, lists the symbols,
+// by import path and name, that have appeared in them, marked +, or
+// disappeared from them, marked -, since the snapshot was taken, as after
+// upgrading a dependency:
+//	doc -compare before.json -r 'New.*'
+// As with diff, the exit status is 1 if there are differences.
+// Flag
+//	-schema
+// Print the JSON Schema describing the output of -out json=file.
+// Flag
+//	-show n
+// Results of searches that may match several declarations (regular
+// expressions, or no package named) are numbered. With a numeric
+// argument, -show prints the result with that number from the last such
+// search in full, with its body and examples. Results from -archive or
+// -stdin, whose files are gone once the search ends, cannot be shown.
+// Flag
+//	-full
+// prints the bodies of functions.
+// Flag
+//	-full-doc
+// prints every line of each doc comment. Otherwise, when results are
+// numbered, a doc comment longer than 20 lines, such as those describing
+// regular expression syntax or time layouts, is cut after 20 and followed by
+//	// …(N more lines, use -full-doc)
+// The number of lines may be set in the configuration file as
+// limit.doclines, or 0 for no limit.
+// Flag
+//	-history
+// lists the queries recorded, if the line
+//	history on
+// appears in the configuration file. With a numeric argument it runs
+// that query again. An argument @name is replaced by the search saved
+// in the configuration file by a line such as
+//	search ctxfuncs -r .*Context
+// Flag
+//	-migrate
+// rewrites the links to godoc.org and golang.org/pkg in the configuration
+// file and the query history to the same pages on pkg.go.dev, making
+// url.scheme pkgsite if url.host was one of those sites, and prints each
+// line changed.
+// Flag
+//	-stable-only
+// omits the symbols given a stability tier other than stable; those given
+// none count as stable.
+// Flag
+//	-assignable-to type [pkg]
+// lists the functions, in all packages or in the one named, and the
+// methods, as method values without their receivers, whose signatures
+// make them assignable to the function type: the ready-made handlers,
+// middlewares and callbacks that fit, as in
+//	doc -assignable-to 'func(http.ResponseWriter, *http.Request)'
+// Types are compared as written, each qualified by its package's name, so
+// an alias other than byte, rune or any is not seen through, and generic
+// functions are not considered.
+// Flag
+//	-matrix pkg.Type
+// prints a table of the fields of the type on each platform, GOOS/GOARCH,
+// listed by go tool dist list, as in
+//	doc -matrix syscall.Stat_t
+// Each platform's files are chosen, by name and build constraint, as the
+// go command would choose them without cgo. Platforms that define the
+// type alike are grouped into variants, labeled A, B and so on, and each
+// row gives a field's type in every variant, or - where it has no such
+// field.
+// Flag
+//	-filter expression
+// prints only the declarations for which the expression, written in Go,
+// is true, as in
+//	doc -filter 'kind == "func" && len(params) <= 2 && returns("error")' -r 'Read.*'
+// The expression may use the variables name, kind (const, var, func,
+// method or type), pkg (the import path), recv (a method's receiver type,
+// without *), doc, stability and visibility, all strings, and params and
+// results, lists of the types of the parameters and results as written;
+// the operators && || ! == != < <= > >=, and + of strings; and the
+// functions len(s or list), returns(type), takes(type), contains(s, sub)
+// and hasPrefix(s, prefix).
+// Flag
+//	-exclude regexp
+// omits symbols and packages whose name or import path matches the
+// regular expression, for instance
+//	doc -exclude '.*_test|encoding/gob' -r 'Marshal.*'
+// Flag
+//	-roots categories
+// searches only the roots of the comma-separated categories, in the order
+// listed: std, GOROOT; module, the module cache; workspace, the current
+// module and GOPATH; and vendored, any vendor directory. Numbered results
+// are labeled with their category, as in
+//	doc -roots workspace,std -r 'New.*Client'
+// Flag
+//	-exclude-dir dirs
+// does not search the comma-separated directories or, for those ending
+// in /..., the trees below them, as in
+//	doc -exclude-dir '$GOPATH/src/github.com/bigvendor/...' -r 'Parse.*'
+// Environment variables in the names are expanded. Directories may also
+// be excluded in the configuration file, one to a line:
+//	exclude.dir /home/me/go/src/mirror/...
+// Flag
+//	-include-generated-sdks
+// searches, when no package is named, the trees of generated API clients
+// such as cloud SDKs, whose thousands of packages would swamp the results
+// of everyday searches, and so are otherwise left out. They are named by
+// import path patterns, as in GOPRIVATE, in the configuration file:
+//	generated.sdk google.golang.org/api,github.com/aws/aws-sdk-go-v2/service
+// Flag
+//	-strict
+// reports directories holding more than one package. Of those, only the
+// package named for the directory is documented, or else the only one not
+// named main; if there is no such package, all are documented.
+// Flag
+//	-files a.go b.go name
+// parses just the named files, as one package, and looks in them for the
+// name, for code that is in no module or GOPATH, such as a script or a
+// file from a patch. With -pkg, every argument is a file.
+// Flag
+//	-dir directory name
+// looks for the name in the package in the directory, given with or
+// without a leading ./, which need be in no module or GOPATH, such as a
+// scratch directory for an experiment:
+//	doc -dir /tmp/scratch Parse
+// With -pkg, the name is omitted. Such a directory has no import path, so
+// the results have no URLs; the same holds for any directory named on the
+// command line that is outside the module, GOROOT and GOPATH.
+// Flag
+//	-stdin name
+// reads Go source from standard input and looks in it for the name, as in
+//	git show HEAD~:reader.go | doc -stdin NewReader
+// Flag
+//	-archive file
+// searches the packages in the zip file or tar file, which may be
+// compressed with gzip, instead of the module, GOROOT and GOPATH, so a
+// snapshot of a repository can be documented without extracting it:
+//	doc -archive review.tar.gz auth.Token
+// Flag
+//	-repo url
+// searches the packages of the repository at the URL instead, to inspect an
+// API before adding the dependency:
+//	doc -repo https://github.com/google/uuid NewString
+// The latest version is fetched from the module proxies of GOPROXY, or if
+// none has it the repository is cloned with git, and kept in the user's
+// cache directory. As the go command's GOVCS does, rules decide which
+// repositories may be cloned, and by which URL schemes: lines in the
+// configuration file such as
+//	repo.vcs github.com/myorg:https|ssh,*.corp.example.com:all,public:off
+// each hold comma-separated rules, pattern:schemes, whose pattern matches a
+// prefix of the repository's host and path, as in GOPRIVATE, or is public
+// or private, meaning the repositories not matched or matched by GOPRIVATE.
+// The schemes are separated by | or are all or off. The first
+// rule that matches decides. After those in the file come the defaults,
+//	public:https,private:https|ssh
+// and a repository no rule matches is not cloned.
+// Flag
+//	-byfile
+// names the file, within its package directory, that declares each result,
+// and lists the matches of a regular expression in a named package by file,
+// in the order of the file names, rather than by kind, to mirror how the
+// package's maintainers have divided it.
+// Flag
+//	-expand
+// prints every signature in full. Otherwise, when results are numbered, a
+// function whose signature is wider than 100 columns has the constraints of
+// its type parameters, then its parameters, then its results elided with …
+// until it fits; -show n prints such a result in full.
+// Flag
+//	-at file.go:line:column
+// type-checks the package holding the file and prints the documentation of
+// what the identifier at the position, its column counted in bytes from 1,
+// refers to, as an editor shows when the cursor rests on a name.
+// Flag
+//	-for-file file.go
+// type-checks the package holding the file and prints, once each and
+// grouped by import path, the exported constants, variables, types,
+// functions and methods of other packages that the file refers to, each
+// with the synopsis of its documentation: a primer to read before
+// reviewing or changing unfamiliar code, as in
+//	doc -for-file handler.go
+// Fields are not listed; the types that hold them are, if named.
+// Flag
+//	-sig-only
+// prints just the declaration of each match, on one line, without comments,
+// positions or URLs, for quick-reference sheets and scripts.
+// Flag
+//	-html-fragment
+// prints the documentation of each match as a fragment of HTML, with no
+// page around it, for embedding in a wiki or a chat: a div holding the
+// declaration, linked to its source, and the rendered doc comment. If a
+// theme is configured, as for -stale, or a style sheet of one's own, as in
+//	theme.css /home/me/doc.css
+// the fragments are preceded by a style element holding the theme's style
+// sheet and then one's own, which apply to the doc class of the divs.
+// Flag
+//	-chat
+// formats the output for pasting into chat tools such as Slack: each
+// declaration in a code block, its doc comment with headings in bold and code
+// fenced, the whole cut to 4000 characters and then ending with a link to the
+// full documentation of the first match on pkg.go.dev.
+// Flag
+//	-screen-reader
+// prints each match as lines that say what they hold, for screen readers
+// and braille displays: Symbol:, Kind:, Package:, Declaration: and
+// Documentation:, with paragraphs on one line, headings, list items and
+// code labeled, and no indentation, alignment by spaces or color, as in
+// -stale's diffs. Declarations and code of several lines are introduced
+// with their length and closed with an end line.
+// Flag
+//	-import
+// prints, instead of the documentation of the single match, the import
+// declaration that brings in its package and an example of its use:
+//	doc -import strings.cut
+// prints
+//	import "strings"
+//
+//	strings.Cut(s, sep)
+// Flag
+//	-hover
+// prints, instead of the documentation of the single match, a JSON object
+// holding what an editor shows in a popup when the cursor rests on the
+// name: its package, kind, signature, doc comment as text reflowed to 80
+// columns and as Markdown, its first example, its Deprecated: paragraph,
+// whether its module is deprecated or retracted, its URL and its position:
+//	doc -hover strings.Cut
+// Flag
+//	-versions pkg.Name
+// reports, from the API files in GOROOT/api, the release of Go that added
+// the standard library symbol, which may be a method or field given as
+// pkg.Type.Name, and any release that changed its declaration or
+// deprecated it, for code that must build with older releases:
+//	doc -versions strings.CutPrefix
+// Flag
+//	-all-versions importpath.Name
+// prints the documentation of the symbol in every version of its module
+// that the module cache holds, oldest first, each labeled module@version,
+// for tracking down a change in behavior after an upgrade. A version whose
+// documentation is unchanged is said to be the same as the one before it.
+// The import path and the name may also be given as two arguments:
+//	doc -all-versions golang.org/x/mod/semver Compare
+// Flag
+//	-added go1.N [pkg]
+// lists the standard library symbols that the release of Go added, from
+// the API files in GOROOT/api, with the first sentence of the
+// documentation of each, grouped by package. A package argument, given as
+// an import path or its last element, restricts the list to that package:
+//	doc -added go1.22 slices
+// Flag
+//	-generate pkg
+// lists, with their positions, the //go:generate directives in the files
+// of the package, including its tests, which otherwise appear nowhere in
+// its documentation.
+// Flag
+//	-directives pkg
+// lists, grouped by file, the directives in the package that change how
+// it is built: //go: lines such as //go:nosplit, //go:linkname and
+// //go:build, //export, and the preambles of import "C" with their #cgo
+// lines.
+// Flag
+//	-aliases-of pkg.Type
+// lists the exported type aliases in GOROOT, the module and GOPATH that
+// denote the type, directly or through other aliases, such as the
+// forwarding aliases left behind when a type moves:
+//	doc -aliases-of fs.FileInfo
+// Flag
+//	-convertible pkgA.T pkgB.U
+// reports whether a value of type T can be assigned or converted to U,
+// following the rules of the language, and, if both are structs, compares
+// them field by field, with the documentation of each field, as when
+// copying between parallel types of different layers of a program.
+// Flag
+//	-iface-diff pkgA.I pkgB.J
+// compares the method sets of the two interfaces, listing with their
+// documentation the methods of I missing from J, those extra in J, and
+// those whose signatures differ, to reconcile a small local interface
+// with a standard one:
+//	doc -iface-diff io.ReadWriter ./myio.ReadWriter
+// Flag
+//	-minify-iface pkg.Type Method,Method...
+// prints the smallest interfaces of the standard library, as the API files
+// in GOROOT/api record them, that the type or a pointer to it implements
+// and that include the listed methods: the type to accept instead of
+// pkg.Type when a function uses only those methods:
+//	doc -minify-iface bytes.Buffer Read,Write
+// If there is none, it prints the interface to declare.
+// Flag
+//	-examples-for pkg.Name
+// prints the few best examples, from the tests of all the packages in
+// GOROOT, the module and GOPATH, whose code uses the symbol, which may be
+// a method given as pkg.Type.Method. The package may be given by import
+// path, as in math/rand/v2.N, to tell it from others of the same name.
+// Examples from other packages, which show it in real use, come before
+// those of its own package.
+// Flag
+//	-complete prefix
+// prints, one a line, the completions of the prefix of an argument, for
+// shell and editor completion: package names, or, once the package is given,
+// as in strings.Cu, its exported symbols and methods. Case is ignored; the
+// candidates closest in case, then the shortest, are printed first.
+// Flag
+//	-exists
+// prints nothing, but exits with status 0 if the name resolves and 1 if
+// not, so a script can test for an API:
+//	if doc -exists strings.CutPrefix; then ...
+// Flag
+//	-clip
+// copies what is printed to the system clipboard as well, using pbcopy on
+// macOS, clip on Windows, and wl-copy, xclip or xsel on Linux. With the
+// restricting flags it copies just a part, so
+//	doc -clip -url strings.Cut
+// copies the link and
+//	doc -clip -import strings.Cut
+// the import declaration and example.
+// Flag
+//	-time
+// reports on standard error, once the query is answered, how many
+// directories were scanned, files parsed and packages type-checked, how many
+// lookups were answered from a cache, and the time spent parsing, type
+// checking and otherwise, to show why a query is slow.
+// Flag
+//	-record bundle.zip
+// answers the query as usual and writes a zip file holding what is needed
+// to reproduce it elsewhere, to attach to a bug report: the arguments, the
+// working directory, the GOROOT, GOPATH and other Go environment variables,
+// the configuration file, the output and exit status, and every source file
+// the query parsed. The variables naming proxies, checksum databases and
+// private modules, which may carry credentials, are left out.
+// Flag
+//	-replay bundle.zip
+// runs again the query recorded in the bundle, with its files in place of
+// the trees and configuration it searched. The compiled standard library is
+// not in the bundle, so type information about imported packages may differ.
+// As a bundle may come from anyone, the query is run offline and without
+// cgo; only the recorded GO111MODULE, GOOS, GOARCH and NO_COLOR are
+// restored, and GOFLAGS is cleared; and a query that runs commands, uses
+// the network or writes files, with -perf, -cover-run, -clip, -history,
+// -matrix, -update, -repo, -stale, -manifest, -o, -force, -out, -snapshot,
+// -migrate, -record or -replay, is refused.
 package main // import "robpike.io/cmd/doc"
 
 import (
@@ -52,18 +736,16 @@ import (
 	"go/parser"
 	"go/printer"
 	"go/token"
+	"go/types"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
-	"runtime"
+	"sort"
+	"strconv"
 	"strings"
-
-	// TODO: Change this to use the new go/types. Can't do that
-	// until MethodSetCache is available in the new repository.
-	_ "golang.org/x/tools/go/gcimporter"
-	"golang.org/x/tools/go/types"
-	"golang.org/x/tools/go/types/typeutil"
+	"time"
 )
 
 const usageDoc = `Find documentation for names.
@@ -73,13 +755,16 @@ usage:
 	doc name       # "doc isupper" finds unicode.IsUpper
 	doc -pkg pkg   # "doc fmt"
 	doc -r expr    # "doc -r '.*exported'"
-pkg is the last component of any package, e.g. fmt, parser
+pkg is the last component of any package, e.g. fmt, parser,
+or a directory such as . or ./internal/auth
 name is the name of an exported symbol; case is ignored in matches.
+A name such as Server.TLSConfig.MinVersion follows the types of fields.
 
 The name may also be a regular expression to select which names
 to match. In regular expression searches, case is ignored and
 the pattern must match the entire name, so ".?print" will match
-Print, Fprint and Sprint but not Fprintf.
+Print, Fprint and Sprint but not Fprintf. When a pattern is matched
+in a named package, the results are listed by kind.
 
 Flags
 	-c(onst) -f(unc) -i(nterface) -m(ethod) -s(truct) -t(ype) -v(ar)
@@ -91,11 +776,292 @@ Flag
 	-r
 takes a single argument (no package), a name or regular expression
 to search for in all packages.
+Flag
+	-explain
+takes a Go expression and prints the documentation of each call in it.
+Flag
+	-method-of pkg.Type
+takes an interface method, e.g. io.Writer.Write, and prints the method
+of the type that satisfies it.
+Flags
+	-tests -bench -fuzz
+take a package and list its tests, benchmarks or fuzz targets and subtests.
+Flags
+	-calls -callers [-depth n]
+list the functions the named function calls, or those that call it.
+Flag
+	-unused
+lists exported symbols of the module, or of the packages in the optional
+pattern such as ./..., that no other package refers to.
+Flag
+	-collisions
+lists exported names declared in more than one package of the module, or
+of the optional pattern, with their positions and synopses.
+Flag
+	-lsif
+writes an LSIF index of the exported declarations of the module, or of the
+optional pattern, with their docs and references.
+Flag
+	-overview pkg
+prints the synopsis, most used types and their constructors, and most
+used functions of the package.
+Flag
+	-cheatsheet pkg
+prints the signatures of the package's functions, grouped by task, and its
+types and methods, one to a line.
+Flag
+	-ptr
+lists the methods, and interfaces satisfied, only by *T for a type T.
+Flag
+	-behavior
+adds the panics and sentinel error returns found in a function's body.
+Flag
+	-concurrency
+notes whether a function starts goroutines, takes a context or uses channels.
+Flag
+	-risk
+notes uses of unsafe and reflect by a function, or with -pkg a package.
+Flag
+	-perf
+adds whether a function can be inlined and which parameters escape.
+Flags
+	-cover profile
+	-cover-run
+add each function's statement coverage, from the profile or running its tests.
+Flag
+	-snippet
+adds code that calls the function, as a start for writing the call.
+Flag
+	-license
+adds the license and copyright line governing each result.
+Flag
+	-verbose-doc
+adds examples, constructors, signature types and linked symbols.
+Flag
+	-follow
+prints the documentation of the function called by an undocumented wrapper.
+Flag
+	-inherit-doc
+prints the documentation of the interface method an undocumented method implements.
+Flag
+	-rank
+orders results by exact match, standard library, import path length,
+popularity and deprecation, with weights set in the configuration file.
+Flag
+	-why
+explains how each result matched and, with -rank, how it was scored.
+Flag
+	-C n
+prints n numbered lines of source around each declaration.
+Flag
+	-raw
+prints doc comments and declarations exactly as in the source.
+Flag
+	-lang code
+prints translated doc comments where available, e.g. from doc.ja.json.
+Flag
+	-local
+restricts the search to the packages of the current module.
+Flag
+	-all-roots
+searches GOROOT and GOPATH even when the current module matched.
+Flag
+	-manifest go.mod [-all] [-force] -o dir
+writes the documentation of the direct dependencies of go.mod to dir,
+skipping files newer than their packages' source unless -force is set.
+Flag
+	-rename-impact pkg.Name
+lists, by module, the references a rename of pkg.Name would change.
+Flag
+	-lint [pattern]
+reports doc comments whose doc links, or first word, name identifiers
+that do not exist.
+Flag
+	-stale [version]
+reports symbols of the current module whose doc comments differ from
+those of its latest (or the given) published version, with diffs
+(side by side with -width n).
+Flag
+	-update [stable|latest]
+installs the newest release of doc if it is newer than this one.
+Flag
+	-offline
+never uses the network.
+Flag
+	-version
+prints the version and build of doc and the version of Go in GOROOT.
+Flag
+	-out targets
+writes the results to each of a comma-separated list of targets: stdout
+(the default), json=file, which writes them to the file as JSON, and
+gob=file, which streams them to the file as gobs.
+Flag
+	-snapshot file.json
+writes the results to the file as JSON, for a later -compare.
+Flag
+	-compare file.json
+lists the symbols that have appeared in (+) or disappeared from (-) the
+results since the snapshot in the file.
+Flag
+	-schema
+prints the JSON Schema of the output of -out json=file.
+Flag
+	-show n
+prints in full result n of the last search, whose results are numbered.
+Flag
+	-full
+prints the bodies of functions.
+Flag
+	-full-doc
+prints long doc comments in full, not cut to 20 lines, when results are numbered.
+Flag
+	-history [n]
+lists recorded queries ("history on" in the configuration file),
+or runs query n again. An argument @name is replaced by the search
+saved in the configuration file as "search name args...".
+Flag
+	-migrate
+rewrites links to godoc.org and golang.org/pkg in the configuration
+and history to pkg.go.dev.
+Flag
+	-stable-only
+omits symbols with a stability tier, set by "// Stability: tier" or in
+the configuration file, other than stable.
+Flag
+	-assignable-to type [pkg]
+lists the functions and methods assignable to the function type.
+Flag
+	-matrix pkg.Type
+prints a table of the type's fields, and their types, across the
+GOOS/GOARCH platforms, with - where a platform lacks a field.
+Flag
+	-filter expression
+prints only the declarations for which the Go expression is true, as in
+-filter 'kind == "func" && len(params) <= 2 && returns("error")'.
+Flag
+	-exclude regexp
+omits symbols and packages whose name or import path matches regexp.
+Flag
+	-roots categories
+searches only roots of the listed categories (std, module, workspace,
+vendored), in that order.
+Flag
+	-exclude-dir dirs
+does not search the comma-separated directories, or trees ending /....
+Flag
+	-include-generated-sdks
+searches, with no package named, the generated SDK trees named by
+"generated.sdk patterns" in the configuration file.
+Flag
+	-strict
+reports directories holding more than one package. Only the package named
+for the directory (or else the one not named main) is documented.
+Flag
+	-files a.go b.go name
+parses just the named files, as one package, and looks in them for name.
+Flag
+	-dir directory name
+looks for name in the package in the directory, which may be anywhere.
+Flag
+	-stdin name
+reads Go source from standard input and looks in it for name.
+Flag
+	-archive file
+searches the packages in the zip or tar(.gz) file instead of the
+module, GOROOT and GOPATH.
+Flag
+	-repo url
+searches the packages of the repository at the URL, fetched through the
+module proxy or cloned with git, instead of the module, GOROOT and GOPATH.
+Flag
+	-byfile
+names the file declaring each result, and lists the matches of a
+regular expression in a named package by file rather than by kind.
+Flag
+	-expand
+prints in full the long signatures that are otherwise shortened with …
+when results are numbered.
+Flag
+	-at file.go:line:column
+prints the documentation of what the identifier at the position refers to.
+Flag
+	-for-file file.go
+prints the synopses of the names from other packages that the file uses.
+Flag
+	-sig-only
+prints just the declaration of each match, on one line.
+Flag
+	-html-fragment
+prints the documentation of each match as a fragment of HTML.
+Flag
+	-chat
+formats the output for pasting into chat tools such as Slack.
+Flag
+	-screen-reader
+prints labeled lines, without alignment or color, for screen readers.
+Flag
+	-import
+prints the import declaration for the single match and an example of its use.
+Flag
+	-hover
+prints, as JSON, the signature, doc, first example, deprecation and URL of
+the single match, for an editor's hover popup.
+Flag
+	-versions pkg.Name
+reports the Go releases that added, changed or deprecated a standard
+library symbol.
+Flag
+	-all-versions importpath.Name
+prints the symbol's documentation in each cached version of its module.
+Flag
+	-added go1.N [pkg]
+lists the standard library symbols added by a release of Go, optionally
+only those of the package.
+Flag
+	-generate pkg
+lists the //go:generate directives of the package.
+Flag
+	-directives pkg
+lists, by file, the compiler, linker and cgo directives of the package.
+Flag
+	-aliases-of pkg.Type
+lists the exported type aliases, anywhere, that denote the type.
+Flag
+	-convertible pkgA.T pkgB.U
+reports whether T converts or assigns to U and compares their fields.
+Flag
+	-iface-diff pkgA.I pkgB.J
+compares the method sets of two interfaces.
+Flag
+	-minify-iface pkg.Type Method,Method...
+prints the smallest standard interfaces of the type with the methods.
+Flag
+	-examples-for pkg.Name
+prints examples, from the tests of any package, whose code uses the symbol.
+Flag
+	-complete prefix
+prints the completions of a package name or pkg.Symbol, one a line.
+Flag
+	-exists
+prints nothing; the exit status is 0 if the name resolves, 1 if not.
+Flag
+	-clip
+copies what is printed to the system clipboard as well.
+Flag
+	-time
+reports the directories, files and packages examined and the time taken.
+Flag
+	-record bundle.zip
+answers the query and writes a zip file from which -replay reproduces it.
+Flag
+	-replay bundle.zip
+runs again the query recorded in the bundle, offline, refusing flags
+that run commands or write files.
 `
 
 func usage() {
 	fmt.Fprintf(os.Stderr, usageDoc)
-	os.Exit(2)
+	exit(2)
 }
 
 var (
@@ -112,12 +1078,111 @@ var (
 
 var (
 	// If none is set, all are set.
-	docFlag    = flag.Bool("doc", false, "restrict output to documentation only")
-	srcFlag    = flag.Bool("src", false, "restrict output to source file only")
-	urlFlag    = flag.Bool("url", false, "restrict output to godoc URL only")
-	regexpFlag = flag.Bool("r", false, "single argument is a regular expression for a name")
+	docFlag              = flag.Bool("doc", false, "restrict output to documentation only")
+	srcFlag              = flag.Bool("src", false, "restrict output to source file only")
+	urlFlag              = flag.Bool("url", false, "restrict output to godoc URL only")
+	regexpFlag           = flag.Bool("r", false, "single argument is a regular expression for a name")
+	explainFlag          = flag.Bool("explain", false, "document each call in the expression argument")
+	methodOfFlag         = flag.String("method-of", "", "print the method of this type satisfying the interface method argument")
+	benchFlag            = flag.Bool("bench", false, "list the benchmarks of the package")
+	fuzzFlag             = flag.Bool("fuzz", false, "list the fuzz targets of the package")
+	testsFlag            = flag.Bool("tests", false, "list the tests of the package, with their subtests")
+	generateFlag         = flag.Bool("generate", false, "list the //go:generate directives of the package")
+	aliasesOfFlag        = flag.Bool("aliases-of", false, "list the exported type aliases that denote the type pkg.Type")
+	convertibleFlag      = flag.Bool("convertible", false, "report whether pkgA.T can be assigned or converted to pkgB.U, comparing their fields")
+	ifaceDiffFlag        = flag.Bool("iface-diff", false, "compare the method sets of the interfaces pkgA.I and pkgB.J")
+	minifyIfaceFlag      = flag.Bool("minify-iface", false, "print the smallest standard interfaces of pkg.Type with the listed methods")
+	examplesForFlag      = flag.Bool("examples-for", false, "print examples, from the tests of any package, that use pkg.Name")
+	directivesFlag       = flag.Bool("directives", false, "list, by file, the compiler and cgo directives of the package")
+	callsFlag            = flag.Bool("calls", false, "list the functions the named function calls")
+	callersFlag          = flag.Bool("callers", false, "list the functions that call the named function")
+	depthFlag            = flag.Int("depth", 1, "depth to which -calls follows calls within the package")
+	unusedFlag           = flag.Bool("unused", false, "list exported symbols no other package refers to")
+	lsifFlag             = flag.Bool("lsif", false, "write an LSIF index of the exported declarations of the module or pattern")
+	collisionsFlag       = flag.Bool("collisions", false, "list exported names declared in more than one package")
+	overviewFlag         = flag.String("overview", "", "print a summary of the `pkg` to start reading it from")
+	cheatsheetFlag       = flag.String("cheatsheet", "", "print a one-page listing of the signatures of the `pkg`, grouped by task")
+	ptrFlag              = flag.Bool("ptr", false, "list methods and interfaces of *T that T lacks")
+	behaviorFlag         = flag.Bool("behavior", false, "show panics and sentinel errors found in function bodies")
+	concurrencyFlag      = flag.Bool("concurrency", false, "note goroutines, contexts and channels in function contracts")
+	riskFlag             = flag.Bool("risk", false, "note uses of unsafe and reflect in functions and packages")
+	perfFlag             = flag.Bool("perf", false, "add the compiler's inlining and escape analysis of functions; builds the package")
+	coverFlag            = flag.String("cover", "", "annotate functions with their statement coverage from the `profile`")
+	coverRunFlag         = flag.Bool("cover-run", false, "annotate functions with their statement coverage, running the package's tests")
+	snippetFlag          = flag.Bool("snippet", false, "add code to call each function matched")
+	licenseFlag          = flag.Bool("license", false, "show the license governing each result")
+	verboseDocFlag       = flag.Bool("verbose-doc", false, "add examples, constructors, signature types and links")
+	followFlag           = flag.Bool("follow", false, "follow undocumented wrappers to the function they call")
+	inheritDocFlag       = flag.Bool("inherit-doc", false, "print, for undocumented methods, the documentation of the interface method they implement")
+	rankFlag             = flag.Bool("rank", false, "order results by how many packages import theirs")
+	contextFlag          = flag.Int("C", 0, "print this many lines of source context around each declaration")
+	rawFlag              = flag.Bool("raw", false, "print comments and declarations exactly as in the source")
+	langFlag             = flag.String("lang", "", "print doc comments translated into this language where available")
+	localFlag            = flag.Bool("local", false, "search only the packages of the current module")
+	manifestFlag         = flag.String("manifest", "", "write the docs of the dependencies in this go.mod file to the -o directory")
+	allFlag              = flag.Bool("all", false, "with -manifest, include every package of each dependency")
+	forceFlag            = flag.Bool("force", false, "with -manifest, rewrite files that are up to date")
+	outDirFlag           = flag.String("o", "", "output directory for -manifest")
+	renameImpactFlag     = flag.Bool("rename-impact", false, "list the references to pkg.Name that a rename would change")
+	lintFlag             = flag.Bool("lint", false, "report doc comments that refer to nonexistent identifiers")
+	staleFlag            = flag.Bool("stale", false, "compare the module's docs with its published version")
+	widthFlag            = flag.Int("width", 0, "show -stale diffs side by side in this many `columns`")
+	updateFlag           = flag.Bool("update", false, "install the newest release of doc on the stable or latest channel")
+	offlineFlag          = flag.Bool("offline", false, "never use the network")
+	versionFlag          = flag.Bool("version", false, "print the version and build of doc and the version of Go in GOROOT")
+	allRootsFlag         = flag.Bool("all-roots", false, "search GOROOT and GOPATH even if closer packages match")
+	outFlag              = flag.String("out", "stdout", "comma-separated output targets: stdout, json=file, gob=file")
+	snapshotFlag         = flag.String("snapshot", "", "write the results to this `file`, as JSON, for a later -compare")
+	compareFlag          = flag.String("compare", "", "report the symbols that appeared in or disappeared from the results since the snapshot in this `file`")
+	schemaFlag           = flag.Bool("schema", false, "print the JSON Schema of the output of -out json=file")
+	showFlag             = flag.Bool("show", false, "print in full the numbered result of the last search")
+	fullFlag             = flag.Bool("full", false, "print function bodies")
+	fullDocFlag          = flag.Bool("full-doc", false, "print long doc comments in full when there are several results")
+	historyFlag          = flag.Bool("history", false, "list recorded queries, or rerun the numbered one")
+	migrateFlag          = flag.Bool("migrate", false, "rewrite links to godoc.org and golang.org/pkg in the configuration and history")
+	excludeFlag          = flag.String("exclude", "", "omit symbols and import paths matching this regular expression")
+	rootsFlag            = flag.String("roots", "", "comma-separated root categories to search, in order: std, module, workspace, vendored")
+	excludeDirFlag       = flag.String("exclude-dir", "", "comma-separated directories, or trees ending /..., not to search")
+	includeGeneratedFlag = flag.Bool("include-generated-sdks", false, "search the generated SDK trees named in the configuration file")
+	strictFlag           = flag.Bool("strict", false, "report directories holding more than one package")
+	filesFlag            = flag.Bool("files", false, "document the named Go files, parsed as one package")
+	dirFlag              = flag.String("dir", "", "look for the name in the package in this `directory`, which need be in no module or GOPATH")
+	stdinFlag            = flag.Bool("stdin", false, "document the Go source read from standard input")
+	archiveFlag          = flag.String("archive", "", "search this zip or tar file instead of the module, GOROOT and GOPATH")
+	repoFlag             = flag.String("repo", "", "search the repository at this URL instead of the module, GOROOT and GOPATH")
+	byFileFlag           = flag.Bool("byfile", false, "name the file declaring each result; list a package's matches by file")
+	expandFlag           = flag.Bool("expand", false, "print long signatures in full when listing results")
+	htmlFragmentFlag     = flag.Bool("html-fragment", false, "print the documentation of each match as a fragment of HTML")
+	screenReaderFlag     = flag.Bool("screen-reader", false, "print labeled lines, without alignment or color, for screen readers and braille displays")
+	chatFlag             = flag.Bool("chat", false, "format the output for pasting into chat tools such as Slack, cut to 4000 characters")
+	sigOnlyFlag          = flag.Bool("sig-only", false, "print only the declaration of each match, one line each")
+	versionsFlag         = flag.Bool("versions", false, "report the Go releases that added or changed the standard library symbol pkg.Name")
+	allVersionsFlag      = flag.Bool("all-versions", false, "print the documentation of importpath.Name in every version of its module in the module cache")
+	addedFlag            = flag.String("added", "", "list the standard library symbols added by the Go `release`, such as go1.22")
+	completeFlag         = flag.Bool("complete", false, "print the completions of the prefix of a package or pkg.Symbol, one a line")
+	existsFlag           = flag.Bool("exists", false, "print nothing; exit with status 0 if the name resolves, 1 if not")
+	clipFlag             = flag.Bool("clip", false, "also copy the output to the system clipboard")
+	importFlag           = flag.Bool("import", false, "print the import declaration for the single match and an example of its use")
+	hoverFlag            = flag.Bool("hover", false, "print, as JSON, what an editor shows on hovering over the single match")
+	timeFlag             = flag.Bool("time", false, "report the work done for the query and the time it took")
+	forFileFlag          = flag.String("for-file", "", "print the synopses of the names from other packages that this Go `file` refers to")
+	atFlag               = flag.String("at", "", "print the documentation of the identifier at this file.go:line:column")
+	stableOnlyFlag       = flag.Bool("stable-only", false, "omit symbols with a stability tier other than stable")
+	whyFlag              = flag.Bool("why", false, "explain how each result matched and, with -rank, its score")
+	matrixFlag           = flag.Bool("matrix", false, "print a table of the fields of pkg.Type on every GOOS/GOARCH")
+	assignableFlag       = flag.String("assignable-to", "", "find the functions and methods whose values are assignable to this function `type`")
+	filterFlag           = flag.String("filter", "", "print only the declarations for which this Go `expression` is true")
+	recordFlag           = flag.String("record", "", "run the query and write a `bundle` zip file to reproduce it with -replay")
+	replayFlag           = flag.String("replay", "", "run again the query recorded in the `bundle` zip file")
 )
 
+// An excludedDir is a directory, or with tree set a whole tree, that is
+// not searched, from -exclude-dir or the configuration file.
+type excludedDir struct {
+	dir  string
+	tree bool
+}
+
 func init() {
 	flag.BoolVar(constantFlag, "c", false, "alias for -const")
 	flag.BoolVar(functionFlag, "f", false, "alias for -func")
@@ -131,66 +1196,729 @@ func init() {
 
 func main() {
 	flag.Usage = usage
-	flag.Parse()
-	if !(*constantFlag || *functionFlag || *interfaceFlag || *methodFlag || *packageFlag || *structFlag || *typeFlag || *variableFlag) { // none set
-		*constantFlag = true
-		*functionFlag = true
-		*methodFlag = true
-		// Not package! It's special.
-		*typeFlag = true
-		*variableFlag = true
-	}
-	if !(*docFlag || *srcFlag || *urlFlag) {
-		*docFlag = true
-		*srcFlag = true
-		*urlFlag = true
+	flag.CommandLine.Parse(expandSearches(os.Args[1:]))
+	checkReplayFlags()
+	if *recordFlag != "" {
+		record(*recordFlag, withoutFlag(os.Args[1:], "record"))
+	}
+	if *replayFlag != "" {
+		if flag.NArg() != 0 {
+			usage()
+		}
+		replay(*replayFlag)
+		return
+	}
+	if historyEnabled() && !replaying && !*historyFlag && !*migrateFlag {
+		recordHistory(os.Args[1:])
+	}
+	s := newSession(os.Stdout)
+	defer s.recoverCrash()
+	exitUnwinds = true
+	if *timeFlag {
+		defer stats.print(os.Stderr)
+	}
+	s.setOutputs(*outFlag)
+	defer s.closeOutputs()
+	if *snapshotFlag != "" {
+		if s.jsonOut != "" {
+			fmt.Fprintf(os.Stderr, "doc: -snapshot: results already written to %s\n", s.jsonOut)
+			exit(2)
+		}
+		s.jsonOut = *snapshotFlag
+	}
+	if *existsFlag {
+		s.out = io.Discard
+	}
+	if *clipFlag {
+		clip := new(bytes.Buffer)
+		s.out = io.MultiWriter(s.out, clip)
+		defer copyToClipboard(clip)
+	}
+	if *chatFlag {
+		defer s.chatOutput()()
+	}
+	if *migrateFlag {
+		if flag.NArg() != 0 {
+			usage()
+		}
+		s.migrate()
+		return
+	}
+	if *historyFlag {
+		if flag.NArg() > 1 {
+			usage()
+		}
+		s.history(flag.Arg(0))
+		return
+	}
+	if *compareFlag != "" {
+		readSnapshot(*compareFlag) // Check it before searching.
+		s.compareTo = *compareFlag
+		s.compareOut, s.out = s.out, io.Discard // The report, not the results.
+	}
+	if *htmlFragmentFlag {
+		fmt.Fprint(s.out, htmlStyle())
+	}
+	if *excludeFlag != "" {
+		var err error
+		s.exclude, err = compileName(*excludeFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: -exclude: %s\n", err)
+			exit(2)
+		}
+	}
+	if *rootsFlag != "" {
+		s.setRoots(*rootsFlag)
+	}
+	if *filterFlag != "" {
+		s.setFilter(*filterFlag)
+	}
+	if *assignableFlag != "" {
+		s.setAssignable(*assignableFlag)
+	}
+	for _, dir := range append(config["exclude.dir"], strings.Split(*excludeDirFlag, ",")...) {
+		dir = os.ExpandEnv(strings.TrimSpace(dir))
+		if dir == "" {
+			continue
+		}
+		dir, tree := strings.CutSuffix(filepath.ToSlash(dir), "/...")
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs // Directories are walked by absolute name.
+		}
+		s.excludedDirs = append(s.excludedDirs, excludedDir{filepath.Clean(dir), tree})
+	}
+	if *schemaFlag {
+		if flag.NArg() != 0 {
+			usage()
+		}
+		fmt.Fprint(s.out, resultSchema)
+		return
+	}
+	if *showFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.show(flag.Arg(0))
+		return
+	}
+	if *manifestFlag != "" {
+		if flag.NArg() != 0 || *outDirFlag == "" {
+			usage()
+		}
+		s.manifest(*manifestFlag, *outDirFlag)
+		return
+	}
+	if *renameImpactFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.renameImpact(flag.Arg(0))
+		return
+	}
+	if *lintFlag {
+		if flag.NArg() > 1 {
+			usage()
+		}
+		s.lint(flag.Arg(0))
+		return
+	}
+	if *versionFlag {
+		if flag.NArg() != 0 {
+			usage()
+		}
+		s.printVersion()
+		return
+	}
+	if *versionsFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.apiVersions(flag.Arg(0))
+		return
+	}
+	if *allVersionsFlag {
+		var pkgPath, name string
+		switch flag.NArg() {
+		case 1:
+			var ok bool
+			if pkgPath, name, ok = splitImportSymbol(flag.Arg(0)); !ok {
+				fmt.Fprintf(os.Stderr, "doc: -all-versions: want importpath.Name, not %s\n", flag.Arg(0))
+				exit(2)
+			}
+		case 2:
+			pkgPath, name = flag.Arg(0), flag.Arg(1)
+		default:
+			usage()
+		}
+		s.allVersions(pkgPath, name)
+		return
+	}
+	if *matrixFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.matrix(flag.Arg(0))
+		return
+	}
+	if *addedFlag != "" {
+		if flag.NArg() > 1 {
+			usage()
+		}
+		s.addedAPI(*addedFlag, flag.Arg(0))
+		return
+	}
+	if *updateFlag {
+		if flag.NArg() > 1 {
+			usage()
+		}
+		s.update(flag.Arg(0))
+		return
+	}
+	if *staleFlag {
+		if flag.NArg() > 1 {
+			usage()
+		}
+		s.stale(flag.Arg(0))
+		return
+	}
+	if *overviewFlag != "" {
+		if flag.NArg() != 0 {
+			usage()
+		}
+		s.overview(*overviewFlag)
+		return
+	}
+	if *cheatsheetFlag != "" {
+		if flag.NArg() != 0 {
+			usage()
+		}
+		s.cheatsheet(*cheatsheetFlag)
+		return
+	}
+	if *unusedFlag {
+		if flag.NArg() > 1 {
+			usage()
+		}
+		s.unused(flag.Arg(0))
+		return
+	}
+	if *collisionsFlag {
+		if flag.NArg() > 1 {
+			usage()
+		}
+		s.collisions(flag.Arg(0))
+		return
+	}
+	if *lsifFlag {
+		if flag.NArg() > 1 {
+			usage()
+		}
+		s.lsif(flag.Arg(0))
+		return
+	}
+	if *benchFlag || *fuzzFlag || *testsFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		var prefixes []string
+		if *testsFlag {
+			prefixes = append(prefixes, "Test")
+		}
+		if *benchFlag {
+			prefixes = append(prefixes, "Benchmark")
+		}
+		if *fuzzFlag {
+			prefixes = append(prefixes, "Fuzz")
+		}
+		s.listTests(s.packageDirs(flag.Arg(0)), prefixes...)
+		return
+	}
+	if *generateFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.listGenerate(s.packageDirs(flag.Arg(0)))
+		return
+	}
+	if *directivesFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.listDirectives(s.packageDirs(flag.Arg(0)))
+		return
+	}
+	if *aliasesOfFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.aliasesOf(flag.Arg(0))
+		return
+	}
+	if *convertibleFlag {
+		if flag.NArg() != 2 {
+			usage()
+		}
+		s.convertible(flag.Arg(0), flag.Arg(1))
+		return
+	}
+	if *ifaceDiffFlag {
+		if flag.NArg() != 2 {
+			usage()
+		}
+		s.ifaceDiff(flag.Arg(0), flag.Arg(1))
+		return
+	}
+	if *minifyIfaceFlag {
+		if flag.NArg() != 2 {
+			usage()
+		}
+		s.minifyIface(flag.Arg(0), flag.Arg(1))
+		return
+	}
+	if *examplesForFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.examplesFor(flag.Arg(0))
+		return
+	}
+	if *completeFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.complete(flag.Arg(0))
+		return
+	}
+	if *explainFlag {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.explain(flag.Arg(0))
+		s.saveResults()
+		return
+	}
+	if *methodOfFlag != "" {
+		if flag.NArg() != 1 {
+			usage()
+		}
+		s.methodOf(*methodOfFlag, flag.Arg(0))
+		s.saveResults()
+		return
+	}
+	if *forFileFlag != "" {
+		if flag.NArg() != 0 {
+			usage()
+		}
+		s.forFile(*forFileFlag)
+		return
+	}
+	if *atFlag != "" {
+		if flag.NArg() != 0 {
+			usage()
+		}
+		s.lookAt(*atFlag)
+		return
+	}
+	if *filesFlag {
+		args, name := flag.Args(), ""
+		if !s.pkgDoc && len(args) > 0 {
+			args, name = args[:len(args)-1], args[len(args)-1]
+		}
+		if len(args) == 0 {
+			usage()
+		}
+		s.numberResults = regexp.QuoteMeta(name) != name
+		s.lookInFiles(args, name)
+		s.saveResults()
+		return
+	}
+	if *stdinFlag {
+		name := flag.Arg(0)
+		if flag.NArg() != 1 && !(s.pkgDoc && flag.NArg() == 0) {
+			usage()
+		}
+		s.showURL = false // The source has no home.
+		s.numberResults = regexp.QuoteMeta(name) != name
+		s.source = "-stdin"
+		s.lookInStdin(name)
+		s.saveResults()
+		return
 	}
 	var pkg, name string
-	switch flag.NArg() {
-	case 1:
-		if *packageFlag {
+	isRegexp := *regexpFlag
+	switch {
+	case *assignableFlag != "" && flag.NArg() <= 1:
+		// Every function, in the package if one is named.
+		pkg, name, isRegexp = flag.Arg(0), ".*", true
+	case *dirFlag != "":
+		if flag.NArg() == 1 && !s.pkgDoc {
+			name = flag.Arg(0)
+		} else if flag.NArg() != 0 || !s.pkgDoc {
+			usage()
+		}
+		dir, err := filepath.Abs(*dirFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+			exit(2)
+		}
+		pkg = dir
+	case flag.NArg() == 1:
+		if s.pkgDoc {
 			pkg = flag.Arg(0)
-		} else if *regexpFlag {
+		} else if isRegexp {
 			name = flag.Arg(0)
 		} else if strings.Contains(flag.Arg(0), ".") {
 			pkg, name = split(flag.Arg(0))
 		} else {
 			name = flag.Arg(0)
 		}
-	case 2:
-		if *packageFlag {
+	case flag.NArg() == 2:
+		if s.pkgDoc {
 			usage()
 		}
 		pkg, name = flag.Arg(0), flag.Arg(1)
+		// As in "doc -m bufio.ReadWriter Read", the first may name the type too.
+		if dot := strings.LastIndex(pkg, "."); dot > strings.LastIndex(pkg, "/") && !isDirectory(pkg) &&
+			token.IsExported(pkg[dot+1:]) && token.IsIdentifier(pkg[dot+1:]) && token.IsIdentifier(name) {
+			pkg, name = pkg[:dot], pkg[dot+1:]+"."+name
+		}
 	default:
 		usage()
 	}
+	if isDirectory(pkg) {
+		if dir, err := filepath.Abs(pkg); err == nil && filepath.IsAbs(filepath.FromSlash(importPath(dir))) {
+			s.showURL = false // The directory is in no module, GOROOT or GOPATH, so has no home.
+		}
+	}
+	if !isRegexp && pkg != "" && *repoFlag == "" && *archiveFlag == "" && isSelectorChain(name) {
+		s.nested(pkg, strings.Split(name, "."))
+		return
+	}
+	if regexp.QuoteMeta(name) != name {
+		// Check the pattern now, rather than when the first file is found.
+		if _, err := compileName(name); err != nil {
+			fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+			exit(2)
+		}
+	}
+	if *repoFlag != "" {
+		if isDirectory(pkg) {
+			usage()
+		}
+		s.showURL = false // The source is a copy in the cache.
+		s.numberResults = isRegexp || pkg == "" || regexp.QuoteMeta(name) != name
+		s.source = "-repo " + *repoFlag
+		s.lookInRepo(*repoFlag, pkg, name)
+		s.saveResults()
+		return
+	}
+	if *archiveFlag != "" {
+		if isDirectory(pkg) {
+			usage()
+		}
+		s.showURL = false // The archive has no home.
+		s.numberResults = isRegexp || pkg == "" || regexp.QuoteMeta(name) != name
+		s.source = "-archive " + *archiveFlag
+		s.lookInArchive(*archiveFlag, pkg, name)
+		s.saveResults()
+		return
+	}
+	if pkg == "" {
+		s.searchFileSize = int64(configFloat("limit.searchfilesize", 5<<20))
+	}
+	switch {
+	case *callsFlag:
+		s.calls(s.packageDirs(pkg), name)
+		s.saveResults()
+	case *callersFlag:
+		s.callers(s.packageDirs(pkg), name)
+		s.saveResults()
+	case *rankFlag:
+		s.rankedSearch(s.packageDirs(pkg), pkg, name)
+		if !s.printed {
+			s.generatedHint()
+		}
+		s.saveResults()
+	default:
+		s.numberResults = isRegexp || pkg == "" || regexp.QuoteMeta(name) != name
+		if !s.numberResults && name != "" && !isDirectory(pkg) && !*allRootsFlag {
+			s.firstMatch(pkg, name) // A plain pkg.name: the first declaration will do.
+		} else {
+			for _, tier := range s.packageTiers(pkg) {
+				for _, dir := range tier {
+					s.lookInDirectory(dir, pkg, name)
+				}
+				if s.printed && !*allRootsFlag {
+					break // Nothing farther away is wanted.
+				}
+			}
+		}
+		if !s.printed && !*existsFlag {
+			s.generatedHint()
+			if len(s.packageDirs(pkg)) == 0 { // Walked again only when nothing matched.
+				movedHint(pkg)
+			}
+		}
+		if *importFlag {
+			s.printImport(name)
+			return
+		}
+		if *hoverFlag {
+			s.printHover(name)
+			return
+		}
+		if *existsFlag {
+			if !s.printed {
+				exit(1)
+			}
+			return
+		}
+		s.saveResults()
+	}
+}
+
+// packageDirs returns the directories that may hold the package named
+// on the command line, which may itself be a directory.
+func (s *session) packageDirs(pkg string) []string {
+	var dirs []string
+	for _, tier := range s.packageTiers(pkg) {
+		dirs = append(dirs, tier...)
+	}
+	return dirs
+}
+
+// packageTiers returns the directories that may hold the package named on
+// the command line, grouped by root in order of distance: the current
+// module, GOROOT, then GOPATH.
+func (s *session) packageTiers(pkg string) [][]string {
+	if isDirectory(pkg) {
+		dir, err := filepath.Abs(pkg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+			exit(2)
+		}
+		return [][]string{{dir}}
+	}
 	if strings.Contains(pkg, "/") {
+		movedHint(pkg)
 		fmt.Fprintf(os.Stderr, "doc: package name cannot contain slash (TODO)\n")
-		os.Exit(2)
+		exit(2)
+	}
+	tiers := s.pathTiers(pkg)
+	for i, tier := range tiers {
+		var dirs []string
+		for _, path := range tier {
+			if skip, _ := s.skipDir(path); !skip && s.rootAllowed(path) && !s.excluded(importPath(path)) {
+				dirs = append(dirs, path)
+			}
+		}
+		tiers[i] = dirs
 	}
-	for _, path := range paths(pkg) {
-		lookInDirectory(path, name)
+	return tiers
+}
+
+// excluded reports whether the name or import path is matched by -exclude.
+func (s *session) excluded(name string) bool {
+	return s.exclude != nil && s.exclude.MatchString(name)
+}
+
+// skipDir reports whether the directory is excluded by -exclude-dir or
+// the configuration file, and whether the tree below it is too.
+func (s *session) skipDir(dir string) (skip, prune bool) {
+	for _, e := range s.excludedDirs {
+		if e.tree && (dir == e.dir || strings.HasPrefix(dir, e.dir+slash)) {
+			return true, true
+		}
+		if dir == e.dir {
+			skip = true
+		}
 	}
+	return skip, false
 }
 
 var slash = string(filepath.Separator)
 var slashDot = string(filepath.Separator) + "."
-var goRootSrcPkg = filepath.Join(runtime.GOROOT(), "src", "pkg")
-var goRootSrcCmd = filepath.Join(runtime.GOROOT(), "src", "cmd")
+var goRootSrc = filepath.Join(goRoot, "src")
+var goRootSrcPkg = filepath.Join(goRoot, "src", "pkg")
+var goRootSrcCmd = filepath.Join(goRoot, "src", "cmd")
 var goPaths = splitGopath()
 
+// isDirectory reports whether the package argument names a directory,
+// either absolute or relative to the current one, rather than a package.
+func isDirectory(pkg string) bool {
+	return pkg == "." || pkg == ".." || strings.HasPrefix(pkg, "./") || strings.HasPrefix(pkg, "../") || filepath.IsAbs(pkg)
+}
+
 func split(arg string) (pkg, name string) {
 	dot := strings.IndexRune(arg, '.') // We know there's one there.
 	return arg[0:dot], arg[dot+1:]
 }
 
-func paths(pkg string) []string {
-	pkgs := pathsFor(runtime.GOROOT(), pkg)
+func (s *session) paths(pkg string) []string {
+	var pkgs []string
+	for _, tier := range s.pathTiers(pkg) {
+		pkgs = append(pkgs, tier...)
+	}
+	return pkgs
+}
+
+// firstMatch looks for the name in the packages named pkg, in the order of
+// pathTiers, and stops at the first package that declares it, without
+// walking the rest of the trees.
+func (s *session) firstMatch(pkg, name string) {
+	if *localFlag && modDir == "" {
+		fmt.Fprintf(os.Stderr, "doc: -local: not in a module\n")
+		exit(2)
+	}
+	var roots []string
+	if modDir != "" {
+		roots = append(roots, modDir)
+	}
+	if !*localFlag {
+		roots = append(roots, filepath.Join(goRoot, "src"))
+		for _, root := range goPaths {
+			roots = append(roots, filepath.Join(root, "src"))
+		}
+	}
+	s.sortRoots(roots)
+	for _, root := range roots {
+		s.walkDirs(root, pkg, func(dir string) bool {
+			inModule := modDir != "" && (dir == modDir || strings.HasPrefix(dir, modDir+slash))
+			if root != modDir && inModule || !s.rootAllowed(dir) || s.excluded(importPath(dir)) {
+				return true
+			}
+			s.lookInDirectory(dir, pkg, name)
+			return !s.printed
+		})
+		if s.printed {
+			return
+		}
+	}
+}
+
+// pathTiers returns the possible directories for the package, as paths
+// does, grouped by root: the current module, GOROOT, then GOPATH. A module
+// inside GOPATH is searched only as the module.
+func (s *session) pathTiers(pkg string) [][]string {
+	if *localFlag {
+		if modDir == "" {
+			fmt.Fprintf(os.Stderr, "doc: -local: not in a module\n")
+			exit(2)
+		}
+		return [][]string{s.dirsFor(modDir, pkg)}
+	}
+	type tier struct {
+		root string
+		dirs []string
+	}
+	var tiers []tier
+	if modDir != "" {
+		tiers = append(tiers, tier{modDir, s.dirsFor(modDir, pkg)})
+	}
+	tiers = append(tiers, tier{filepath.Join(goRoot, "src"), s.pathsFor(goRoot, pkg)})
+	goPath := tier{}
+	for _, root := range goPaths {
+		if goPath.root == "" {
+			goPath.root = filepath.Join(root, "src")
+		}
+		for _, dir := range s.pathsFor(root, pkg) {
+			if modDir == "" || dir != modDir && !strings.HasPrefix(dir, modDir+slash) {
+				goPath.dirs = append(goPath.dirs, dir)
+			}
+		}
+	}
+	tiers = append(tiers, goPath)
+	// With -roots, the roots are searched in the order listed.
+	sort.SliceStable(tiers, func(i, j int) bool { return s.rootRank(tiers[i].root) < s.rootRank(tiers[j].root) })
+	dirs := make([][]string, len(tiers))
+	for i, t := range tiers {
+		dirs[i] = t.dirs
+	}
+	return dirs
+}
+
+// importPath returns the import path for the package in the directory,
+// derived from its location in the current module, the module cache, or
+// under GOROOT or GOPATH. A vendored package is imported by the path it has
+// below the vendor directory. If the directory is in none of these places,
+// the directory itself is returned.
+func importPath(dir string) string {
+	if modDir != "" && (dir == modDir || strings.HasPrefix(dir, modDir+slash)) {
+		return unvendor(path.Join(modPath, filepath.ToSlash(strings.TrimPrefix(dir, modDir))))
+	}
+	if pkgPath, _, ok := cachedPackage(dir); ok {
+		return unvendor(pkgPath)
+	}
+	roots := append([]string{goRoot}, goPaths...)
+	for _, root := range roots {
+		src := filepath.Join(root, "src") + slash
+		if strings.HasPrefix(dir, src) {
+			return unvendor(filepath.ToSlash(strings.TrimPrefix(dir, src)))
+		}
+	}
+	return filepath.ToSlash(dir)
+}
+
+// unvendor returns the import path with any prefix ending in a vendor
+// element removed.
+func unvendor(pkgPath string) string {
+	if i := strings.LastIndex("/"+pkgPath, "/vendor/"); i >= 0 {
+		return pkgPath[i+len("vendor/"):]
+	}
+	return pkgPath
+}
+
+// importLine returns the import declaration, ending in a newline, that
+// brings in the file's package, or nothing if output is restricted by
+// -doc, -src or -url, or the package, such as a command or an external
+// test, cannot be imported.
+func (f *File) importLine() string {
+	pkgPath := importPath(filepath.Dir(f.name))
+	if !f.s.showDoc || !f.s.showSrc || !f.s.showURL || *htmlFragmentFlag || *chatFlag || *screenReaderFlag || f.file.Name.Name == "main" || strings.HasSuffix(f.file.Name.Name, "_test") || filepath.IsAbs(filepath.FromSlash(pkgPath)) {
+		return ""
+	}
+	return fmt.Sprintf("import %q\n", pkgPath)
+}
+
+// dirForImport returns the directory holding the package with the import path,
+// as imported by a file in srcDir, or in the current module if srcDir is empty.
+// It looks in the current module and GOROOT, then in the build list of the
+// module holding srcDir, which finds dependencies in the module cache or
+// vendored, and then in GOPATH. It returns the empty string if the package
+// cannot be found.
+func dirForImport(pkgPath, srcDir string) string {
+	var dirs []string
+	if modDir != "" && (pkgPath == modPath || strings.HasPrefix(pkgPath, modPath+"/")) {
+		dirs = append(dirs, filepath.Join(modDir, filepath.FromSlash(strings.TrimPrefix(pkgPath, modPath))))
+	}
+	goRootSrc := filepath.Join(goRoot, "src")
+	dirs = append(dirs, filepath.Join(goRootSrc, pkgPath), filepath.Join(goRootSrc, "vendor", pkgPath))
+	for _, dir := range dirs {
+		if isDir(dir) {
+			return dir
+		}
+	}
+	if srcDir == "" {
+		srcDir = modDir
+	}
+	if dir := listedDir(pkgPath, srcDir); dir != "" {
+		return dir
+	}
 	for _, root := range goPaths {
-		pkgs = append(pkgs, pathsFor(root, pkg)...)
+		if dir := filepath.Join(root, "src", pkgPath); isDir(dir) {
+			return dir
+		}
 	}
-	return pkgs
+	return ""
+}
+
+// filesDir returns the directory of the first of the files, from a single
+// package, or the empty string if there are none.
+func filesDir(files []*File) string {
+	if len(files) == 0 {
+		return ""
+	}
+	return filepath.Dir(files[0].name)
 }
 
 func splitGopath() []string {
@@ -203,39 +1931,145 @@ func splitGopath() []string {
 
 // pathsFor recursively walks the tree looking for possible directories for the package:
 // those whose basename is pkg.
-func pathsFor(root, pkg string) []string {
-	root = path.Join(root, "src")
+func (s *session) pathsFor(root, pkg string) []string {
+	return s.dirsFor(path.Join(root, "src"), pkg)
+}
+
+// dirsFor walks the tree rooted at root, returning the directories
+// whose basename is pkg, or all directories if pkg is empty.
+func (s *session) dirsFor(root, pkg string) []string {
 	pkgPaths := make([]string, 0, 10)
+	s.walkDirs(root, pkg, func(dir string) bool {
+		pkgPaths = append(pkgPaths, dir)
+		return true
+	})
+	return pkgPaths
+}
+
+// walkDirs walks the tree rooted at root, calling fn for the directories
+// whose basename is pkg, or all directories if pkg is empty, until fn
+// returns false.
+func (s *session) walkDirs(root, pkg string, fn func(dir string) bool) {
+	if walkCache != nil {
+		walkCache.walk(s, root, root, pkg, fn)
+		walkCache.save()
+		return
+	}
+	// A root that is a symbolic link, as to a network file system, is
+	// walked where it leads, but reported under its own name.
+	walkRoot := root
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		walkRoot = resolved
+	}
 	visit := func(pathName string, f os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
+		pathName = root + pathName[len(walkRoot):]
 		// One package per directory. Ignore the files themselves.
 		if !f.IsDir() {
 			return nil
 		}
-		// No .hg or other dot nonsense please.
-		if strings.Contains(pathName, slashDot) {
+		stats.dirs.Add(1)
+		// No .hg or other dot nonsense please, though the root may be in a dot directory.
+		if strings.Contains(pathName[len(root):], slashDot) {
+			return filepath.SkipDir
+		}
+		skip, prune := s.skipDir(pathName)
+		if prune || s.skipGenerated(pathName, pkg) {
 			return filepath.SkipDir
 		}
+		if skip {
+			return nil
+		}
 		// Is the last element of the path correct
-		if pkg == "" || filepath.Base(pathName) == pkg {
-			pkgPaths = append(pkgPaths, pathName)
+		if (pkg == "" || filepath.Base(pathName) == pkg) && !fn(pathName) {
+			return filepath.SkipAll
 		}
 		return nil
 	}
 
-	filepath.Walk(root, visit)
-	return pkgPaths
+	filepath.Walk(walkRoot, visit)
 }
 
 // lookInDirectory looks in the package (if any) in the directory for the named exported identifier.
-func lookInDirectory(directory, name string) {
+// The pkgName is the package named on the command line, possibly empty.
+// Files larger than the session's searchFileSize are skipped, with a notice.
+func (s *session) lookInDirectory(directory, pkgName, name string) {
+	var filter func(os.FileInfo) bool
+	if s.searchFileSize > 0 {
+		filter = func(info os.FileInfo) bool {
+			if info.Size() > s.searchFileSize {
+				fmt.Fprintf(os.Stderr, "doc: skipping %s: larger than %d bytes\n", filepath.Join(directory, info.Name()), s.searchFileSize)
+				return false
+			}
+			return true
+		}
+	}
+	s.examining = directory
+	fset := token.NewFileSet()
+	pkgs, _ := parseDir(fset, directory, filter, parser.ParseComments) // Ignore the error.
+	for _, pkg := range choosePackages(directory, pkgs) {
+		s.doPackage(pkg, fset, pkgName, name)
+	}
+}
+
+// lookInFiles looks for the named exported identifier in the files, which are
+// parsed together as one package wherever they are, so code outside any
+// module or GOPATH, or a file from a patch, can be documented.
+func (s *session) lookInFiles(names []string, name string) {
+	for i, fileName := range names {
+		fileName, err := filepath.Abs(fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+			exit(2)
+		}
+		s.sources[fileName], err = readSource(fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+			exit(2)
+		}
+		names[i] = fileName
+	}
+	s.lookInSources(names, name)
+}
+
+// lookInStdin looks for the named exported identifier in the Go source
+// read from standard input, which is named <stdin> in the output.
+func (s *session) lookInStdin(name string) {
+	src, err := io.ReadAll(os.Stdin)
+	if err == nil {
+		src, err = decodeSource("<stdin>", src)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+		exit(2)
+	}
+	s.sources["<stdin>"] = src
+	s.lookInSources([]string{"<stdin>"}, name)
+}
+
+// lookInSources looks for the named exported identifier in the session's
+// sources with the names, which are parsed together as one package.
+func (s *session) lookInSources(names []string, name string) {
 	fset := token.NewFileSet()
-	pkgs, _ := parser.ParseDir(fset, directory, nil, parser.ParseComments) // Ignore the error.
-	for _, pkg := range pkgs {
-		doPackage(pkg, fset, name)
+	var pkg *ast.Package
+	for _, fileName := range names {
+		file, err := parseFile(fset, fileName, s.sources[fileName], parser.ParseComments)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+			exit(2)
+		}
+		if pkg == nil {
+			pkg = &ast.Package{Name: file.Name.Name, Files: make(map[string]*ast.File)}
+		}
+		if file.Name.Name != pkg.Name {
+			fmt.Fprintf(os.Stderr, "doc: %s is in package %s, not %s\n", fileName, file.Name.Name, pkg.Name)
+			exit(2)
+		}
+		pkg.Files[fileName] = file
 	}
+	s.doPackage(pkg, fset, pkg.Name, name)
 }
 
 // prefixDirectory places the directory name on the beginning of each name in the list.
@@ -250,74 +2084,176 @@ func prefixDirectory(directory string, names []string) {
 // File is a wrapper for the state of a file used in the parser.
 // The parse tree walkers are all methods of this type.
 type File struct {
+	s          *session
 	fset       *token.FileSet
 	name       string // Name of file.
 	ident      string // Identifier we are searching for.
 	regexp     *regexp.Regexp
 	pathPrefix string // Prefix from GOROOT/GOPATH.
-	urlPrefix  string // Start of corresponding URL for golang.org or godoc.org.
+	urlPrefix  string // Start of corresponding URL for golang.org or godoc.org; see modernURL.
 	file       *ast.File
 	comments   ast.CommentMap
+	types      *types.Package // The type-checked package, if known.
+	typeErrs   []types.Error  // The errors found type-checking it.
+	src        []byte         // Contents of the file, read on demand.
 	objs       map[*ast.Ident]types.Object
+	uses       map[*ast.Ident]types.Object
 	doPrint    bool
 	found      bool
-	allFiles   []*File // All files in the package.
+	matches    []ast.Decl // Declarations holding matches, found before printing.
+	allFiles   []*File    // All files in the package.
+	listing    *listing   // If non-nil, output is sorted into sections.
 }
 
-const godocOrg = "http://godoc.org"
+// Kinds of declaration, in the order they are listed.
+const (
+	constKind = iota
+	varKind
+	funcKind
+	typeKind
+	numKinds
+)
 
-// doPackage analyzes the single package constructed from the named files, looking for
-// the definition of ident.
-func doPackage(pkg *ast.Package, fset *token.FileSet, ident string) {
-	var files []*File
-	found := false
-	for name, astFile := range pkg.Files {
-		if *packageFlag && astFile.Doc == nil {
-			continue
+var kindHeading = [numKinds]string{"Constants", "Variables", "Functions", "Types"}
+
+// listing accumulates the output for a package, one buffer per kind of
+// declaration, so it can be presented like a package page rather than
+// in the order the declarations appear in the files. With -byfile, there
+// is one buffer per file instead, to follow the package's own organization.
+type listing struct {
+	section [numKinds]bytes.Buffer
+	file    map[string]*bytes.Buffer // Keyed by file name, if -byfile is set.
+}
+
+// empty reports whether nothing has been listed.
+func (l *listing) empty() bool {
+	for _, b := range l.file {
+		if b.Len() > 0 {
+			return false
 		}
-		file := &File{
-			fset:     fset,
-			name:     name,
-			ident:    ident,
-			file:     astFile,
-			comments: ast.NewCommentMap(fset, astFile, astFile.Comments),
-		}
-		if regexp.QuoteMeta(ident) != ident {
-			// It's a regular expression.
-			var err error
-			file.regexp, err = regexp.Compile("^(?i:" + ident + ")$")
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "regular expression `%s`:", err)
-				os.Exit(2)
+	}
+	for i := range l.section {
+		if l.section[i].Len() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// print writes the non-empty sections of the listing to w.
+func (l *listing) print(w io.Writer) {
+	if l.file != nil {
+		var names []string
+		for name := range l.file {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			switch {
+			case *chatFlag:
+				fmt.Fprintf(w, "*%s*\n\n", name)
+			case *screenReaderFlag:
+				fmt.Fprintf(w, "File: %s\n\n", name)
+			case !*sigOnlyFlag && !*htmlFragmentFlag:
+				fmt.Fprintf(w, "%s\n\n", name)
 			}
+			w.Write(l.file[name].Bytes())
+		}
+		return
+	}
+	for kind := range l.section {
+		if l.section[kind].Len() == 0 {
+			continue
 		}
 		switch {
-		case strings.HasPrefix(name, goRootSrcPkg):
-			file.urlPrefix = "http://golang.org/pkg"
-			file.pathPrefix = goRootSrcPkg
-		case strings.HasPrefix(name, goRootSrcCmd):
-			file.urlPrefix = "http://golang.org/cmd"
-			file.pathPrefix = goRootSrcCmd
-		default:
-			file.urlPrefix = godocOrg
-			for _, path := range goPaths {
-				p := filepath.Join(path, "src")
-				if strings.HasPrefix(name, p) {
-					file.pathPrefix = p
-					break
-				}
+		case *chatFlag:
+			fmt.Fprintf(w, "*%s*\n\n", kindHeading[kind])
+		case *screenReaderFlag:
+			fmt.Fprintf(w, "Section: %s\n\n", kindHeading[kind])
+		case !*sigOnlyFlag && !*htmlFragmentFlag:
+			fmt.Fprintf(w, "%s\n\n", kindHeading[kind])
+		}
+		w.Write(l.section[kind].Bytes())
+	}
+}
+
+// nodeKind returns the kind of declaration the node represents.
+// Methods are listed with the types.
+func nodeKind(node ast.Node) int {
+	switch n := node.(type) {
+	case *ast.GenDecl:
+		switch n.Tok {
+		case token.CONST:
+			return constKind
+		case token.VAR:
+			return varKind
+		}
+	case *ast.FuncDecl:
+		if n.Recv == nil {
+			return funcKind
+		}
+	}
+	return typeKind
+}
+
+// output returns the writer for declarations of the given kind.
+func (f *File) output(kind int) io.Writer {
+	switch {
+	case f.listing == nil:
+		return f.s.out
+	case f.listing.file != nil:
+		name := filepath.Base(f.name)
+		if f.listing.file[name] == nil {
+			f.listing.file[name] = new(bytes.Buffer)
+		}
+		return f.listing.file[name]
+	}
+	return &f.listing.section[kind]
+}
+
+const godocOrg = "http://godoc.org"
+
+// doPackage analyzes the single package constructed from the named files, looking for
+// the definition of ident. The pkgName is the package named on the command line, possibly empty.
+func (s *session) doPackage(pkg *ast.Package, fset *token.FileSet, pkgName, ident string) {
+	if strings.HasSuffix(pkg.Name, "_test") {
+		// The external test package; go test gives it this import path.
+		for name := range pkg.Files {
+			if s.excluded(importPath(filepath.Dir(name)) + "_test") {
+				return
 			}
+			break
 		}
-		files = append(files, file)
-		if found {
+	}
+	// Visit the files in order, so variants of a declaration for
+	// different platforms are printed in the same order every time.
+	var names []string
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var files []*File
+	found := false
+	for _, name := range names {
+		astFile := pkg.Files[name]
+		if s.pkgDoc && astFile.Doc == nil {
 			continue
 		}
+		s.examining = name
+		file := s.newFile(fset, name, ident, astFile)
+		files = append(files, file)
 		file.doPrint = false
-		if *packageFlag {
+		if s.pkgDoc {
 			file.pkgComments()
-		} else {
-			ast.Walk(file, file.file)
+			continue
+		}
+		// Record the declarations holding matches, so the printing pass
+		// need not walk the rest of the file again.
+		for _, decl := range astFile.Decls {
+			file.found = false
+			ast.Walk(file, decl)
 			if file.found {
+				file.matches = append(file.matches, decl)
 				found = true
 			}
 		}
@@ -328,14 +2264,126 @@ func doPackage(pkg *ast.Package, fset *token.FileSet, ident string) {
 	}
 
 	// Type check to build map from name to type.
-	objects := make(map[*ast.Ident]types.Object)
+	typesPkg, info, typeErrs := typeCheckErrors(fset, pkg)
+	objects, uses := info.Defs, info.Uses
+
+	// We need to search all files for methods, so record the full list in each file.
+	// A regular expression search within a named package is listed by kind,
+	// or with -byfile by file.
+	var list *listing
+	if pkgName != "" && !s.pkgDoc && files[0].regexp != nil {
+		list = new(listing)
+		if *byFileFlag {
+			list.file = make(map[string]*bytes.Buffer)
+		}
+	}
+	for _, file := range files {
+		file.allFiles = files
+		file.listing = list
+	}
+	for _, file := range files {
+		s.examining = file.name
+		file.doPrint = true
+		file.types = typesPkg
+		file.typeErrs = typeErrs
+		file.objs = objects
+		file.uses = uses
+		if s.pkgDoc {
+			file.pkgComments()
+			continue
+		}
+		for _, decl := range file.matches {
+			ast.Walk(file, decl)
+		}
+	}
+	if list != nil {
+		if !list.empty() {
+			fmt.Fprint(s.out, files[0].importLine())
+		}
+		list.print(s.out)
+	}
+}
+
+// newFile returns a File, ready to walk, for the parsed file with the given name.
+func (s *session) newFile(fset *token.FileSet, name, ident string, astFile *ast.File) *File {
+	file := &File{
+		s:        s,
+		fset:     fset,
+		name:     name,
+		ident:    ident,
+		file:     astFile,
+		comments: ast.NewCommentMap(fset, astFile, astFile.Comments),
+	}
+	if regexp.QuoteMeta(ident) != ident {
+		// It's a regular expression.
+		var err error
+		file.regexp, err = compileName(ident)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+			exit(2)
+		}
+	}
+	switch {
+	case strings.HasPrefix(name, goRootSrcPkg):
+		file.urlPrefix = "http://golang.org/pkg"
+		file.pathPrefix = goRootSrcPkg
+	case strings.HasPrefix(name, goRootSrcCmd):
+		file.urlPrefix = "http://golang.org/cmd"
+		file.pathPrefix = goRootSrcCmd
+	case strings.HasPrefix(name, goRootSrc+slash):
+		// Since Go 1.4 the standard library is in GOROOT/src, not GOROOT/src/pkg.
+		file.urlPrefix = "http://golang.org/pkg"
+		file.pathPrefix = goRootSrc
+	case modDir != "" && strings.HasPrefix(name, modDir+slash):
+		file.urlPrefix = godocOrg + "/" + modPath
+		file.pathPrefix = modDir
+	default:
+		if pkgPath, _, ok := cachedPackage(filepath.Dir(name)); ok {
+			// A dependency in the module cache, whose directory names its version.
+			file.urlPrefix = godocOrg + "/" + pkgPath
+			file.pathPrefix = filepath.Dir(name)
+			break
+		}
+		file.urlPrefix = godocOrg
+		for _, path := range goPaths {
+			p := filepath.Join(path, "src")
+			if strings.HasPrefix(name, p) {
+				file.pathPrefix = p
+				break
+			}
+		}
+	}
+	return file
+}
+
+// typeCheck type checks the package, returning the result and the maps from
+// identifiers to the objects they define and use, and from selector
+// expressions to what they select. Errors are ignored, so
+// the information may be incomplete. The language version is that of the
+// package's module, so that, for instance, the meaning of a loop variable
+// is the one its authors intended.
+func typeCheck(fset *token.FileSet, pkg *ast.Package) (*types.Package, *types.Info) {
+	typesPkg, info, _ := typeCheckErrors(fset, pkg)
+	return typesPkg, info
+}
+
+// typeCheckErrors is typeCheck, returning also up to maxTypeErrors of the
+// errors found, for typeUnavailable.
+func typeCheckErrors(fset *token.FileSet, pkg *ast.Package) (*types.Package, *types.Info, []types.Error) {
 	// By providing the Context with our own error function, it will continue
-	// past the first error. There is no need for that function to do anything.
+	// past the first error.
+	var errs []types.Error
 	config := types.Config{
-		Error: func(error) {},
+		Error: func(err error) {
+			if e, ok := err.(types.Error); ok && len(errs) < maxTypeErrors {
+				errs = append(errs, e)
+			}
+		},
 	}
 	info := &types.Info{
-		Defs: objects,
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
 	}
 	path := ""
 	var astFiles []*ast.File
@@ -345,25 +2393,17 @@ func doPackage(pkg *ast.Package, fset *token.FileSet, ident string) {
 		}
 		astFiles = append(astFiles, astFile)
 	}
-	config.Check(path, fset, astFiles, info) // Ignore errors.
-
-	// We need to search all files for methods, so record the full list in each file.
-	for _, file := range files {
-		file.allFiles = files
-	}
-	for _, file := range files {
-		file.doPrint = true
-		file.objs = objects
-		if *packageFlag {
-			file.pkgComments()
-		} else {
-			ast.Walk(file, file.file)
-		}
+	config.Importer = exportData.forDir(filepath.Dir(path))
+	if path != "" {
+		config.GoVersion = languageVersion(filepath.Dir(path))
 	}
+	start := time.Now()
+	typesPkg, _ := config.Check(path, fset, astFiles, info) // The errors are in errs.
+	stats.packages.Add(1)
+	stats.typeCheck.Add(since(start))
+	return typesPkg, info, errs
 }
 
-var methodSetCache typeutil.MethodSetCache
-
 // Visit implements the ast.Visitor interface.
 func (f *File) Visit(node ast.Node) ast.Visitor {
 	switch n := node.(type) {
@@ -372,10 +2412,10 @@ func (f *File) Visit(node ast.Node) ast.Visitor {
 		for _, spec := range n.Specs {
 			switch spec := spec.(type) {
 			case *ast.ValueSpec:
-				if *constantFlag && n.Tok == token.CONST || *variableFlag && n.Tok == token.VAR {
+				if f.s.consts && n.Tok == token.CONST || f.s.vars && n.Tok == token.VAR {
 					for _, ident := range spec.Names {
 						if f.match(ident.Name) {
-							f.printNode(n, ident, f.nameURL(ident.Name))
+							f.printNode(n, ident, f.nameURL(n, ident))
 							break
 						}
 					}
@@ -391,26 +2431,38 @@ func (f *File) Visit(node ast.Node) ast.Visitor {
 					node = spec
 				}
 				if f.match(spec.Name.Name) {
-					if *typeFlag {
-						f.printNode(node, spec.Name, f.nameURL(spec.Name.Name))
+					if f.s.types {
+						f.printNode(node, spec.Name, f.nameURL(node, spec.Name))
 					} else {
 						switch spec.Type.(type) {
 						case *ast.InterfaceType:
-							if *interfaceFlag {
-								f.printNode(node, spec.Name, f.nameURL(spec.Name.Name))
+							if f.s.ifaces {
+								f.printNode(node, spec.Name, f.nameURL(node, spec.Name))
 							}
 						case *ast.StructType:
-							if *structFlag {
-								f.printNode(node, spec.Name, f.nameURL(spec.Name.Name))
+							if f.s.structs {
+								f.printNode(node, spec.Name, f.nameURL(node, spec.Name))
 							}
 						}
 					}
-					if f.doPrint && f.objs[spec.Name] != nil && f.objs[spec.Name].Type() != nil {
-						ms := methodSetCache.MethodSet(f.objs[spec.Name].Type())
+					if *sigOnlyFlag || *importFlag || *hoverFlag || *htmlFragmentFlag || *chatFlag || *screenReaderFlag {
+						// Just the declaration.
+					} else if spec.Assign.IsValid() {
+						if f.doPrint && f.s.types {
+							f.alias(spec)
+						}
+					} else if f.doPrint && f.objs[spec.Name] != nil && f.objs[spec.Name].Type() != nil {
+						if reason := f.typeUnavailable(spec); reason != "" {
+							fmt.Fprintf(f.output(typeKind), "(type information unavailable: %s)\n\n", reason)
+						}
+						ms := f.s.methodSets.MethodSet(f.objs[spec.Name].Type())
 						if ms.Len() == 0 {
-							ms = methodSetCache.MethodSet(types.NewPointer(f.objs[spec.Name].Type()))
+							ms = f.s.methodSets.MethodSet(types.NewPointer(f.objs[spec.Name].Type()))
 						}
 						f.methodSet(ms)
+						if *ptrFlag {
+							f.pointerOnly(f.objs[spec.Name])
+						}
 					}
 				}
 			case *ast.ImportSpec:
@@ -420,100 +2472,441 @@ func (f *File) Visit(node ast.Node) ast.Visitor {
 	case *ast.FuncDecl:
 		// Methods, top-level functions.
 		if f.match(n.Name.Name) {
-			n.Body = nil // Do not print the function body.
-			if *methodFlag && n.Recv != nil {
-				f.printNode(n, n.Name, f.methodURL(n.Recv.List[0].Type, n.Name.Name))
-			} else if *functionFlag && n.Recv == nil {
-				f.printNode(n, n.Name, f.nameURL(n.Name.Name))
+			body := n.Body
+			if !f.s.full {
+				n.Body = nil // Do not print the function body.
+			}
+			printed := false
+			if f.s.methods && n.Recv != nil {
+				f.printNode(n, n.Name, f.methodURL(n))
+				printed = true
+			} else if f.s.funcs && n.Recv == nil {
+				f.printNode(n, n.Name, f.nameURL(n, n.Name))
+				printed = true
+			}
+			n.Body = body
+			if *sigOnlyFlag || *importFlag || *hoverFlag || *htmlFragmentFlag || *chatFlag || *screenReaderFlag {
+				printed = false // Just the declaration.
+			}
+			if printed && f.doPrint && *behaviorFlag {
+				f.behavior(n)
+			}
+			if printed && f.doPrint && *concurrencyFlag {
+				f.concurrency(n)
+			}
+			if printed && f.doPrint && *riskFlag {
+				f.risk(n)
+			}
+			if printed && f.doPrint && *perfFlag {
+				f.perf(n)
+			}
+			if printed && f.doPrint && (*coverFlag != "" || *coverRunFlag) {
+				f.coverage(n)
+			}
+			if printed && f.doPrint && *snippetFlag {
+				f.snippet(n)
+			}
+			if printed && f.doPrint && *followFlag && n.Doc == nil {
+				f.follow(n)
+			}
+			if printed && f.doPrint && *inheritDocFlag && n.Doc == nil && n.Recv != nil {
+				f.inheritDoc(n)
 			}
 		}
 	}
 	return f
 }
 
+// alias reports the type the alias declared by spec stands for, and
+// follows it to print the documentation of the aliased type.
+func (f *File) alias(spec *ast.TypeSpec) {
+	typ := spec.Type
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	var dir, pkgPath, name string
+	switch t := typ.(type) {
+	case *ast.Ident:
+		dir = filepath.Dir(f.name)
+		pkgPath, name = importPath(dir), t.Name
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return
+		}
+		pkgPath, name = f.importFor(x.Name), t.Sel.Name
+		dir = dirForImport(pkgPath, filepath.Dir(f.name))
+	default:
+		return
+	}
+	fmt.Fprintf(f.output(typeKind), "%s is an alias for %s.%s\n\n", spec.Name.Name, pkgPath, name)
+	// Within a sorted listing the output would land outside its section.
+	if f.listing == nil && dir != "" && ast.IsExported(name) {
+		f.s.lookInDirectory(dir, "", name)
+	}
+}
+
+// importFor returns the import path of the package the file imports under
+// the given name. If there is no such import, it returns the name itself.
+func (f *File) importFor(name string) string {
+	for _, imp := range f.file.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		if importName(imp) == name {
+			return p
+		}
+	}
+	return name
+}
+
 func (f *File) match(name string) bool {
 	// name must  be exported.
-	if !ast.IsExported(name) {
+	if !ast.IsExported(name) || f.s.excluded(name) {
 		return false
 	}
 	if f.regexp == nil {
-		return strings.ToLower(name) == strings.ToLower(f.ident)
+		return equalFold(name, f.ident)
 	}
 	return f.regexp.MatchString(name)
 }
 
 func (f *File) printNode(node, ident ast.Node, url string) {
+	id, _ := ident.(*ast.Ident)
+	tier := f.stability(node, id)
+	if *stableOnlyFlag && !stable(tier) || !f.filterMatches(node, id) || !f.assignableMatches(node) {
+		return
+	}
 	if !f.doPrint {
 		f.found = true
 		return
 	}
-	fmt.Printf("%s%s%s", url, f.sourcePos(f.fset.Position(ident.Pos())), f.docs(node))
+	pos := f.fset.Position(ident.Pos())
+	if f.s.showAt.IsValid() && (pos.Filename != f.s.showAt.Filename || pos.Line != f.s.showAt.Line) {
+		return
+	}
+	f.s.printed = true
+	if id != nil && id.Name == f.ident {
+		f.s.exactMatch = true
+	}
+	if *importFlag && id != nil {
+		f.addImport(node, id)
+		return
+	}
+	if *hoverFlag && id != nil {
+		f.addHover(node, id, url)
+		return
+	}
+	kind := nodeKind(node)
+	var text []byte
+	if *sigOnlyFlag && id != nil {
+		text = []byte(f.signature(node, id) + "\n")
+	} else {
+		text = f.docs(node)
+	}
+	pkgPath := importPath(filepath.Dir(pos.Filename))
+	module := f.s.moduleStatusOf(filepath.Dir(pos.Filename))
+	number := ""
+	if id != nil {
+		vis, _ := visibility(pos.Filename, f.file.Name.Name, pkgPath)
+		number = f.s.record(result{
+			Name:         id.Name,
+			File:         pos.Filename,
+			Line:         pos.Line,
+			Package:      pkgPath,
+			Kind:         resultKind(node),
+			URL:          strings.TrimSpace(url),
+			Text:         strings.TrimSpace(string(text)),
+			Root:         rootCategory(filepath.Dir(pos.Filename)),
+			Visibility:   vis,
+			ModuleStatus: module.kind(),
+			Stability:    tier,
+		})
+	}
+	w := f.output(kind)
+	if *sigOnlyFlag {
+		w.Write(text)
+		return
+	}
+	if *htmlFragmentFlag && id != nil {
+		fmt.Fprint(w, f.htmlFragment(node, id, url))
+		return
+	}
+	if *chatFlag && id != nil {
+		fmt.Fprint(w, f.chatFragment(node, id, url))
+		return
+	}
+	if *screenReaderFlag && id != nil {
+		fmt.Fprint(w, f.screenReaderFragment(node, id, url))
+		return
+	}
+	importLine := ""
+	if f.listing == nil {
+		importLine = f.importLine() // A listing names it once, at the top.
+	}
+	fmt.Fprintf(w, "%s%s%s%s%s%s%s%s%s%s%s", number, importLine, url, f.sourcePos(pos), f.fileNote(pos), stabilityNote(tier),
+		f.platformNote(node, id), visibilityNote(pos.Filename, f.file.Name.Name, pkgPath), module.note(), f.whyNote(id), text)
+	if *contextFlag > 0 {
+		w.Write(f.context(pos, *contextFlag))
+	}
+	if *licenseFlag {
+		fmt.Fprint(w, f.licenseNote())
+	}
+	if id != nil && f.s.verboseDoc {
+		f.verbose(node, id)
+	}
 }
 
 func (f *File) docs(node ast.Node) []byte {
-	if !*docFlag {
+	if !f.s.showDoc {
 		return nil
 	}
+	if *rawFlag {
+		if raw := f.raw(node); raw != nil {
+			return append(raw, "\n\n"...)
+		}
+	}
 	commentedNode := printer.CommentedNode{Node: node}
 	if comments := f.comments.Filter(node).Comments(); comments != nil {
 		commentedNode.Comments = comments
 	}
 	var b bytes.Buffer
+	docLines := 0 // The number of lines the doc comment takes at the start of b.
+	if doc := docComment(node); doc != nil {
+		docLines = f.fset.Position(doc.End()).Line - f.fset.Position(doc.Pos()).Line + 1
+	}
+	if text, ok := f.translation(symbolKey(node)); ok {
+		// Replace the doc comment with its translation.
+		docLines = strings.Count(commentLines(text), "\n")
+		b.WriteString(commentLines(text))
+		doc := docComment(node)
+		defer setDocComment(node, setDocComment(node, nil)) // The printer prints it from the node.
+		commentedNode.Comments = nil
+		for _, c := range f.comments.Filter(node).Comments() {
+			if c != doc {
+				commentedNode.Comments = append(commentedNode.Comments, c)
+			}
+		}
+	}
+	if fn, ok := node.(*ast.FuncDecl); ok && f.s.numberResults && !*expandFlag && !f.s.full {
+		if short, ok := f.shortSignature(fn); ok {
+			// Comments within the elided parts would have nowhere to go.
+			var comments []*ast.CommentGroup
+			for _, c := range commentedNode.Comments {
+				if c.End() <= fn.Type.Pos() {
+					comments = append(comments, c)
+				}
+			}
+			commentedNode = printer.CommentedNode{Node: short, Comments: comments}
+		}
+	}
 	printer.Fprint(&b, f.fset, &commentedNode)
 	b.Write([]byte("\n\n")) // Add a blank line between entries if we print documentation.
+	if f.s.numberResults && !*fullDocFlag && !f.s.full {
+		return truncateDoc(b.Bytes(), docLines)
+	}
+	return b.Bytes()
+}
+
+// maxDocLines is how many lines of a doc comment are printed for each of
+// several results, set in the configuration file as limit.doclines, where
+// 0 means no limit.
+var maxDocLines = int(configFloat("limit.doclines", 20))
+
+// truncateDoc returns the text, whose first docLines lines are a doc
+// comment, with the comment cut to maxDocLines lines, and a line saying
+// how many were left out, if it is longer by more than a line.
+func truncateDoc(text []byte, docLines int) []byte {
+	if maxDocLines <= 0 || docLines <= maxDocLines+1 {
+		return text
+	}
+	lines := bytes.SplitAfter(text, []byte("\n"))
+	if len(lines) < docLines {
+		return text
+	}
+	var b bytes.Buffer
+	for _, line := range lines[:maxDocLines] {
+		b.Write(line)
+	}
+	fmt.Fprintf(&b, "// …(%d more lines, use -full-doc)\n", docLines-maxDocLines)
+	for _, line := range lines[docLines:] {
+		b.Write(line)
+	}
+	return b.Bytes()
+}
+
+// source returns the contents of the file, or nil if it cannot be read.
+func (f *File) source() []byte {
+	if f.src == nil {
+		if src, ok := f.s.sources[f.name]; ok {
+			f.src = src
+		} else {
+			f.src, _ = readSource(f.name)
+		}
+	}
+	return f.src
+}
+
+// context returns the lines of the file within n lines of the position,
+// numbered in the style of grep: a colon follows the number of the line
+// holding the position, a hyphen the others.
+func (f *File) context(pos token.Position, n int) []byte {
+	lines := strings.Split(strings.TrimSuffix(string(f.source()), "\n"), "\n")
+	var b bytes.Buffer
+	for i := max(pos.Line-n, 1); i <= min(pos.Line+n, len(lines)); i++ {
+		sep := "-"
+		if i == pos.Line {
+			sep = ":"
+		}
+		fmt.Fprintf(&b, "%d%s%s\n", i, sep, lines[i-1])
+	}
+	b.WriteString("\n")
 	return b.Bytes()
 }
 
+// raw returns the source text of the declaration, from the start of its doc
+// comment, exactly as it appears in the file. It returns nil if the file
+// cannot be read.
+func (f *File) raw(node ast.Node) []byte {
+	if f.source() == nil {
+		return nil
+	}
+	start := node.Pos()
+	if doc := docComment(node); doc != nil {
+		start = doc.Pos()
+	}
+	from, to := f.fset.Position(start).Offset, f.fset.Position(node.End()).Offset
+	if from < 0 || to > len(f.src) || from > to {
+		return nil
+	}
+	return append([]byte(nil), f.src[from:to]...)
+}
+
 func (f *File) pkgComments() {
 	doc := f.file.Doc
 	if doc == nil {
 		return
 	}
+	f.s.printed = true
+	if *sigOnlyFlag {
+		fmt.Fprintf(f.s.out, "package %s\n", f.file.Name.Name)
+		return
+	}
+	if *htmlFragmentFlag {
+		fmt.Fprint(f.s.out, htmlDoc(f.file.Name.Name, "package "+f.file.Name.Name, f.packageURL(), f.linkPath(), doc))
+		return
+	}
+	if *chatFlag {
+		f.chatLink("", f.packageURL())
+		fmt.Fprint(f.s.out, chatDoc("package "+f.file.Name.Name, doc))
+		return
+	}
+	if *screenReaderFlag {
+		pkgPath := importPath(filepath.Dir(f.name))
+		if filepath.IsAbs(filepath.FromSlash(pkgPath)) {
+			pkgPath = ""
+		}
+		fmt.Fprint(f.s.out, screenReaderPackage(f.file.Name.Name, pkgPath, doc))
+		return
+	}
 	url := ""
-	if *urlFlag {
+	if f.s.showURL && scheme == nil {
 		url = f.packageURL() + "\n"
+	} else if f.s.showURL {
+		url = scheme.url(f.declaration()) + "\n"
 	}
 	docText := ""
-	if *docFlag {
-		docText = fmt.Sprintf("package %s\n%s\n\n", f.file.Name.Name, doc.Text())
+	if f.s.showDoc {
+		text := doc.Text()
+		if translated, ok := f.translation(""); ok {
+			text = translated
+		} else if *rawFlag {
+			text = string(f.raw(doc))
+		}
+		docText = fmt.Sprintf("package %s\n%s\n\n", f.file.Name.Name, text)
+	}
+	fmt.Fprintf(f.s.out, "%s%s%s%s", f.importLine(), url, f.sourcePos(f.fset.Position(doc.Pos())), docText)
+	if *licenseFlag {
+		fmt.Fprint(f.s.out, f.licenseNote())
+	}
+	if *riskFlag {
+		fmt.Fprint(f.s.out, f.packageRisk())
 	}
-	fmt.Printf("%s%s%s", url, f.sourcePos(f.fset.Position(doc.Pos())), docText)
 }
 
 func (f *File) packageURL() string {
 	s := strings.TrimPrefix(f.name, f.pathPrefix)
-	// Now we have a path with a final file name. Drop it.
-	if i := strings.LastIndex(s, slash); i > 0 {
+	// Now we have a path with a final file name. Drop it, even when the
+	// file is at the root, as in a module's root package.
+	if i := strings.LastIndex(s, slash); i >= 0 {
 		s = s[:i+1]
+	} else {
+		s = ""
 	}
-	return f.urlPrefix + s
+	u := modernURL(f.urlPrefix + s)
+	// A vendored package, such as GOROOT's cmd/vendor/golang.org/x/mod/semver,
+	// is documented under the path by which it is imported.
+	if p, ok := strings.CutPrefix(u, "https://pkg.go.dev/"); ok {
+		u = "https://pkg.go.dev/" + unvendor(p)
+	}
+	return u
 }
 
 func (f *File) sourcePos(posn token.Position) string {
-	if !*srcFlag {
+	if !f.s.showSrc {
 		return ""
 	}
 	return fmt.Sprintf("%s:%d:\n", posn.Filename, posn.Line)
 }
 
-func (f *File) nameURL(name string) string {
-	if !*urlFlag {
+// fileNote returns, if -byfile is set, a line naming the file, relative to
+// the package directory, that holds the position. Within a listing by file
+// the heading names it instead.
+func (f *File) fileNote(posn token.Position) string {
+	if !*byFileFlag || f.listing != nil && f.listing.file != nil {
 		return ""
 	}
-	return fmt.Sprintf("%s#%s\n", f.packageURL(), name)
+	return fmt.Sprintf("in %s\n", filepath.Base(posn.Filename))
 }
 
-func (f *File) methodURL(typ ast.Expr, name string) string {
-	if !*urlFlag {
+func (f *File) nameURL(node ast.Node, id *ast.Ident) string {
+	if !f.s.showURL {
 		return ""
 	}
-	var b bytes.Buffer
-	printer.Fprint(&b, f.fset, typ)
-	typeName := b.Bytes()
-	if len(typeName) > 0 && typeName[0] == '*' {
-		typeName = typeName[1:]
+	return f.declURL(nodeKind(node), id.Name, f.fset.Position(id.Pos()).Line)
+}
+
+// methodURL returns the link for the method declaration. The receiver's type
+// is named as the type checker resolves it, so that type parameters and
+// aliases do not lead the link astray.
+func (f *File) methodURL(decl *ast.FuncDecl) string {
+	if !f.s.showURL {
+		return ""
+	}
+	typeName := recvTypeName(decl)
+	if fn, ok := f.objs[decl.Name].(*types.Func); ok && receiverName(fn) != "" {
+		typeName = receiverName(fn)
 	}
-	return fmt.Sprintf("%s#%s.%s\n", f.packageURL(), typeName, name)
+	return f.declURL(typeKind, typeName+"."+decl.Name.Name, f.fset.Position(decl.Name.Pos()).Line)
+}
+
+// promotedURL returns the link for the method promoted to the type through
+// an embedded field, which is listed with that type rather than with the
+// type declaring it. The files are those of the type's package.
+func promotedURL(files []*File, tn *types.TypeName, method string) string {
+	for _, file := range files {
+		if !file.s.showURL {
+			return ""
+		}
+		if pos := file.fset.Position(tn.Pos()); pos.Filename == file.name {
+			return file.declURL(typeKind, tn.Name()+"."+method, pos.Line)
+		}
+	}
+	return ""
 }
 
 // Here follows the code to find and print a method (actually a method set, because
@@ -561,7 +2954,7 @@ func (f *File) methodSet(set *types.MethodSet) {
 	// Print them in order. The incoming method set is sorted by name.
 	for _, doc := range docs {
 		if doc != "" {
-			fmt.Print(doc)
+			fmt.Fprint(f.output(typeKind), doc)
 		}
 	}
 }
@@ -573,8 +2966,12 @@ func (visitor *methodVisitor) Visit(node ast.Node) ast.Visitor {
 		for i, method := range visitor.methods {
 			// If this is the right one, the position of the name of its identifier will match.
 			if method.Obj().Pos() == n.Name.Pos() {
-				n.Body = nil // TODO. Ugly - don't print the function body.
-				visitor.docs[method.index] = fmt.Sprintf("%s", visitor.File.docs(n))
+				if !visitor.s.full {
+					n.Body = nil // TODO. Ugly - don't print the function body.
+				}
+				if !*stableOnlyFlag || stable(visitor.File.stability(n, n.Name)) {
+					visitor.docs[method.index] = fmt.Sprintf("%s", visitor.File.docs(n))
+				}
 				// If this was the last method, we're done.
 				if len(visitor.methods) == 1 {
 					return nil
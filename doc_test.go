@@ -0,0 +1,1024 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestMain runs doc itself, not the tests, when runDoc starts the test
+// binary with $DOC_TEST_MAIN set.
+func TestMain(m *testing.M) {
+	if os.Getenv("DOC_TEST_MAIN") != "" {
+		main()
+		os.Exit(0)
+	}
+	dir, err := os.MkdirTemp("", "doc-test-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	docTestDir = dir
+	// The go command's build cache, kept when runDoc moves the user's
+	// cache directory, so that the standard library is not rebuilt.
+	if out, err := exec.Command("go", "env", "GOCACHE").Output(); err == nil {
+		goCache = strings.TrimSpace(string(out))
+	}
+	status := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(status)
+}
+
+// docTestDir holds the caches and configuration of the doc commands runDoc
+// runs, shared by them all.
+var docTestDir string
+
+// goCache is the go command's build cache.
+var goCache string
+
+// runDoc runs doc with the arguments in the directory, with its caches and
+// configuration directory in docTestDir, no colors and no network, and
+// returns what it writes to standard output and standard error and the
+// status with which it exits.
+func runDoc(t *testing.T, dir string, args ...string) (stdout, stderr string, status int) {
+	t.Helper()
+	return runDocInput(t, dir, "", args...)
+}
+
+// runDocInput is runDoc with the input as doc's standard input.
+func runDocInput(t *testing.T, dir, input string, args ...string) (stdout, stderr string, status int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"DOC_TEST_MAIN=1",
+		"XDG_CACHE_HOME="+filepath.Join(docTestDir, "cache"),
+		"XDG_CONFIG_HOME="+filepath.Join(docTestDir, "config"),
+		"GOCACHE="+goCache,
+		"NO_COLOR=1",
+		"GOFLAGS=-mod=mod",
+		"GOWORK=off",
+		"GOPROXY=off",
+	)
+	var out, errOut bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &errOut
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		status = exitErr.ExitCode()
+	case err != nil:
+		t.Fatal(err)
+	}
+	return out.String(), errOut.String(), status
+}
+
+// testModule is a module whose packages import one another, as
+// written by writeModule.
+var testModule = map[string]string{
+	"go.mod": "module example.com/m/v2\n\ngo 1.22\n",
+	"m.go": `// Package m is a test module.
+package m
+
+// Config configures things.
+type Config struct{ Name string }
+
+// Alias is another name for Config.
+type Alias = Config
+`,
+	"internal/auth/auth.go": `// Package auth authenticates.
+package auth
+
+import "example.com/m/v2"
+
+// Token holds a m.Config.
+type Token struct{ C m.Config }
+
+// Make makes a Token.
+func Make() Token { return Token{} }
+`,
+	"v/v.go": `package v
+
+import . "example.com/m/v2/internal/auth"
+
+var T = Make()
+`,
+}
+
+// shopModule is a module whose package shop spreads declarations of
+// every kind across two files, with another package shop beside it.
+var shopModule = map[string]string{
+	"go.mod": "module example.com/shop\n\ngo 1.22\n",
+	"shop/b.go": `package shop
+
+// Cart holds items.
+type Cart struct{ Items []Item }
+
+// Add adds an item to the cart.
+func (c *Cart) Add(i Item) { c.Items = append(c.Items, i) }
+
+// Currency is the currency of prices.
+const Currency = "EUR"
+`,
+	"shop/a.go": `// Package shop sells things.
+package shop
+
+// Item is a thing for sale.
+type Item struct {
+	Name  string
+	Price int
+}
+
+// Catalog lists the items for sale.
+var Catalog []Item
+
+// Checkout totals the cart.
+func Checkout(c *Cart) int { return 0 }
+
+// CatalogSize is the number of items.
+const CatalogSize = 10
+`,
+	"other/shop/shop.go": "// Package shop is another shop.\npackage shop\n\n// Other is elsewhere.\nfunc Other() {}\n",
+}
+
+// depModule returns the files of a module that requires golang.org/x/mod,
+// a dependency of doc's own module and so in the module cache, and calls
+// semver.Compare at line 10, column 21, of main.go.
+func depModule(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"go.mod": "module example.com/dep\n\ngo 1.26.0\n\nrequire golang.org/x/mod v0.41.0\n",
+		"go.sum": sumLines(t, "golang.org/x/mod"),
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/semver"
+)
+
+func main() {
+	fmt.Println(semver.Compare("v1", "v2"))
+}
+`,
+	}
+}
+
+// sumLines returns the lines of doc's own go.sum file for the modules.
+func sumLines(t *testing.T, modules ...string) string {
+	t.Helper()
+	sums, err := os.ReadFile("go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(string(sums), "\n") {
+		for _, m := range modules {
+			if strings.HasPrefix(line, m+" ") {
+				b.WriteString(line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeModule writes the files, keyed by slash-separated name, to a
+// temporary directory and makes it the current module for the test,
+// returning the directory.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, text := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(text), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldDir, oldPath := modDir, modPath
+	modDir, modPath = dir, modulePathIn(filepath.Join(dir, "go.mod"))
+	t.Cleanup(func() { modDir, modPath = oldDir, oldPath })
+	return dir
+}
+
+// exitStatus returns the status with which f exits, or 0 if it returns.
+func exitStatus(f func()) (status int) {
+	exitUnwinds = true
+	defer func() {
+		exitUnwinds = false
+		if code, ok := recover().(exitCode); ok {
+			status = int(code)
+		}
+	}()
+	f()
+	return 0
+}
+
+// defaultFlags sets the flags newSession sets in the session when none of
+// a kind is given, so that every kind of declaration, with its comment,
+// source and URL, is printed, and restores them when the test ends.
+func defaultFlags(t *testing.T) {
+	t.Helper()
+	flags := []*bool{constantFlag, functionFlag, methodFlag, typeFlag, variableFlag, docFlag, srcFlag, urlFlag}
+	old := make([]bool, len(flags))
+	for i, f := range flags {
+		old[i], *f = *f, true
+	}
+	t.Cleanup(func() {
+		for i, f := range flags {
+			*f = old[i]
+		}
+	})
+}
+
+// setFlag sets the boolean flag for the test and restores it when the
+// test ends.
+func setFlag(t *testing.T, flag *bool, value bool) {
+	t.Helper()
+	old := *flag
+	*flag = value
+	t.Cleanup(func() { *flag = old })
+}
+
+func TestLookupModes(t *testing.T) {
+	dir := writeModule(t, testModule)
+	auth := filepath.Join(dir, "internal", "auth")
+	archive := writeTestArchive(t, "src.tar.gz", map[string]string{"auth/auth.go": testModule["internal/auth/auth.go"]})
+	tests := []struct {
+		mode    string
+		flags   []*bool // Set for the lookup.
+		lookup  func(s *session)
+		want    []string
+		notWant []string
+	}{
+		{
+			mode:   "pkg.Name",
+			lookup: func(s *session) { s.firstMatch("auth", "Token") },
+			want:   []string{"// Token holds a m.Config.", "type Token struct{ C m.Config }"},
+		},
+		{
+			mode:    "a name in a directory",
+			lookup:  func(s *session) { s.lookInDirectory(dir, "", "config") },
+			want:    []string{"type Config struct{ Name string }"},
+			notWant: []string{"Alias"},
+		},
+		{
+			mode:    "-r pattern",
+			flags:   []*bool{regexpFlag},
+			lookup:  func(s *session) { s.lookInDirectory(auth, "auth", "m.*") },
+			want:    []string{"func Make() Token"},
+			notWant: []string{"type Token"},
+		},
+		{
+			mode:   "-package",
+			flags:  []*bool{packageFlag},
+			lookup: func(s *session) { s.lookInDirectory(dir, "m", "") },
+			want:   []string{"Package m is a test module."},
+		},
+		{
+			mode:   "a field through a chain",
+			lookup: func(s *session) { s.nested("auth", []string{"Token", "C", "Name"}) },
+			want:   []string{"Name string"},
+		},
+		{
+			mode:    "-filter",
+			flags:   []*bool{regexpFlag},
+			lookup:  func(s *session) { s.setFilter(`kind == "func"`); s.lookInDirectory(auth, "auth", ".*") },
+			want:    []string{"func Make() Token"},
+			notWant: []string{"type Token"},
+		},
+		{
+			mode:   "-archive",
+			lookup: func(s *session) { s.lookInArchive(archive, "auth", "Make") },
+			want:   []string{"// Make makes a Token.", "func Make() Token"},
+		},
+		{
+			mode:   "-files",
+			lookup: func(s *session) { s.lookInFiles([]string{filepath.Join(dir, "m.go")}, "Alias") },
+			want:   []string{"// Alias is another name for Config.", "type Alias = Config"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			defaultFlags(t)
+			for _, flag := range test.flags {
+				setFlag(t, flag, true)
+			}
+			var out bytes.Buffer
+			test.lookup(newSession(&out))
+			for _, want := range test.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("printed\n%s\nwant it to contain %q", out.String(), want)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if strings.Contains(out.String(), notWant) {
+					t.Errorf("printed\n%s\nwant it not to contain %q", out.String(), notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestSessionsInTurn(t *testing.T) {
+	dir := writeModule(t, testModule)
+	defaultFlags(t)
+	var pkgOut, nameOut bytes.Buffer
+	pkgSession, nameSession := newSession(&pkgOut), newSession(&nameOut)
+	pkgSession.pkgDoc = true // As -at sets it for a package name.
+	pkgSession.lookInDirectory(dir, "m", "")
+	nameSession.lookInDirectory(dir, "", "config")
+	if !strings.Contains(pkgOut.String(), "Package m is a test module.") {
+		t.Errorf("package lookup printed\n%s\nwant the package's documentation", pkgOut.String())
+	}
+	if !strings.Contains(nameOut.String(), "type Config struct{ Name string }") || strings.Contains(nameOut.String(), "Package m is") {
+		t.Errorf("name lookup printed\n%s\nwant Config's declaration alone", nameOut.String())
+	}
+}
+
+func TestPackageURL(t *testing.T) {
+	goroot := filepath.Join(string(filepath.Separator)+"goroot", "src")
+	mod := string(filepath.Separator) + "mod"
+	tests := []struct {
+		name       string // Of the file.
+		pathPrefix string
+		urlPrefix  string
+		want       string
+	}{
+		{filepath.Join(goroot, "strings", "reader.go"), goroot, "http://golang.org/pkg", "https://pkg.go.dev/strings"},
+		{filepath.Join(goroot, "cmd", "go", "main.go"), filepath.Join(goroot, "cmd"), "http://golang.org/cmd", "https://pkg.go.dev/cmd/go"},
+		{filepath.Join(mod, "m.go"), mod, godocOrg + "/example.com/m", "https://pkg.go.dev/example.com/m"}, // A module's root package.
+		{filepath.Join(mod, "internal", "auth", "auth.go"), mod, godocOrg + "/example.com/m", "https://pkg.go.dev/example.com/m/internal/auth"},
+		{"m.go", "", godocOrg, "https://pkg.go.dev"}, // No directory at all.
+		{filepath.Join(goroot, "cmd", "vendor", "golang.org", "x", "mod", "semver", "semver.go"), filepath.Join(goroot, "cmd"), "http://golang.org/cmd", "https://pkg.go.dev/golang.org/x/mod/semver"},
+		{filepath.Join(goroot, "vendor", "golang.org", "x", "net", "idna", "idna.go"), goroot, "http://golang.org/pkg", "https://pkg.go.dev/golang.org/x/net/idna"},
+		{filepath.Join(mod, "vendor", "example.com", "dep", "dep.go"), mod, godocOrg + "/example.com/m", "https://pkg.go.dev/example.com/dep"},
+	}
+	for _, test := range tests {
+		f := &File{name: test.name, pathPrefix: test.pathPrefix, urlPrefix: test.urlPrefix}
+		if got := f.packageURL(); got != test.want {
+			t.Errorf("packageURL of %s = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func FuzzSplitArgument(f *testing.F) {
+	for _, arg := range []string{"bytes.Buffer", "Buffer", "example.com/a/b.Name", "example.com/a.b/c.Name.Method", ".", "a.", "/.", "Server.TLSConfig.MinVersion", "Read.*"} {
+		f.Add(arg)
+	}
+	f.Fuzz(func(t *testing.T, arg string) {
+		if strings.Contains(arg, ".") {
+			if pkg, name := split(arg); pkg+"."+name != arg {
+				t.Fatalf("split(%q) = %q, %q", arg, pkg, name)
+			}
+		}
+		if pkgPath, name, ok := splitImportSymbol(arg); ok {
+			// The split is at the first dot after the last slash.
+			elem := pkgPath[strings.LastIndex(pkgPath, "/")+1:]
+			if pkgPath+"."+name != arg || strings.Contains(elem, ".") || strings.Contains(name, "/") {
+				t.Fatalf("splitImportSymbol(%q) = %q, %q", arg, pkgPath, name)
+			}
+		}
+		if isSelectorChain(arg) && regexp.QuoteMeta(arg) != strings.ReplaceAll(arg, ".", `\.`) {
+			t.Fatalf("isSelectorChain(%q) holds for a regular expression", arg)
+		}
+	})
+}
+
+func TestDirForImportDependency(t *testing.T) {
+	dir := writeModule(t, depModule(t))
+	tests := []struct {
+		pkgPath string
+		srcDir  string
+		want    string // The end of the directory, or empty if none is wanted.
+	}{
+		{"golang.org/x/mod/semver", "", filepath.Join("golang.org", "x", "mod@v0.41.0", "semver")},
+		{"golang.org/x/mod/semver", dir, filepath.Join("golang.org", "x", "mod@v0.41.0", "semver")},
+		{"strings", "", filepath.Join("src", "strings")},
+		{"example.com/dep", "", dir},
+		{"example.com/nonexistent", "", ""},
+	}
+	for _, test := range tests {
+		got := dirForImport(test.pkgPath, test.srcDir)
+		if test.want == "" && got != "" || !strings.HasSuffix(got, test.want) {
+			t.Errorf("dirForImport(%q, %q) = %q, want one ending in %q", test.pkgPath, test.srcDir, got, test.want)
+		}
+	}
+}
+
+// shownSymbols returns the symbols, as import path#Name, whose pkg.go.dev
+// URLs doc printed, in order.
+func shownSymbols(out string) []string {
+	var syms []string
+	for _, line := range strings.Split(out, "\n") {
+		if sym, ok := strings.CutPrefix(line, "https://pkg.go.dev/"); ok {
+			syms = append(syms, sym)
+		}
+	}
+	return syms
+}
+
+func TestExclude(t *testing.T) {
+	dir := writeModule(t, testModule)
+	tests := []struct {
+		exclude string
+		want    []string
+		status  int
+	}{
+		{
+			exclude: "alias", // Case is ignored.
+			want:    []string{"example.com/m/v2#Config", "example.com/m/v2/internal/auth#Token", "example.com/m/v2/internal/auth#Make", "example.com/m/v2/v#T"},
+		},
+		{
+			exclude: ".*auth|T", // The whole name or import path must match.
+			want:    []string{"example.com/m/v2#Config", "example.com/m/v2#Alias", "example.com/m/v2#Config"},
+		},
+		{
+			exclude: "auth", // Not the whole import path.
+			want:    []string{"example.com/m/v2#Config", "example.com/m/v2#Alias", "example.com/m/v2#Config", "example.com/m/v2/internal/auth#Token", "example.com/m/v2/internal/auth#Make", "example.com/m/v2/v#T"},
+		},
+		{exclude: "(", status: 2},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, "-exclude", test.exclude, "-r", ".*")
+		if status != test.status {
+			t.Errorf("-exclude %s exited with %d, want %d; stderr:\n%s", test.exclude, status, test.status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("-exclude %s showed %q, want %q", test.exclude, got, test.want)
+		}
+	}
+}
+
+// outline returns the section headings, file names and symbols of a
+// listing doc printed, one to a line, with the symbols as #Name.
+func outline(out string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(out, "\n") {
+		if _, sym, ok := strings.Cut(line, "https://pkg.go.dev/"); ok {
+			_, name, _ := strings.Cut(sym, "#")
+			line = "#" + name
+		} else if !slices.Contains(kindHeading[:], line) && !strings.HasSuffix(line, ".go") {
+			continue
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func TestSortedListing(t *testing.T) {
+	dir := writeModule(t, shopModule)
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{
+			args: []string{"-r", "shop", "c.*"},
+			want: "Constants\n#CatalogSize\n#Currency\nVariables\n#Catalog\nFunctions\n#Checkout\nTypes\n#Cart\n",
+		},
+		{
+			args: []string{"-r", "c.*"}, // Across packages, in the order found.
+			want: "#Catalog\n#Checkout\n#CatalogSize\n#Cart\n#Currency\n",
+		},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := outline(out); got != test.want {
+			t.Errorf("doc %q listed\n%s\nwant\n%s", test.args, got, test.want)
+		}
+	}
+	// The methods of a type are listed with it.
+	out, _, _ := runDoc(t, dir, "-r", "shop", "ca.t")
+	if !strings.Contains(out, "Types\n") || !strings.Contains(out, "func (c *Cart) Add(i Item)") {
+		t.Errorf("doc -r shop ca.t printed\n%s\nwant Cart with its method Add under Types", out)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":             "module example.com/l\n\ngo 1.22\n",
+		"strings/strings.go": "package strings\n\n// Cut is a local cut.\nfunc Cut() {}\n",
+	})
+	tests := []struct {
+		dir    string
+		args   []string
+		want   []string
+		status int
+	}{
+		{dir: dir, args: []string{"-local", "strings.Cut"}, want: []string{"example.com/l/strings#Cut"}},
+		{dir: dir, args: []string{"-local", "strings.Clone"}}, // Only in the standard library.
+		{dir: dir, args: []string{"-local", "-r", "cu.*"}, want: []string{"example.com/l/strings#Cut"}},
+		{dir: t.TempDir(), args: []string{"-local", "strings.Cut"}, status: 2},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, test.dir, test.args...)
+		if status != test.status {
+			t.Errorf("doc %q exited with %d, want %d; stderr:\n%s", test.args, status, test.status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q showed %q, want %q", test.args, got, test.want)
+		}
+	}
+}
+
+func TestRelativePackage(t *testing.T) {
+	dir := writeModule(t, shopModule)
+	tests := []struct {
+		dir  string
+		args []string
+		want []string
+	}{
+		{dir, []string{"./shop", "Cart"}, []string{"example.com/shop/shop#Cart"}},
+		{dir, []string{"./shop", "Other"}, nil},
+		{dir, []string{"./other/shop", "Other"}, []string{"example.com/shop/other/shop#Other"}},
+		{filepath.Join(dir, "shop"), []string{".", "Checkout"}, []string{"example.com/shop/shop#Checkout"}},
+		{filepath.Join(dir, "shop"), []string{"../other/shop", "Other"}, []string{"example.com/shop/other/shop#Other"}},
+		{dir, []string{filepath.Join(dir, "shop"), "Item"}, []string{"example.com/shop/shop#Item"}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, test.dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q in %s showed %q, want %q", test.args, test.dir, got, test.want)
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/a\n\ngo 1.24\n",
+		"a/a.go": `package a
+
+import "example.com/a/b"
+
+// List is a list.
+type List[T any] struct{ Elems []T }
+
+type Ints = List[int]
+
+type Named = b.Named
+
+type unexported struct{}
+
+type Hidden = unexported
+
+// Defined is not an alias.
+type Defined b.Named
+`,
+		"b/b.go": "package b\n\n// Named is declared in b.\ntype Named struct{}\n",
+	})
+	tests := []struct {
+		name  string
+		alias string // The alias line, if any.
+		want  []string
+	}{
+		{"Ints", "Ints is an alias for example.com/a/a.List", []string{"example.com/a/a#Ints", "example.com/a/a#List"}},
+		{"Named", "Named is an alias for example.com/a/b.Named", []string{"example.com/a/a#Named", "example.com/a/b#Named"}},
+		{"Hidden", "Hidden is an alias for example.com/a/a.unexported", []string{"example.com/a/a#Hidden"}},
+		{"Defined", "", []string{"example.com/a/a#Defined"}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, "a", test.name)
+		if status != 0 {
+			t.Errorf("doc a %s exited with %d; stderr:\n%s", test.name, status, stderr)
+			continue
+		}
+		if got := strings.Contains(out, " is an alias for "); got != (test.alias != "") || !strings.Contains(out, test.alias) {
+			t.Errorf("doc a %s printed\n%s\nwant the line %q", test.name, out, test.alias)
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc a %s showed %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRaw(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/raw\n\ngo 1.22\n",
+		"r/r.go": `/*
+Package r keeps its comments.
+*/
+package r
+
+/* Block is commented
+   with a block.  */
+func Block(a,  b int) {   }
+
+// Line is commented
+//   with lines.
+type Line struct {
+	X int // The X.
+}
+
+func Bare() {}
+`,
+	})
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-raw", "r", "Block"}, "/* Block is commented\n   with a block.  */\nfunc Block(a,  b int)\n"},
+		{[]string{"-raw", "r", "Line"}, "// Line is commented\n//   with lines.\ntype Line struct {\n\tX int // The X.\n}\n"},
+		{[]string{"-raw", "r", "Bare"}, "r.go:16:\nfunc Bare()\n"},
+		{[]string{"-raw", "-pkg", "r"}, "package r\n/*\nPackage r keeps its comments.\n*/\n"},
+		{[]string{"r", "Block"}, "/*\nBlock is commented\n\n\twith a block.\n*/\nfunc Block(a, b int)\n"}, // Reformatted.
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if !strings.Contains(out, test.want) {
+			t.Errorf("doc %q printed\n%s\nwant it to contain\n%s", test.args, out, test.want)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/c\n\ngo 1.22\n",
+		"c/c.go": "package c\n\n// A is first.\nfunc A() {}\n\n// B is last.\nconst B = 1\n",
+	})
+	tests := []struct {
+		args []string
+		want string // The lines of context.
+	}{
+		{[]string{"-C", "1", "c", "A"}, "\n3-// A is first.\n4:func A() {}\n5-\n\n"},
+		{[]string{"-C", "2", "c", "B"}, "\n5-\n6-// B is last.\n7:const B = 1\n\n"}, // No line after the last.
+		{[]string{"-C", "9", "c", "A"}, "\n1-package c\n2-\n3-// A is first.\n4:func A() {}\n5-\n6-// B is last.\n7-const B = 1\n\n"},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if !strings.HasSuffix(out, test.want) {
+			t.Errorf("doc %q printed\n%s\nwant it to end with\n%s", test.args, out, test.want)
+		}
+	}
+	if out, _, _ := runDoc(t, dir, "c", "A"); strings.Contains(out, "4:func") {
+		t.Errorf("doc c A printed\n%s\nwant no context without -C", out)
+	}
+}
+
+func TestSearchFileSize(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":     "module example.com/g\n\ngo 1.22\n",
+		"g/big.go":   "package g\n\n// Big is generated.\nfunc Big() {}\n\n" + strings.Repeat("// Padding.\n", 20),
+		"g/small.go": "package g\n\n// Small is written by hand.\nfunc Small() {}\n",
+	})
+	conf := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(conf, []byte("limit.searchfilesize 200\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCCONFIG", conf)
+	tests := []struct {
+		args    []string
+		want    []string
+		skipped bool // Whether big.go is skipped with a notice.
+	}{
+		{args: []string{"Big"}, skipped: true},
+		{args: []string{"Small"}, want: []string{"example.com/g/g#Small"}, skipped: true},
+		{args: []string{"g", "Big"}, want: []string{"example.com/g/g#Big"}}, // A named package is parsed in full.
+		{args: []string{"g.Big"}, want: []string{"example.com/g/g#Big"}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q showed %q, want %q", test.args, got, test.want)
+		}
+		if got := strings.Contains(stderr, "big.go: larger than 200 bytes"); got != test.skipped {
+			t.Errorf("doc %q wrote\n%s\nto stderr; notice of skipping big.go: %t, want %t", test.args, stderr, got, test.skipped)
+		}
+	}
+}
+
+func TestSearchOrder(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":             "module example.com/l\n\ngo 1.22\n",
+		"strings/strings.go": "package strings\n\n// Cut is a local cut.\nfunc Cut() {}\n",
+	})
+	goPath := t.TempDir()
+	src := filepath.Join(goPath, "src", "example.org", "strings")
+	if err := os.MkdirAll(src, 0777); err != nil {
+		t.Fatal(err)
+	}
+	err := os.WriteFile(filepath.Join(src, "strings.go"), []byte("package strings\n\n// Cut is a GOPATH cut.\nfunc Cut() {}\n\n// Extra is only in GOPATH.\nfunc Extra() {}\n"), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOPATH", goPath)
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"strings.Cut"}, []string{"example.com/l/strings#Cut"}},
+		{[]string{"strings", "Cut"}, []string{"example.com/l/strings#Cut"}},
+		{[]string{"-all-roots", "strings.Cut"}, []string{"example.com/l/strings#Cut", "strings#Cut", "example.org/strings#Cut"}},
+		{[]string{"strings.Clone"}, []string{"strings#Clone"}},
+		{[]string{"strings.Extra"}, []string{"example.org/strings#Extra"}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q showed %q, want %q", test.args, got, test.want)
+		}
+	}
+}
+
+func TestFirstMatch(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":         "module example.com/u\n\ngo 1.22\n",
+		"a/util/util.go": "package util\n\n// Name is in a.\nfunc Name() {}\n",
+		"b/util/util.go": "package util\n\n// Name is in b.\nfunc Name() {}\n\n// Only is only in b.\nfunc Only() {}\n",
+	})
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"util.Name"}, []string{"example.com/u/a/util#Name"}},
+		{[]string{"util.Only"}, []string{"example.com/u/b/util#Only"}},
+		{[]string{"-all-roots", "util.Name"}, []string{"example.com/u/a/util#Name", "example.com/u/b/util#Name"}},
+		{[]string{"./b/util", "Name"}, []string{"example.com/u/b/util#Name"}},
+		{[]string{"util.Missing"}, nil},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q showed %q, want %q", test.args, got, test.want)
+		}
+	}
+	// The walk stops at the first match, far short of GOROOT.
+	_, stderr, _ := runDoc(t, dir, "-time", "util.Name")
+	var scanned int
+	if _, err := fmt.Sscanf(stderr, "doc: %d directories scanned", &scanned); err != nil || scanned > 10 {
+		t.Errorf("doc -time util.Name reported\n%s\nwant at most 10 directories scanned", stderr)
+	}
+}
+
+func TestMatchedDeclarations(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/op\n\ngo 1.22\n",
+		"p/a.go": `package p
+
+// Count counts.
+func Count() int { return 0 }
+
+// T is a type.
+type T struct{}
+
+// Count counts Ts.
+func (T) Count() int { return 0 }
+
+// Other is not wanted.
+func Other() {}
+`,
+		"p/b.go": `package p
+
+// Counter holds a count.
+type Counter struct {
+	Count int // The count.
+}
+
+// Total is the total.
+var Total int
+`,
+	})
+	tests := []struct {
+		args []string
+		want []string // Each printed once, in order.
+	}{
+		{[]string{"p", "count"}, []string{"example.com/op/p#Count", "example.com/op/p#T.Count"}},
+		{[]string{"-m", "p", "count"}, []string{"example.com/op/p#T.Count"}},
+		{[]string{"-f", "p", "count"}, []string{"example.com/op/p#Count"}},
+		{[]string{"p", "counter"}, []string{"example.com/op/p#Counter"}},
+		{[]string{"p", "total"}, []string{"example.com/op/p#Total"}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q showed %q, want %q", test.args, got, test.want)
+		}
+		if strings.Contains(out, "Other") {
+			t.Errorf("doc %q printed\n%s\nwant no unmatched declarations", test.args, out)
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := writeModule(t, map[string]string{ // No go.mod: a script.
+		"a.go": "// Package s is a script.\npackage s\n\n// Run runs.\nfunc Run() {}\n",
+		"b.go": "package s\n\n// Config configures Run.\ntype Config struct{}\n",
+		"c.go": "package other\n\nfunc X() {}\n",
+	})
+	tests := []struct {
+		args   []string
+		want   string
+		status int
+	}{
+		{args: []string{"-files", "a.go", "b.go", "Config"}, want: "b.go:4:\n// Config configures Run.\ntype Config struct{}\n"},
+		{args: []string{"-files", "a.go", "b.go", "r.*"}, want: "Functions\n\n[1] "},
+		{args: []string{"-files", "b.go", "Run"}},
+		{args: []string{"-pkg", "-files", "a.go", "b.go"}, want: "package s\nPackage s is a script.\n"},
+		{args: []string{"-files", "a.go", "c.go", "X"}, want: "c.go is in package other, not s\n", status: 2},
+		{args: []string{"-files", "missing.go", "X"}, want: "missing.go: no such file or directory\n", status: 2},
+		{args: []string{"-files"}, want: "usage:", status: 2},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != test.status {
+			t.Errorf("doc %q exited with %d, want %d; stderr:\n%s", test.args, status, test.status, stderr)
+			continue
+		}
+		if status != 0 {
+			out = stderr
+		}
+		if !strings.Contains(out, test.want) || test.want == "" && out != "" {
+			t.Errorf("doc %q printed\n%s\nwant it to contain\n%s", test.args, out, test.want)
+		}
+	}
+}
+
+func TestStdin(t *testing.T) {
+	const src = "// Package s is piped.\npackage s\n\n// Run runs.\nfunc Run() {}\n\n// Config configures Run.\ntype Config struct{}\n"
+	tests := []struct {
+		input  string
+		args   []string
+		want   string
+		status int
+	}{
+		{input: src, args: []string{"-stdin", "Config"}, want: "<stdin>:8:\n// Config configures Run.\ntype Config struct{}\n"},
+		{input: src, args: []string{"-stdin", "r.*"}, want: "Functions\n\n[1] <stdin>:5:\n// Run runs.\nfunc Run()\n"},
+		{input: src, args: []string{"-stdin", "Missing"}},
+		{input: src, args: []string{"-pkg", "-stdin"}, want: "package s\nPackage s is piped.\n"},
+		{input: "\uFEFF" + strings.ReplaceAll(src, "\n", "\r\n"), args: []string{"-stdin", "Run"}, want: "// Run runs.\nfunc Run()\n"},
+		{input: "package s\nfunc (", args: []string{"-stdin", "Run"}, want: "doc: <stdin>:", status: 2},
+		{input: src, args: []string{"-stdin"}, want: "usage:", status: 2},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDocInput(t, t.TempDir(), test.input, test.args...)
+		if status != test.status {
+			t.Errorf("doc %q exited with %d, want %d; stderr:\n%s", test.args, status, test.status, stderr)
+			continue
+		}
+		if status != 0 {
+			out = stderr
+		}
+		if !strings.Contains(out, test.want) || test.want == "" && out != "" {
+			t.Errorf("doc %q printed\n%s\nwant it to contain\n%s", test.args, out, test.want)
+		}
+		if status == 0 && strings.Contains(out, "pkg.go.dev") {
+			t.Errorf("doc %q printed\n%s\nwant no URL for standard input", test.args, out)
+		}
+	}
+}
+
+func TestByFile(t *testing.T) {
+	dir := writeModule(t, shopModule)
+	tests := []struct {
+		args []string
+		want string // The outline, with the "in file" notes.
+	}{
+		{[]string{"-byfile", "-r", "shop", "c.*"}, "a.go\n#Catalog\n#Checkout\n#CatalogSize\nb.go\n#Cart\n#Currency\n"},
+		{[]string{"-byfile", "shop", "Cart"}, "#Cart\nin b.go\n"},
+		{[]string{"-byfile", "shop", "Checkout"}, "#Checkout\nin a.go\n"},
+		{[]string{"-byfile", "-r", "ca.*"}, "#Catalog\nin a.go\n#CatalogSize\nin a.go\n#Cart\nin b.go\n"},
+		{[]string{"shop", "Cart"}, "#Cart\n"},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := outline(out); got != test.want {
+			t.Errorf("doc %q listed\n%s\nwant\n%s", test.args, got, test.want)
+		}
+	}
+}
+
+func TestMethodURLs(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/mu\n\ngo 1.24\n",
+		"mu/mu.go": `package mu
+
+// List is generic.
+type List[T any] struct{ elems []T }
+
+// Len returns the length.
+func (l *List[T]) Len() int { return len(l.elems) }
+
+// Base is aliased.
+type Base struct{}
+
+// Alias is another name for Base.
+type Alias = Base
+
+// Reset resets.
+func (a *Alias) Reset() {}
+`,
+		"fs/fs.go": ioModule["fs/fs.go"],
+	})
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-m", "mu", "len"}, []string{"example.com/mu/mu#List.Len"}},
+		{[]string{"-m", "mu", "reset"}, []string{"example.com/mu/mu#Base.Reset"}}, // As pkg.go.dev lists it.
+		{[]string{"-m", "fs", "write"}, []string{"example.com/mu/fs#File.Write"}},
+		{[]string{"-method-of", "fs.File", "io.Writer.Write"}, []string{"example.com/mu/fs#File.Write"}},
+		{[]string{"-method-of", "fs.Logger", "io.Writer.Write"}, []string{"example.com/mu/fs#Logger.Write"}}, // Promoted.
+		{[]string{"-method-of", "fs.Buffered", "io.Closer.Close"}, []string{"example.com/mu/fs#Buffered.Close"}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q showed %q, want %q", test.args, got, test.want)
+		}
+	}
+}
+
+func TestExcludeDir(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":             "module example.com/x\n\ngo 1.22\n",
+		"a/util/util.go":     "package util\n\n// Name is in a.\nfunc Name() {}\n",
+		"a/util/sub/util.go": "package util\n\n// Name is in a's sub.\nfunc Name() {}\n",
+		"b/util/util.go":     "package util\n\n// Name is in b.\nfunc Name() {}\n",
+	})
+	conf := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(conf, []byte("exclude.dir $DOC_TEST_DIR/b/...\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOC_TEST_DIR", dir)
+	tests := []struct {
+		config bool
+		args   []string
+		want   string // The outline of what is printed.
+	}{
+		{args: []string{"-local", "-r", "name"}, want: "#Name\n#Name\n#Name\n"},
+		{args: []string{"-exclude-dir", filepath.Join(dir, "a", "util"), "-local", "-r", "name"}, want: "#Name\n#Name\n"},
+		{args: []string{"-exclude-dir", filepath.Join(dir, "a", "util") + "/...", "-local", "-r", "name"}, want: "#Name\n"},
+		{args: []string{"-exclude-dir", "./a/...,./b/...", "-local", "-r", "name"}, want: ""}, // Relative to the current directory.
+		{args: []string{"-exclude-dir", "$DOC_TEST_DIR/a/...", "-local", "util.Name"}, want: "#Name\n"},
+		{config: true, args: []string{"-local", "-r", "name"}, want: "#Name\n#Name\n"},
+		{config: true, args: []string{"-exclude-dir", "./a/util", "-local", "-r", "name"}, want: "#Name\n"},
+	}
+	for _, test := range tests {
+		if test.config {
+			t.Setenv("DOCCONFIG", conf)
+		}
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := outline(out); got != test.want {
+			t.Errorf("doc %q (configuration %t) listed\n%s\nwant\n%s", test.args, test.config, got, test.want)
+		}
+	}
+}
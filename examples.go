@@ -0,0 +1,155 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxExamples is the number of examples examplesFor prints.
+const maxExamples = 5
+
+// A usageExample is an example function, in some package's tests, that
+// uses the symbol examplesFor is looking for.
+type usageExample struct {
+	name  string // As in ExampleReader_Read.
+	path  string // Import path of the package it tests.
+	pos   token.Position
+	code  string
+	uses  int  // How many times it refers to the symbol.
+	local bool // Whether it is an example of the symbol's own package.
+}
+
+// examplesFor prints the examples, from the tests of the packages in
+// GOROOT, the module and GOPATH, whose code uses the symbol, given as
+// pkg.Name or pkg.Type.Method, where pkg may be an import path. A package
+// name stands for the first package with that name, as the lookup orders
+// them, that declares the symbol. Uses are matched by import path, so that
+// math/rand, math/rand/v2 and crypto/rand are told apart. Examples from
+// other packages, which show the symbol in use rather than on display,
+// come first; then those that use it most, and then the shortest.
+func (s *session) examplesFor(arg string) {
+	if !strings.Contains(arg, ".") {
+		usage()
+	}
+	var target, name string
+	if strings.Contains(arg, "/") {
+		var ok bool
+		if target, name, ok = splitImportSymbol(arg); !ok {
+			usage()
+		}
+	} else {
+		var pkg string
+		pkg, name = split(arg)
+		typeName, _, _ := strings.Cut(name, ".")
+		if target = s.declaringPackage(pkg, typeName); target == "" {
+			fmt.Fprintf(os.Stderr, "doc: -examples-for: no package %s declares %s\n", pkg, typeName)
+			exit(1)
+		}
+	}
+	typeName, method, isMethod := strings.Cut(name, ".")
+	dirs := s.pathsFor(goRoot, "")
+	dirs = append(dirs, s.searchDirs()...)
+	var found []usageExample
+	for _, dir := range dirs {
+		fset := token.NewFileSet()
+		isTest := func(info os.FileInfo) bool { return strings.HasSuffix(info.Name(), "_test.go") }
+		pkgs, _ := parseDir(fset, dir, isTest, parser.ParseComments) // Ignore the error.
+		from := importPath(dir)
+		for _, astPkg := range pkgs {
+			for _, file := range astPkg.Files {
+				local := from == target
+				qualifiers := make(map[string]bool) // Names by which the file refers to the package.
+				for _, imp := range file.Imports {
+					if p, _ := strconv.Unquote(imp.Path.Value); p == target {
+						qualifiers[importName(imp)] = true
+					}
+				}
+				if len(qualifiers) == 0 && !local {
+					continue
+				}
+				for _, ex := range doc.Examples(file) {
+					// Without types, a method is known only by its name, so
+					// any use of the package must do to tie the two together.
+					uses, methods, pkgUses := 0, 0, 0
+					ast.Inspect(ex.Code, func(node ast.Node) bool {
+						switch n := node.(type) {
+						case *ast.SelectorExpr:
+							if x, ok := n.X.(*ast.Ident); ok && qualifiers[x.Name] {
+								pkgUses++
+								if n.Sel.Name == typeName {
+									uses++
+								}
+							} else if isMethod && n.Sel.Name == method {
+								methods++
+							}
+						case *ast.Ident:
+							if local && !strings.HasSuffix(file.Name.Name, "_test") && n.Name == typeName {
+								uses++
+							}
+						}
+						return true
+					})
+					if isMethod && (methods == 0 || uses == 0 && pkgUses == 0 && !local) {
+						continue
+					}
+					if !isMethod && uses == 0 {
+						continue
+					}
+					found = append(found, usageExample{
+						name:  "Example" + ex.Name,
+						path:  from,
+						pos:   fset.Position(ex.Code.Pos()),
+						code:  exampleCode(fset, ex.Code),
+						uses:  uses + methods,
+						local: local,
+					})
+				}
+			}
+		}
+	}
+	if len(found) == 0 {
+		fmt.Fprintf(os.Stderr, "doc: -examples-for: no example uses %s\n", arg)
+		exit(1)
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.local != b.local {
+			return !a.local
+		}
+		if a.uses != b.uses {
+			return a.uses > b.uses
+		}
+		return strings.Count(a.code, "\n") < strings.Count(b.code, "\n")
+	})
+	if len(found) > maxExamples {
+		found = found[:maxExamples]
+	}
+	for _, ex := range found {
+		fmt.Fprintf(s.out, "%s in %s\n%s:%d:\n%s\n\n", ex.name, ex.path, ex.pos.Filename, ex.pos.Line, indent(ex.code))
+	}
+}
+
+// declaringPackage returns the import path of the first package named pkg,
+// in the order of packageTiers, that declares the exported name, or "" if
+// none does.
+func (s *session) declaringPackage(pkg, name string) string {
+	for _, dir := range s.packageDirs(pkg) {
+		for _, sym := range exportedSymbols(dir) {
+			if sym.name == name {
+				return sym.path
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExamplesFor(t *testing.T) {
+	fakeGOROOT(t, nil)
+	old := goPaths
+	goPaths = nil
+	defer func() { goPaths = old }()
+	writeModule(t, map[string]string{
+		"go.mod": "module example.com/ex\n\ngo 1.22\n",
+		"shop/shop.go": `package shop
+
+// Cart holds items.
+type Cart struct{}
+
+// Add adds.
+func (c *Cart) Add(item string) {}
+
+// Total totals.
+func Total() int { return 0 }
+`,
+		"shop/example_test.go": `package shop_test
+
+import "example.com/ex/shop"
+
+func ExampleTotal() {
+	shop.Total()
+}
+
+func ExampleCart_Add() {
+	var c shop.Cart
+	c.Add("x")
+}
+`,
+		"user/user.go": "package user\n",
+		"user/example_test.go": `package user_test
+
+import (
+	"fmt"
+
+	s "example.com/ex/shop"
+)
+
+func Example() {
+	fmt.Println(s.Total() + s.Total())
+}
+
+func ExampleLong() {
+	var c s.Cart
+	c.Add("a")
+	c.Add("b")
+	fmt.Println(s.Total())
+}
+`,
+		// Another package named shop, whose Total is not the one wanted.
+		"v1/shop/shop.go": "package shop\n\n// Total totals.\nfunc Total() int { return 0 }\n",
+		"other/example_test.go": `package other_test
+
+import "example.com/ex/v1/shop"
+
+func Example() {
+	shop.Total()
+}
+`,
+	})
+	tests := []struct {
+		arg    string
+		want   []string // Headers of the examples, in order.
+		status int
+	}{
+		// Examples of other packages come first, those using the symbol
+		// most first among them.
+		{arg: "shop.Total", want: []string{"Example in example.com/ex/user", "ExampleLong in example.com/ex/user", "ExampleTotal in example.com/ex/shop"}},
+		{arg: "example.com/ex/v1/shop.Total", want: []string{"Example in example.com/ex/other"}},
+		{arg: "shop.Cart.Add", want: []string{"ExampleLong in example.com/ex/user", "ExampleCart_Add in example.com/ex/shop"}},
+		{arg: "shop.Nothing", status: 1},
+		{arg: "shop", status: 2},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).examplesFor(test.arg) })
+		if status != test.status {
+			t.Errorf("-examples-for %s exited with %d, want %d", test.arg, status, test.status)
+			continue
+		}
+		var got []string
+		for _, line := range strings.Split(out.String(), "\n") {
+			if strings.HasPrefix(line, "Example") {
+				got = append(got, line)
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("-examples-for %s printed\n%s\nwant the examples\n%s", test.arg, out.String(), strings.Join(test.want, "\n"))
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestExists(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/ex\n\ngo 1.22\n",
+		"shop/shop.go": `package shop
+
+// Cart holds items.
+type Cart struct {
+	// Items are the items.
+	Items []string
+}
+
+// Add adds.
+func (c *Cart) Add(item string) {}
+
+// Total totals.
+func Total() float64 { return 0 }
+`,
+	})
+	tests := []struct {
+		args   []string
+		status int
+	}{
+		{[]string{"shop.cart"}, 0},
+		{[]string{"shop.nothing"}, 1},
+		{[]string{"shop.cart.items"}, 0},
+		{[]string{"shop.cart.add"}, 0},
+		{[]string{"shop.cart.nothing"}, 1},
+		{[]string{"-t", "shop.cart"}, 0},
+		{[]string{"-f", "shop.cart"}, 1},
+		{[]string{"-local", "-r", "tot.*"}, 0},
+		{[]string{"-local", "-r", "zz.*"}, 1},
+		{[]string{"-local", "nopkg.Cart"}, 1},
+	}
+	for _, test := range tests {
+		args := append([]string{"-exists"}, test.args...)
+		out, stderr, status := runDoc(t, dir, args...)
+		if status != test.status {
+			t.Errorf("doc %q exited with %d, want %d", args, status, test.status)
+		}
+		if out != "" || stderr != "" {
+			t.Errorf("doc %q printed %q and reported %q, want nothing", args, out, stderr)
+		}
+	}
+}
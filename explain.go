@@ -0,0 +1,177 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+)
+
+// An explainer walks an expression, printing the documentation for each
+// function and method it calls in the order they are called.
+type explainer struct {
+	s    *session
+	pkgs map[string]*checkedPackage // Keyed by package name.
+	step int
+}
+
+// A checkedPackage is a parsed and type-checked package.
+type checkedPackage struct {
+	types *types.Package
+	files []*File
+}
+
+// explain prints the documentation of each call in the expression, as in
+//	doc -explain 'json.NewDecoder(r).Decode(&v)'
+// Packages are named by their last element, as elsewhere; other identifiers
+// are unknown and need not be declared.
+func (s *session) explain(src string) {
+	expr, err := parser.ParseExpr(src)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -explain: %s\n", err)
+		exit(2)
+	}
+	e := &explainer{s: s, pkgs: make(map[string]*checkedPackage)}
+	e.expr(expr)
+	if e.step == 0 {
+		fmt.Fprintf(os.Stderr, "doc: -explain: no calls found in %s\n", src)
+		exit(1)
+	}
+}
+
+// expr explains the calls in the expression and returns its type, if known.
+func (e *explainer) expr(expr ast.Expr) types.Type {
+	switch x := expr.(type) {
+	case *ast.ParenExpr:
+		return e.expr(x.X)
+	case *ast.StarExpr:
+		if ptr, ok := e.expr(x.X).(*types.Pointer); ok {
+			return ptr.Elem()
+		}
+	case *ast.UnaryExpr:
+		e.expr(x.X)
+	case *ast.BinaryExpr:
+		e.expr(x.X)
+		e.expr(x.Y)
+	case *ast.IndexExpr:
+		e.expr(x.X)
+		e.expr(x.Index)
+	case *ast.SelectorExpr:
+		obj, _ := e.selector(x)
+		if obj != nil {
+			return obj.Type()
+		}
+	case *ast.CallExpr:
+		var obj types.Object
+		var pkg *checkedPackage
+		switch fun := x.Fun.(type) {
+		case *ast.SelectorExpr:
+			obj, pkg = e.selector(fun)
+		default:
+			e.expr(fun)
+		}
+		for _, arg := range x.Args {
+			e.expr(arg)
+		}
+		fn, ok := obj.(*types.Func)
+		if !ok {
+			return nil
+		}
+		e.print(fn, pkg)
+		if results := fn.Type().(*types.Signature).Results(); results.Len() > 0 {
+			return results.At(0).Type()
+		}
+	}
+	return nil
+}
+
+// selector resolves a selector expression, either a qualified identifier pkg.Name
+// or a field or method of the value on the left, returning the object it denotes
+// and, if it is from a package that was loaded, the package.
+func (e *explainer) selector(sel *ast.SelectorExpr) (types.Object, *checkedPackage) {
+	if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+		if pkg := e.load(id.Name); pkg != nil {
+			return pkg.types.Scope().Lookup(sel.Sel.Name), pkg
+		}
+	}
+	typ := e.expr(sel.X)
+	if typ == nil {
+		return nil, nil
+	}
+	obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, sel.Sel.Name)
+	if obj == nil || obj.Pkg() == nil {
+		return obj, nil
+	}
+	return obj, e.pkgs[obj.Pkg().Name()]
+}
+
+// load returns the package with the name, parsing and type-checking it
+// the first time it is needed. It returns nil if there is no such package.
+func (e *explainer) load(name string) *checkedPackage {
+	if pkg, ok := e.pkgs[name]; ok {
+		return pkg
+	}
+	e.pkgs[name] = nil
+	for _, dir := range e.s.paths(name) {
+		fset := token.NewFileSet()
+		pkgs, _ := parseDir(fset, dir, nil, parser.ParseComments) // Ignore the error.
+		astPkg, ok := pkgs[name]
+		if !ok {
+			continue
+		}
+		typesPkg, _ := typeCheck(fset, astPkg)
+		if typesPkg == nil {
+			continue
+		}
+		pkg := &checkedPackage{types: typesPkg}
+		for fileName, astFile := range astPkg.Files {
+			if strings.HasSuffix(fileName, "_test.go") {
+				continue
+			}
+			file := e.s.newFile(fset, fileName, "", astFile)
+			file.doPrint = true
+			file.types = typesPkg
+			pkg.files = append(pkg.files, file)
+		}
+		e.pkgs[name] = pkg
+		break
+	}
+	return e.pkgs[name]
+}
+
+// print prints the step number and documentation of the function or method.
+func (e *explainer) print(fn *types.Func, pkg *checkedPackage) {
+	e.step++
+	name := fn.Pkg().Name() + "." + fn.Name()
+	if recv := receiverName(fn); recv != "" {
+		name = fmt.Sprintf("(%s.%s).%s", fn.Pkg().Name(), recv, fn.Name())
+		if _, ok := fn.Type().(*types.Signature).Recv().Type().(*types.Pointer); ok {
+			name = fmt.Sprintf("(*%s.%s).%s", fn.Pkg().Name(), recv, fn.Name())
+		}
+	}
+	fmt.Fprintf(e.s.out, "%d. %s\n\n", e.step, name)
+	var files []*File
+	if pkg != nil {
+		files = pkg.files
+	}
+	file, decl := findFunc(e.s, fn, files)
+	if decl == nil {
+		fmt.Fprintf(e.s.out, "%s\n\n", types.ObjectString(fn, func(p *types.Package) string { return p.Name() }))
+		return
+	}
+	body := decl.Body
+	decl.Body = nil // Do not print the function body.
+	url := file.nameURL(decl, decl.Name)
+	if decl.Recv != nil {
+		url = file.methodURL(decl)
+	}
+	file.printNode(decl, decl.Name, url)
+	decl.Body = body
+}
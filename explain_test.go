@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	writeModule(t, map[string]string{
+		"go.mod": "module example.com/x\n\ngo 1.22\n",
+		"cart/cart.go": `package cart
+
+// Cart holds items.
+type Cart struct{ Total int }
+
+// Item is a thing for sale.
+type Item struct{ Price int }
+
+// New returns an empty cart.
+func New() *Cart { return new(Cart) }
+
+// Lookup finds the named item.
+func Lookup(name string) Item { return Item{} }
+
+// Add adds the item to the cart.
+func (c *Cart) Add(i Item) *Cart { return c }
+
+// Sum returns the total.
+func (c Cart) Sum() int { return c.Total }
+`,
+	})
+	steps := regexp.MustCompile(`(?m)^\d+\. (.*)$`)
+	tests := []struct {
+		expr   string
+		want   []string // The calls explained, in order.
+		status int
+	}{
+		{expr: "cart.New().Add(cart.Lookup(name)).Sum()", want: []string{"cart.New", "cart.Lookup", "(*cart.Cart).Add", "(cart.Cart).Sum"}},
+		{expr: "cart.Lookup(a) == cart.Lookup(b)", want: []string{"cart.Lookup", "cart.Lookup"}},
+		{expr: "(*cart.New()).Sum() + len(x)", want: []string{"cart.New", "(cart.Cart).Sum"}},
+		{expr: "x.y(z)", status: 1},
+		{expr: "cart.New(", status: 2},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).explain(test.expr) })
+		if status != test.status {
+			t.Errorf("-explain %s exited with %d, want %d", test.expr, status, test.status)
+			continue
+		}
+		var got []string
+		for _, m := range steps.FindAllStringSubmatch(out.String(), -1) {
+			got = append(got, m[1])
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("-explain %s explained %q, want %q; output:\n%s", test.expr, got, test.want, out.String())
+		}
+		if status == 0 && !strings.Contains(out.String(), "// New returns an empty cart.") && test.want[0] == "cart.New" {
+			t.Errorf("-explain %s printed\n%s\nwant the documentation of cart.New", test.expr, out.String())
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Every request doc makes to the network goes through tryFetch, which keeps
+// the responses in doc's cache directory and revalidates them with their
+// ETag or modification time, retries failures with exponential backoff, and
+// makes no more than httpRate requests a second. A request, including
+// reading the response, is given up after httpTimeout, and a server's
+// Retry-After is honoured up to maxRetryAfter. The rate, the number of
+// retries and the timeout may be set in the configuration file as
+// http.rate, http.retries and http.timeout (seconds).
+var (
+	httpRate      = configFloat("http.rate", 4)
+	httpRetries   = int(configFloat("http.retries", 3))
+	httpTimeout   = time.Duration(configFloat("http.timeout", 300) * float64(time.Second))
+	maxRetryAfter = time.Minute
+)
+
+// httpClient makes the requests of tryFetch.
+var httpClient = &http.Client{Timeout: httpTimeout}
+
+// httpLimiter spaces requests to keep to httpRate.
+var httpLimiter struct {
+	sync.Mutex
+	next time.Time // When the next request may be made.
+}
+
+// A notFoundError is the error of a request the server answered with 404
+// Not Found or 410 Gone, after which a list of module proxies goes on to
+// the next.
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) }
+
+// cachedResponse is what is kept, besides the body, of a response.
+type cachedResponse struct {
+	URL          string
+	ETag         string
+	LastModified string
+}
+
+// fetch returns the contents of the URL, or exits if it cannot be retrieved.
+func fetch(url string) []byte {
+	data, err := tryFetch(url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+		exit(1)
+	}
+	return data
+}
+
+// tryFetch returns the contents of the URL, which may be no larger than a
+// module zip file. A cached copy is used if the server reports it is still
+// current. With -offline, it fails without trying unless the URL is cached.
+func tryFetch(url string) ([]byte, error) {
+	cached, body := readHTTPCache(url)
+	if *offlineFlag {
+		if body != nil {
+			return body, nil
+		}
+		return nil, fmt.Errorf("%s: offline", url)
+	}
+	for attempt := 0; ; attempt++ {
+		data, retryAfter, err := get(url, cached, body)
+		if err == nil || retryAfter < 0 || attempt >= httpRetries {
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", url, err)
+			}
+			return data, nil
+		}
+		if retryAfter == 0 {
+			retryAfter = time.Second << attempt
+		}
+		time.Sleep(retryAfter)
+	}
+}
+
+// get makes one request for the URL, revalidating the cached response if
+// there is one. If the request fails, get returns how long to wait before
+// trying again: zero if the server did not say, negative if it is not worth
+// trying again.
+func get(url string, cached *cachedResponse, body []byte) ([]byte, time.Duration, error) {
+	waitForRate()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, -1, err
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && body != nil:
+		return body, 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		wait := time.Duration(0)
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			wait = min(time.Duration(secs)*time.Second, maxRetryAfter)
+		}
+		return nil, wait, fmt.Errorf("%s", resp.Status)
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, -1, notFoundError(resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, -1, fmt.Errorf("%s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxZipSize+1))
+	if err == nil && int64(len(data)) > maxZipSize {
+		return nil, -1, fmt.Errorf("larger than %d bytes", maxZipSize)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	writeHTTPCache(url, resp.Header, data)
+	return data, 0, nil
+}
+
+// waitForRate sleeps until a request may be made without exceeding httpRate.
+// A rate of 0 is no limit.
+func waitForRate() {
+	if httpRate <= 0 {
+		return
+	}
+	httpLimiter.Lock()
+	now := time.Now()
+	wait := httpLimiter.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	httpLimiter.next = now.Add(wait + time.Duration(float64(time.Second)/httpRate))
+	httpLimiter.Unlock()
+	time.Sleep(wait)
+}
+
+// httpCachePath returns the name of the file holding the cached body of
+// the URL's response; the rest of the response is in the same name with
+// .json added.
+func httpCachePath(url string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "doc", "http", hex.EncodeToString(sum[:16]))
+}
+
+// readHTTPCache returns the cached response to the URL and its body, or nils.
+func readHTTPCache(url string) (*cachedResponse, []byte) {
+	name := httpCachePath(url)
+	if name == "" {
+		return nil, nil
+	}
+	var cached cachedResponse
+	data, err := os.ReadFile(name + ".json")
+	if err != nil || json.Unmarshal(data, &cached) != nil || cached.URL != url {
+		return nil, nil
+	}
+	body, err := os.ReadFile(name)
+	if err != nil {
+		return nil, nil
+	}
+	return &cached, body
+}
+
+// writeHTTPCache keeps the response to the URL, if it can be revalidated.
+// Each file is replaced whole, the body first, so a concurrent reader, or
+// one after a failed write, never sees a body paired with a validator newer
+// than it. Failure to write the cache is not an error.
+func writeHTTPCache(url string, header http.Header, body []byte) {
+	cached := cachedResponse{URL: url, ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified")}
+	name := httpCachePath(url)
+	if name == "" || cached.ETag == "" && cached.LastModified == "" {
+		return
+	}
+	data, _ := json.Marshal(cached)
+	if writeFileAtomic(name, body) != nil {
+		return
+	}
+	writeFileAtomic(name+".json", data)
+}
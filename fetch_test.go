@@ -0,0 +1,125 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTryFetch(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	oldRate, oldRetries := httpRate, httpRetries
+	httpRate, httpRetries = 0, 1
+	defer func() { httpRate, httpRetries = oldRate, oldRetries }()
+	var requests, revalidated atomic.Int32
+	var failures atomic.Int32 // Failures still to come for /flaky.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				revalidated.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte("tagged"))
+		case "/plain":
+			w.Write([]byte("plain"))
+		case "/flaky":
+			if failures.Add(-1) >= 0 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("recovered"))
+		case "/down":
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path     string
+		failures int32
+		want     string
+		requests int32 // Requests made to the server.
+		notFound bool
+		fails    bool
+	}{
+		{path: "/etag", want: "tagged", requests: 1},
+		// The cached copy is revalidated and kept.
+		{path: "/etag", want: "tagged", requests: 1},
+		{path: "/plain", want: "plain", requests: 1},
+		// A response without a validator is not cached.
+		{path: "/plain", want: "plain", requests: 1},
+		{path: "/flaky", failures: 1, want: "recovered", requests: 2},
+		{path: "/down", requests: 2, fails: true},
+		{path: "/forbidden", requests: 1, fails: true},
+		{path: "/missing", requests: 1, fails: true, notFound: true},
+	}
+	for _, test := range tests {
+		requests.Store(0)
+		failures.Store(test.failures)
+		data, err := tryFetch(server.URL + test.path)
+		if got := requests.Load(); got != test.requests {
+			t.Errorf("tryFetch(%s) made %d requests, want %d", test.path, got, test.requests)
+		}
+		if test.fails {
+			if err == nil {
+				t.Errorf("tryFetch(%s) = %q, want an error", test.path, data)
+			}
+			var nf notFoundError
+			if errors.As(err, &nf) != test.notFound {
+				t.Errorf("tryFetch(%s) failed with %v; want a not-found error: %t", test.path, err, test.notFound)
+			}
+			continue
+		}
+		if err != nil || string(data) != test.want {
+			t.Errorf("tryFetch(%s) = %q, %v, want %q", test.path, data, err, test.want)
+		}
+	}
+	if revalidated.Load() != 1 {
+		t.Errorf("the cached response was revalidated %d times, want 1", revalidated.Load())
+	}
+
+	// Offline, only what is cached is available, with no requests.
+	*offlineFlag = true
+	defer func() { *offlineFlag = false }()
+	requests.Store(0)
+	if data, err := tryFetch(server.URL + "/etag"); err != nil || string(data) != "tagged" {
+		t.Errorf("offline tryFetch(/etag) = %q, %v, want the cached copy", data, err)
+	}
+	if _, err := tryFetch(server.URL + "/plain"); err == nil {
+		t.Errorf("offline tryFetch(/plain) succeeded; want an error, as it is not cached")
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("offline, %d requests were made", n)
+	}
+}
+
+func TestWaitForRate(t *testing.T) {
+	old := httpRate
+	httpRate = 20
+	defer func() { httpRate = old }()
+	start := time.Now()
+	for range 5 {
+		waitForRate()
+	}
+	// The first request waits for none before it, the rest 50ms each.
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("5 requests at 20 a second took %v, want 200ms", elapsed)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// setFilter parses the expression given by -filter, which is written in
+// Go's syntax, and checks that it uses only what filterValue and
+// filterCall provide.
+func (s *session) setFilter(expr string) {
+	x, err := parser.ParseExpr(expr)
+	if err == nil {
+		_, err = evalFilter(x, filterVars(nil, nil, nil))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -filter: %s\n", err)
+		exit(2)
+	}
+	s.filterExpr = x
+}
+
+// filterMatches reports whether the declaration of id in node satisfies
+// -filter, if it is set.
+func (f *File) filterMatches(node ast.Node, id *ast.Ident) bool {
+	if f.s.filterExpr == nil {
+		return true
+	}
+	v, err := evalFilter(f.s.filterExpr, filterVars(f, node, id))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -filter: %s\n", err)
+		exit(2)
+	}
+	b, ok := v.(bool)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "doc: -filter: %s is not a boolean\n", types.ExprString(f.s.filterExpr))
+		exit(2)
+	}
+	return b
+}
+
+// filterVars returns the variables a -filter expression may use, describing
+// the declaration of id in node:
+//	name        string    the name
+//	kind        string    const, var, func, method or type
+//	pkg         string    the import path of its package
+//	recv        string    the receiver's type, without *, for a method
+//	params      []string  the types of the parameters, one for each
+//	results     []string  the types of the results, one for each
+//	doc         string    the doc comment
+//	stability   string    the stability tier, if one is given
+//	visibility  string    exported, internal, test or main
+// With a nil File, for checking the expression, all are empty.
+func filterVars(f *File, node ast.Node, id *ast.Ident) map[string]any {
+	vars := map[string]any{
+		"name": "", "kind": "", "pkg": "", "recv": "", "doc": "", "stability": "", "visibility": "",
+		"params": []string(nil), "results": []string(nil),
+	}
+	if f == nil {
+		return vars
+	}
+	if id != nil {
+		vars["name"] = id.Name
+	}
+	pos := f.fset.Position(node.Pos())
+	pkgPath := importPath(filepath.Dir(f.name))
+	vars["kind"] = resultKind(node)
+	vars["pkg"] = pkgPath
+	vars["stability"] = f.stability(node, id)
+	vars["visibility"], _ = visibility(pos.Filename, f.file.Name.Name, pkgPath)
+	if doc := docComment(node); doc != nil {
+		vars["doc"] = doc.Text()
+	}
+	if fn, ok := node.(*ast.FuncDecl); ok {
+		vars["recv"] = recvTypeName(fn)
+		vars["params"] = fieldTypes(fn.Type.Params)
+		vars["results"] = fieldTypes(fn.Type.Results)
+	}
+	return vars
+}
+
+// fieldTypes returns the type of each field of the list, one for each name.
+func fieldTypes(list *ast.FieldList) []string {
+	if list == nil {
+		return nil
+	}
+	var typs []string
+	for _, field := range list.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			typs = append(typs, types.ExprString(field.Type))
+		}
+	}
+	return typs
+}
+
+// evalFilter returns the value, a string, int64, bool or []string, of the
+// -filter expression. Besides the variables, literals, parentheses, the
+// operators && || ! == != < <= > >= and + for strings, it knows these
+// functions:
+//	len(x)             the length of a string or list
+//	returns(t)         whether a result has the type t, such as "error"
+//	takes(t)           whether a parameter has the type t
+//	contains(s, sub)   whether sub is within s
+//	hasPrefix(s, p)    whether s begins with p
+func evalFilter(x ast.Expr, vars map[string]any) (any, error) {
+	switch x := x.(type) {
+	case *ast.ParenExpr:
+		return evalFilter(x.X, vars)
+	case *ast.Ident:
+		switch x.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		v, ok := vars[x.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown name %s", x.Name)
+		}
+		return v, nil
+	case *ast.BasicLit:
+		v := constant.MakeFromLiteral(x.Value, x.Kind, 0)
+		switch v.Kind() {
+		case constant.String:
+			return constant.StringVal(v), nil
+		case constant.Int:
+			if n, ok := constant.Int64Val(v); ok {
+				return n, nil
+			}
+		}
+		return nil, fmt.Errorf("bad literal %s", x.Value)
+	case *ast.UnaryExpr:
+		v, err := evalFilter(x.X, vars)
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := v.(bool); ok && x.Op == token.NOT {
+			return !b, nil
+		}
+		return nil, fmt.Errorf("bad operand for %s: %s", x.Op, types.ExprString(x.X))
+	case *ast.BinaryExpr:
+		return evalBinary(x, vars)
+	case *ast.CallExpr:
+		return evalCall(x, vars)
+	}
+	return nil, fmt.Errorf("unsupported expression %s", types.ExprString(x))
+}
+
+// evalBinary returns the value of the binary expression. Both operands
+// of && and || are evaluated, so that errors are found whatever the values.
+func evalBinary(x *ast.BinaryExpr, vars map[string]any) (any, error) {
+	l, err := evalFilter(x.X, vars)
+	if err != nil {
+		return nil, err
+	}
+	r, err := evalFilter(x.Y, vars)
+	if err != nil {
+		return nil, err
+	}
+	mismatch := fmt.Errorf("mismatched operands in %s", types.ExprString(x))
+	switch l := l.(type) {
+	case bool:
+		r, ok := r.(bool)
+		if !ok {
+			return nil, mismatch
+		}
+		switch x.Op {
+		case token.LAND:
+			return l && r, nil
+		case token.LOR:
+			return l || r, nil
+		case token.EQL:
+			return l == r, nil
+		case token.NEQ:
+			return l != r, nil
+		}
+	case int64:
+		r, ok := r.(int64)
+		if !ok {
+			return nil, mismatch
+		}
+		switch x.Op {
+		case token.EQL:
+			return l == r, nil
+		case token.NEQ:
+			return l != r, nil
+		case token.LSS:
+			return l < r, nil
+		case token.LEQ:
+			return l <= r, nil
+		case token.GTR:
+			return l > r, nil
+		case token.GEQ:
+			return l >= r, nil
+		}
+	case string:
+		r, ok := r.(string)
+		if !ok {
+			return nil, mismatch
+		}
+		switch x.Op {
+		case token.ADD:
+			return l + r, nil
+		case token.EQL:
+			return l == r, nil
+		case token.NEQ:
+			return l != r, nil
+		case token.LSS:
+			return l < r, nil
+		case token.LEQ:
+			return l <= r, nil
+		case token.GTR:
+			return l > r, nil
+		case token.GEQ:
+			return l >= r, nil
+		}
+	}
+	return nil, fmt.Errorf("bad operator %s in %s", x.Op, types.ExprString(x))
+}
+
+// evalCall returns the value of a call of one of the functions evalFilter knows.
+func evalCall(x *ast.CallExpr, vars map[string]any) (any, error) {
+	fn, ok := x.Fun.(*ast.Ident)
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", types.ExprString(x.Fun))
+	}
+	var args []any
+	for _, arg := range x.Args {
+		v, err := evalFilter(arg, vars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	bad := fmt.Errorf("bad arguments in %s", types.ExprString(x))
+	str := func(i int) string { s, _ := args[i].(string); return s }
+	isStr := func(i int) bool { _, ok := args[i].(string); return ok }
+	has := func(list any, t string) bool {
+		for _, typ := range list.([]string) {
+			if typ == t {
+				return true
+			}
+		}
+		return false
+	}
+	switch fn.Name {
+	case "len":
+		if len(args) == 1 {
+			switch v := args[0].(type) {
+			case string:
+				return int64(len(v)), nil
+			case []string:
+				return int64(len(v)), nil
+			}
+		}
+		return nil, bad
+	case "returns", "takes":
+		if len(args) != 1 || !isStr(0) {
+			return nil, bad
+		}
+		if fn.Name == "returns" {
+			return has(vars["results"], str(0)), nil
+		}
+		return has(vars["params"], str(0)), nil
+	case "contains", "hasPrefix":
+		if len(args) != 2 || !isStr(0) || !isStr(1) {
+			return nil, bad
+		}
+		if fn.Name == "contains" {
+			return strings.Contains(str(0), str(1)), nil
+		}
+		return strings.HasPrefix(str(0), str(1)), nil
+	}
+	return nil, fmt.Errorf("unknown function %s", fn.Name)
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/parser"
+	"reflect"
+	"testing"
+)
+
+func TestEvalFilter(t *testing.T) {
+	vars := filterVars(nil, nil, nil)
+	vars["name"] = "ReadAll"
+	vars["kind"] = "func"
+	vars["pkg"] = "io"
+	vars["doc"] = "ReadAll reads from r until an error or EOF.\n\nDeprecated: for the test.\n"
+	vars["visibility"] = "exported"
+	vars["params"] = []string{"Reader"}
+	vars["results"] = []string{"[]byte", "error"}
+	tests := []struct {
+		expr string
+		want any // Nil for an error.
+	}{
+		{`kind == "func" && returns("error")`, true},
+		{`returns("[]byte")`, true},
+		{`returns("int")`, false},
+		{`takes("Reader")`, true},
+		{`takes("io.Reader")`, false},
+		{`len(params)`, int64(1)},
+		{`len(results) > 1`, true},
+		{`len(name) <= 6`, false},
+		{`contains(doc, "Deprecated:")`, true},
+		{`hasPrefix(name, "Read") && !hasPrefix(name, "Reader")`, true},
+		{`name + "er" == "ReadAller"`, true},
+		{`pkg < "j" && pkg >= "io"`, true},
+		{`recv == "" || kind == "method"`, true},
+		{`(kind == "type") != (visibility == "exported")`, true},
+		{`stability`, ""},
+		{`10 != 2*5`, nil}, // No arithmetic.
+		{`-1`, nil},
+		{`1.5 > 1`, nil},
+		{`unknown`, nil},
+		{`true || unknown`, nil}, // Both operands are checked.
+		{`name == 1`, nil},
+		{`name - "x"`, nil},
+		{`1 && 2`, nil},
+		{`len(1)`, nil},
+		{`len(name, doc)`, nil},
+		{`returns(1)`, nil},
+		{`contains(doc)`, nil},
+		{`f()`, nil},
+		{`x.f()`, nil},
+		{`params[0]`, nil},
+	}
+	for _, test := range tests {
+		x, err := parser.ParseExpr(test.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", test.expr, err)
+		}
+		got, err := evalFilter(x, vars)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("evalFilter(%s) = %#v, want an error", test.expr, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("evalFilter(%s) = %#v, %v; want %#v", test.expr, got, err, test.want)
+		}
+	}
+}
+
+func FuzzFilter(f *testing.F) {
+	for _, expr := range []string{`kind == "func" && returns("error")`, `len(params) > 2`, `!contains(doc, "Deprecated")`, `name + "x" < pkg`, `len(1)`, `returns(1)`, `hasPrefix(name)`, `f()()`, `1 && 2`, `"\xff"`} {
+		f.Add(expr)
+	}
+	f.Fuzz(func(t *testing.T, expr string) {
+		x, err := parser.ParseExpr(expr)
+		if err != nil {
+			return
+		}
+		v, err := evalFilter(x, filterVars(nil, nil, nil))
+		if err != nil {
+			return
+		}
+		switch v.(type) {
+		case string, int64, bool, []string:
+		default:
+			t.Fatalf("evalFilter(%s) = %#v, of type %T", expr, v, v)
+		}
+	})
+}
+
+func TestFilter(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/f\n\ngo 1.22\n",
+		"io/io.go": `package io
+
+// Reader reads.
+type Reader struct{}
+
+// Read reads into p.
+func (r *Reader) Read(p []byte) (n int, err error) { return 0, nil }
+
+// ReadAll reads everything.
+//
+// Deprecated: use Read.
+func ReadAll(r *Reader) ([]byte, error) { return nil, nil }
+
+// ReadByte reads a byte.
+func ReadByte(a, b int) byte { return 0 }
+
+// ReadLimit is a limit.
+const ReadLimit = 10
+`,
+	})
+	tests := []struct {
+		expr string
+		want string // The outline of what is printed.
+	}{
+		{`returns("error")`, "#Reader.Read\n#ReadAll\n"},
+		{`kind == "method"`, "#Reader.Read\n"},
+		{`recv == "Reader"`, "#Reader.Read\n"},
+		{`kind == "const" || kind == "type"`, "#Reader\n#ReadLimit\n"},
+		{`len(params) == 2`, "#ReadByte\n"}, // One for each name.
+		{`takes("*Reader")`, "#ReadAll\n"},
+		{`!contains(doc, "Deprecated:") && pkg == "example.com/f/io" && kind == "func"`, "#ReadByte\n"},
+		{`visibility != "exported"`, ""},
+	}
+	for _, test := range tests {
+		args := []string{"-filter", test.expr, "-local", "-r", "read.*"}
+		out, stderr, status := runDoc(t, dir, args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", args, status, stderr)
+			continue
+		}
+		if got := outline(out); got != test.want {
+			t.Errorf("doc %q listed\n%s\nwant\n%s", args, got, test.want)
+		}
+	}
+	for _, expr := range []string{`name`, `unknown == ""`, `(`} {
+		if _, _, status := runDoc(t, dir, "-filter", expr, "-local", "-r", "read.*"); status != 2 {
+			t.Errorf("doc -filter %q exited with %d, want 2", expr, status)
+		}
+	}
+}
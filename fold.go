@@ -0,0 +1,62 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// foldSpecial holds the letters whose case folding is more than a mapping
+// among the runes of one orbit of unicode.SimpleFold: the dotted capital
+// and dotless small I of Turkish and Azeri, which fold to i so a query
+// matches whichever the name's author typed, and the sharp s, which folds
+// to ss.
+var foldSpecial = map[rune]string{
+	'İ': "i", // U+0130 LATIN CAPITAL LETTER I WITH DOT ABOVE
+	'ı': "i", // U+0131 LATIN SMALL LETTER DOTLESS I
+	'ß': "ss",
+	'ẞ': "ss", // U+1E9E LATIN CAPITAL LETTER SHARP S
+}
+
+// foldName returns the name case folded, so that two names equal but for
+// case fold to the same string. Unlike strings.ToLower, it maps every
+// letter of a case orbit, such as K, k and the Kelvin sign, to one rune,
+// and treats the letters of foldSpecial.
+func foldName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if s, ok := foldSpecial[r]; ok {
+			b.WriteString(s)
+			continue
+		}
+		// The smallest rune of the orbit, in lower case as the values of
+		// foldSpecial are, stands for all of it.
+		min := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+		b.WriteRune(unicode.ToLower(min))
+	}
+	return b.String()
+}
+
+// lessFold reports whether name a sorts before b in a listing: by foldName,
+// so that case does not separate names as byte order does, and then by
+// byte order. It is not collation for a locale, which would also order
+// accented letters with their base letters.
+func lessFold(a, b string) bool {
+	if fa, fb := foldName(a), foldName(b); fa != fb {
+		return fa < fb
+	}
+	return a < b
+}
+
+// equalFold reports whether the names are equal under foldName.
+func equalFold(a, b string) bool {
+	return strings.EqualFold(a, b) || foldName(a) == foldName(b)
+}
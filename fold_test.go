@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFoldName(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"ReadAll", "readall", true},
+		{"Straße", "STRASSE", true},
+		{"Straße", "strasse", true},
+		{"STRAẞE", "straße", true},
+		{"İstanbul", "istanbul", true},
+		{"ıstanbul", "Istanbul", true},
+		{"Kelvin", "kelvin", true}, // The Kelvin sign.
+		{"ſize", "Size", true},     // The long s.
+		{"ΟΔΟΣ", "οδος", true},
+		{"ΟΔΟΣ", "οδοσ", true},
+		{"Read", "Reads", false},
+		{"Straße", "Strase", false},
+		{"é", "e", false},
+	}
+	for _, test := range tests {
+		if got := equalFold(test.a, test.b); got != test.equal {
+			t.Errorf("equalFold(%q, %q) = %t, want %t", test.a, test.b, got, test.equal)
+		}
+		if got := foldName(test.a) == foldName(test.b); got != test.equal {
+			t.Errorf("foldName(%q) = %q, foldName(%q) = %q; equal %t, want %t", test.a, foldName(test.a), test.b, foldName(test.b), got, test.equal)
+		}
+	}
+}
+
+func TestLessFold(t *testing.T) {
+	names := []string{"zeta", "Beta", "alpha", "Alpha", "Äpfel", "beta", "Straße", "strasse", "Zeta"}
+	slices.SortFunc(names, func(a, b string) int {
+		switch {
+		case lessFold(a, b):
+			return -1
+		case lessFold(b, a):
+			return 1
+		}
+		return 0
+	})
+	want := []string{"Alpha", "alpha", "Beta", "beta", "Straße", "strasse", "Zeta", "zeta", "Äpfel"}
+	if !slices.Equal(names, want) {
+		t.Errorf("sorted by lessFold: %q, want %q", names, want)
+	}
+}
+
+func TestFoldLookup(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":       "module example.com/fold\n\ngo 1.22\n",
+		"addr/addr.go": "package addr\n\n// Straße is a street.\ntype Straße string\n\n// Kelvin is a temperature.\ntype Kelvin float64\n",
+	})
+	for _, name := range []string{"addr.strasse", "addr.STRASSE", "addr.Kelvin"} {
+		out, stderr, status := runDoc(t, dir, "-local", name)
+		if status != 0 || len(shownSymbols(out)) != 1 {
+			t.Errorf("doc %s printed\n%s\nand exited with %d, want one symbol; stderr:\n%s", name, out, status, stderr)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+)
+
+// follow prints the documentation of the function called by fn, whose body
+// must be a single call, as in
+//	func Marshal(v any) ([]byte, error) { return defaultEncoder.Marshal(v) }
+func (f *File) follow(fn *ast.FuncDecl) {
+	call := singleCall(fn.Body)
+	if call == nil {
+		return
+	}
+	callee := calleeIdent(call.Fun)
+	if callee == nil {
+		return
+	}
+	obj, ok := f.uses[callee].(*types.Func)
+	if !ok || obj.Pkg() == nil {
+		return
+	}
+	file, decl := findFunc(f.s, obj, f.allFiles)
+	if decl != nil {
+		file.listing = f.listing
+		file.printFollowed(fn, obj.Pkg().Name(), receiverName(obj), decl)
+	}
+}
+
+// findFunc returns the declaration of the function or method, and the file
+// holding it. Declarations in the files, which must be from a single
+// type-checked package, are found by position; others by parsing the package
+// that declares them. If the declaration cannot be found, findFunc returns nil.
+func findFunc(s *session, obj *types.Func, files []*File) (*File, *ast.FuncDecl) {
+	if len(files) > 0 && files[0].types != nil && obj.Pkg() == files[0].types {
+		for _, file := range files {
+			for _, decl := range file.file.Decls {
+				if decl, ok := decl.(*ast.FuncDecl); ok && decl.Name.Pos() == obj.Pos() {
+					return file, decl
+				}
+			}
+		}
+		return nil, nil
+	}
+	if obj.Pkg() == nil {
+		return nil, nil
+	}
+	dir := dirForImport(obj.Pkg().Path(), filesDir(files))
+	if dir == "" {
+		return nil, nil
+	}
+	recv := receiverName(obj)
+	fset := token.NewFileSet()
+	pkgs, _ := parseDir(fset, dir, nil, parser.ParseComments) // Ignore the error.
+	for _, pkg := range pkgs {
+		for name, astFile := range pkg.Files {
+			for _, decl := range astFile.Decls {
+				decl, ok := decl.(*ast.FuncDecl)
+				if !ok || decl.Name.Name != obj.Name() || recvTypeName(decl) != recv {
+					continue
+				}
+				file := s.newFile(fset, name, obj.Name(), astFile)
+				file.doPrint = true
+				return file, decl
+			}
+		}
+	}
+	return nil, nil
+}
+
+// printFollowed prints decl, the function called by the wrapper fn, with attribution.
+func (f *File) printFollowed(fn *ast.FuncDecl, pkg, recv string, decl *ast.FuncDecl) {
+	name := pkg + "." + decl.Name.Name
+	url := f.nameURL(decl, decl.Name)
+	if recv != "" {
+		name = fmt.Sprintf("%s.%s.%s", pkg, recv, decl.Name.Name)
+		url = f.methodURL(decl)
+	}
+	fmt.Fprintf(f.output(nodeKind(fn)), "%s is undocumented; it calls %s:\n\n", fn.Name.Name, name)
+	body := decl.Body
+	decl.Body = nil // Do not print the function body.
+	f.printNode(decl, decl.Name, url)
+	decl.Body = body
+}
+
+// singleCall returns the call expression that forms the entire body, if any.
+func singleCall(body *ast.BlockStmt) *ast.CallExpr {
+	if body == nil || len(body.List) != 1 {
+		return nil
+	}
+	var expr ast.Expr
+	switch stmt := body.List[0].(type) {
+	case *ast.ReturnStmt:
+		if len(stmt.Results) != 1 {
+			return nil
+		}
+		expr = stmt.Results[0]
+	case *ast.ExprStmt:
+		expr = stmt.X
+	}
+	call, _ := expr.(*ast.CallExpr)
+	return call
+}
+
+// calleeIdent returns the identifier naming the function in a call, if any.
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	switch f := fun.(type) {
+	case *ast.IndexExpr: // Instantiated generic function.
+		return calleeIdent(f.X)
+	case *ast.IndexListExpr:
+		return calleeIdent(f.X)
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	}
+	return nil
+}
+
+// receiverName returns the name of the receiver's base type for a method,
+// or the empty string for a function.
+func receiverName(fn *types.Func) string {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return ""
+	}
+	typ := types.Unalias(recv.Type())
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = types.Unalias(ptr.Elem())
+	}
+	if named, ok := typ.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+// recvTypeName returns the name of the receiver's base type in a method
+// declaration, or the empty string for a function.
+func recvTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
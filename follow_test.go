@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFollow(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/w\n\ngo 1.22\n",
+		"w/w.go": `package w
+
+import (
+	"strings"
+
+	"example.com/w/impl"
+)
+
+func Marshal(v any) ([]byte, error) { return impl.Marshal(v) }
+
+func Run() { defaultRunner.Run() }
+
+func Upper(s string) string { return strings.ToUpper(s) }
+
+func Max[T int | float64](a, b T) T { return max2[T](a, b) }
+
+func Two() { Run(); Run() }
+
+// Documented calls Run.
+func Documented() { Run() }
+
+// max2 is the larger of a and b.
+func max2[T int | float64](a, b T) T { return max(a, b) }
+
+type runner struct{}
+
+var defaultRunner runner
+
+// Run runs the runner.
+func (runner) Run() {}
+`,
+		"impl/impl.go": "package impl\n\n// Marshal encodes v.\nfunc Marshal(v any) ([]byte, error) { return nil, nil }\n",
+	})
+	tests := []struct {
+		name string
+		want string // The attribution, followed by the callee's documentation, or empty if none.
+		doc  string
+	}{
+		{"Marshal", "Marshal is undocumented; it calls impl.Marshal:", "// Marshal encodes v.\nfunc Marshal(v any) ([]byte, error)\n"},
+		{"Run", "Run is undocumented; it calls w.runner.Run:", "// Run runs the runner.\nfunc (runner) Run()\n"},
+		{"Upper", "Upper is undocumented; it calls strings.ToUpper:", "func ToUpper(s string) string\n"},
+		{"Max", "Max is undocumented; it calls w.max2:", "// max2 is the larger of a and b.\n"},
+		{"Two", "", ""},
+		{"Documented", "", ""},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, "-follow", "w", test.name)
+		if status != 0 {
+			t.Errorf("doc -follow w %s exited with %d; stderr:\n%s", test.name, status, stderr)
+			continue
+		}
+		_, followed, ok := strings.Cut(out, " is undocumented; it calls ")
+		if test.want == "" {
+			if ok {
+				t.Errorf("doc -follow w %s printed\n%s\nwant no callee", test.name, out)
+			}
+			continue
+		}
+		if !strings.Contains(out, test.want) || !strings.Contains(followed, test.doc) {
+			t.Errorf("doc -follow w %s printed\n%s\nwant %q, then %q", test.name, out, test.want, test.doc)
+		}
+	}
+	// Without -follow, only the wrapper is printed.
+	if out, _, _ := runDoc(t, dir, "w", "Marshal"); strings.Contains(out, "undocumented") {
+		t.Errorf("doc w Marshal printed\n%s\nwant the wrapper alone", out)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// forFile prints a digest of what the file depends on from other packages:
+// each exported constant, variable, type, function and method of another
+// package that the file refers to, once, with the synopsis of its
+// documentation, grouped by import path. The file's package is type-checked
+// to know what each name refers to, so a method is found through whatever
+// value it is called on.
+func (s *session) forFile(name string) {
+	name, err := filepath.Abs(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -for-file: %s\n", err)
+		exit(2)
+	}
+	fset := token.NewFileSet()
+	pkg, astFile := parseEnclosing(fset, name)
+	if astFile == nil {
+		fmt.Fprintf(os.Stderr, "doc: -for-file: cannot parse %s\n", name)
+		exit(1)
+	}
+	typesPkg, info := typeCheck(fset, pkg)
+	refs := make(map[string]map[string]bool) // Names referred to, keyed by import path.
+	ast.Inspect(astFile, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		path, name := externalName(info.Uses[id], typesPkg)
+		if name == "" {
+			return true
+		}
+		if refs[path] == nil {
+			refs[path] = make(map[string]bool)
+		}
+		refs[path][name] = true
+		return true
+	})
+	if len(refs) == 0 {
+		fmt.Fprintf(os.Stderr, "doc: -for-file: %s refers to nothing exported by another package\n", name)
+		exit(1)
+	}
+	paths := make([]string, 0, len(refs))
+	for path := range refs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for i, path := range paths {
+		if i > 0 {
+			fmt.Fprintln(s.out)
+		}
+		fmt.Fprintln(s.out, path)
+		docs := synopses(dirForImport(path, filepath.Dir(name)))
+		names := make([]string, 0, len(refs[path]))
+		for name := range refs[path] {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool { return lessFold(names[i], names[j]) })
+		for _, name := range names {
+			fmt.Fprintf(s.out, "\t%s", name)
+			if doc := docs[name]; doc != "" {
+				fmt.Fprintf(s.out, " // %s", doc)
+			}
+			fmt.Fprintln(s.out)
+		}
+	}
+}
+
+// externalName returns the import path of the package declaring the object
+// and its name there, Type.Method for a method, if it is an exported name
+// declared at the top level of a package other than the one given, or a
+// method of such a type. Otherwise the name is empty.
+func externalName(obj types.Object, pkg *types.Package) (path, name string) {
+	if obj == nil || obj.Pkg() == nil || obj.Pkg() == pkg || !obj.Exported() {
+		return "", ""
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		fn = fn.Origin()
+		if fn.Type().(*types.Signature).Recv() != nil {
+			recv := receiverName(fn)
+			if !token.IsExported(recv) {
+				return "", ""
+			}
+			return fn.Pkg().Path(), recv + "." + fn.Name()
+		}
+	}
+	if obj.Parent() != obj.Pkg().Scope() {
+		return "", "" // A field, or a method of an interface literal.
+	}
+	return obj.Pkg().Path(), obj.Name()
+}
+
+// synopses returns the synopses of the documentation of the exported names
+// declared in the package in the directory, keyed by name, with methods,
+// those of interfaces included, keyed as Type.Method.
+func synopses(dir string) map[string]string {
+	docs := make(map[string]string)
+	fset := token.NewFileSet()
+	pkgs, _ := parseDir(fset, dir, nil, parser.ParseComments) // Ignore the error.
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					if decl.Recv == nil {
+						docs[decl.Name.Name] = synopsis(decl.Doc)
+					} else if recv := recvTypeName(decl); recv != "" {
+						docs[recv+"."+decl.Name.Name] = synopsis(decl.Doc)
+					}
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						switch spec := spec.(type) {
+						case *ast.TypeSpec:
+							doc := spec.Doc
+							if doc == nil {
+								doc = decl.Doc
+							}
+							docs[spec.Name.Name] = synopsis(doc)
+							if iface, ok := spec.Type.(*ast.InterfaceType); ok {
+								for _, method := range iface.Methods.List {
+									doc := method.Doc
+									if doc == nil {
+										doc = method.Comment
+									}
+									for _, id := range method.Names {
+										docs[spec.Name.Name+"."+id.Name] = synopsis(doc)
+									}
+								}
+							}
+						case *ast.ValueSpec:
+							doc := spec.Doc
+							if doc == nil {
+								doc = decl.Doc
+							}
+							for _, id := range spec.Names {
+								docs[id.Name] = synopsis(doc)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return docs
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestForFileModuleImport(t *testing.T) {
+	dir := writeModule(t, testModule)
+	var out bytes.Buffer
+	newSession(&out).forFile(filepath.Join(dir, "internal", "auth", "auth.go"))
+	want := "example.com/m/v2\n\tConfig // Config configures things.\n"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("-for-file auth.go printed\n%s\nwant it to contain\n%s", out.String(), want)
+	}
+}
+
+func TestForFileDependency(t *testing.T) {
+	dir := writeModule(t, depModule(t))
+	var out bytes.Buffer
+	newSession(&out).forFile(filepath.Join(dir, "main.go"))
+	want := "golang.org/x/mod/semver\n\tCompare // Compare returns an integer comparing two versions according to semantic version precedence.\n"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("-for-file main.go printed\n%s\nwant it to contain\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFullDoc(t *testing.T) {
+	longDoc := func(name string, n int) string {
+		var b strings.Builder
+		fmt.Fprintf(&b, "// %s is long.\n", name)
+		for i := 2; i <= n; i++ {
+			fmt.Fprintf(&b, "// Line %d.\n", i)
+		}
+		return b.String()
+	}
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/fd\n\ngo 1.22\n",
+		"layout/layout.go": "package layout\n\n" +
+			longDoc("Layout", 25) + "const Layout = \"2006\"\n\n" +
+			longDoc("Layer", 21) + "func Layer() {}\n",
+	})
+	const cut = "// …(5 more lines, use -full-doc)\n"
+	tests := []struct {
+		args []string
+		cut  bool
+	}{
+		{[]string{"-local", "-r", "layout"}, true},
+		{[]string{"-full-doc", "-local", "-r", "layout"}, false},
+		{[]string{"-full", "-local", "-r", "layout"}, false},
+		{[]string{"-local", "layout.Layout"}, false}, // A single result is not numbered.
+		{[]string{"-local", "-r", "layer"}, false},   // One line over the limit is not worth cutting.
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := strings.Contains(out, cut); got != test.cut {
+			t.Errorf("doc %q printed\n%s\ncut %t, want %t", test.args, out, got, test.cut)
+		}
+		if test.cut {
+			if strings.Contains(out, "Line 21.") || !strings.Contains(out, "Line 20.\n"+cut+"const Layout") {
+				t.Errorf("doc %q printed\n%s\nwant 20 lines of doc, then the declaration", test.args, out)
+			}
+		} else if !strings.Contains(out, "Line 21.") {
+			t.Errorf("doc %q printed\n%s\nwant the whole doc comment", test.args, out)
+		}
+	}
+}
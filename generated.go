@@ -0,0 +1,41 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// skipGenerated reports whether the tree at the directory is not to be
+// searched because it holds a generated API client, such as a cloud SDK,
+// whose thousands of packages would swamp the results. Such trees are
+// named, by import path patterns as in GOPRIVATE, by lines of the
+// configuration file such as
+//	generated.sdk google.golang.org/api,github.com/aws/aws-sdk-go-v2/service
+// They are searched only with -include-generated-sdks or, since pkg is
+// then not empty, when a package is named.
+func (s *session) skipGenerated(dir, pkg string) bool {
+	patterns := config["generated.sdk"]
+	if len(patterns) == 0 || pkg != "" || *includeGeneratedFlag {
+		return false
+	}
+	if !module.MatchPrefixPatterns(strings.Join(patterns, ","), importPath(dir)) {
+		return false
+	}
+	s.generatedSkipped[dir] = true
+	return true
+}
+
+// generatedHint says, when nothing was found, that there are generated
+// SDK trees that were not searched.
+func (s *session) generatedHint() {
+	if n := len(s.generatedSkipped); n > 0 {
+		fmt.Fprintf(os.Stderr, "doc: %d generated SDK tree(s) not searched; see -include-generated-sdks\n", n)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedSDK(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":                 "module example.com/g\n\ngo 1.22\n",
+		"client/client.go":       "package client\n\n// Name is handwritten.\nfunc Name() {}\n",
+		"api/storage/storage.go": "package storage\n\n// Name is generated.\nfunc Name() {}\n\n// Bucket is generated.\ntype Bucket struct{}\n",
+		"api/compute/compute.go": "package compute\n\n// Name is generated.\nfunc Name() {}\n",
+		"apiary/apiary.go":       "package apiary\n\n// Name is not in the tree.\nfunc Name() {}\n",
+	})
+	conf := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(conf, []byte("generated.sdk example.com/g/api\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCCONFIG", conf)
+	tests := []struct {
+		args []string
+		want string // The outline of what is printed.
+		hint bool   // Whether to say that generated trees were not searched.
+	}{
+		{args: []string{"-local", "-r", "name"}, want: "#Name\n#Name\n"},
+		{args: []string{"-include-generated-sdks", "-local", "-r", "name"}, want: "#Name\n#Name\n#Name\n#Name\n"},
+		{args: []string{"-local", "storage.Bucket"}, want: "#Bucket\n"}, // A package is named.
+		{args: []string{"-local", "-r", "nothing"}, hint: true},
+		{args: []string{"-include-generated-sdks", "-local", "-r", "nothing"}},
+		{args: []string{"-local", "storage.Nothing"}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got := outline(out); got != test.want {
+			t.Errorf("doc %q listed\n%s\nwant\n%s", test.args, got, test.want)
+		}
+		if hint := strings.Contains(stderr, "generated SDK tree(s) not searched"); hint != test.hint {
+			t.Errorf("doc %q reported\n%s\nwant hint %t", test.args, stderr, test.hint)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+module robpike.io/cmd/doc
+
+go 1.26.0
+
+require (
+	golang.org/x/mod v0.41.0
+	golang.org/x/text v0.40.0
+	golang.org/x/tools v0.50.0
+)
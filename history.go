@@ -0,0 +1,138 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// expandSearches replaces each argument of the form @name with the words of
+// the search saved under that name in the configuration file, as in
+//	search ctxfuncs -r .*Context
+func expandSearches(args []string) []string {
+	var expanded []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "@") || len(arg) == 1 {
+			expanded = append(expanded, arg)
+			continue
+		}
+		found := false
+		for _, search := range config["search"] {
+			words := strings.Fields(search)
+			if len(words) > 0 && words[0] == arg[1:] {
+				expanded = append(expanded, words[1:]...)
+				found = true
+			}
+		}
+		if !found {
+			fmt.Fprintf(os.Stderr, "doc: no saved search %s\n", arg)
+			exit(2)
+		}
+	}
+	return expanded
+}
+
+// historyEnabled reports whether queries are recorded, as set by
+//	history on
+// in the configuration file.
+func historyEnabled() bool {
+	return configValue("history") == "on"
+}
+
+// historyPath returns the name of the file holding the query history.
+func historyPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "doc", "history")
+}
+
+// recordHistory appends the arguments of a query to the history file,
+// one JSON array per line.
+func recordHistory(args []string) {
+	name := historyPath()
+	if name == "" || len(args) == 0 {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return
+	}
+	fd, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer fd.Close()
+	line, _ := json.Marshal(args)
+	fmt.Fprintf(fd, "%s\n", line)
+}
+
+// readHistory returns the queries in the history file, oldest first.
+func readHistory() [][]string {
+	fd, err := os.Open(historyPath())
+	if err != nil {
+		return nil
+	}
+	defer fd.Close()
+	var queries [][]string
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		var args []string
+		if json.Unmarshal(scanner.Bytes(), &args) == nil {
+			queries = append(queries, args)
+		}
+	}
+	return queries
+}
+
+// history lists the recorded queries, numbered, or if an argument is given,
+// runs the query with that number again.
+func (s *session) history(arg string) {
+	queries := readHistory()
+	if arg == "" {
+		for i, args := range queries {
+			fmt.Fprintf(s.out, "%d\tdoc %s\n", i+1, shellQuote(args))
+		}
+		return
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(queries) {
+		fmt.Fprintf(os.Stderr, "doc: -history: no query %s\n", arg)
+		exit(2)
+	}
+	self, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+		exit(2)
+	}
+	cmd := exec.Command(self, queries[n-1]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, s.out, os.Stderr
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exit(exitErr.ExitCode())
+		}
+		fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+		exit(2)
+	}
+}
+
+// shellQuote returns the arguments as they might be typed to a shell.
+func shellQuote(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$*?[]|&;<>()`!#~") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSavedSearchesAndHistory(t *testing.T) {
+	dir := writeModule(t, testModule)
+	conf := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(conf, []byte(`history on
+search token ./internal/auth Token
+search funcs -f -r   m.*  # Functions beginning with m.
+`), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCCONFIG", conf)
+	os.Remove(filepath.Join(docTestDir, "cache", "doc", "history"))
+	tests := []struct {
+		args   []string
+		want   []string // Symbols shown, or with -history the listing.
+		status int
+	}{
+		{args: []string{"@token"}, want: []string{"example.com/m/v2/internal/auth#Token"}},
+		{args: []string{"-local", "@funcs"}, want: []string{"example.com/m/v2/internal/auth#Make"}},
+		{args: []string{"@missing"}, status: 2},
+		{args: []string{"-history", "1"}, want: []string{"example.com/m/v2/internal/auth#Token"}},
+		{args: []string{"-history", "9"}, status: 2},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != test.status {
+			t.Errorf("doc %q exited with %d, want %d; stderr:\n%s", test.args, status, test.status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q showed %q, want %q", test.args, got, test.want)
+		}
+	}
+	// The queries are recorded as typed; failures to parse and -history
+	// itself are not.
+	out, _, _ := runDoc(t, dir, "-history")
+	want := "1\tdoc @token\n2\tdoc -local @funcs\n3\tdoc @token\n"
+	if out != want {
+		t.Errorf("doc -history printed\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-r", "m.*"}, "-r 'm.*'"},
+		{[]string{"fmt", "Printf"}, "fmt Printf"},
+		{[]string{"-exclude", ""}, "-exclude ''"},
+		{[]string{"it's"}, `'it'\''s'`},
+		{[]string{"$HOME", "a b"}, "'$HOME' 'a b'"},
+	}
+	for _, test := range tests {
+		if got := shellQuote(test.args); got != test.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", test.args, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A hover is what -hover prints, as JSON, for the single match: all an
+// editor shows in a popup when the cursor rests on a name.
+type hover struct {
+	Name         string        // Type.Method for a method.
+	Package      string        // Import path.
+	Kind         string        // As in a result.
+	Signature    string        // The declaration, on one line, as -sig-only prints it.
+	Doc          string        // The doc comment as text, reflowed to 80 columns.
+	Markdown     string        // The doc comment as Markdown.
+	Example      *hoverExample `json:",omitempty"`
+	Deprecated   string        `json:",omitempty"` // The Deprecated: paragraph of the doc comment.
+	ModuleStatus string        `json:",omitempty"` // As in a result.
+	URL          string        `json:",omitempty"`
+	Source       string        // File:line of the declaration.
+}
+
+// A hoverExample is the first example of a hover's symbol.
+type hoverExample struct {
+	Name   string // Without the Example prefix.
+	Code   string
+	Output string `json:",omitempty"`
+}
+
+// addHover records, for -hover, what to print for the declaration of the
+// name id in node.
+func (f *File) addHover(node ast.Node, id *ast.Ident, url string) {
+	pos := f.fset.Position(id.Pos())
+	h := hover{
+		Name:         id.Name,
+		Package:      importPath(filepath.Dir(f.name)),
+		Kind:         resultKind(node),
+		Signature:    f.signature(node, id),
+		ModuleStatus: f.s.moduleStatusOf(filepath.Dir(f.name)).kind(),
+		URL:          strings.TrimSpace(url),
+		Source:       fmt.Sprintf("%s:%d", pos.Filename, pos.Line),
+	}
+	recv := ""
+	if fn, ok := node.(*ast.FuncDecl); ok && fn.Recv != nil {
+		recv = recvTypeName(fn)
+		h.Name = recv + "." + id.Name
+	}
+	if doc := docComment(node); doc != nil {
+		parsed := parseDoc(doc)
+		printer := &comment.Printer{TextWidth: 80, DocLinkURL: docLinkURL(f.linkPath())}
+		h.Doc = string(printer.Text(parsed))
+		h.Markdown = string(printer.Markdown(parsed))
+		h.Deprecated = deprecatedParagraph(parsed)
+	}
+	if examples := f.examplesOf(recv, id.Name); len(examples) > 0 {
+		ex := examples[0]
+		h.Example = &hoverExample{Name: ex.Name, Code: ex.Doc, Output: ex.Output}
+	}
+	f.s.hovers = append(f.s.hovers, h)
+}
+
+// deprecatedParagraph returns the text of the paragraph of the doc comment
+// that begins Deprecated:, on one line, or the empty string.
+func deprecatedParagraph(doc *comment.Doc) string {
+	printer := &comment.Printer{TextWidth: -1} // No wrapping.
+	for _, block := range doc.Content {
+		if p, ok := block.(*comment.Paragraph); ok {
+			text := strings.TrimSpace(string(printer.Text(&comment.Doc{Content: []comment.Block{p}})))
+			if strings.HasPrefix(text, "Deprecated:") {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// printHover prints, for -hover, the hover of the single match as JSON. Of
+// several, one matching the name including case is chosen if there is
+// just one.
+func (s *session) printHover(name string) {
+	hovers := s.hovers
+	if len(hovers) > 1 {
+		var exact []hover
+		for _, h := range hovers {
+			if _, short, _ := strings.Cut(h.Name, "."); h.Name == name || short == name {
+				exact = append(exact, h)
+			}
+		}
+		if len(exact) == 1 {
+			hovers = exact
+		}
+	}
+	switch len(hovers) {
+	case 0:
+		fmt.Fprintf(os.Stderr, "doc: -hover: no match for %s\n", name)
+		exit(1)
+	case 1:
+		data, _ := json.MarshalIndent(hovers[0], "", "\t")
+		fmt.Fprintf(s.out, "%s\n", data)
+	default:
+		fmt.Fprintf(os.Stderr, "doc: -hover: %d matches for %s; name just one\n", len(hovers), name)
+		exit(1)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHover(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/h\n\ngo 1.22\n",
+		"shop/shop.go": `package shop
+
+// Cart holds the [Item] values a shopper buys.
+type Cart struct {
+	// Items are the items.
+	Items []Item
+}
+
+// Item is an item.
+type Item string
+
+// Add adds the item to the cart.
+func (c *Cart) Add(item Item) {}
+
+// Total returns the total.
+//
+// Deprecated: Use Cart.Sum.
+func Total(c Cart) float64 { return 0 }
+
+// Sum sums.
+func (c *Cart) Sum() float64 { return 0 }
+
+// Summary summarizes.
+func Summary() {}
+`,
+		"shop/shop_test.go": `package shop_test
+
+import (
+	"fmt"
+
+	"example.com/h/shop"
+)
+
+func ExampleCart_Add() {
+	var c shop.Cart
+	c.Add("pear")
+	fmt.Println(len(c.Items))
+	// Output: 1
+}
+`,
+	})
+	tests := []struct {
+		args []string
+		want hover // Fields compared if set; Doc and Markdown by prefix.
+	}{
+		{[]string{"shop.Cart"}, hover{Name: "Cart", Package: "example.com/h/shop", Kind: "type", Signature: "type Cart struct{ ... }", Doc: "Cart holds the Item values", Markdown: "Cart holds the [Item](https://pkg.go.dev/example.com/h/shop#Item) values"}},
+		{[]string{"shop.Cart.Add"}, hover{Name: "Cart.Add", Kind: "method", Signature: "func (c *Cart) Add(item Item)"}},
+		{[]string{"shop.Total"}, hover{Name: "Total", Kind: "func", Deprecated: "Deprecated: Use Cart.Sum."}},
+		{[]string{"shop.Cart.Items"}, hover{Name: "Items"}}, // A field, through a chain.
+		{[]string{"shop.sum"}, hover{Name: "Cart.Sum"}},     // Not Summary, which differs in more than case.
+	}
+	for _, test := range tests {
+		args := append([]string{"-hover"}, test.args...)
+		out, stderr, status := runDoc(t, dir, args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", args, status, stderr)
+			continue
+		}
+		var got hover
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Errorf("doc %q printed\n%s\nwhich is not a hover: %v", args, out, err)
+			continue
+		}
+		w := test.want
+		if got.Name != w.Name || w.Package != "" && got.Package != w.Package || w.Kind != "" && got.Kind != w.Kind ||
+			w.Signature != "" && got.Signature != w.Signature || got.Deprecated != w.Deprecated ||
+			!strings.HasPrefix(got.Doc, w.Doc) || !strings.HasPrefix(got.Markdown, w.Markdown) {
+			t.Errorf("doc %q printed\n%s\nwant %+v", args, out, w)
+		}
+		if got.Source == "" || got.URL == "" {
+			t.Errorf("doc %q printed\n%s\nwant a source position and URL", args, out)
+		}
+	}
+
+	out, stderr, status := runDoc(t, dir, "-hover", "shop.Cart.Add")
+	var h hover
+	if err := json.Unmarshal([]byte(out), &h); err != nil || status != 0 {
+		t.Fatalf("doc -hover shop.Cart.Add printed %q and exited with %d; stderr:\n%s", out, status, stderr)
+	}
+	if h.Example == nil || h.Example.Name != "Cart_Add" || h.Example.Output != "1\n" || !strings.Contains(h.Example.Code, `c.Add("pear")`) {
+		t.Errorf("hover of Cart.Add has example %+v, want the one with output 1", h.Example)
+	}
+
+	// Ambiguity and absence are errors.
+	for _, args := range [][]string{{"-hover", "-local", "-r", "s.*"}, {"-hover", "-local", "shop.Nothing"}} {
+		if out, _, status := runDoc(t, dir, args...); status != 1 || out != "" {
+			t.Errorf("doc %q printed %q and exited with %d, want nothing and 1", args, out, status)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/printer"
+	"html"
+	"path/filepath"
+	"strings"
+)
+
+// htmlFragment returns, for -html-fragment, the documentation of the
+// declaration of id in node as a fragment of HTML, with no page around it,
+// to be embedded elsewhere.
+func (f *File) htmlFragment(node ast.Node, id *ast.Ident, url string) string {
+	return htmlDoc(id.Name, f.bareDeclaration(node), url, f.linkPath(), docComment(node))
+}
+
+// linkPath returns the import path by which doc links to names of the
+// file's own package are resolved, or the empty string if it has none.
+func (f *File) linkPath() string {
+	pkgPath := importPath(filepath.Dir(f.name))
+	if filepath.IsAbs(filepath.FromSlash(pkgPath)) {
+		return ""
+	}
+	return pkgPath
+}
+
+// bareDeclaration returns the node printed without its doc comment, which the
+// printer includes for a node given alone, so the comment can be shown rendered.
+func (f *File) bareDeclaration(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		c := *n
+		c.Doc, node = nil, &c
+	case *ast.GenDecl:
+		c := *n
+		c.Doc, node = nil, &c
+	case *ast.TypeSpec:
+		c := *n
+		c.Doc, node = nil, &c
+	}
+	var decl bytes.Buffer
+	printer.Fprint(&decl, f.fset, node)
+	return decl.String()
+}
+
+// htmlDoc returns a div, whose id is the name, holding the declaration,
+// linked to its source if there is a URL, and the doc comment rendered as
+// go/doc/comment does, with doc links to pkg.go.dev. Links to names
+// without a package, such as [Reader], are to those of pkgPath.
+func htmlDoc(name, decl, url, pkgPath string, doc *ast.CommentGroup) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<div class=\"doc\" id=\"%s\">\n", html.EscapeString(name))
+	if url = strings.TrimSpace(url); url != "" {
+		fmt.Fprintf(&b, "<pre><code><a href=\"%s\">%s</a></code></pre>\n", html.EscapeString(url), html.EscapeString(decl))
+	} else {
+		fmt.Fprintf(&b, "<pre><code>%s</code></pre>\n", html.EscapeString(decl))
+	}
+	if doc != nil {
+		p := &comment.Printer{
+			DocLinkURL:   docLinkURL(pkgPath),
+			HeadingLevel: 4,
+			HeadingID:    func(*comment.Heading) string { return "" },
+		}
+		b.Write(p.HTML(parseDoc(doc)))
+	}
+	b.WriteString("</div>\n")
+	return b.String()
+}
+
+// parseDoc parses the doc comment, taking [Name] and [Type.Method] for doc
+// links to exported symbols of its own package.
+func parseDoc(doc *ast.CommentGroup) *comment.Doc {
+	parser := &comment.Parser{
+		LookupSym: func(recv, name string) bool { return ast.IsExported(name) },
+	}
+	return parser.Parse(doc.Text())
+}
+
+// docLinkURL returns the function a comment.Printer uses to give the
+// pkg.go.dev URL of a doc link, resolving those to symbols of the comment's
+// own package in the package with the import path.
+func docLinkURL(pkgPath string) func(*comment.DocLink) string {
+	return func(link *comment.DocLink) string {
+		if link.ImportPath == "" {
+			l := *link
+			l.ImportPath = pkgPath
+			link = &l
+		}
+		return link.DefaultURL("https://pkg.go.dev")
+	}
+}
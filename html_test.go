@@ -0,0 +1,98 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"strings"
+	"testing"
+)
+
+func TestHTMLDoc(t *testing.T) {
+	comment := func(text string) *ast.CommentGroup {
+		group := new(ast.CommentGroup)
+		for _, line := range strings.Split(text, "\n") {
+			group.List = append(group.List, &ast.Comment{Text: line})
+		}
+		return group
+	}
+	tests := []struct {
+		name, decl, url, pkgPath string
+		doc                      *ast.CommentGroup
+		want                     string
+	}{
+		{
+			name: "F", decl: "func F()",
+			want: "<div class=\"doc\" id=\"F\">\n<pre><code>func F()</code></pre>\n</div>\n",
+		},
+		{
+			name: "F", decl: "func F(a <-chan int)", url: "https://pkg.go.dev/p#F\n",
+			doc: comment("// F reads a & b."),
+			want: "<div class=\"doc\" id=\"F\">\n" +
+				"<pre><code><a href=\"https://pkg.go.dev/p#F\">func F(a &lt;-chan int)</a></code></pre>\n" +
+				"<p>F reads a &amp; b.\n" +
+				"</div>\n",
+		},
+		{
+			name: "T", decl: "type T int", pkgPath: "example.com/p",
+			doc: comment("// T is like [U], [io.Reader] and [not].\n//\n// # Use\n//\n// Use it."),
+			want: "<div class=\"doc\" id=\"T\">\n<pre><code>type T int</code></pre>\n" +
+				"<p>T is like <a href=\"https://pkg.go.dev/example.com/p#U\">U</a>, " +
+				"<a href=\"https://pkg.go.dev/io#Reader\">io.Reader</a> and [not].\n" +
+				"<h4>Use</h4>\n<p>Use it.\n</div>\n",
+		},
+		{
+			// Without an import path, a link is to the name on the same page.
+			name: "T", decl: "type T int",
+			doc: comment("// T is like [U]."),
+			want: "<div class=\"doc\" id=\"T\">\n<pre><code>type T int</code></pre>\n" +
+				"<p>T is like <a href=\"#U\">U</a>.\n</div>\n",
+		},
+	}
+	for _, test := range tests {
+		if got := htmlDoc(test.name, test.decl, test.url, test.pkgPath, test.doc); got != test.want {
+			t.Errorf("htmlDoc(%q, %q) =\n%s\nwant\n%s", test.name, test.decl, got, test.want)
+		}
+	}
+}
+
+func TestHTMLFragment(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/hf\n\ngo 1.22\n",
+		"shop/shop.go": `// Package shop sells, as [Cart] shows.
+package shop
+
+// Total totals the [Cart].
+func Total(c Cart) int { return 0 }
+
+// Cart holds items.
+type Cart struct{}
+`,
+	})
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"shop.total"}, "<div class=\"doc\" id=\"Total\">\n" +
+			"<pre><code><a href=\"https://pkg.go.dev/example.com/hf/shop#Total\">func Total(c Cart) int</a></code></pre>\n" +
+			"<p>Total totals the <a href=\"https://pkg.go.dev/example.com/hf/shop#Cart\">Cart</a>.\n" +
+			"</div>\n"},
+		{[]string{"-pkg", "shop"}, "<div class=\"doc\" id=\"shop\">\n" +
+			"<pre><code><a href=\"https://pkg.go.dev/example.com/hf/shop\">package shop</a></code></pre>\n" +
+			"<p>Package shop sells, as <a href=\"https://pkg.go.dev/example.com/hf/shop#Cart\">Cart</a> shows.\n" +
+			"</div>\n"},
+	}
+	for _, test := range tests {
+		args := append([]string{"-html-fragment"}, test.args...)
+		out, stderr, status := runDoc(t, dir, args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", args, status, stderr)
+			continue
+		}
+		if out != test.want {
+			t.Errorf("doc %q printed\n%s\nwant\n%s", args, out, test.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"strings"
+)
+
+// ifaceDiff compares the method sets of the interfaces given as pkg.Type,
+// printing the methods of the first missing from the second, those extra
+// in the second, and those whose signatures differ, with their docs.
+func (s *session) ifaceDiff(argA, argB string) {
+	a, okA := s.findType(argA)
+	b, okB := s.findType(argB)
+	for _, t := range []struct {
+		arg string
+		ok  bool
+	}{{argA, okA}, {argB, okB}} {
+		if !t.ok {
+			fmt.Fprintf(os.Stderr, "doc: -iface-diff: no type %s\n", t.arg)
+			exit(1)
+		}
+	}
+	ifaceA, okA := a.obj.Type().Underlying().(*types.Interface)
+	ifaceB, okB := b.obj.Type().Underlying().(*types.Interface)
+	if !okA || !okB {
+		fmt.Fprintf(os.Stderr, "doc: -iface-diff: %s and %s must both be interfaces\n", argA, argB)
+		exit(1)
+	}
+	nameA, nameB := a.typeString(a.obj.Type()), b.typeString(b.obj.Type())
+	fmt.Fprintf(s.out, "%s and %s\n\n", nameA, nameB)
+	var missing, extra, changed, same []string
+	for i := 0; i < ifaceA.NumMethods(); i++ {
+		fa := ifaceA.Method(i)
+		fb := lookupMethod(ifaceB, fa.Name())
+		switch {
+		case fb == nil:
+			missing = append(missing, s.methodLine(a, fa))
+		case a.signatureKey(fa) != b.signatureKey(fb):
+			lineA := indent(nameA + ": " + strings.TrimPrefix(s.methodLine(a, fa), "\t"))
+			lineB := indent(nameB + ": " + strings.TrimPrefix(s.methodLine(b, fb), "\t"))
+			changed = append(changed, fmt.Sprintf("\t%s\n\t%s\n\t%s", fa.Name(), lineA, lineB))
+		default:
+			same = append(same, fa.Name())
+		}
+	}
+	for i := 0; i < ifaceB.NumMethods(); i++ {
+		if fb := ifaceB.Method(i); lookupMethod(ifaceA, fb.Name()) == nil {
+			extra = append(extra, s.methodLine(b, fb))
+		}
+	}
+	printSection(s.out, "missing from "+nameB, missing)
+	printSection(s.out, "extra in "+nameB, extra)
+	printSection(s.out, "changed", changed)
+	if len(same) > 0 {
+		fmt.Fprintf(s.out, "same: %s\n", strings.Join(same, ", "))
+	}
+}
+
+// lookupMethod returns the method of the interface with the name, or nil.
+func lookupMethod(iface *types.Interface, name string) *types.Func {
+	for i := 0; i < iface.NumMethods(); i++ {
+		if iface.Method(i).Name() == name {
+			return iface.Method(i)
+		}
+	}
+	return nil
+}
+
+// methodSignature returns the method, which appears in the interface t,
+// as it would be written in the interface.
+func (t namedType) methodSignature(fn *types.Func) string {
+	return fn.Name() + strings.TrimPrefix(t.typeString(fn.Type()), "func")
+}
+
+// signatureKey returns the signature of the method without the names of
+// its parameters and results, which do not change what satisfies it.
+func (t namedType) signatureKey(fn *types.Func) string {
+	return t.typeString(unnamedSignature(fn))
+}
+
+// unnamedSignature returns the signature of the function, less its
+// receiver and the names of its parameters and results.
+func unnamedSignature(fn *types.Func) *types.Signature {
+	sig := fn.Type().(*types.Signature)
+	unnamed := func(tuple *types.Tuple) *types.Tuple {
+		var vars []*types.Var
+		for i := 0; i < tuple.Len(); i++ {
+			vars = append(vars, types.NewVar(0, nil, "", tuple.At(i).Type()))
+		}
+		return types.NewTuple(vars...)
+	}
+	return types.NewSignatureType(nil, nil, nil, unnamed(sig.Params()), unnamed(sig.Results()), sig.Variadic())
+}
+
+// methodLine returns, for a section, the method of the interface t with
+// the first sentence of its documentation.
+func (s *session) methodLine(t namedType, fn *types.Func) string {
+	line := "\t" + t.methodSignature(fn)
+	files := t.files
+	if fn.Pkg() != t.obj.Pkg() {
+		files = nil
+		if dir := dirForImport(fn.Pkg().Path(), ""); dir != "" {
+			files = s.parsePackageFiles(dir)
+		}
+	}
+	iface := ""
+	if named, ok := fn.Type().(*types.Signature).Recv().Type().(*types.Named); ok {
+		iface = named.Obj().Name()
+	}
+	if syn := synopsis(interfaceMethodDoc(files, iface, fn.Name())); syn != "" {
+		line += "\n\t\t" + syn
+	}
+	return line
+}
+
+// interfaceMethodDoc returns the doc comment, or else the line comment, of
+// the method declared in the named interface type, or in any interface type
+// of the files if iface is empty.
+func interfaceMethodDoc(files []*ast.File, iface, method string) *ast.CommentGroup {
+	var doc *ast.CommentGroup
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok || doc != nil {
+				return doc == nil
+			}
+			it, ok := spec.Type.(*ast.InterfaceType)
+			if !ok || iface != "" && spec.Name.Name != iface {
+				return false
+			}
+			for _, field := range it.Methods.List {
+				for _, id := range field.Names {
+					if id.Name == method {
+						doc = field.Doc
+						if doc == nil {
+							doc = field.Comment
+						}
+					}
+				}
+			}
+			return false
+		})
+	}
+	return doc
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIfaceDiff(t *testing.T) {
+	fakeGOROOT(t, nil)
+	old := goPaths
+	goPaths = nil
+	defer func() { goPaths = old }()
+	writeModule(t, map[string]string{
+		"go.mod": "module example.com/id\n\ngo 1.22\n",
+		"std/std.go": `package std
+
+// Reader reads.
+type Reader interface {
+	// Read reads into p.
+	Read(p []byte) (n int, err error)
+}
+
+// ReadWriteCloser groups the methods.
+type ReadWriteCloser interface {
+	Reader
+	// Write writes p.
+	Write(p []byte) (n int, err error)
+	// Close closes.
+	Close() error
+}
+
+// Buffer is not an interface.
+type Buffer struct{}
+`,
+		"mine/mine.go": `package mine
+
+// ReadWriteCloser is a local version.
+type ReadWriteCloser interface {
+	// Read reads.
+	Read(buf []byte) (int, error)
+	// Write writes a string.
+	Write(s string) (int, error)
+	// Flush flushes.
+	Flush() error
+}
+
+// Any is empty.
+type Any interface{}
+`,
+	})
+	tests := []struct {
+		a, b   string
+		want   string
+		status int
+	}{
+		{
+			a: "std.ReadWriteCloser", b: "mine.ReadWriteCloser",
+			want: `example.com/id/std.ReadWriteCloser and example.com/id/mine.ReadWriteCloser
+
+missing from example.com/id/mine.ReadWriteCloser:
+	Close() error
+		Close closes.
+
+extra in example.com/id/mine.ReadWriteCloser:
+	Flush() error
+		Flush flushes.
+
+changed:
+	Write
+		example.com/id/std.ReadWriteCloser: Write(p []byte) (n int, err error)
+			Write writes p.
+		example.com/id/mine.ReadWriteCloser: Write(s string) (int, error)
+			Write writes a string.
+
+same: Read
+`,
+		},
+		{
+			// An embedded method is documented in the interface embedded.
+			a: "mine.Any", b: "std.ReadWriteCloser",
+			want: `example.com/id/mine.Any and example.com/id/std.ReadWriteCloser
+
+extra in example.com/id/std.ReadWriteCloser:
+	Close() error
+		Close closes.
+	Read(p []byte) (n int, err error)
+		Read reads into p.
+	Write(p []byte) (n int, err error)
+		Write writes p.
+
+`,
+		},
+		{a: "std.Reader", b: "std.Buffer", status: 1},
+		{a: "std.Reader", b: "std.Nothing", status: 1},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).ifaceDiff(test.a, test.b) })
+		if status != test.status {
+			t.Errorf("-iface-diff %s %s exited with %d, want %d", test.a, test.b, status, test.status)
+			continue
+		}
+		if status == 0 && out.String() != test.want {
+			t.Errorf("-iface-diff %s %s printed\n%s\nwant\n%s", test.a, test.b, out.String(), test.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportPath(t *testing.T) {
+	fakeGOROOT(t, nil)
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	mod := writeModule(t, testModule)
+	goPath := t.TempDir()
+	old := goPaths
+	goPaths = []string{goPath}
+	defer func() { goPaths = old }()
+	other := t.TempDir()
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{mod, "example.com/m/v2"},
+		{filepath.Join(mod, "internal", "auth"), "example.com/m/v2/internal/auth"},
+		{filepath.Join(mod, "vendor", "example.com", "dep"), "example.com/dep"},
+		{filepath.Join(cache, "github.com", "!burnt!sushi", "toml@v1.3.2", "internal"), "github.com/BurntSushi/toml/internal"},
+		{filepath.Join(goRoot, "src", "net", "http"), "net/http"},
+		{filepath.Join(goRoot, "src", "vendor", "golang.org", "x", "net", "idna"), "golang.org/x/net/idna"},
+		{filepath.Join(goPath, "src", "example.org", "p"), "example.org/p"},
+		{other, filepath.ToSlash(other)},
+	}
+	for _, test := range tests {
+		if got := importPath(test.dir); got != test.want {
+			t.Errorf("importPath(%s) = %q, want %q", test.dir, got, test.want)
+		}
+	}
+}
+
+func TestUnvendor(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"example.com/p", "example.com/p"},
+		{"vendor/golang.org/x/net/idna", "golang.org/x/net/idna"},
+		{"example.com/m/vendor/example.com/dep", "example.com/dep"},
+		{"example.com/a/vendor/b/vendor/c", "c"},
+		{"example.com/vendorless", "example.com/vendorless"},
+	}
+	for _, test := range tests {
+		if got := unvendor(test.path); got != test.want {
+			t.Errorf("unvendor(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestImportLine(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":                "module example.com/m/v2\n\ngo 1.22\n",
+		"internal/auth/auth.go": "package auth\n\n// Make makes.\nfunc Make() {}\n",
+		"cmd/tool/main.go":      "package main\n\n// Run runs.\nfunc Run() {}\n",
+	})
+	const line = `import "example.com/m/v2/internal/auth"`
+	tests := []struct {
+		args []string
+		want string // First line printed.
+	}{
+		{[]string{"auth.Make"}, line},
+		{[]string{"-doc", "auth.Make"}, "internal to example.com/m/v2"},
+		{[]string{"-src", "auth.Make"}, filepath.Join(dir, "internal", "auth", "auth.go") + ":4:"},
+		{[]string{"-url", "auth.Make"}, "https://pkg.go.dev/example.com/m/v2/internal/auth#Make"},
+		// A command cannot be imported.
+		{[]string{"tool.Run"}, "https://pkg.go.dev/example.com/m/v2/cmd/tool#Run"},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if got, _, _ := strings.Cut(out, "\n"); got != test.want {
+			t.Errorf("doc %q printed first %q, want %q", test.args, got, test.want)
+		}
+	}
+}
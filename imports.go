@@ -0,0 +1,121 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// An importUse is what -import prints for a match: the import declaration
+// for its package and an example of its use.
+type importUse struct {
+	name string // As declared.
+	text string
+}
+
+// addImport records, for -import, the import declaration and an example
+// of the use of the declaration of the name id in node.
+func (f *File) addImport(node ast.Node, id *ast.Ident) {
+	line := f.importLine()
+	if line == "" {
+		line = fmt.Sprintf("// %s cannot be imported\n", f.file.Name.Name)
+	}
+	f.s.imports = append(f.s.imports, importUse{id.Name, line + "\n" + f.usage(node, id) + "\n"})
+}
+
+// usage returns an example of the use of the declaration from outside its
+// package: a call, with the parameters' names as arguments, for a function
+// or method, a variable for a type, a selector of such a variable for a
+// field, and the qualified name otherwise.
+func (f *File) usage(node ast.Node, id *ast.Ident) string {
+	qualified := f.file.Name.Name + "." + id.Name
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		call := fmt.Sprintf("%s(%s)", id.Name, arguments(n.Type.Params))
+		typeName := recvTypeName(n)
+		if typeName == "" {
+			return f.file.Name.Name + "." + call
+		}
+		v := varName(typeName)
+		return fmt.Sprintf("var %s %s.%s\n%s.%s", v, f.file.Name.Name, typeName, v, call)
+	case *ast.TypeSpec:
+		return fmt.Sprintf("var %s %s", varName(id.Name), qualified)
+	case *ast.GenDecl:
+		if len(n.Specs) == 0 {
+			break
+		}
+		spec, ok := n.Specs[0].(*ast.TypeSpec)
+		if !ok {
+			break
+		}
+		v := varName(spec.Name.Name)
+		if spec.Name != id {
+			// A field, as printed by nested.
+			return fmt.Sprintf("var %s %s.%s\n%s.%s", v, f.file.Name.Name, spec.Name.Name, v, id.Name)
+		}
+		return fmt.Sprintf("var %s %s", v, qualified)
+	}
+	return qualified
+}
+
+// arguments returns the names of the parameters, separated by commas, with
+// ... after a variadic one, or "..." if any parameter is unnamed.
+func arguments(params *ast.FieldList) string {
+	var names []string
+	for _, field := range params.List {
+		if len(field.Names) == 0 {
+			return "..."
+		}
+		for _, name := range field.Names {
+			if name.Name == "_" {
+				return "..."
+			}
+			names = append(names, name.Name)
+		}
+		if _, ok := field.Type.(*ast.Ellipsis); ok {
+			names[len(names)-1] += "..."
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// varName returns a name for a variable of the type: its initial, in lower case.
+func varName(typeName string) string {
+	r, _ := utf8.DecodeRuneInString(typeName)
+	return string(unicode.ToLower(r))
+}
+
+// printImport prints, for -import, the import declaration and example of
+// the single match. Of several, one matching the name including case is
+// chosen if there is just one.
+func (s *session) printImport(name string) {
+	uses := s.imports
+	if len(uses) > 1 {
+		var exact []importUse
+		for _, u := range uses {
+			if u.name == name {
+				exact = append(exact, u)
+			}
+		}
+		if len(exact) == 1 {
+			uses = exact
+		}
+	}
+	switch len(uses) {
+	case 0:
+		fmt.Fprintf(os.Stderr, "doc: -import: no match for %s\n", name)
+		exit(1)
+	case 1:
+		fmt.Fprint(s.out, uses[0].text)
+	default:
+		fmt.Fprintf(os.Stderr, "doc: -import: %d matches for %s; name just one\n", len(uses), name)
+		exit(1)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"strings"
+	"testing"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		fn   string
+		want string
+	}{
+		{"func()", ""},
+		{"func(s, sep string)", "s, sep"},
+		{"func(format string, a ...any)", "format, a..."},
+		{"func(int, string)", "..."},
+		{"func(_ int, s string)", "..."},
+	}
+	for _, test := range tests {
+		expr, err := parser.ParseExpr(test.fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := arguments(expr.(*ast.FuncType).Params); got != test.want {
+			t.Errorf("arguments(%s) = %q, want %q", test.fn, got, test.want)
+		}
+	}
+}
+
+func TestVarName(t *testing.T) {
+	tests := []struct {
+		typeName string
+		want     string
+	}{
+		{"Cart", "c"},
+		{"URL", "u"},
+		{"Ärger", "ä"},
+	}
+	for _, test := range tests {
+		if got := varName(test.typeName); got != test.want {
+			t.Errorf("varName(%q) = %q, want %q", test.typeName, got, test.want)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/im\n\ngo 1.22\n",
+		"shop/shop.go": `package shop
+
+// Cart holds items.
+type Cart struct {
+	// Items are the items.
+	Items []string
+}
+
+// Add adds.
+func (c *Cart) Add(item string, n int) {}
+
+// Total totals.
+func Total(prices ...float64) float64 { return 0 }
+
+// Skip skips.
+func Skip(int, string) {}
+
+// MaxItems is the limit.
+const MaxItems = 10
+
+// Count counts.
+func Count() {}
+
+// COUNT counts loudly.
+func COUNT() {}
+`,
+		"cmd/t/main.go": "package main\n\n// Run runs.\nfunc Run() {}\n",
+	})
+	const imp = "import \"example.com/im/shop\"\n\n"
+	tests := []struct {
+		arg    string
+		want   string
+		stderr string
+		status int
+	}{
+		{arg: "shop.cart", want: imp + "var c shop.Cart\n"},
+		{arg: "shop.add", want: imp + "var c shop.Cart\nc.Add(item, n)\n"},
+		{arg: "shop.cart.items", want: imp + "var c shop.Cart\nc.Items\n"},
+		{arg: "shop.total", want: imp + "shop.Total(prices...)\n"},
+		{arg: "shop.skip", want: imp + "shop.Skip(...)\n"},
+		{arg: "shop.maxitems", want: imp + "shop.MaxItems\n"},
+		// Of several matches, the one matching including case is chosen.
+		{arg: "shop.Count", want: imp + "shop.Count()\n"},
+		{arg: "shop.COUNT", want: imp + "shop.COUNT()\n"},
+		{arg: "shop.count", stderr: "doc: -import: 2 matches for count; name just one\n", status: 1},
+		{arg: "shop.nothing", stderr: "doc: -import: no match for nothing\n", status: 1},
+		{arg: "t.run", want: "// main cannot be imported\n\nmain.Run()\n"},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, "-import", test.arg)
+		if status != test.status {
+			t.Errorf("doc -import %s exited with %d, want %d; stderr:\n%s", test.arg, status, test.status, stderr)
+			continue
+		}
+		if out != test.want {
+			t.Errorf("doc -import %s printed\n%s\nwant\n%s", test.arg, out, test.want)
+		}
+		if !strings.Contains(stderr, test.stderr) {
+			t.Errorf("doc -import %s reported %q, want %q", test.arg, stderr, test.stderr)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+)
+
+// inheritDoc prints, for the undocumented method fn, the documentation of
+// the interface method it implements, as is the custom when a type is
+// written to satisfy an interface. The interfaces looked at are those of
+// the method's package, then those of the packages it imports; the first
+// that the receiver implements and that documents the method is used. The
+// documentation of an interface of one method, such as io.Reader, is often
+// that of the type, not the method.
+func (f *File) inheritDoc(fn *ast.FuncDecl) {
+	obj, ok := f.objs[fn.Name].(*types.Func)
+	if !ok || f.types == nil {
+		return
+	}
+	recv := obj.Type().(*types.Signature).Recv().Type()
+	for _, pkg := range append([]*types.Package{f.types}, f.types.Imports()...) {
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !tn.Exported() || tn.IsAlias() {
+				continue
+			}
+			iface, ok := tn.Type().Underlying().(*types.Interface)
+			if !ok || !declaresMethod(iface, obj.Name()) || !types.Implements(recv, iface) {
+				continue
+			}
+			if doc := f.interfaceDoc(pkg, name, obj.Name(), iface.NumMethods() == 1); doc != nil {
+				fmt.Fprintf(f.output(nodeKind(fn)), "%s(documented on %s.%s.%s)\n\n", commentLines(doc.Text()), pkg.Name(), name, obj.Name())
+				return
+			}
+		}
+	}
+}
+
+// declaresMethod reports whether the interface itself, not one it embeds,
+// declares the method, so the documentation of the method is on it.
+func declaresMethod(iface *types.Interface, name string) bool {
+	for i := 0; i < iface.NumExplicitMethods(); i++ {
+		if iface.ExplicitMethod(i).Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceDoc returns the documentation of the method of the named
+// interface in the package, which is the file's own or one it imports, or
+// if the method has none and typeDoc is set, that of the interface.
+func (f *File) interfaceDoc(pkg *types.Package, iface, method string, typeDoc bool) *ast.CommentGroup {
+	var files []*ast.File
+	if pkg == f.types {
+		for _, file := range f.allFiles {
+			files = append(files, file.file)
+		}
+	} else if dir := dirForImport(pkg.Path(), filepath.Dir(f.name)); dir != "" {
+		files = f.s.parsePackageFiles(dir)
+	}
+	if doc := interfaceMethodDoc(files, iface, method); doc != nil || !typeDoc {
+		return doc
+	}
+	return findDoc(files, "", iface)
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInheritDoc(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/ih\n\ngo 1.22\n",
+		"shop/shop.go": `package shop
+
+import "io"
+
+// Store stores.
+type Store interface {
+	// Get gets the value for k.
+	Get(k string) string
+	Put(k, v string)
+}
+
+// Mem is in memory.
+type Mem struct{}
+
+func (Mem) Get(k string) string { return "" }
+
+func (Mem) Put(k, v string) {}
+
+// Read reads into nothing.
+func (Mem) Read(p []byte) (int, error) { return 0, nil }
+
+// Stream streams.
+type Stream struct{}
+
+func (*Stream) Read(p []byte) (int, error) { return 0, nil }
+
+var _ io.Reader = (*Stream)(nil)
+`,
+	})
+	const get = "// Get gets the value for k.\n(documented on shop.Store.Get)\n"
+	tests := []struct {
+		args []string
+		decl string
+		want string // What follows the declaration, if anything.
+	}{
+		{[]string{"-inherit-doc", "-m", "shop.get"}, "func (Mem) Get(k string) string", get},
+		{[]string{"-inherit-doc", "shop.mem.get"}, "func (Mem) Get(k string) string", get},
+		// An interface method without documentation gives none.
+		{[]string{"-inherit-doc", "-m", "shop.put"}, "func (Mem) Put(k, v string)", ""},
+		// A documented method keeps its own.
+		{[]string{"-inherit-doc", "shop.mem.read"}, "func (Mem) Read(p []byte) (int, error)", ""},
+		// The documentation of an interface of one method is that of the type.
+		{[]string{"-inherit-doc", "shop.stream.read"}, "func (*Stream) Read(p []byte) (int, error)", "// Reader is the interface that wraps the basic Read method."},
+		{[]string{"-m", "shop.get"}, "func (Mem) Get(k string) string", ""},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		_, after, _ := strings.Cut(out, test.decl+"\n\n")
+		if !strings.HasPrefix(after, test.want) || test.want == "" && strings.Contains(after, "documented on") {
+			t.Errorf("doc %q printed\n%s\nwant after the declaration\n%s", test.args, out, test.want)
+		}
+	}
+	out, _, _ := runDoc(t, dir, "-inherit-doc", "shop.stream.read")
+	if !strings.HasSuffix(out, "(documented on io.Reader.Read)\n\n") {
+		t.Errorf("doc -inherit-doc shop.stream.read printed\n%s\nwant it attributed to io.Reader.Read", out)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// licenseFiles holds the names of the files that hold a license, in order of preference.
+var licenseFiles = []string{"LICENSE", "LICENSE.txt", "LICENSE.md", "LICENCE", "COPYING", "COPYING.txt"}
+
+// licenseNote returns a description of the license covering the file: the
+// SPDX identifier in its header, or else the license file governing its
+// module or GOPATH project, together with the file's copyright line.
+func (f *File) licenseNote() string {
+	var copyright, spdx string
+	for _, c := range f.file.Comments {
+		if c.Pos() > f.file.Package {
+			break
+		}
+		for _, line := range strings.Split(c.Text(), "\n") {
+			switch {
+			case copyright == "" && strings.HasPrefix(line, "Copyright"):
+				copyright = line
+			case strings.HasPrefix(line, "SPDX-License-Identifier:"):
+				spdx = strings.TrimSpace(strings.TrimPrefix(line, "SPDX-License-Identifier:"))
+			}
+		}
+	}
+	note := "License: "
+	if spdx != "" {
+		note += spdx
+	} else {
+		note += f.s.findLicense(filepath.Dir(f.name))
+	}
+	if copyright != "" {
+		note += "\n" + copyright
+	}
+	return note + "\n\n"
+}
+
+// findLicense looks in the directory and its parents, stopping at the root of
+// a module or of a source tree, or at GOROOT, for a license file. It returns the kind of
+// license, if recognized, and the file's name.
+func (s *session) findLicense(dir string) string {
+	if note, ok := s.licenses[dir]; ok {
+		stats.cacheHits.Add(1)
+		return note
+	}
+	note := "none found"
+	for d := dir; ; {
+		if d == filepath.Join(goRoot, "src") {
+			d = goRoot // The standard library's license is at the top.
+		}
+		if name := licenseFileIn(d); name != "" {
+			note = fmt.Sprintf("%s (%s)", licenseKind(name), name)
+			break
+		}
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(d)
+		if parent == d || filepath.Base(d) == "src" {
+			break
+		}
+		d = parent
+	}
+	s.licenses[dir] = note
+	return note
+}
+
+// licenseFileIn returns the name of the license file in the directory, if any.
+func licenseFileIn(dir string) string {
+	for _, name := range licenseFiles {
+		name = filepath.Join(dir, name)
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// licenseKind guesses the SPDX identifier of the license in the named file
+// from some telltale phrases.
+func licenseKind(name string) string {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "unknown"
+	}
+	text := strings.Join(strings.Fields(string(data)), " ") // Normalize spacing.
+	has := func(s string) bool { return strings.Contains(text, s) }
+	switch {
+	case has("Apache License") && has("Version 2.0"):
+		return "Apache-2.0"
+	case has("Mozilla Public License") && has("2.0"):
+		return "MPL-2.0"
+	case has("GNU AFFERO GENERAL PUBLIC LICENSE"):
+		return "AGPL"
+	case has("GNU LESSER GENERAL PUBLIC LICENSE"):
+		return "LGPL"
+	case has("GNU GENERAL PUBLIC LICENSE"):
+		return "GPL"
+	case has("Redistribution and use in source and binary forms"):
+		if has("Neither the name") {
+			return "BSD-3-Clause"
+		}
+		return "BSD-2-Clause"
+	case has("Permission is hereby granted, free of charge"):
+		return "MIT"
+	case has("Permission to use, copy, modify, and/or distribute"):
+		return "ISC"
+	case has("This is free and unencumbered software released into the public domain"):
+		return "Unlicense"
+	}
+	return "unknown"
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLicense(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":  "module example.com/lic\n\ngo 1.22\n",
+		"LICENSE": "Copyright 2020 Someone\n\nPermission is hereby granted, free of charge, to any person\nobtaining a copy...",
+		"a/a.go":  "// Copyright 2021 The A Authors.\n\n// Package a is MIT.\npackage a\n\n// A is a.\nfunc A() {}\n",
+		"b/b.go": `// Copyright 2022 The B Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package b
+
+// Copyright 2099 is not in the header.
+
+// B is b.
+func B() {}
+`,
+		"c/go.mod":     "module example.com/lic/c\n\ngo 1.22\n",
+		"c/c.go":       "package c\n\n// C is c.\nfunc C() {}\n",
+		"d/COPYING":    "GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3",
+		"d/d.go":       "package d\n\n// D is d.\nfunc D() {}\n",
+		"e/LICENSE.md": "Some terms of our own.",
+		"e/e.go":       "package e\n\n// E is e.\nfunc E() {}\n",
+	})
+	tests := []struct {
+		pkg, name string
+		want      string // With the module directory as $DIR.
+	}{
+		{"a", "A", "License: MIT ($DIR/LICENSE)\nCopyright 2021 The A Authors.\n"},
+		{"b", "B", "License: Apache-2.0\nCopyright 2022 The B Authors.\n"},
+		{"c", "C", "License: none found\n"}, // The module c has none of its own.
+		{"d", "D", "License: LGPL ($DIR/d/COPYING)\n"},
+		{"e", "E", "License: unknown ($DIR/e/LICENSE.md)\n"},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, "-license", "./"+test.pkg, test.name)
+		if status != 0 {
+			t.Errorf("doc -license %s %s exited with %d; stderr:\n%s", test.pkg, test.name, status, stderr)
+			continue
+		}
+		out = strings.ReplaceAll(out, dir, "$DIR")
+		if !strings.Contains(out, test.want+"\n") {
+			t.Errorf("doc -license %s %s printed\n%s\nwant\n%s", test.pkg, test.name, out, test.want)
+		}
+	}
+}
+
+func TestLicenseKind(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"Apache License\n   Version 2.0, January 2004", "Apache-2.0"},
+		{"Mozilla Public License Version 2.0", "MPL-2.0"},
+		{"GNU AFFERO GENERAL PUBLIC LICENSE", "AGPL"},
+		{"GNU GENERAL PUBLIC LICENSE Version 3", "GPL"},
+		{"Redistribution and use in source and binary forms ... Neither the name of", "BSD-3-Clause"},
+		{"Redistribution and use in\nsource and binary forms", "BSD-2-Clause"},
+		{"Permission to use, copy, modify, and/or distribute this software", "ISC"},
+		{"This is free and unencumbered software released into the public domain.", "Unlicense"},
+		{"All rights reserved.", "unknown"},
+	}
+	for _, test := range tests {
+		name := filepath.Join(t.TempDir(), "LICENSE")
+		if err := os.WriteFile(name, []byte(test.text), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if got := licenseKind(name); got != test.want {
+			t.Errorf("licenseKind of %q = %s, want %s", test.text, got, test.want)
+		}
+	}
+}
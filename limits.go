@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
+	"regexp"
+	"regexp/syntax"
+	"sync"
+	"time"
+)
+
+// The limits on source downloaded from the module proxy, which may be
+// pathological or malicious, and so is checked before it is parsed. They
+// may be set in the configuration file, where they are written
+// limit.filesize (bytes), limit.nesting and limit.parsetime (seconds).
+// The size of a module zip file is limited as the proxy limits it.
+var (
+	maxFileSize  = int(configFloat("limit.filesize", 10<<20))
+	maxNesting   = int(configFloat("limit.nesting", 1000))
+	maxParseTime = time.Duration(configFloat("limit.parsetime", 10) * float64(time.Second))
+	maxZipSize   = int64(500 << 20)
+)
+
+// The limits on the regular expressions of queries and of -exclude, which
+// may be set in the configuration file as limit.regexplen (bytes) and
+// limit.regexpsize (instructions of the compiled program). Go's regular
+// expressions match in time linear in the input, and names are short, so
+// matching cannot run away; but a long pattern, or one repeating repeated
+// alternatives such as ((a|b){30}(c|d){30}(e|f){30}(g|h){30}){30}, compiles
+// to a program so large that compiling it, and running it on each of many
+// names, is slow.
+var (
+	maxRegexpLen  = int(configFloat("limit.regexplen", 1000))
+	maxRegexpSize = int(configFloat("limit.regexpsize", 10000))
+)
+
+// nameRegexps caches the compiled patterns of compileName. As a compiled
+// pattern is safe for concurrent use, the cache is shared by the process,
+// guarded for the type checks that run in parallel.
+var nameRegexps = struct {
+	sync.Mutex
+	byPattern map[string]*regexp.Regexp
+}{byPattern: make(map[string]*regexp.Regexp)}
+
+// compileName returns the regular expression matching a whole name, ignoring
+// case, for the pattern, refusing one beyond the limits. The pattern must
+// be a regular expression by itself, or one such as a)|(b could break out
+// of the anchors wrapped around it. Each pattern is compiled just once.
+func compileName(pattern string) (*regexp.Regexp, error) {
+	nameRegexps.Lock()
+	re, ok := nameRegexps.byPattern[pattern]
+	nameRegexps.Unlock()
+	if ok {
+		return re, nil
+	}
+	if len(pattern) > maxRegexpLen {
+		return nil, fmt.Errorf("regular expression longer than %d bytes (limit.regexplen)", maxRegexpLen)
+	}
+	if _, err := syntax.Parse(pattern, syntax.Perl); err != nil {
+		return nil, err
+	}
+	expr := "^(?i:" + pattern + ")$"
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	if len(prog.Inst) > maxRegexpSize {
+		return nil, fmt.Errorf("regular expression %s too complex: %d instructions, more than %d (limit.regexpsize)", pattern, len(prog.Inst), maxRegexpSize)
+	}
+	re, err = regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	nameRegexps.Lock()
+	nameRegexps.byPattern[pattern] = re
+	nameRegexps.Unlock()
+	return re, nil
+}
+
+// readZipFile returns the contents of the file in a downloaded zip file,
+// refusing to decompress more than the limit on the size of a file.
+func readZipFile(zf *zip.File) ([]byte, error) {
+	if zf.UncompressedSize64 > uint64(maxFileSize) {
+		return nil, fmt.Errorf("%s: file larger than %d bytes", zf.Name, maxFileSize)
+	}
+	rc, err := zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	src, err := io.ReadAll(io.LimitReader(rc, int64(maxFileSize)+1))
+	if err == nil && len(src) > maxFileSize {
+		err = fmt.Errorf("%s: file larger than %d bytes", zf.Name, maxFileSize)
+	}
+	return src, err
+}
+
+// parseUntrusted parses the source of a downloaded file, returning an error
+// instead of the file if the source exceeds the limits: it is too big, its
+// parentheses, brackets and braces nest too deeply, or it takes too long to parse.
+//
+// The time limit only stops the wait. A goroutine cannot be stopped, so a
+// parse that runs too long is abandoned to finish, or not, on its own, and
+// the CPU and memory it uses are not bounded; the limits on size and nesting
+// are what keep it from running away. So that an abandoned parse writes
+// nothing shared, the file is parsed into a private FileSet, beginning where
+// fset ends, and added to fset only once parsed. Nothing else may add to
+// fset while the file is parsed.
+func parseUntrusted(fset *token.FileSet, name string, src []byte) (*ast.File, error) {
+	if len(src) > maxFileSize {
+		return nil, fmt.Errorf("%s: file larger than %d bytes", name, maxFileSize)
+	}
+	if nestingDepth(src) > maxNesting {
+		return nil, fmt.Errorf("%s: nesting deeper than %d", name, maxNesting)
+	}
+	type parsed struct {
+		file *ast.File
+		err  error
+	}
+	private := token.NewFileSet()
+	private.AddFile("", fset.Base(), 0) // Start the parsed file's positions past fset's.
+	c := make(chan parsed, 1)
+	start := time.Now()
+	go func() {
+		file, err := parser.ParseFile(private, name, src, parser.ParseComments)
+		c <- parsed{file, err}
+	}()
+	select {
+	case p := <-c:
+		stats.files.Add(1)
+		stats.parse.Add(since(start))
+		if p.file != nil {
+			fset.AddExistingFiles(private.File(p.file.FileStart))
+		}
+		return p.file, p.err
+	case <-time.After(maxParseTime):
+		return nil, fmt.Errorf("%s: parse took longer than %s", name, maxParseTime)
+	}
+}
+
+// nestingDepth returns the deepest nesting of parentheses, brackets and
+// braces in the source, found by scanning, which unlike parsing takes
+// constant space however deep the nesting.
+func nestingDepth(src []byte) int {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", -1, len(src)), src, nil, 0)
+	depth, deepest := 0, 0
+	for {
+		_, tok, _ := s.Scan()
+		switch tok {
+		case token.EOF:
+			return deepest
+		case token.LPAREN, token.LBRACK, token.LBRACE:
+			depth++
+			if depth > deepest {
+				deepest = depth
+			}
+		case token.RPAREN, token.RBRACK, token.RBRACE:
+			depth--
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"regexp/syntax"
+	"strings"
+	"testing"
+)
+
+func TestCompileName(t *testing.T) {
+	tests := []struct {
+		pattern string
+		err     string // A substring of the error, if one is wanted.
+		match   []string
+		noMatch []string
+	}{
+		{pattern: "Read.*", match: []string{"Read", "ReadAll", "readfull"}, noMatch: []string{"XRead"}},
+		{pattern: "a|b", match: []string{"a", "B"}, noMatch: []string{"ab"}}, // Alternatives match whole names.
+		{pattern: "(", err: "missing closing )"},
+		{pattern: "a)|(b", err: "unexpected )"}, // It would break out of the anchors.
+		{pattern: strings.Repeat("x", maxRegexpLen+1), err: "limit.regexplen"},
+		{pattern: "((a|b){30}(c|d){30}(e|f){30}(g|h){30}){30}", err: "limit.regexpsize"},
+	}
+	for _, test := range tests {
+		re, err := compileName(test.pattern)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("compileName(%.20q) error = %v, want one containing %q", test.pattern, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("compileName(%q): %v", test.pattern, err)
+			continue
+		}
+		for _, name := range test.match {
+			if !re.MatchString(name) {
+				t.Errorf("compileName(%q) does not match %s", test.pattern, name)
+			}
+		}
+		for _, name := range test.noMatch {
+			if re.MatchString(name) {
+				t.Errorf("compileName(%q) matches %s", test.pattern, name)
+			}
+		}
+	}
+}
+
+func TestNestingDepth(t *testing.T) {
+	tests := []struct {
+		src  string
+		want int
+	}{
+		{"package p", 0},
+		{"package p\nfunc f() {}", 1},
+		{"package p\nvar x = [][]int{{1}, {(2)}}", 3},
+		{"package p\nvar s = \"(((\" // ((((", 0}, // Not in strings or comments.
+		{"package p\nvar x = f(((", 3},            // Unbalanced, as malicious source may be.
+		{strings.Repeat("(", 5000) + strings.Repeat(")", 5000), 5000},
+	}
+	for _, test := range tests {
+		if got := nestingDepth([]byte(test.src)); got != test.want {
+			t.Errorf("nestingDepth(%.30q) = %d, want %d", test.src, got, test.want)
+		}
+	}
+}
+
+func TestParseUntrusted(t *testing.T) {
+	oldSize, oldNesting := maxFileSize, maxNesting
+	maxFileSize, maxNesting = 100, 3
+	defer func() { maxFileSize, maxNesting = oldSize, oldNesting }()
+	tests := []struct {
+		src string
+		err string // A substring of the error, if one is wanted.
+	}{
+		{src: "package p\n\nfunc f() { g((1)) }\n"},
+		{src: "package p\n\nvar x = f((((1))))\n", err: "nesting deeper than 3"},
+		{src: "package p\n\n" + strings.Repeat("// Comment.\n", 10), err: "file larger than 100 bytes"},
+		{src: "package p\n\nfunc (\n", err: "expected"},
+	}
+	fset := token.NewFileSet()
+	for _, test := range tests {
+		file, err := parseUntrusted(fset, "p.go", []byte(test.src))
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("parseUntrusted(%.30q) error = %v, want one containing %q", test.src, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseUntrusted(%.30q): %v", test.src, err)
+			continue
+		}
+		if got := fset.Position(file.Package).Filename; got != "p.go" {
+			t.Errorf("parsed file is at %q in the FileSet, want p.go", got)
+		}
+	}
+}
+
+func FuzzCompileName(f *testing.F) {
+	for _, pattern := range []string{"Read", "Read.*", "a|b", "(", "a)|(b", `\Q)\E`, "(?i)x", "[[:alpha:]]+", "x{1000}"} {
+		f.Add(pattern)
+	}
+	f.Fuzz(func(t *testing.T, pattern string) {
+		re, err := compileName(pattern)
+		if err != nil {
+			return
+		}
+		if len(pattern) > maxRegexpLen {
+			t.Fatalf("compileName accepted a pattern of %d bytes", len(pattern))
+		}
+		// The anchors must hold the whole pattern between them.
+		parsed, err := syntax.Parse(re.String(), syntax.Perl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.Op != syntax.OpConcat || parsed.Sub[0].Op != syntax.OpBeginText || parsed.Sub[len(parsed.Sub)-1].Op != syntax.OpEndText {
+			t.Fatalf("compileName(%q) = %s, not anchored at both ends", pattern, re)
+		}
+	})
+}
+
+func FuzzNestingDepth(f *testing.F) {
+	for _, src := range []string{"package p", "package p\nvar x = [][]int{{1}}", "(((", ")))", "\"(\" // (", "`(`"} {
+		f.Add([]byte(src))
+	}
+	f.Fuzz(func(t *testing.T, src []byte) {
+		depth := nestingDepth(src)
+		opens := bytes.Count(src, []byte("(")) + bytes.Count(src, []byte("[")) + bytes.Count(src, []byte("{"))
+		if depth < 0 || depth > opens {
+			t.Fatalf("nestingDepth = %d with %d opening brackets in the source", depth, opens)
+		}
+	})
+}
+
+func FuzzParseUntrusted(f *testing.F) {
+	for _, src := range []string{"package p\n", "package p\nfunc f() { g(((1))) }\n", "package p\nvar x = " + strings.Repeat("(", 2000), "not go"} {
+		f.Add([]byte(src))
+	}
+	f.Fuzz(func(t *testing.T, src []byte) {
+		fset := token.NewFileSet()
+		file, err := parseUntrusted(fset, "fuzz.go", src)
+		if err != nil {
+			return
+		}
+		if nestingDepth(src) > maxNesting {
+			t.Fatalf("parsed source nested deeper than %d", maxNesting)
+		}
+		if fset.File(file.Pos()) == nil {
+			t.Fatalf("parsed file is not in the FileSet")
+		}
+	})
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// lint checks the doc comments of the packages named by the pattern, or of
+// the current module, and reports the problems it finds, one per line.
+func (s *session) lint(pattern string) {
+	var pkgs []*checkUnit
+	for _, dir := range s.treeDirs(pattern) {
+		fset := token.NewFileSet()
+		// Type checking needs the files of a single build configuration.
+		filter := func(info os.FileInfo) bool {
+			ok, _ := build.Default.MatchFile(dir, info.Name())
+			return ok && !strings.HasSuffix(info.Name(), "_test.go")
+		}
+		astPkgs, _ := parseDir(fset, dir, filter, parser.ParseComments) // Ignore the error.
+		for _, pkg := range astPkgs {
+			pkgs = append(pkgs, &checkUnit{fset: fset, pkg: pkg})
+		}
+	}
+	typeCheckAll(pkgs)
+	for _, p := range pkgs {
+		for _, file := range p.pkg.Files {
+			s.lintFile(p.fset, file, p.types)
+		}
+	}
+}
+
+// lintFile reports the doc comments in the file that refer to identifiers,
+// by doc link or by naming them in the first word, that do not exist.
+// They are usually left behind when a symbol is renamed or deleted.
+func (s *session) lintFile(fset *token.FileSet, file *ast.File, pkg *types.Package) {
+	imports := make(map[string]string) // Import path by package name.
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		imports[importName(spec)] = importPath
+	}
+	lookupPackage := func(name string) (string, bool) {
+		importPath, ok := imports[name]
+		return importPath, ok
+	}
+	check := func(cg *ast.CommentGroup, name *ast.Ident) {
+		if cg == nil {
+			return
+		}
+		for _, link := range docLinks(cg.Text(), lookupPackage) {
+			if !resolves(pkg, link) {
+				text := linkText(link)
+				fmt.Fprintf(s.out, "%s: [%s] refers to nothing\n", commentPos(fset, cg, "["+text+"]"), text)
+			}
+		}
+		// By convention, the comment on an exported declaration begins with its name.
+		if name != nil && name.IsExported() {
+			first, _, _ := strings.Cut(strings.TrimSpace(cg.Text()), " ")
+			if first != name.Name && looksLikeIdent(first) && pkg.Scope().Lookup(first) == nil {
+				fmt.Fprintf(s.out, "%s: comment begins with %s, not %s\n", commentPos(fset, cg, first), first, name.Name)
+			}
+		}
+	}
+	check(file.Doc, nil)
+	// The doc comment of a lone spec is attached to the GenDecl.
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			check(decl.Doc, decl.Name)
+		case *ast.GenDecl:
+			if len(decl.Specs) != 1 {
+				check(decl.Doc, nil)
+			}
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					doc := spec.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					check(doc, spec.Name)
+				case *ast.ValueSpec:
+					doc := spec.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					check(doc, nil) // Value comments often describe several names.
+				}
+			}
+		}
+	}
+}
+
+// resolves reports whether the doc link refers to a declaration, or a method
+// or field, that exists. A link to or into a package that cannot be loaded
+// is assumed to be good.
+func resolves(pkg *types.Package, link *comment.DocLink) bool {
+	if link.ImportPath != "" {
+		pkg = importedPackage(pkg, link.ImportPath)
+		if pkg == nil {
+			return true
+		}
+	}
+	if pkg == nil || link.Name == "" { // A link to a package.
+		return true
+	}
+	if link.Recv == "" {
+		return pkg.Scope().Lookup(link.Name) != nil
+	}
+	recv, ok := pkg.Scope().Lookup(strings.TrimPrefix(link.Recv, "*")).(*types.TypeName)
+	if !ok {
+		return false
+	}
+	obj, _, _ := types.LookupFieldOrMethod(recv.Type(), true, pkg, link.Name)
+	return obj != nil
+}
+
+// importedPackage returns the type-checked package with the import path,
+// or nil if it cannot be loaded.
+func importedPackage(from *types.Package, importPath string) *types.Package {
+	if from != nil {
+		for _, imp := range from.Imports() {
+			if imp.Path() == importPath {
+				return imp
+			}
+		}
+	}
+	imp, err := exportData.forDir("").Import(importPath)
+	if err != nil {
+		return nil
+	}
+	return imp
+}
+
+// linkText returns the text of the doc link as written between the brackets.
+func linkText(link *comment.DocLink) string {
+	var b strings.Builder
+	for _, t := range link.Text {
+		if p, ok := t.(comment.Plain); ok {
+			b.WriteString(string(p))
+		}
+	}
+	return b.String()
+}
+
+// commentPos returns the position of the first line of the comment
+// containing the text, or of the comment itself.
+func commentPos(fset *token.FileSet, cg *ast.CommentGroup, text string) token.Position {
+	for _, c := range cg.List {
+		if i := strings.Index(c.Text, text); i >= 0 {
+			pos := fset.Position(c.Pos())
+			pos.Line += strings.Count(c.Text[:i], "\n")
+			pos.Column = 0
+			return pos
+		}
+	}
+	return fset.Position(cg.Pos())
+}
+
+// looksLikeIdent reports whether the word is an exported identifier
+// that could not be an ordinary word or acronym, such as NewReader or MAX_SIZE.
+func looksLikeIdent(word string) bool {
+	if !token.IsIdentifier(word) || !token.IsExported(word) {
+		return false
+	}
+	if strings.Contains(word, "_") {
+		return true
+	}
+	upper, lower := false, false
+	for _, r := range word[1:] {
+		upper = upper || unicode.IsUpper(r) || unicode.IsDigit(r)
+		lower = lower || unicode.IsLower(r)
+	}
+	return upper && lower
+}
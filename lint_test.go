@@ -0,0 +1,62 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	writeModule(t, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.22\n",
+		"m.go": `// Package m refers to [Gone] and [strings.Gone].
+package m
+
+import "strings"
+
+// Config is described by [Config.Name] and [Config.Missing].
+type Config struct{ Name string }
+
+// Make returns a [Config], as [strings.NewReader] returns a reader.
+func Make() Config { return Config{} }
+
+// OldName was the name of New.
+func New() {}
+
+// The return of a value.
+func Value() {}
+
+// Reader wraps [*strings.Reader.Len] and [strings.Reader.Nope].
+type Reader struct{ r *strings.Reader }
+`,
+	})
+	var out bytes.Buffer
+	newSession(&out).lint("")
+	tests := []struct {
+		text     string
+		reported bool
+	}{
+		{"[Gone] refers to nothing", true},
+		{"[strings.Gone] refers to nothing", true},
+		{"[Config.Missing] refers to nothing", true},
+		{"[strings.Reader.Nope] refers to nothing", true},
+		{"comment begins with OldName, not New", true},
+		{"[Config.Name]", false},
+		{"[Config]", false},
+		{"[strings.NewReader]", false},
+		{"[*strings.Reader.Len]", false},
+		{"The", false}, // Not an identifier of the package, but a word.
+	}
+	for _, test := range tests {
+		if got := strings.Contains(out.String(), test.text); got != test.reported {
+			t.Errorf("-lint reports %q: %t, want %t; output:\n%s", test.text, got, test.reported, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), "m.go:1:") {
+		t.Errorf("-lint output\n%s\nlacks the position of the package comment", out.String())
+	}
+}
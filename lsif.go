@@ -0,0 +1,259 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// An lsifSymbol is an exported declaration written to an LSIF index: the
+// package-level names and the methods of exported types.
+type lsifSymbol struct {
+	path string // Import path of the package.
+	name string // Name, or Type.Method.
+	pos  token.Position
+	end  token.Position
+	decl string // As signature returns it.
+	doc  string // The doc comment as Markdown.
+
+	resultSet, definitions, references int // Vertex IDs.
+}
+
+// An lsifRange is a use of a symbol in a document: its definition or a
+// reference to it.
+type lsifRange struct {
+	sym      *lsifSymbol
+	pos, end token.Position
+	def      bool
+}
+
+// An lsifWriter writes the vertices and edges of an LSIF graph, as lines
+// of JSON, numbering them as it goes.
+type lsifWriter struct {
+	enc *json.Encoder
+	id  int
+}
+
+// emit writes the element, a vertex or an edge with the label, and returns its ID.
+func (w *lsifWriter) emit(typ, label string, fields map[string]any) int {
+	w.id++
+	element := map[string]any{"id": w.id, "type": typ, "label": label}
+	for k, v := range fields {
+		element[k] = v
+	}
+	w.enc.Encode(element)
+	return w.id
+}
+
+// lsif writes to standard output, for code intelligence platforms, an index
+// in the Language Server Index Format of the exported declarations of the
+// packages named by the pattern: for each, its definition, a hover holding
+// its declaration and doc comment, a moniker naming it import/path:Name,
+// and the references to it, through qualified identifiers, in the other
+// packages indexed.
+func (s *session) lsif(pattern string) {
+	dirs := s.treeDirs(pattern)
+	symbols := make(map[string]*lsifSymbol)
+	var order []*lsifSymbol
+	ranges := make(map[string][]lsifRange) // By file name.
+	for _, dir := range dirs {
+		fset := token.NewFileSet()
+		notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+		pkgs, _ := parseDir(fset, dir, notTest, parser.ParseComments) // Ignore the error.
+		for _, pkg := range pkgs {
+			if pkg.Name == "main" {
+				continue
+			}
+			for name, astFile := range pkg.Files {
+				file := s.newFile(fset, name, "", astFile)
+				for _, sym := range file.lsifSymbols(importPath(dir)) {
+					symbols[sym.path+"."+sym.name] = sym
+					order = append(order, sym)
+					ranges[name] = append(ranges[name], lsifRange{sym, sym.pos, sym.end, true})
+				}
+			}
+		}
+	}
+	walkReferences(dirs, 0, func(ref reference) {
+		if sym := symbols[ref.path+"."+ref.name]; sym != nil && ref.from != ref.path {
+			end := ref.pos
+			end.Column += len(ref.name)
+			end.Offset += len(ref.name)
+			ranges[ref.pos.Filename] = append(ranges[ref.pos.Filename], lsifRange{sym, ref.pos, end, false})
+		}
+	})
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].path != order[j].path {
+			return order[i].path < order[j].path
+		}
+		return order[i].name < order[j].name
+	})
+
+	w := &lsifWriter{enc: json.NewEncoder(s.out)}
+	root, _ := os.Getwd()
+	if modDir != "" {
+		root = modDir
+	}
+	w.emit("vertex", "metaData", map[string]any{
+		"version":          "0.4.3",
+		"projectRoot":      fileURI(root),
+		"positionEncoding": "utf-16",
+		"toolInfo":         map[string]any{"name": "doc"},
+	})
+	project := w.emit("vertex", "project", map[string]any{"kind": "go"})
+	for _, sym := range order {
+		sym.resultSet = w.emit("vertex", "resultSet", nil)
+		moniker := w.emit("vertex", "moniker", map[string]any{
+			"scheme":     "gomod",
+			"identifier": sym.path + ":" + sym.name,
+			"unique":     "scheme",
+			"kind":       "export",
+		})
+		w.emit("edge", "moniker", map[string]any{"outV": sym.resultSet, "inV": moniker})
+		contents := []any{map[string]any{"language": "go", "value": sym.decl}}
+		if sym.doc != "" {
+			contents = append(contents, sym.doc)
+		}
+		hover := w.emit("vertex", "hoverResult", map[string]any{"result": map[string]any{"contents": contents}})
+		w.emit("edge", "textDocument/hover", map[string]any{"outV": sym.resultSet, "inV": hover})
+		sym.definitions = w.emit("vertex", "definitionResult", nil)
+		w.emit("edge", "textDocument/definition", map[string]any{"outV": sym.resultSet, "inV": sym.definitions})
+		sym.references = w.emit("vertex", "referenceResult", nil)
+		w.emit("edge", "textDocument/references", map[string]any{"outV": sym.resultSet, "inV": sym.references})
+	}
+	var names []string
+	for name := range ranges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var documents []int
+	for _, name := range names {
+		src, _ := readSource(name)
+		document := w.emit("vertex", "document", map[string]any{"uri": fileURI(name), "languageId": "go"})
+		documents = append(documents, document)
+		var ids []int
+		type item struct {
+			outV     int
+			property string
+		}
+		items := make(map[item][]int)
+		for _, r := range ranges[name] {
+			id := w.emit("vertex", "range", map[string]any{"start": lsifPosition(src, r.pos), "end": lsifPosition(src, r.end)})
+			ids = append(ids, id)
+			w.emit("edge", "next", map[string]any{"outV": id, "inV": r.sym.resultSet})
+			if r.def {
+				items[item{r.sym.definitions, ""}] = append(items[item{r.sym.definitions, ""}], id)
+				items[item{r.sym.references, "definitions"}] = append(items[item{r.sym.references, "definitions"}], id)
+			} else {
+				items[item{r.sym.references, "references"}] = append(items[item{r.sym.references, "references"}], id)
+			}
+		}
+		w.emit("edge", "contains", map[string]any{"outV": document, "inVs": ids})
+		var keys []item
+		for k := range items {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].outV != keys[j].outV {
+				return keys[i].outV < keys[j].outV
+			}
+			return keys[i].property < keys[j].property
+		})
+		for _, k := range keys {
+			fields := map[string]any{"outV": k.outV, "inVs": items[k], "document": document}
+			if k.property != "" {
+				fields["property"] = k.property
+			}
+			w.emit("edge", "item", fields)
+		}
+	}
+	if len(documents) > 0 {
+		w.emit("edge", "contains", map[string]any{"outV": project, "inVs": documents})
+	}
+}
+
+// lsifSymbols returns the exported package-level declarations of the file,
+// and the exported methods of its exported types, for the package with
+// the import path.
+func (f *File) lsifSymbols(pkgPath string) []*lsifSymbol {
+	var syms []*lsifSymbol
+	add := func(node ast.Node, id *ast.Ident, doc *ast.CommentGroup, name string) {
+		sym := &lsifSymbol{
+			path: pkgPath,
+			name: name,
+			pos:  f.fset.Position(id.Pos()),
+			end:  f.fset.Position(id.End()),
+			decl: f.signature(node, id),
+		}
+		if doc != nil {
+			printer := &comment.Printer{DocLinkBaseURL: "https://pkg.go.dev"}
+			sym.doc = string(printer.Markdown(new(comment.Parser).Parse(doc.Text())))
+		}
+		syms = append(syms, sym)
+	}
+	for _, decl := range f.file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if !decl.Name.IsExported() {
+				continue
+			}
+			if recv := recvTypeName(decl); recv == "" {
+				add(decl, decl.Name, decl.Doc, decl.Name.Name)
+			} else if token.IsExported(recv) {
+				add(decl, decl.Name, decl.Doc, recv+"."+decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.Name.IsExported() {
+						add(spec, spec.Name, orDoc(spec.Doc, decl), spec.Name.Name)
+					}
+				case *ast.ValueSpec:
+					for _, id := range spec.Names {
+						if id.IsExported() {
+							add(decl, id, orDoc(spec.Doc, decl), id.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return syms
+}
+
+// orDoc returns the doc comment of a spec, or else that of its declaration
+// if the declaration has just the one spec.
+func orDoc(doc *ast.CommentGroup, decl *ast.GenDecl) *ast.CommentGroup {
+	if doc == nil && len(decl.Specs) == 1 {
+		return decl.Doc
+	}
+	return doc
+}
+
+// lsifPosition returns the position in LSIF's terms: the line and
+// character, counted from zero, with characters in UTF-16 code units.
+func lsifPosition(src []byte, pos token.Position) map[string]int {
+	character := pos.Column - 1
+	if start := pos.Offset - character; src != nil && start >= 0 && pos.Offset <= len(src) {
+		character = len(utf16.Encode(bytes.Runes(src[start:pos.Offset])))
+	}
+	return map[string]int{"line": pos.Line - 1, "character": character}
+}
+
+// fileURI returns the file: URI of the absolute file name.
+func fileURI(name string) string {
+	return fmt.Sprintf("file://%s", strings.ReplaceAll(name, string(os.PathSeparator), "/"))
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLSIF(t *testing.T) {
+	dir := writeModule(t, testModule)
+	var out bytes.Buffer
+	newSession(&out).lsif("")
+	type element struct {
+		ID         int
+		Type       string
+		Label      string
+		OutV, InV  int
+		InVs       []int
+		Property   string
+		Identifier string
+		URI        string
+		Result     struct{ Contents []json.RawMessage }
+	}
+	elements := make(map[int]element)
+	var edges []element
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var e element
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+		elements[e.ID] = e
+		if e.Type == "edge" {
+			edges = append(edges, e)
+		}
+	}
+	if first := elements[1]; first.Label != "metaData" {
+		t.Errorf("first element is %q, want metaData", first.Label)
+	}
+	// to returns the elements the edges with the label lead to from id.
+	to := func(id int, label, property string) []element {
+		var list []element
+		for _, e := range edges {
+			if e.Label == label && e.OutV == id && e.Property == property {
+				if e.InV != 0 {
+					list = append(list, elements[e.InV])
+				}
+				for _, in := range e.InVs {
+					list = append(list, elements[in])
+				}
+			}
+		}
+		return list
+	}
+	// documentOf returns the base name of the document containing the range.
+	documentOf := func(r element) string {
+		for _, e := range edges {
+			if e.Label == "contains" && slices.Contains(e.InVs, r.ID) {
+				return filepath.Base(elements[e.OutV].URI)
+			}
+		}
+		return ""
+	}
+	resultSets := make(map[string]int) // By moniker.
+	var monikers []string
+	for _, e := range edges {
+		if e.Label == "moniker" {
+			id := elements[e.InV].Identifier
+			resultSets[id] = e.OutV
+			monikers = append(monikers, id)
+		}
+	}
+	slices.Sort(monikers)
+	want := []string{"example.com/m/v2/internal/auth:Make", "example.com/m/v2/internal/auth:Token", "example.com/m/v2/v:T", "example.com/m/v2:Alias", "example.com/m/v2:Config"}
+	if !reflect.DeepEqual(monikers, want) {
+		t.Errorf("monikers %q, want %q", monikers, want)
+	}
+	tests := []struct {
+		moniker     string
+		hover       string // Part of the hover's declaration.
+		definition  string // The file of the definition.
+		referencing []string
+	}{
+		{"example.com/m/v2:Config", "type Config struct", "m.go", []string{"auth.go"}},
+		{"example.com/m/v2/internal/auth:Make", "func Make() Token", "auth.go", []string{"v.go"}}, // Through a dot import.
+		{"example.com/m/v2/internal/auth:Token", "type Token struct", "auth.go", nil},
+	}
+	for _, test := range tests {
+		set := resultSets[test.moniker]
+		hovers := to(set, "textDocument/hover", "")
+		if len(hovers) != 1 || len(hovers[0].Result.Contents) == 0 || !strings.Contains(string(hovers[0].Result.Contents[0]), test.hover) {
+			t.Errorf("%s: hover %+v, want one containing %q", test.moniker, hovers, test.hover)
+		}
+		defs := to(set, "textDocument/definition", "")
+		if len(defs) != 1 {
+			t.Fatalf("%s: %d definition results, want 1", test.moniker, len(defs))
+		}
+		if ranges := to(defs[0].ID, "item", ""); len(ranges) != 1 || documentOf(ranges[0]) != test.definition {
+			t.Errorf("%s: definition ranges %+v, want one in %s", test.moniker, ranges, test.definition)
+		}
+		refs := to(set, "textDocument/references", "")
+		if len(refs) != 1 {
+			t.Fatalf("%s: %d reference results, want 1", test.moniker, len(refs))
+		}
+		var referencing []string
+		for _, r := range to(refs[0].ID, "item", "references") {
+			referencing = append(referencing, documentOf(r))
+		}
+		if !reflect.DeepEqual(referencing, test.referencing) {
+			t.Errorf("%s: referenced in %q, want %q", test.moniker, referencing, test.referencing)
+		}
+	}
+	if !strings.Contains(out.String(), fileURI(filepath.Join(dir, "m.go"))) {
+		t.Errorf("no document for m.go in\n%s", out.String())
+	}
+}
+
+func TestLSIFPosition(t *testing.T) {
+	src := []byte("var x = \"é𝄞\" + y\n")
+	tests := []struct {
+		offset        int
+		wantCharacter int
+	}{
+		{0, 0},
+		{4, 4},
+		{strings.Index(string(src), "+"), 14}, // é is one UTF-16 unit, 𝄞 two.
+	}
+	for _, test := range tests {
+		pos := token.Position{Line: 1, Column: test.offset + 1, Offset: test.offset}
+		got := lsifPosition(src, pos)
+		if got["line"] != 0 || got["character"] != test.wantCharacter {
+			t.Errorf("lsifPosition at offset %d = %v, want line 0, character %d", test.offset, got, test.wantCharacter)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// manifest writes the documentation of each direct dependency in the go.mod
+// file, at the version the build selects or, if the go command cannot say,
+// the version it requires, to a file in outDir named by the import path
+// with .txt appended. With -all, every package of each dependency is
+// written, not just the one at the root of the module. Dependencies missing
+// from the module cache are fetched from the module proxy, so the result is
+// a bundle of documentation that can be read without a network.
+func (s *session) manifest(goMod, outDir string) {
+	reqs, err := requirementsIn(goMod)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -manifest: %s\n", err)
+		exit(1)
+	}
+	// There is no server to link to, and the source may be a temporary copy.
+	s.showURL, s.showSrc = false, false
+	selected := selectedVersions(filepath.Dir(goMod))
+	if selected == nil {
+		fmt.Fprintf(os.Stderr, "doc: -manifest: cannot list the build's modules; using the versions go.mod requires\n")
+	}
+	for _, req := range reqs {
+		if req.indirect {
+			continue
+		}
+		if v := selected[req.path]; v != "" {
+			req.version = v
+		}
+		stamp := req.path + "@" + req.version
+		if !*allFlag && !*forceFlag && upToDate(docFileName(outDir, req.path), stamp) {
+			continue
+		}
+		dir, temporary := moduleSource(req, filepath.Join(filepath.Dir(goMod), "go.sum"))
+		dirs := []string{dir}
+		if *allFlag {
+			dirs = s.dirsFor(dir, "")
+		}
+		for _, d := range dirs {
+			rel, _ := filepath.Rel(dir, d)
+			name := docFileName(outDir, path.Join(req.path, filepath.ToSlash(rel)))
+			if err := s.writePackageDoc(name, d, stamp); err != nil {
+				fmt.Fprintf(os.Stderr, "doc: -manifest: %s\n", err)
+				exit(1)
+			}
+		}
+		if temporary {
+			os.RemoveAll(dir)
+		}
+	}
+}
+
+// moduleSource returns the directory holding the source of the required
+// module version, from the module cache or, failing that, downloaded from
+// a module proxy, verified against the go.sum file or the checksum
+// database, and unpacked in a temporary directory, in which case temporary
+// is true.
+func moduleSource(req requirement, goSum string) (dir string, temporary bool) {
+	dir, err := moduleDir(moduleCacheDir(), req.path, req.version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -manifest: %s\n", err)
+		exit(1)
+	}
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir, false
+	}
+	data := fetchModule(req.path, "@v/"+req.version+".zip")
+	if err := verifyZip(req.path, req.version, data, goSum); err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -manifest: %s\n", err)
+		exit(1)
+	}
+	tmp, err := os.MkdirTemp("", "doc")
+	if err == nil {
+		err = unpackModule(req, data, tmp)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -manifest: %s\n", err)
+		exit(1)
+	}
+	return tmp, true
+}
+
+// unpackModule writes the Go files, other than tests, of the module zip file
+// for the required version to dir. Files that exceed the limits on downloaded
+// source are skipped with a notice.
+func unpackModule(req requirement, data []byte, dir string) error {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("%s@%s: %s", req.path, req.version, err)
+	}
+	prefix := req.path + "@" + req.version + "/"
+	for _, zf := range r.File {
+		name := strings.TrimPrefix(zf.Name, prefix)
+		if name == zf.Name || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if !strings.HasPrefix(target, dir+slash) { // A malicious zip.
+			continue
+		}
+		src, err := readZipFile(zf)
+		if err == nil {
+			_, err = parseUntrusted(token.NewFileSet(), name, src)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: skipping %s\n", err)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, src, 0666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// docFileName returns the name of the file in outDir to which -manifest
+// writes the documentation of the package with the import path.
+func docFileName(outDir, pkgPath string) string {
+	return filepath.Join(outDir, filepath.FromSlash(pkgPath)+".txt")
+}
+
+// writePackageDoc writes the package comment and the documentation of every
+// exported declaration of the package in the directory to the named file,
+// and the stamp, naming the module version documented, to the file's stamp
+// file. If the directory holds no Go package, no file is written. Unless
+// -force is set, a file whose stamp matches is up to date and is kept. The
+// stamp is written last, so an interrupted run leaves no file to be taken
+// as up to date when it is not.
+func (s *session) writePackageDoc(name, dir, stamp string) error {
+	if !*forceFlag && upToDate(name, stamp) {
+		return nil
+	}
+	var b bytes.Buffer
+	saved := s.out
+	s.out = &b
+	defer func() { s.out = saved }()
+	s.pkgDoc = true
+	s.lookInDirectory(dir, "", "")
+	s.pkgDoc = false
+	s.lookInDirectory(dir, "", ".*")
+	if b.Len() == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+		return err
+	}
+	os.Remove(name + ".stamp") // The file is about to change.
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, b.Bytes(), 0666); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		return err
+	}
+	return os.WriteFile(name+".stamp", []byte(stamp+"\n"), 0666)
+}
+
+// upToDate reports whether the named file exists and its stamp file names
+// the module version, as module@version, that it is to document.
+func upToDate(name, stamp string) bool {
+	if _, err := os.Stat(name); err != nil {
+		return false
+	}
+	data, err := os.ReadFile(name + ".stamp")
+	return err == nil && strings.TrimSpace(string(data)) == stamp
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	// The modules are dependencies of doc's own module, and so in the module cache.
+	tests := []struct {
+		name    string
+		goMod   string
+		all     bool
+		want    map[string]string // File in the output directory, and text it must hold.
+		notWant []string
+	}{
+		{
+			name:  "root packages",
+			goMod: "module example.com/m\n\ngo 1.26.0\n\nrequire (\n\tgolang.org/x/text v0.40.0\n\tgolang.org/x/sync v0.23.0\n\tgolang.org/x/mod v0.41.0 // indirect\n)\n",
+			want: map[string]string{
+				"golang.org/x/text.txt":       "package text",
+				"golang.org/x/text.txt.stamp": "golang.org/x/text@v0.40.0\n",
+			},
+			notWant: []string{
+				"golang.org/x/sync.txt",          // No package at its root.
+				"golang.org/x/sync/errgroup.txt", // Not without -all.
+				"golang.org/x/mod/semver.txt",    // An indirect requirement.
+			},
+		},
+		{
+			name:  "all",
+			goMod: "module example.com/m\n\ngo 1.26.0\n\nrequire golang.org/x/sync v0.23.0\n",
+			all:   true,
+			want: map[string]string{
+				"golang.org/x/sync/errgroup.txt":       "func WithContext(ctx context.Context) (*Group, context.Context)",
+				"golang.org/x/sync/errgroup.txt.stamp": "golang.org/x/sync@v0.23.0\n",
+				"golang.org/x/sync/semaphore.txt":      "func NewWeighted(n int64) *Weighted",
+			},
+			notWant: []string{"golang.org/x/sync.txt"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defaultFlags(t)
+			setFlag(t, allFlag, test.all)
+			setFlag(t, offlineFlag, true)
+			dir := writeModule(t, map[string]string{
+				"go.mod": test.goMod,
+				"go.sum": sumLines(t, "golang.org/x/text", "golang.org/x/sync", "golang.org/x/mod"),
+			})
+			outDir := t.TempDir()
+			newSession(io.Discard).manifest(filepath.Join(dir, "go.mod"), outDir)
+			for name, want := range test.want {
+				data, err := os.ReadFile(filepath.Join(outDir, filepath.FromSlash(name)))
+				if err != nil {
+					t.Errorf("%s: %v", name, err)
+					continue
+				}
+				if !strings.Contains(string(data), want) {
+					t.Errorf("%s holds\n%s\nwant it to contain %q", name, data, want)
+				}
+				if strings.Contains(string(data), "https://pkg.go.dev/") {
+					t.Errorf("%s holds a link to pkg.go.dev:\n%s", name, data)
+				}
+			}
+			for _, name := range test.notWant {
+				if _, err := os.Stat(filepath.Join(outDir, filepath.FromSlash(name))); err == nil {
+					t.Errorf("%s written", name)
+				}
+			}
+		})
+	}
+}
+
+func TestWritePackageDocStamp(t *testing.T) {
+	dir := writeModule(t, testModule)
+	defaultFlags(t)
+	name := docFileName(t.TempDir(), "example.com/m/v2")
+	s := newSession(io.Discard)
+	const kept = "kept\n" // Written over the documentation to see whether it is rewritten.
+	tests := []struct {
+		stamp   string
+		force   bool
+		rewrite bool
+	}{
+		{"example.com/m/v2@v2.0.0", false, true}, // No file yet.
+		{"example.com/m/v2@v2.0.0", false, false},
+		{"example.com/m/v2@v2.1.0", false, true}, // Another version, however old its files.
+		{"example.com/m/v2@v2.0.0", false, true}, // Back to the first version.
+		{"example.com/m/v2@v2.0.0", true, true},
+	}
+	for i, test := range tests {
+		setFlag(t, forceFlag, test.force)
+		if i > 0 {
+			if err := os.WriteFile(name, []byte(kept), 0666); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.writePackageDoc(name, dir, test.stamp); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rewritten := string(data) != kept; rewritten != test.rewrite {
+			t.Errorf("%d: writePackageDoc for %s, -force=%t: rewritten %t, want %t", i, test.stamp, test.force, rewritten, test.rewrite)
+		}
+		if test.rewrite && !strings.Contains(string(data), "type Config struct") {
+			t.Errorf("%d: writePackageDoc wrote\n%s\nwant Config's documentation", i, data)
+		}
+		if stamp, _ := os.ReadFile(name + ".stamp"); strings.TrimSpace(string(stamp)) != test.stamp {
+			t.Errorf("%d: stamp %q, want %q", i, stamp, test.stamp)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(name), "v2.txt.tmp")); err == nil {
+		t.Errorf("writePackageDoc left its temporary file")
+	}
+}
+
+func TestSelectedVersions(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.26.0\n\nrequire golang.org/x/text v0.40.0\n",
+		"go.sum": sumLines(t, "golang.org/x/text"),
+	})
+	got := selectedVersions(dir)
+	if got["golang.org/x/text"] != "v0.40.0" {
+		t.Errorf("selectedVersions = %v, want golang.org/x/text at v0.40.0", got)
+	}
+	if _, ok := got["example.com/m"]; ok {
+		t.Errorf("selectedVersions = %v, want no version for the main module", got)
+	}
+
+	// A module not in the cache cannot be listed without the network.
+	dir = writeModule(t, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.26.0\n\nrequire example.com/nowhere v1.0.0\n",
+	})
+	if got := selectedVersions(dir); got != nil {
+		t.Errorf("selectedVersions with an uncached requirement = %v, want nil", got)
+	}
+}
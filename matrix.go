@@ -0,0 +1,201 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// A typeVariant is one definition of a type that differs by platform,
+// and the platforms, GOOS/GOARCH, whose files define it so.
+type typeVariant struct {
+	fields    [][2]string // Name and type of each field, or of the type itself if it is not a struct.
+	platforms []string
+}
+
+// matrix prints, for the type named pkg.Name, a table of its fields on
+// every platform go tool dist lists: which platforms define the type
+// alike, each group a variant labeled A, B and so on, and for each field
+// its type in each variant, or - where the variant lacks it. Each
+// platform's files are chosen by their names and build constraints, as
+// the go command chooses them, without cgo.
+func (s *session) matrix(arg string) {
+	if !strings.Contains(arg, ".") {
+		fmt.Fprintf(os.Stderr, "doc: -matrix: want pkg.Type, not %s\n", arg)
+		exit(2)
+	}
+	pkg, name := split(arg)
+	platforms := distList()
+	found := false
+	for _, dir := range s.packageDirs(pkg) {
+		variants := typeVariants(dir, name, platforms)
+		if len(variants) == 0 {
+			continue
+		}
+		if found {
+			fmt.Fprintln(s.out)
+		}
+		found = true
+		printMatrix(s.out, importPath(dir)+"."+name, variants)
+	}
+	if !found {
+		fmt.Fprintf(os.Stderr, "doc: -matrix: no type %s\n", arg)
+		exit(1)
+	}
+}
+
+// distList returns the platforms the go command supports, as GOOS/GOARCH.
+func distList() []string {
+	out, err := exec.Command("go", "tool", "dist", "list").Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -matrix: go tool dist list: %v\n", err)
+		exit(1)
+	}
+	return strings.Fields(string(out))
+}
+
+// typeVariants returns the definitions of the named type in the package in
+// the directory, each with the platforms on which it is so defined, in the
+// order of the platforms.
+func typeVariants(dir, name string, platforms []string) []*typeVariant {
+	fset := token.NewFileSet()
+	files := make(map[string]*ast.File)
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if !strings.HasSuffix(fileName, ".go") || strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, fileName), nil, parser.SkipObjectResolution)
+		if err == nil {
+			files[fileName] = file
+		}
+	}
+	var variants []*typeVariant
+	byKey := make(map[string]*typeVariant)
+	for _, platform := range platforms {
+		goos, goarch, _ := strings.Cut(platform, "/")
+		ctxt := build.Default
+		ctxt.GOOS, ctxt.GOARCH, ctxt.CgoEnabled = goos, goarch, false
+		for fileName, file := range files {
+			if ok, err := ctxt.MatchFile(dir, fileName); err != nil || !ok {
+				continue
+			}
+			spec := typeSpecIn(file, name)
+			if spec == nil {
+				continue
+			}
+			fields := typeFields(spec)
+			key := fmt.Sprint(fields)
+			v := byKey[key]
+			if v == nil {
+				v = &typeVariant{fields: fields}
+				byKey[key] = v
+				variants = append(variants, v)
+			}
+			v.platforms = append(v.platforms, platform)
+			break
+		}
+	}
+	return variants
+}
+
+// typeSpecIn returns the declaration of the named type in the file, or nil.
+func typeSpecIn(file *ast.File, name string) *ast.TypeSpec {
+	for _, decl := range file.Decls {
+		if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.TYPE {
+			for _, spec := range decl.Specs {
+				if spec := spec.(*ast.TypeSpec); spec.Name.Name == name {
+					return spec
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// typeFields returns the name and type of each field of the struct type,
+// an embedded one named by its type, or for another type just the type.
+func typeFields(spec *ast.TypeSpec) [][2]string {
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return [][2]string{{"(type)", types.ExprString(spec.Type)}}
+	}
+	var fields [][2]string
+	for _, field := range st.Fields.List {
+		typ := types.ExprString(field.Type)
+		if len(field.Names) == 0 {
+			fields = append(fields, [2]string{typ, typ})
+		}
+		for _, id := range field.Names {
+			fields = append(fields, [2]string{id.Name, typ})
+		}
+	}
+	return fields
+}
+
+// printMatrix prints the variants of the type: the platforms of each, and
+// then a row for each field, in the order they first appear, giving its
+// type in each variant.
+func printMatrix(w io.Writer, name string, variants []*typeVariant) {
+	count := 0
+	for _, v := range variants {
+		count += len(v.platforms)
+	}
+	fmt.Fprintf(w, "%s on %d platforms, in %d variants:\n", name, count, len(variants))
+	for i, v := range variants {
+		sort.Strings(v.platforms)
+		fmt.Fprintf(w, "%c\t%s\n", 'A'+i, strings.Join(v.platforms, " "))
+	}
+	fmt.Fprintln(w)
+	var order []string
+	typeOf := make([]map[string]string, len(variants))
+	for i, v := range variants {
+		typeOf[i] = make(map[string]string)
+		for _, field := range v.fields {
+			if _, ok := typeOf[i][field[0]]; ok {
+				continue // A blank field such as _ [0]int, seen before.
+			}
+			typeOf[i][field[0]] = field[1]
+			seen := false
+			for _, name := range order {
+				seen = seen || name == field[0]
+			}
+			if !seen {
+				order = append(order, field[0])
+			}
+		}
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprint(tw, "Field")
+	for i := range variants {
+		fmt.Fprintf(tw, "\t%c", 'A'+i)
+	}
+	fmt.Fprintln(tw)
+	for _, field := range order {
+		fmt.Fprint(tw, field)
+		for i := range variants {
+			typ, ok := typeOf[i][field]
+			if !ok {
+				typ = "-"
+			}
+			fmt.Fprintf(tw, "\t%s", typ)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
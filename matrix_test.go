@@ -0,0 +1,121 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// matrixModule defines Stat one way on Linux, another on Windows, and on
+// other systems a third way that depends on the build constraint of a file.
+var matrixModule = map[string]string{
+	"go.mod": "module example.com/sys\n\ngo 1.22\n",
+	"stat_linux.go": `package sys
+
+type Stat struct {
+	Dev  uint64
+	Ino  uint64
+	Mode uint32
+}
+`,
+	"stat_windows.go": `package sys
+
+type Stat struct {
+	Attrs uint32
+	Mode  uint32
+}
+`,
+	"stat_other.go": `//go:build !linux && !windows
+
+package sys
+
+type Stat struct {
+	Dev  int32
+	Mode uint16
+	_    [4]byte
+	_    [4]byte
+}
+`,
+	"handle_windows.go": "package sys\n\ntype Handle uintptr\n",
+	"handle_other.go":   "//go:build !windows\n\npackage sys\n\ntype Handle int\n",
+	"stat_test.go":      "package sys\n\ntype Stat struct{ Test bool }\n",
+}
+
+func TestMatrix(t *testing.T) {
+	dir := writeModule(t, matrixModule)
+	platforms := []string{"darwin/arm64", "linux/386", "linux/amd64", "windows/amd64", "plan9/386"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{
+			name: "Stat",
+			want: `sys.Stat on 5 platforms, in 3 variants:
+A	darwin/arm64 plan9/386
+B	linux/386 linux/amd64
+C	windows/amd64
+
+Field  A        B       C
+Dev    int32    uint64  -
+Mode   uint16   uint32  uint32
+_      [4]byte  -       -
+Ino    -        uint64  -
+Attrs  -        -       uint32
+`,
+		},
+		{
+			name: "Handle",
+			want: `sys.Handle on 5 platforms, in 2 variants:
+A	darwin/arm64 linux/386 linux/amd64 plan9/386
+B	windows/amd64
+
+Field   A    B
+(type)  int  uintptr
+`,
+		},
+	}
+	for _, test := range tests {
+		variants := typeVariants(dir, test.name, platforms)
+		var out bytes.Buffer
+		printMatrix(&out, "sys."+test.name, variants)
+		if out.String() != test.want {
+			t.Errorf("matrix of %s:\n%s\nwant\n%s", test.name, out.String(), test.want)
+		}
+	}
+	if variants := typeVariants(dir, "Missing", platforms); len(variants) != 0 {
+		t.Errorf("typeVariants found %d variants of a missing type", len(variants))
+	}
+}
+
+func TestMatrixSession(t *testing.T) {
+	files := map[string]string{"go.mod": "module example.com/m\n\ngo 1.22\n"}
+	for name, text := range matrixModule {
+		if name != "go.mod" {
+			files["sys/"+name] = text
+		}
+	}
+	writeModule(t, files)
+	tests := []struct {
+		arg    string
+		want   string
+		status int
+	}{
+		{arg: "sys.Stat", want: "example.com/m/sys.Stat on "},
+		{arg: "sys.Missing", status: 1},
+		{arg: "Stat", status: 2},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).matrix(test.arg) })
+		if status != test.status {
+			t.Errorf("-matrix %s exited with %d, want %d", test.arg, status, test.status)
+		}
+		if !strings.HasPrefix(out.String(), test.want) {
+			t.Errorf("-matrix %s printed\n%s\nwant it to begin %q", test.arg, out.String(), test.want)
+		}
+	}
+}
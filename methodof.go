@@ -0,0 +1,186 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+)
+
+// methodOf prints the declaration of the method that the concrete type,
+// given as pkg.Type, uses to satisfy the interface method, given as
+// pkg.Interface.Method or just Method.
+func (s *session) methodOf(typeArg, methodArg string) {
+	if !strings.Contains(typeArg, ".") {
+		fmt.Fprintf(os.Stderr, "doc: -method-of: type must be pkg.Type\n")
+		exit(2)
+	}
+	pkg, typeName := split(typeArg)
+	method := methodArg[strings.LastIndex(methodArg, ".")+1:]
+	found := false
+	for _, dir := range s.paths(pkg) {
+		if s.methodIn(dir, typeName, method, methodArg) {
+			found = true
+		}
+	}
+	if !found {
+		fmt.Fprintf(os.Stderr, "doc: -method-of: no type %s\n", typeArg)
+		exit(1)
+	}
+}
+
+// methodIn looks in the package in the directory for the named type and prints
+// the declaration of its method, reporting whether the type was found.
+func (s *session) methodIn(dir, typeName, method, methodArg string) bool {
+	fset := token.NewFileSet()
+	pkgs, _ := parseDir(fset, dir, nil, parser.ParseComments) // Ignore the error.
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.Name, "_test") {
+			continue
+		}
+		typesPkg, info := typeCheck(fset, pkg)
+		if typesPkg == nil {
+			continue
+		}
+		tn := lookupType(typesPkg.Scope(), typeName)
+		if tn == nil {
+			continue
+		}
+		name := pkg.Name + "." + tn.Name()
+		obj, index, _ := types.LookupFieldOrMethod(types.NewPointer(tn.Type()), true, typesPkg, method)
+		fn, ok := obj.(*types.Func)
+		if !ok {
+			fmt.Fprintf(s.out, "%s has no method %s, so it does not satisfy %s\n", name, method, methodArg)
+			return true
+		}
+		how := ""
+		if len(index) > 1 {
+			how = fmt.Sprintf(", promoted through embedded field %s", strings.Join(embeddingPath(tn.Type(), index), "."))
+		}
+		if s.methodSets.MethodSet(tn.Type()).Lookup(fn.Pkg(), fn.Name()) == nil {
+			how += "; the receiver is a pointer, so only *" + tn.Name() + " satisfies it"
+		}
+		fmt.Fprintf(s.out, "%s satisfies %s with %s.%s.%s%s\n\n", name, methodArg, fn.Pkg().Name(), receiverName(fn), fn.Name(), how)
+		var files []*File
+		for name, astFile := range pkg.Files {
+			file := s.newFile(fset, name, method, astFile)
+			file.doPrint = true
+			file.types = typesPkg
+			file.objs = info.Defs
+			files = append(files, file)
+		}
+		file, decl := findFunc(s, fn, files)
+		if decl == nil {
+			fmt.Fprintf(s.out, "%s\n\n", fset.Position(fn.Pos()))
+			return true
+		}
+		body := decl.Body
+		decl.Body = nil // Do not print the function body.
+		url := file.methodURL(decl)
+		if len(index) > 1 {
+			url = promotedURL(files, tn, fn.Name())
+		}
+		file.printNode(decl, decl.Name, url)
+		decl.Body = body
+		return true
+	}
+	return false
+}
+
+// lookupType returns the exported type with the name, ignoring case, in the scope.
+func lookupType(scope *types.Scope, name string) *types.TypeName {
+	for _, n := range scope.Names() {
+		if tn, ok := scope.Lookup(n).(*types.TypeName); ok && ast.IsExported(n) && equalFold(n, name) {
+			return tn
+		}
+	}
+	return nil
+}
+
+// embeddingPath returns the names of the embedded fields that lead, following
+// the index sequence from types.LookupFieldOrMethod, to a promoted method.
+func embeddingPath(typ types.Type, index []int) []string {
+	var names []string
+	for _, i := range index[:len(index)-1] {
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		st, ok := typ.Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+		names = append(names, st.Field(i).Name())
+		typ = st.Field(i).Type()
+	}
+	return names
+}
+
+// pointerOnly prints the methods in the method set of *T but not of T,
+// where T is the type named by obj, and the interfaces, from the universe,
+// T's package and the packages it imports, that only *T implements.
+func (f *File) pointerOnly(obj types.Object) {
+	typ := obj.Type()
+	if types.IsInterface(typ) {
+		return
+	}
+	ptr := types.NewPointer(typ)
+	valueSet := f.s.methodSets.MethodSet(typ)
+	ptrSet := f.s.methodSets.MethodSet(ptr)
+	var methods []string
+	for i := 0; i < ptrSet.Len(); i++ {
+		m := ptrSet.At(i).Obj()
+		if ast.IsExported(m.Name()) && valueSet.Lookup(m.Pkg(), m.Name()) == nil {
+			methods = append(methods, m.Name())
+		}
+	}
+	if len(methods) == 0 {
+		return
+	}
+	w := f.output(typeKind)
+	fmt.Fprintf(w, "Methods of *%s not in the method set of %s:\n", obj.Name(), obj.Name())
+	for _, m := range methods {
+		fmt.Fprintf(w, "\t%s\n", m)
+	}
+	var ifaces []string
+	candidates := []types.Object{types.Universe.Lookup("error")}
+	if obj.Pkg() != nil {
+		for _, pkg := range append([]*types.Package{obj.Pkg()}, obj.Pkg().Imports()...) {
+			scope := pkg.Scope()
+			for _, name := range scope.Names() {
+				if ast.IsExported(name) {
+					candidates = append(candidates, scope.Lookup(name))
+				}
+			}
+		}
+	}
+	for _, c := range candidates {
+		if _, ok := c.(*types.TypeName); !ok {
+			continue
+		}
+		iface, ok := c.Type().Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 {
+			continue
+		}
+		if types.Implements(ptr, iface) && !types.Implements(typ, iface) {
+			name := c.Name()
+			if c.Pkg() != nil && c.Pkg() != obj.Pkg() {
+				name = c.Pkg().Name() + "." + name
+			}
+			ifaces = append(ifaces, name)
+		}
+	}
+	if len(ifaces) > 0 {
+		fmt.Fprintf(w, "Interfaces satisfied only by *%s:\n", obj.Name())
+		for _, name := range ifaces {
+			fmt.Fprintf(w, "\t%s\n", name)
+		}
+	}
+	fmt.Fprintln(w)
+}
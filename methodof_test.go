@@ -0,0 +1,140 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ioModule is a module whose package fs has types that implement
+// io.Writer and io.Closer directly, through an embedded field, through
+// a pointer receiver, or not at all.
+var ioModule = map[string]string{
+	"go.mod": "module example.com/fs\n\ngo 1.22\n",
+	"fs/fs.go": `package fs
+
+// File is a file.
+type File struct{}
+
+// Write writes p to the file.
+func (f *File) Write(p []byte) (int, error) { return len(p), nil }
+
+// Close closes the file.
+func (f File) Close() error { return nil }
+
+// Logger logs to a file.
+type Logger struct {
+	*File
+	Prefix string
+}
+
+// Buffered buffers a logger.
+type Buffered struct {
+	Logger
+}
+
+// Name is just a name.
+type Name string
+`,
+}
+
+func TestMethodOf(t *testing.T) {
+	dir := writeModule(t, ioModule)
+	tests := []struct {
+		typ, method string
+		want        string // The first line printed.
+		decl        string // The declaration printed after it.
+		status      int
+	}{
+		{
+			typ: "fs.File", method: "io.Writer.Write",
+			want: "fs.File satisfies io.Writer.Write with fs.File.Write; the receiver is a pointer, so only *File satisfies it",
+			decl: "// Write writes p to the file.\nfunc (f *File) Write(p []byte) (int, error)\n",
+		},
+		{
+			typ: "fs.file", method: "Close", // Case is ignored.
+			want: "fs.File satisfies Close with fs.File.Close",
+			decl: "// Close closes the file.\nfunc (f File) Close() error\n",
+		},
+		{
+			typ: "fs.Logger", method: "io.Writer.Write",
+			want: "fs.Logger satisfies io.Writer.Write with fs.File.Write, promoted through embedded field File",
+			decl: "func (f *File) Write(p []byte) (int, error)\n",
+		},
+		{
+			typ: "fs.Buffered", method: "io.Closer.Close",
+			want: "fs.Buffered satisfies io.Closer.Close with fs.File.Close, promoted through embedded field Logger.File",
+			decl: "func (f File) Close() error\n",
+		},
+		{
+			typ: "fs.Name", method: "io.Writer.Write",
+			want: "fs.Name has no method Write, so it does not satisfy io.Writer.Write",
+		},
+		{typ: "fs.Missing", method: "Write", status: 1},
+		{typ: "File", method: "Write", status: 2},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, "-method-of", test.typ, test.method)
+		if status != test.status {
+			t.Errorf("-method-of %s %s exited with %d, want %d; stderr:\n%s", test.typ, test.method, status, test.status, stderr)
+			continue
+		}
+		if status != 0 {
+			continue
+		}
+		first, rest, _ := strings.Cut(out, "\n")
+		if first != test.want || !strings.Contains(rest, test.decl) {
+			t.Errorf("-method-of %s %s printed\n%s\nwant %q, then %q", test.typ, test.method, out, test.want, test.decl)
+		}
+	}
+}
+
+func TestPointerOnly(t *testing.T) {
+	files := map[string]string{"go.mod": ioModule["go.mod"], "fs/fs.go": ioModule["fs/fs.go"] + `
+// Stringer has a String method.
+type Stringer interface{ String() string }
+
+// Path is a path.
+type Path struct{}
+
+func (p *Path) String() string { return "" }
+
+func (p *Path) Error() string { return "" }
+
+func (p Path) Base() string { return "" }
+
+// Writer writes.
+type Writer interface{ Write([]byte) (int, error) }
+`}
+	dir := writeModule(t, files)
+	tests := []struct {
+		typ  string
+		want string // The -ptr section printed, or empty if none.
+	}{
+		{"File", "Methods of *File not in the method set of File:\n\tWrite\nInterfaces satisfied only by *File:\n\tWriter\n\n"},
+		{"Path", "Methods of *Path not in the method set of Path:\n\tError\n\tString\nInterfaces satisfied only by *Path:\n\terror\n\tStringer\n\n"},
+		{"Logger", ""}, // Its embedded *File gives Logger itself Write.
+		{"Writer", ""}, // An interface.
+		{"Name", ""},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, "-ptr", "fs", test.typ)
+		if status != 0 {
+			t.Errorf("doc -ptr fs %s exited with %d; stderr:\n%s", test.typ, status, stderr)
+			continue
+		}
+		_, section, found := strings.Cut(out, "Methods of *")
+		if test.want == "" {
+			if found {
+				t.Errorf("doc -ptr fs %s printed\n%s\nwant no pointer-only methods", test.typ, out)
+			}
+			continue
+		}
+		if !strings.HasPrefix("Methods of *"+section, test.want) {
+			t.Errorf("doc -ptr fs %s printed\n%s\nwant\n%s", test.typ, out, test.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// migrate rewrites, in the configuration file and the query history, the
+// links to godoc.org and golang.org/pkg, which pkg.go.dev has replaced, to
+// their pages on pkg.go.dev, as modernURL does for what doc prints, and
+// reports each change. A url.host on a retired site becomes pkg.go.dev
+// and the url.scheme pkgsite. The caches hold no such links.
+func (s *session) migrate() {
+	changed := false
+	if name := configPath(); name != "" {
+		changed = migrateFile(name, s.migrateConfig) || changed
+	}
+	if name := historyPath(); name != "" {
+		changed = migrateFile(name, s.migrateHistory) || changed
+	}
+	if !changed {
+		fmt.Fprintln(s.out, "no links to migrate")
+	}
+}
+
+// migrateFile rewrites the named file, if it exists, with the function,
+// which reports each change it makes to the lines. It reports whether any
+// line changed.
+func migrateFile(name string, rewrite func(name string, lines []string) bool) bool {
+	data, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return false
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -migrate: %s\n", err)
+		exit(1)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if !rewrite(name, lines) {
+		return false
+	}
+	info, err := os.Stat(name)
+	if err == nil {
+		err = os.WriteFile(name, []byte(strings.Join(lines, "\n")+"\n"), info.Mode().Perm())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -migrate: %s\n", err)
+		exit(1)
+	}
+	return true
+}
+
+// migrateConfig rewrites the links in the lines of the configuration file,
+// leaving comments and the lines without such links as they are.
+func (s *session) migrateConfig(name string, lines []string) bool {
+	retiredHost := modernURL(configValue("url.host")) != configValue("url.host")
+	changed := false
+	for i, line := range lines {
+		text, comment := line, ""
+		if j := strings.IndexByte(line, '#'); j >= 0 {
+			text, comment = line[:j], line[j:]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		edited := false
+		for j := 1; j < len(fields); j++ {
+			if modern := modernURL(fields[j]); modern != fields[j] {
+				fields[j], edited = modern, true
+			}
+		}
+		if fields[0] == "url.scheme" && retiredHost && len(fields) == 2 && fields[1] != "pkgsite" {
+			fields[1], edited = "pkgsite", true
+		}
+		if !edited {
+			continue
+		}
+		lines[i] = strings.Join(fields, " ")
+		if comment != "" {
+			lines[i] += " " + comment
+		}
+		fmt.Fprintf(s.out, "%s:%d: %s\n\t-> %s\n", name, i+1, strings.TrimSpace(line), lines[i])
+		changed = true
+	}
+	return changed
+}
+
+// migrateHistory rewrites the links in the arguments of the queries in the
+// lines of the history file.
+func (s *session) migrateHistory(name string, lines []string) bool {
+	changed := false
+	for i, line := range lines {
+		var args []string
+		if json.Unmarshal([]byte(line), &args) != nil {
+			continue
+		}
+		edited := false
+		for j, arg := range args {
+			if modern := modernURL(arg); modern != arg {
+				args[j], edited = modern, true
+			}
+		}
+		if !edited {
+			continue
+		}
+		data, _ := json.Marshal(args)
+		lines[i] = string(data)
+		fmt.Fprintf(s.out, "%s:%d: %s\n\t-> %s\n", name, i+1, line, lines[i])
+		changed = true
+	}
+	return changed
+}
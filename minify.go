@@ -0,0 +1,161 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// An apiInterface is an interface type of the standard library, as the
+// API files record it, with its methods, embedded ones included.
+type apiInterface struct {
+	pkgPath string
+	name    string
+	methods map[string]string // Name to signature, such as Read([]uint8) (int, error).
+}
+
+// apiBasic matches the names of predeclared aliases, which the API files
+// record by the types they denote.
+var apiBasic = regexp.MustCompile(`\b(byte|rune|any)\b`)
+
+// minifyIface prints the smallest interfaces of the standard library that
+// the type, given as pkg.Type, or a pointer to it, implements and that
+// include the methods, listed as Read,Write, so that code using just
+// those methods can accept the interface instead of the type.
+func (s *session) minifyIface(typeArg, methodList string) {
+	t, ok := s.findType(typeArg)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "doc: -minify-iface: no type %s\n", typeArg)
+		exit(1)
+	}
+	typ := types.Type(t.obj.Type())
+	if !types.IsInterface(typ) {
+		typ = types.NewPointer(typ)
+	}
+	have := make(map[string]string) // Methods of typ, in the form of the API files.
+	mset := s.methodSets.MethodSet(typ)
+	for i := 0; i < mset.Len(); i++ {
+		fn := mset.At(i).Obj().(*types.Func)
+		have[fn.Name()] = apiSignature(fn)
+	}
+	var want []string
+	for _, m := range strings.Split(methodList, ",") {
+		m = strings.TrimSpace(m)
+		if _, ok := have[m]; !ok {
+			fmt.Fprintf(os.Stderr, "doc: -minify-iface: %s has no method %s\n", typeArg, m)
+			exit(1)
+		}
+		want = append(want, m)
+	}
+	var best []apiInterface
+	for _, iface := range apiInterfaces() {
+		if !iface.covers(want) || !iface.implementedBy(have) {
+			continue
+		}
+		switch {
+		case len(best) == 0 || len(iface.methods) < len(best[0].methods):
+			best = []apiInterface{iface}
+		case len(iface.methods) == len(best[0].methods):
+			best = append(best, iface)
+		}
+	}
+	name := types.TypeString(typ, (*types.Package).Name)
+	if len(best) == 0 {
+		fmt.Fprintf(s.out, "no standard interface implemented by %s has methods %s; declare one:\n", name, strings.Join(want, ", "))
+		fmt.Fprintf(s.out, "\tinterface {\n")
+		for _, m := range want {
+			fmt.Fprintf(s.out, "\t\t%s\n", have[m])
+		}
+		fmt.Fprintf(s.out, "\t}\n")
+		return
+	}
+	fmt.Fprintf(s.out, "the smallest standard interfaces implemented by %s with methods %s:\n", name, strings.Join(want, ", "))
+	for _, iface := range best {
+		fmt.Fprintf(s.out, "\t%s.%s\n", iface.pkgPath, iface.name)
+		dir := filepath.Join(goRoot, "src", filepath.FromSlash(iface.pkgPath))
+		if syn := synopsis(findDoc(s.parsePackageFiles(dir), "", iface.name)); syn != "" {
+			fmt.Fprintf(s.out, "\t\t%s\n", syn)
+		}
+	}
+}
+
+// apiSignature returns the signature of the method as the API files write
+// it: without parameter names, with types qualified by package name, and
+// with byte and rune spelled out.
+func apiSignature(fn *types.Func) string {
+	sig := strings.TrimPrefix(types.TypeString(unnamedSignature(fn), (*types.Package).Name), "func")
+	return fn.Name() + apiBasic.ReplaceAllStringFunc(sig, func(name string) string {
+		return map[string]string{"byte": "uint8", "rune": "int32", "any": "interface{}"}[name]
+	})
+}
+
+// apiInterfaces returns the exported interfaces of the standard library,
+// as recorded by the API files, omitting those with unexported methods,
+// which no other package can implement, and those without methods.
+func apiInterfaces() []apiInterface {
+	names, _ := filepath.Glob(filepath.Join(goRoot, "api", "go1*.txt"))
+	ifaces := make(map[string]*apiInterface)
+	closed := make(map[string]bool)
+	for _, file := range names {
+		for _, e := range readAPI(file) {
+			rest, ok := strings.CutPrefix(e.decl, "type ")
+			if !ok {
+				continue
+			}
+			name, method, ok := strings.Cut(rest, " interface, ")
+			if !ok {
+				continue
+			}
+			key := e.pkgPath + "." + name
+			if method == "unexported methods" {
+				closed[key] = true
+				continue
+			}
+			iface := ifaces[key]
+			if iface == nil {
+				iface = &apiInterface{pkgPath: e.pkgPath, name: name, methods: make(map[string]string)}
+				ifaces[key] = iface
+			}
+			iface.methods[apiName(method)] = method
+		}
+	}
+	var list []apiInterface
+	for key, iface := range ifaces {
+		if !closed[key] {
+			list = append(list, *iface)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].pkgPath+"."+list[i].name < list[j].pkgPath+"."+list[j].name
+	})
+	return list
+}
+
+// covers reports whether the interface has all the named methods.
+func (iface apiInterface) covers(names []string) bool {
+	for _, name := range names {
+		if _, ok := iface.methods[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// implementedBy reports whether the methods, by name, in the form of the
+// API files, include all those of the interface.
+func (iface apiInterface) implementedBy(methods map[string]string) bool {
+	for name, sig := range iface.methods {
+		if methods[name] != sig {
+			return false
+		}
+	}
+	return true
+}
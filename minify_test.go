@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMinifyIface(t *testing.T) {
+	fakeGOROOT(t, map[string]string{
+		"api/go1.txt": `pkg io, type Reader interface { Read }
+pkg io, type Reader interface, Read([]uint8) (int, error)
+pkg io, type Writer interface { Write }
+pkg io, type Writer interface, Write([]uint8) (int, error)
+pkg io, type ReadWriter interface { Read, Write }
+pkg io, type ReadWriter interface, Read([]uint8) (int, error)
+pkg io, type ReadWriter interface, Write([]uint8) (int, error)
+pkg io, type ReadWriteCloser interface { Close, Read, Write }
+pkg io, type ReadWriteCloser interface, Close() error
+pkg io, type ReadWriteCloser interface, Read([]uint8) (int, error)
+pkg io, type ReadWriteCloser interface, Write([]uint8) (int, error)
+pkg io, type Sealed interface, Read([]uint8) (int, error)
+pkg io, type Sealed interface, unexported methods
+`,
+		"api/go1.1.txt": `pkg fmt, type Stringer interface { String }
+pkg fmt, type Stringer interface, String() string
+pkg expvar, type Var interface { String }
+pkg expvar, type Var interface, String() string
+`,
+		"src/io/io.go":         "package io\n\n// Reader reads.\ntype Reader interface{}\n\n// ReadWriter reads and writes.\ntype ReadWriter interface{}\n",
+		"src/fmt/print.go":     "package fmt\n\n// Stringer is a String method.\ntype Stringer interface{}\n",
+		"src/expvar/expvar.go": "package expvar\n\n// Var is a variable.\ntype Var interface{}\n",
+	})
+	old := goPaths
+	goPaths = nil
+	defer func() { goPaths = old }()
+	writeModule(t, map[string]string{
+		"go.mod": "module example.com/mi\n\ngo 1.22\n",
+		"buf/buf.go": `package buf
+
+// Buffer buffers.
+type Buffer struct{}
+
+func (b *Buffer) Read(p []byte) (int, error)  { return 0, nil }
+func (b *Buffer) Write(p []byte) (int, error) { return 0, nil }
+func (b *Buffer) Close() error                { return nil }
+func (b *Buffer) String() string              { return "" }
+func (b *Buffer) Len() int                    { return 0 }
+
+// Text reads strings.
+type Text struct{}
+
+func (t Text) Read(s string) (int, error) { return 0, nil }
+`,
+	})
+	tests := []struct {
+		typ, methods string
+		want         string
+		status       int
+	}{
+		{
+			typ: "buf.Buffer", methods: "Read",
+			want: "the smallest standard interfaces implemented by *buf.Buffer with methods Read:\n" +
+				"\tio.Reader\n\t\tReader reads.\n",
+		},
+		{
+			typ: "buf.Buffer", methods: "Read, Write",
+			want: "the smallest standard interfaces implemented by *buf.Buffer with methods Read, Write:\n" +
+				"\tio.ReadWriter\n\t\tReadWriter reads and writes.\n",
+		},
+		{
+			// Interfaces of the same size are all listed.
+			typ: "buf.Buffer", methods: "String",
+			want: "the smallest standard interfaces implemented by *buf.Buffer with methods String:\n" +
+				"\texpvar.Var\n\t\tVar is a variable.\n" +
+				"\tfmt.Stringer\n\t\tStringer is a String method.\n",
+		},
+		{
+			typ: "buf.Buffer", methods: "Len",
+			want: "no standard interface implemented by *buf.Buffer has methods Len; declare one:\n" +
+				"\tinterface {\n\t\tLen() int\n\t}\n",
+		},
+		{
+			// The signature must match too.
+			typ: "buf.Text", methods: "Read",
+			want: "no standard interface implemented by *buf.Text has methods Read; declare one:\n" +
+				"\tinterface {\n\t\tRead(string) (int, error)\n\t}\n",
+		},
+		{typ: "buf.Buffer", methods: "Flush", status: 1},
+		{typ: "buf.Nothing", methods: "Read", status: 1},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).minifyIface(test.typ, test.methods) })
+		if status != test.status {
+			t.Errorf("-minify-iface %s %s exited with %d, want %d", test.typ, test.methods, status, test.status)
+			continue
+		}
+		if status == 0 && out.String() != test.want {
+			t.Errorf("-minify-iface %s %s printed\n%s\nwant\n%s", test.typ, test.methods, out.String(), test.want)
+		}
+	}
+}
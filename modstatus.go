@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// A moduleStatus is what the go.mod file of the latest version of a module
+// says of the module, and of the version of it holding a package.
+type moduleStatus struct {
+	path       string // Of the module.
+	version    string // Of the module holding the package, if known.
+	deprecated string // The deprecation message, if the module is deprecated.
+	retracted  string // Why the version is retracted, if it is.
+}
+
+// moduleStatusOf returns the status of the module holding the package in
+// the directory, or nil if it is in no module or the standard library.
+// The module's deprecation and retractions are those of the go.mod file of
+// its latest version, from the module proxy for a version in the module
+// cache of a module that is not private, or else of its own go.mod file.
+func (s *session) moduleStatusOf(dir string) *moduleStatus {
+	if rootCategory(dir) == "std" {
+		return nil
+	}
+	root := moduleRootOf(dir)
+	if root == "" {
+		return nil
+	}
+	if status, ok := s.moduleStatuses[root]; ok {
+		return status
+	}
+	goMod, _ := os.ReadFile(filepath.Join(root, "go.mod"))
+	status := &moduleStatus{path: modulePathIn(filepath.Join(root, "go.mod"))}
+	if _, version, ok := strings.Cut(filepath.Base(root), "@"); ok {
+		status.version = version
+	}
+	if status.version != "" && !module.MatchPrefixPatterns(os.Getenv("GOPRIVATE"), status.path) {
+		if latest := latestGoMod(status.path); latest != nil {
+			goMod = latest
+		}
+	}
+	status.deprecated = deprecationIn(goMod)
+	if status.version != "" {
+		status.retracted = retractionIn(goMod, status.version)
+	}
+	if status.deprecated == "" && status.retracted == "" {
+		status = nil
+	}
+	s.moduleStatuses[root] = status
+	return status
+}
+
+// latestGoMod returns the go.mod file of the latest version of the module,
+// from the module proxies, or nil if it cannot be had.
+func latestGoMod(modulePath string) []byte {
+	var info struct{ Version string }
+	data, err := tryFetchModule(modulePath, "@latest")
+	if err != nil || json.Unmarshal(data, &info) != nil || info.Version == "" {
+		return nil
+	}
+	data, err = tryFetchModule(modulePath, "@v/"+info.Version+".mod")
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// deprecationIn returns the message of the Deprecated: paragraph of the
+// comment on or just before the module directive of the go.mod file, or
+// the empty string if the module is not deprecated. The message's lines
+// are joined into one.
+func deprecationIn(goMod []byte) string {
+	f, err := modfile.ParseLax("go.mod", goMod, nil)
+	if err != nil || f.Module == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(f.Module.Deprecated), " ")
+}
+
+// retractionIn returns, if a retract directive of the go.mod file covers
+// the version, its rationale, the comment after or just before it, or
+// "no reason given".
+func retractionIn(goMod []byte, version string) string {
+	f, err := modfile.ParseLax("go.mod", goMod, nil)
+	if err != nil {
+		return ""
+	}
+	for _, r := range f.Retract {
+		if semver.Compare(r.Low, version) <= 0 && semver.Compare(version, r.High) <= 0 {
+			if r.Rationale == "" {
+				return "no reason given"
+			}
+			return r.Rationale
+		}
+	}
+	return ""
+}
+
+// note returns the lines to print with the documentation of a symbol of
+// the module, which say whether it is deprecated, with its message, which
+// often names its successor, and whether its version is retracted.
+func (m *moduleStatus) note() string {
+	if m == nil {
+		return ""
+	}
+	var b bytes.Buffer
+	if m.deprecated != "" {
+		fmt.Fprintf(&b, "module %s is deprecated: %s\n", m.path, m.deprecated)
+	}
+	if m.retracted != "" {
+		fmt.Fprintf(&b, "%s@%s is retracted: %s\n", m.path, m.version, m.retracted)
+	}
+	return b.String()
+}
+
+// kind returns, for -out json, "retracted" or "deprecated", or the empty
+// string for a module that is neither.
+func (m *moduleStatus) kind() string {
+	switch {
+	case m == nil:
+		return ""
+	case m.retracted != "":
+		return "retracted"
+	case m.deprecated != "":
+		return "deprecated"
+	}
+	return ""
+}
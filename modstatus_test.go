@@ -0,0 +1,61 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestDeprecationIn(t *testing.T) {
+	tests := []struct {
+		goMod string
+		want  string
+	}{
+		{"module example.com/m\n", ""},
+		{"// Deprecated: use example.com/n.\nmodule example.com/m\n", "use example.com/n."},
+		{"module example.com/m // Deprecated: use example.com/n.\n", "use example.com/n."},
+		{"// The m module.\n//\n// Deprecated: use\n// example.com/n.\nmodule example.com/m\n", "use example.com/n."},
+		{"// Deprecated: not on the module directive.\n\nmodule example.com/m\n", ""},
+		{"module example.com/m\n\nfrobnicate x\n", ""},
+	}
+	for _, test := range tests {
+		if got := deprecationIn([]byte(test.goMod)); got != test.want {
+			t.Errorf("deprecationIn of\n%s= %q, want %q", test.goMod, got, test.want)
+		}
+	}
+}
+
+func TestRetractionIn(t *testing.T) {
+	const goMod = `module example.com/m
+
+// Published by mistake.
+retract v1.0.0
+
+retract [v1.1.0, v1.1.3] // Breaks the build.
+
+retract (
+	v1.2.0
+	// A security hole.
+	v1.3.0
+)
+`
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"v0.9.0", ""},
+		{"v1.0.0", "Published by mistake."},
+		{"v1.1.0", "Breaks the build."},
+		{"v1.1.2", "Breaks the build."},
+		{"v1.1.3", "Breaks the build."},
+		{"v1.1.4", ""},
+		{"v1.2.0", "no reason given"},
+		{"v1.3.0", "A security hole."},
+		{"v1.3.0-rc.1", ""},
+	}
+	for _, test := range tests {
+		if got := retractionIn([]byte(goMod), test.version); got != test.want {
+			t.Errorf("retractionIn(%s) = %q, want %q", test.version, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/build"
+	"go/version"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// The module containing the current directory, if any.
+var modDir, modPath = findModule()
+
+// findModule looks in the current directory and its parents for a go.mod file
+// and returns the directory holding it and the module path it declares.
+// If there is no module, it returns empty strings.
+func findModule() (dir, modulePath string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", ""
+	}
+	for {
+		if p := modulePathIn(filepath.Join(dir, "go.mod")); p != "" {
+			return dir, p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// moduleRootOf returns the directory holding the go.mod file of the module
+// containing the directory, or the empty string if it is in no module.
+func moduleRootOf(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// modulePathIn returns the path in the module directive of the named go.mod file,
+// or the empty string if the file cannot be read or has no module directive.
+func modulePathIn(goMod string) string {
+	f, err := parseGoMod(goMod)
+	if err != nil || f.Module == nil {
+		return ""
+	}
+	return f.Module.Mod.Path
+}
+
+// parseGoMod reads and parses the named go.mod file. Like the go command
+// reading a dependency's go.mod, it ignores directives it does not know.
+func parseGoMod(goMod string) (*modfile.File, error) {
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return nil, err
+	}
+	return modfile.ParseLax(goMod, data, nil)
+}
+
+// languageVersion returns the version of Go, such as go1.22, in which the
+// package in the directory is written: that of the go directive of the
+// go.mod file of its module, which for the standard library is GOROOT's.
+// It returns the empty string, which means the newest version this program
+// knows, if there is no go directive or it names a newer version.
+func languageVersion(dir string) string {
+	for {
+		goMod := filepath.Join(dir, "go.mod")
+		if _, err := os.Stat(goMod); err == nil {
+			v := goVersionIn(goMod)
+			if version.Compare(v, version.Lang(runtime.Version())) > 0 {
+				return ""
+			}
+			return v
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// goVersionIn returns the version in the go directive of the named go.mod
+// file, as in go1.22, or the empty string if there is none.
+func goVersionIn(goMod string) string {
+	f, err := parseGoMod(goMod)
+	if err != nil || f.Go == nil || !version.IsValid("go"+f.Go.Version) {
+		return ""
+	}
+	return "go" + f.Go.Version
+}
+
+// A requirement is a module version required by a go.mod file.
+type requirement struct {
+	path     string
+	version  string
+	indirect bool // Marked // indirect.
+}
+
+// requirementsIn returns the requirements listed in the named go.mod file.
+func requirementsIn(goMod string) ([]requirement, error) {
+	f, err := parseGoMod(goMod)
+	if err != nil {
+		return nil, err
+	}
+	var reqs []requirement
+	for _, req := range f.Require {
+		reqs = append(reqs, requirement{req.Mod.Path, req.Mod.Version, req.Indirect})
+	}
+	return reqs, nil
+}
+
+// selectedVersions returns the version of each module in the build list of
+// the module whose go.mod file is in the directory: those minimal version
+// selection picks over the pruned module graph, as go list -m all reports
+// them. A required version can be older than the one selected when another
+// dependency requires a newer one, and go.mod files before go 1.17 do not
+// list every module a build uses. It returns nil if the go command fails,
+// as it does when the graph needs go.mod files not in the module cache;
+// the network is never used.
+func selectedVersions(dir string) map[string]string {
+	cmd := exec.Command("go", "list", "-mod=readonly", "-m", "-f", "{{.Path}} {{.Version}}", "all")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOPROXY=off", "GOFLAGS=")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	versions := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			versions[fields[0]] = fields[1]
+		}
+	}
+	return versions
+}
+
+// listedDirs caches the results of listedDir, by source directory and
+// import path.
+var listedDirs = struct {
+	sync.Mutex
+	byKey map[[2]string]string
+}{byKey: make(map[[2]string]string)}
+
+// listedDir returns the directory of the package with the import path in
+// the build list of the module holding srcDir, as go list reports it: in
+// the module cache, the module's vendor directory or a workspace module.
+// It returns the empty string if srcDir is empty or in no module, or the
+// go command cannot find the package without the network.
+func listedDir(pkgPath, srcDir string) string {
+	if srcDir == "" || strings.HasPrefix(pkgPath, "-") {
+		return ""
+	}
+	key := [2]string{srcDir, pkgPath}
+	listedDirs.Lock()
+	dir, ok := listedDirs.byKey[key]
+	listedDirs.Unlock()
+	if ok {
+		return dir
+	}
+	cmd := exec.Command("go", "list", "-mod=readonly", "-f", "{{.Dir}}", pkgPath)
+	cmd.Dir = srcDir
+	cmd.Env = append(os.Environ(), "GOPROXY=off", "GOFLAGS=")
+	if out, err := cmd.Output(); err == nil {
+		dir = strings.TrimSpace(string(out))
+	}
+	listedDirs.Lock()
+	listedDirs.byKey[key] = dir
+	listedDirs.Unlock()
+	return dir
+}
+
+// moduleCacheDir returns the module cache: $GOMODCACHE, or pkg/mod in the
+// first element of GOPATH or its default.
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	list := filepath.SplitList(build.Default.GOPATH)
+	if len(list) == 0 {
+		return ""
+	}
+	return filepath.Join(list[0], "pkg", "mod")
+}
+
+// cachedPackage reports whether the directory is in the module cache and,
+// if so, returns the import path of the package it holds and the version
+// of its module.
+func cachedPackage(dir string) (pkgPath, version string, ok bool) {
+	cache := moduleCacheDir()
+	if cache == "" || !strings.HasPrefix(dir, cache+slash) {
+		return "", "", false
+	}
+	rel := filepath.ToSlash(strings.TrimPrefix(dir, cache+slash))
+	at := strings.Index(rel, "@")
+	if at < 0 {
+		return "", "", false
+	}
+	version, rest, _ := strings.Cut(rel[at+1:], "/")
+	modulePath, err := module.UnescapePath(rel[:at])
+	if err != nil {
+		return "", "", false
+	}
+	return path.Join(modulePath, rest), version, true
+}
+
+// moduleDir returns the directory in root, which is laid out like the module
+// cache, holding the version of the module, with the path and version
+// escaped as the module cache escapes them. The path and version, which may
+// come from a go.mod file or a URL, must be valid, so they cannot lead out
+// of root.
+func moduleDir(root, modulePath, version string) (string, error) {
+	if err := module.Check(modulePath, version); err != nil {
+		return "", err
+	}
+	escapedPath, _ := module.EscapePath(modulePath)
+	escapedVersion, _ := module.EscapeVersion(version)
+	return filepath.Join(root, escapedPath+"@"+escapedVersion), nil
+}
+
+// gorootVersion returns the version of Go in GOROOT, such as go1.22.1,
+// from its VERSION file, or the empty string if it has none, as in a
+// development tree.
+func gorootVersion() string {
+	data, err := os.ReadFile(filepath.Join(goRoot, "VERSION"))
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(line)
+}
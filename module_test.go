@@ -0,0 +1,171 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRequirementsIn(t *testing.T) {
+	tests := []struct {
+		goMod string
+		want  []requirement
+	}{
+		{"module m\n", nil},
+		{
+			"module m\n\nrequire example.com/a v1.0.0\n",
+			[]requirement{{"example.com/a", "v1.0.0", false}},
+		},
+		{
+			"module m\n\nrequire (\n\texample.com/a v1.0.0\n\t\"example.com/b\" v0.2.0 // indirect\n\n\t// A comment.\n)\n\nrequire example.com/c v2.0.0+incompatible\n",
+			[]requirement{
+				{"example.com/a", "v1.0.0", false},
+				{"example.com/b", "v0.2.0", true},
+				{"example.com/c", "v2.0.0+incompatible", false},
+			},
+		},
+		{
+			// Replacements, exclusions and retractions are not requirements.
+			"module m\n\nreplace example.com/a => ../a\n\nexclude example.com/b v0.1.0\n\nretract v0.9.0\n",
+			nil,
+		},
+		{
+			// Directives this program does not know are ignored.
+			"module m\n\ngo 1.30\n\ntoolchain go1.30.1\n\nfrobnicate x\n\nrequire example.com/a v1.0.0 // indirect; for x\n",
+			[]requirement{{"example.com/a", "v1.0.0", true}},
+		},
+	}
+	for _, test := range tests {
+		goMod := filepath.Join(t.TempDir(), "go.mod")
+		if err := os.WriteFile(goMod, []byte(test.goMod), 0666); err != nil {
+			t.Fatal(err)
+		}
+		got, err := requirementsIn(goMod)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("requirementsIn of\n%s= %v, want %v", test.goMod, got, test.want)
+		}
+	}
+	if _, err := requirementsIn(filepath.Join(t.TempDir(), "go.mod")); err == nil {
+		t.Errorf("requirementsIn of a missing go.mod succeeded")
+	}
+}
+
+func TestModulePathAndGoVersion(t *testing.T) {
+	tests := []struct {
+		goMod   string
+		path    string
+		version string
+	}{
+		{"module example.com/m\n\ngo 1.22\n", "example.com/m", "go1.22"},
+		{"// Deprecated: use v2.\nmodule \"example.com/m\"\n", "example.com/m", ""},
+		{"module example.com/m // comment\ngo 1.21.0\ntoolchain go1.22.1\n", "example.com/m", "go1.21.0"},
+		{"go 1.22\n", "", "go1.22"},
+		{"module example.com/m\ngo bad\n", "", ""},
+	}
+	for _, test := range tests {
+		goMod := filepath.Join(t.TempDir(), "go.mod")
+		if err := os.WriteFile(goMod, []byte(test.goMod), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if got := modulePathIn(goMod); got != test.path {
+			t.Errorf("modulePathIn of\n%s= %q, want %q", test.goMod, got, test.path)
+		}
+		if got := goVersionIn(goMod); got != test.version {
+			t.Errorf("goVersionIn of\n%s= %q, want %q", test.goMod, got, test.version)
+		}
+	}
+}
+
+func TestLanguageVersion(t *testing.T) {
+	dir := t.TempDir()
+	for name, text := range map[string]string{
+		"old/go.mod":        "module example.com/old\n\ngo 1.21\n",
+		"new/go.mod":        "module example.com/new\n\ngo 1.999\n",
+		"old/bare/go.mod":   "module example.com/bare\n",
+		"old/sub/x.go":      "package sub\n",
+		"new/x.go":          "package x\n",
+		"old/bare/pkg/x.go": "package pkg\n",
+	} {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(text), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"old", "go1.21"},
+		// A package inherits the version of the module holding it.
+		{"old/sub", "go1.21"},
+		// A version newer than this program knows means the newest it does.
+		{"new", ""},
+		// A go.mod without a go directive ends the search all the same.
+		{"old/bare/pkg", ""},
+	}
+	for _, test := range tests {
+		if got := languageVersion(filepath.Join(dir, filepath.FromSlash(test.dir))); got != test.want {
+			t.Errorf("languageVersion(%s) = %q, want %q", test.dir, got, test.want)
+		}
+	}
+}
+
+func TestCachedPackage(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	tests := []struct {
+		escaped string // Within the cache.
+		pkgPath string
+		version string
+	}{
+		{"golang.org/x/tools@v0.50.0/go/ast", "golang.org/x/tools/go/ast", "v0.50.0"},
+		{"github.com/!burnt!sushi/toml@v1.3.2", "github.com/BurntSushi/toml", "v1.3.2"},
+		{"github.com/!a!b!c@v1.0.0/x", "github.com/ABC/x", "v1.0.0"},
+	}
+	for _, test := range tests {
+		pkgPath, version, ok := cachedPackage(filepath.Join(cache, filepath.FromSlash(test.escaped)))
+		if !ok || pkgPath != test.pkgPath || version != test.version {
+			t.Errorf("cachedPackage(%q) = %q, %q, %t, want %q, %q", test.escaped, pkgPath, version, ok, test.pkgPath, test.version)
+		}
+	}
+	// A capital letter is not a valid escaped path.
+	if _, _, ok := cachedPackage(filepath.Join(cache, "github.com", "BurntSushi", "toml@v1.3.2")); ok {
+		t.Errorf("cachedPackage of an unescaped path succeeded")
+	}
+}
+
+func TestModuleDir(t *testing.T) {
+	root := t.TempDir()
+	tests := []struct {
+		path, version string
+		want          string // Within root, or empty for an error.
+	}{
+		{"golang.org/x/tools", "v0.50.0", "golang.org/x/tools@v0.50.0"},
+		{"github.com/BurntSushi/toml", "v1.3.2", "github.com/!burnt!sushi/toml@v1.3.2"},
+		{"example.com/m", "v2.0.0+incompatible", "example.com/m@v2.0.0+incompatible"},
+		{"example.com/../../../x", "v1.0.0", ""},
+		{"../x", "v1.0.0", ""},
+		{"example.com/m", "../../x", ""},
+		{"example.com/m", "latest", ""},
+	}
+	for _, test := range tests {
+		dir, err := moduleDir(root, test.path, test.version)
+		switch {
+		case test.want == "" && err == nil:
+			t.Errorf("moduleDir(%q, %q) = %q, want an error", test.path, test.version, dir)
+		case test.want != "" && dir != filepath.Join(root, filepath.FromSlash(test.want)):
+			t.Errorf("moduleDir(%q, %q) = %q, %v, want %s", test.path, test.version, dir, err, test.want)
+		}
+	}
+}
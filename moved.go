@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// movedPackages maps the import paths of well-known packages that were
+// renamed or moved, and the trees below them, to their successors.
+var movedPackages = [][2]string{
+	{"golang.org/x/net/context", "context"},
+	{"golang.org/x/exp/slices", "slices"},
+	{"golang.org/x/exp/maps", "maps"},
+	{"golang.org/x/exp/slog", "log/slog"},
+	{"golang.org/x/crypto/ssh/terminal", "golang.org/x/term"},
+	{"github.com/golang/protobuf", "google.golang.org/protobuf"},
+	{"github.com/golang/mock", "go.uber.org/mock"},
+	{"github.com/dgrijalva/jwt-go", "github.com/golang-jwt/jwt"},
+	{"code.google.com/p/go.net", "golang.org/x/net"},
+	{"code.google.com/p/go.tools", "golang.org/x/tools"},
+	{"code.google.com/p/go.crypto", "golang.org/x/crypto"},
+	{"code.google.com/p/go.text", "golang.org/x/text"},
+}
+
+// movedTable returns the renames in the lines of the configuration file
+//	moved old/path new/path
+// followed by those of movedPackages, so the configuration takes precedence.
+func movedTable() [][2]string {
+	var table [][2]string
+	for _, value := range config["moved"] {
+		if fields := strings.Fields(value); len(fields) == 2 {
+			table = append(table, [2]string{fields[0], fields[1]})
+		}
+	}
+	return append(table, movedPackages...)
+}
+
+// movedTo returns the successor of the package with the import path, or the
+// empty string if it has none.
+func movedTo(pkgPath string) string {
+	for _, entry := range movedTable() {
+		if rest, ok := strings.CutPrefix(pkgPath, entry[0]); ok && (rest == "" || rest[0] == '/') {
+			return entry[1] + rest
+		}
+	}
+	return ""
+}
+
+// movedHint says, when the package named pkg, an import path or the last
+// element of one, cannot be found, where it has moved to if it is known to
+// have. A last element is compared with those of the old paths, and each
+// that matches is suggested.
+func movedHint(pkg string) {
+	if pkg == "" || isDirectory(pkg) {
+		return
+	}
+	var moves [][2]string
+	if strings.Contains(pkg, "/") {
+		if to := movedTo(pkg); to != "" {
+			moves = append(moves, [2]string{pkg, to})
+		}
+	} else {
+		for _, entry := range movedTable() {
+			if path.Base(entry[0]) == pkg {
+				moves = append(moves, entry)
+			}
+		}
+	}
+	for _, move := range moves {
+		if path.Base(move[0]) == path.Base(move[1]) {
+			fmt.Fprintf(os.Stderr, "doc: %s has moved to %s\n", move[0], move[1])
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "doc: %s has moved to %s; try doc %s\n", move[0], move[1], path.Base(move[1]))
+	}
+}
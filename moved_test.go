@@ -0,0 +1,70 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMovedTo(t *testing.T) {
+	old := config
+	config = map[string][]string{"moved": {"github.com/oldorg/lib github.com/neworg/lib", "golang.org/x/exp/slices example.com/myslices", "bad line"}}
+	defer func() { config = old }()
+	tests := []struct {
+		pkgPath, want string
+	}{
+		{"golang.org/x/net/context", "context"},
+		{"golang.org/x/net/context/ctxhttp", "context/ctxhttp"},
+		{"golang.org/x/net/contextual", ""},
+		{"github.com/golang/protobuf/proto", "google.golang.org/protobuf/proto"},
+		{"github.com/oldorg/lib/sub", "github.com/neworg/lib/sub"},
+		{"golang.org/x/exp/slices", "example.com/myslices"}, // The configuration comes first.
+		{"strings", ""},
+	}
+	for _, test := range tests {
+		if got := movedTo(test.pkgPath); got != test.want {
+			t.Errorf("movedTo(%q) = %q, want %q", test.pkgPath, got, test.want)
+		}
+	}
+}
+
+func TestMovedHint(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":     "module example.com/mv\n\ngo 1.22\n",
+		"lib/lib.go": "package lib\n\n// Name names.\nfunc Name() {}\n",
+	})
+	conf := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(conf, []byte("moved github.com/oldorg/oldlib github.com/neworg/newlib\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCCONFIG", conf)
+	tests := []struct {
+		args []string
+		want string // The hint reported, or empty for none.
+	}{
+		{[]string{"-local", "terminal.MakeRaw"}, "doc: golang.org/x/crypto/ssh/terminal has moved to golang.org/x/term; try doc term\n"},
+		{[]string{"-local", "protobuf.Name"}, "doc: github.com/golang/protobuf has moved to google.golang.org/protobuf\n"},
+		{[]string{"-local", "oldlib.Name"}, "doc: github.com/oldorg/oldlib has moved to github.com/neworg/newlib; try doc newlib\n"},
+		{[]string{"-local", "terminal.MakeRaw.Field"}, "doc: golang.org/x/crypto/ssh/terminal has moved to golang.org/x/term; try doc term\n"},
+		{[]string{"-local", "nothing.Name"}, ""},
+		{[]string{"-local", "lib.Nothing"}, ""}, // The package is there.
+		{[]string{"-exists", "-local", "terminal.MakeRaw"}, ""},
+	}
+	for _, test := range tests {
+		_, stderr, _ := runDoc(t, dir, test.args...)
+		var got string
+		for _, line := range strings.SplitAfter(stderr, "\n") {
+			if strings.Contains(line, " has moved to ") {
+				got += line
+			}
+		}
+		if got != test.want {
+			t.Errorf("doc %q reported\n%s\nwant the hint\n%s", test.args, stderr, test.want)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+)
+
+// isSelectorChain reports whether the name is a chain of identifiers
+// separated by dots, such as Server.TLSConfig.MinVersion, rather than a
+// regular expression.
+func isSelectorChain(name string) bool {
+	elems := strings.Split(name, ".")
+	if len(elems) < 2 {
+		return false
+	}
+	for _, elem := range elems {
+		if !token.IsIdentifier(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// nested prints the documentation of the field or method at the end of a
+// chain of selectors, such as Server.TLSConfig.MinVersion, that begins with
+// a type or variable of the packages named pkg. Each field's type, through
+// pointers, slices, arrays and maps, names the type in which the next
+// element is looked up, in whatever package declares it.
+func (s *session) nested(pkg string, elems []string) {
+	dirs := s.paths(pkg)
+	for _, dir := range dirs {
+		if s.nestedIn(dir, elems) {
+			if *importFlag {
+				s.printImport(elems[len(elems)-1])
+			}
+			if *hoverFlag {
+				s.printHover(elems[len(elems)-1])
+			}
+			return
+		}
+	}
+	if len(dirs) == 0 && !*existsFlag {
+		movedHint(pkg)
+	}
+	s.notFound("no %s.%s", pkg, elems[0])
+}
+
+// nestedIn follows the chain from the package in the directory, reporting
+// whether the package declares its first element.
+func (s *session) nestedIn(dir string, elems []string) bool {
+	fset := token.NewFileSet()
+	pkgs, _ := parseDir(fset, dir, nil, parser.ParseComments) // Ignore the error.
+	for _, pkg := range choosePackages(dir, pkgs) {
+		if strings.HasSuffix(pkg.Name, "_test") {
+			continue
+		}
+		typesPkg, info := typeCheck(fset, pkg)
+		if typesPkg == nil {
+			continue
+		}
+		var typ types.Type
+		chain := pkg.Name + "."
+		switch obj := lookupObject(typesPkg.Scope(), elems[0]).(type) {
+		case *types.TypeName:
+			typ, chain = obj.Type(), chain+obj.Name()
+		case *types.Var:
+			typ, chain = elemType(obj.Type()), chain+obj.Name()
+		default:
+			continue
+		}
+		var obj types.Object
+		var owner *types.TypeName
+		promoted := "" // A note of the embedded fields promoting the last element.
+		for i, elem := range elems[1:] {
+			var index []int
+			obj, index = lookupFieldFold(typ, elem)
+			if obj == nil {
+				s.notFound("%s has no field or method %s", chain, elem)
+			}
+			chain += "." + obj.Name()
+			if _, ok := obj.(*types.Func); ok && i < len(elems)-2 {
+				s.notFound("%s is a method; only fields can be followed", chain)
+			}
+			promoted = embeddedPath(typ, index)
+			owner = fieldOwner(typ, index)
+			typ = elemType(obj.Type())
+		}
+		var files []*File
+		for name, astFile := range pkg.Files {
+			file := s.newFile(fset, name, obj.Name(), astFile)
+			file.doPrint = true
+			file.types = typesPkg
+			file.objs = info.Defs
+			files = append(files, file)
+		}
+		if fn, ok := obj.(*types.Func); ok {
+			if !*importFlag && !*hoverFlag {
+				fmt.Fprintf(s.out, "%s is method %s of %s.%s%s\n\n", chain, fn.Name(), fn.Pkg().Name(), receiverName(fn), promoted)
+			}
+			file, decl := findFunc(s, fn, files)
+			if decl == nil {
+				fmt.Fprintf(s.out, "%s\n\n", fset.Position(fn.Pos()))
+				return true
+			}
+			body := decl.Body
+			decl.Body = nil // Do not print the function body.
+			file.printNode(decl, decl.Name, file.methodURL(decl))
+			decl.Body = body
+			if *snippetFlag && !*importFlag && !*hoverFlag {
+				file.snippet(decl)
+			}
+			if *inheritDocFlag && decl.Doc == nil && file.types == typesPkg {
+				file.allFiles = files
+				file.inheritDoc(decl)
+			}
+			return true
+		}
+		if owner == nil {
+			s.notFound("%s is a field of an unnamed struct type", chain)
+		}
+		if !*importFlag && !*hoverFlag {
+			fmt.Fprintf(s.out, "%s is field %s of %s.%s%s\n\n", chain, obj.Name(), owner.Pkg().Name(), owner.Name(), promoted)
+		}
+		file, decl, id := findField(s, owner, obj.Name(), files)
+		if decl == nil {
+			fmt.Fprintf(s.out, "%s\n\n", fset.Position(obj.Pos()))
+			return true
+		}
+		file.printNode(decl, id, file.declURL(typeKind, owner.Name()+"."+id.Name, fset.Position(id.Pos()).Line))
+		return true
+	}
+	return false
+}
+
+// lookupObject returns the exported object with the name, ignoring case, in the scope.
+func lookupObject(scope *types.Scope, name string) types.Object {
+	for _, n := range scope.Names() {
+		if ast.IsExported(n) && equalFold(n, name) {
+			return scope.Lookup(n)
+		}
+	}
+	return nil
+}
+
+// elemType returns the type reached from typ through pointers and unnamed
+// slices, arrays and maps: for *[]*tls.Config, tls.Config.
+func elemType(typ types.Type) types.Type {
+	for {
+		switch t := typ.(type) {
+		case *types.Pointer:
+			typ = t.Elem()
+		case *types.Slice:
+			typ = t.Elem()
+		case *types.Array:
+			typ = t.Elem()
+		case *types.Map:
+			typ = t.Elem()
+		default:
+			return typ
+		}
+	}
+}
+
+// lookupFieldFold returns the exported field or method of the type with
+// the name, ignoring case, and the index sequence that leads to it, as
+// types.LookupFieldOrMethod does. Fields and methods promoted through
+// embedded fields are found only if the case matches.
+func lookupFieldFold(typ types.Type, name string) (types.Object, []int) {
+	if !ast.IsExported(name) {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	if obj, index, _ := types.LookupFieldOrMethod(typ, true, nil, name); obj != nil && obj.Exported() {
+		return obj, index
+	}
+	var names []string
+	if st, ok := typ.Underlying().(*types.Struct); ok {
+		for i := 0; i < st.NumFields(); i++ {
+			names = append(names, st.Field(i).Name())
+		}
+	}
+	ms := types.NewMethodSet(types.NewPointer(typ))
+	for i := 0; i < ms.Len(); i++ {
+		names = append(names, ms.At(i).Obj().Name())
+	}
+	for _, n := range names {
+		if ast.IsExported(n) && equalFold(n, name) {
+			obj, index, _ := types.LookupFieldOrMethod(typ, true, nil, n)
+			return obj, index
+		}
+	}
+	return nil, nil
+}
+
+// fieldOwner returns the named struct type that declares the field reached
+// from typ by the index sequence, which passes through embedded fields
+// for a promoted field, or nil if the struct type has no name.
+func fieldOwner(typ types.Type, index []int) *types.TypeName {
+	for _, i := range index[:len(index)-1] {
+		st, ok := elemType(typ).Underlying().(*types.Struct)
+		if !ok {
+			return nil
+		}
+		typ = st.Field(i).Type()
+	}
+	if named, ok := elemType(typ).(*types.Named); ok {
+		return named.Obj()
+	}
+	return nil
+}
+
+// embeddedPath returns, for the field or method of typ with the index path,
+// a note of the embedded fields through which it is promoted, such as
+// ", promoted through *Reader", or the empty string if typ declares it.
+func embeddedPath(typ types.Type, index []int) string {
+	var path []string
+	for _, i := range index[:len(index)-1] {
+		st, ok := elemType(typ).Underlying().(*types.Struct)
+		if !ok {
+			return ""
+		}
+		field := st.Field(i)
+		path = append(path, types.TypeString(field.Type(), func(*types.Package) string { return "" }))
+		typ = field.Type()
+	}
+	if len(path) == 0 {
+		return ""
+	}
+	return ", promoted through " + strings.Join(path, ", then ")
+}
+
+// findField returns a declaration of the type owner holding just its field
+// with the name, for printing, the identifier naming the field, and the file
+// holding it. The files, from a single type-checked package, are searched if
+// they declare the owner; otherwise the package that does is parsed. If the
+// field cannot be found, findField returns nil.
+func findField(s *session, owner *types.TypeName, name string, files []*File) (*File, *ast.GenDecl, *ast.Ident) {
+	local := len(files) > 0 && files[0].types != nil && owner.Pkg() == files[0].types
+	if !local {
+		dir := dirForImport(owner.Pkg().Path(), filesDir(files))
+		if dir == "" {
+			return nil, nil, nil
+		}
+		fset := token.NewFileSet()
+		pkgs, _ := parseDir(fset, dir, nil, parser.ParseComments) // Ignore the error.
+		files = nil
+		for _, pkg := range pkgs {
+			for fileName, astFile := range pkg.Files {
+				file := s.newFile(fset, fileName, name, astFile)
+				file.doPrint = true
+				files = append(files, file)
+			}
+		}
+	}
+	for _, file := range files {
+		for _, decl := range file.file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if spec.Name.Name != owner.Name() || local && spec.Name.Pos() != owner.Pos() {
+					continue
+				}
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					for _, id := range field.Names {
+						if id.Name == name {
+							return file, fieldDecl(decl, spec, st, field), id
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// fieldDecl returns a copy of the declaration of the struct type that holds
+// only the field, keeping the positions of the original so the field and
+// its comments print as they appear in the source.
+func fieldDecl(decl *ast.GenDecl, spec *ast.TypeSpec, st *ast.StructType, field *ast.Field) *ast.GenDecl {
+	fields := *st.Fields
+	fields.List = []*ast.Field{field}
+	short := *st
+	short.Fields = &fields
+	typeSpec := *spec
+	typeSpec.Doc, typeSpec.Comment = nil, nil
+	typeSpec.Type = &short
+	return &ast.GenDecl{TokPos: decl.TokPos, Tok: token.TYPE, Specs: []ast.Spec{&typeSpec}}
+}
+
+// notFound reports, unless -exists asks for silence, that the chain of
+// selectors does not resolve, and exits.
+func (s *session) notFound(format string, args ...any) {
+	if !*existsFlag {
+		fmt.Fprintf(os.Stderr, "doc: "+format+"\n", args...)
+		s.generatedHint()
+	}
+	exit(1)
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNested(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/n\n\ngo 1.22\n",
+		"srv/srv.go": `package srv
+
+import "example.com/n/conf"
+
+// Server serves.
+type Server struct {
+	// TLS configures TLS.
+	TLS      *conf.Config
+	Handlers map[string][]*conf.Config
+	conf.Base
+	Local struct{ X int }
+}
+
+// Default is the default server.
+var Default Server
+`,
+		"conf/conf.go": `package conf
+
+// Config configures.
+type Config struct {
+	// MinVersion is the minimum version.
+	MinVersion uint16
+}
+
+// Clone clones.
+func (c *Config) Clone() *Config { return c }
+
+// Base is embedded.
+type Base struct {
+	// Name names.
+	Name string
+}
+`,
+	})
+	tests := []struct {
+		arg    string
+		want   string // The first line printed, or of the error.
+		syms   []string
+		status int
+	}{
+		{arg: "srv.Server.TLS.MinVersion", want: "srv.Server.TLS.MinVersion is field MinVersion of conf.Config", syms: []string{"example.com/n/conf#Config.MinVersion"}},
+		{arg: "srv.server.tls.minversion", want: "srv.Server.TLS.MinVersion is field MinVersion of conf.Config", syms: []string{"example.com/n/conf#Config.MinVersion"}},
+		{arg: "srv.Server.Handlers.MinVersion", want: "srv.Server.Handlers.MinVersion is field MinVersion of conf.Config", syms: []string{"example.com/n/conf#Config.MinVersion"}},
+		{arg: "srv.Default.TLS.MinVersion", want: "srv.Default.TLS.MinVersion is field MinVersion of conf.Config", syms: []string{"example.com/n/conf#Config.MinVersion"}},
+		{arg: "srv.Server.TLS.Clone", want: "srv.Server.TLS.Clone is method Clone of conf.Config", syms: []string{"example.com/n/conf#Config.Clone"}},
+		{arg: "srv.Server.Name", want: "srv.Server.Name is field Name of conf.Base, promoted through Base", syms: []string{"example.com/n/conf#Base.Name"}},
+		{arg: "srv.Server.TLS.Clone.X", want: "doc: srv.Server.TLS.Clone is a method; only fields can be followed", status: 1},
+		{arg: "srv.Server.Missing", want: "doc: srv.Server has no field or method Missing", status: 1},
+		{arg: "srv.Server.Local.X", want: "doc: srv.Server.Local.X is a field of an unnamed struct type", status: 1},
+		{arg: "srv.Nothing.X", want: "doc: no srv.Nothing", status: 1},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.arg)
+		if status != test.status {
+			t.Errorf("doc %s exited with %d, want %d; stderr:\n%s", test.arg, status, test.status, stderr)
+			continue
+		}
+		if status != 0 {
+			out = stderr
+		}
+		if first, _, _ := strings.Cut(out, "\n"); first != test.want {
+			t.Errorf("doc %s printed\n%s\nwant first %q", test.arg, out, test.want)
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.syms) {
+			t.Errorf("doc %s showed %q, want %q", test.arg, got, test.syms)
+		}
+	}
+	out, _, _ := runDoc(t, dir, "srv.Server.TLS.MinVersion")
+	if !strings.Contains(out, "type Config struct {\n\t// MinVersion is the minimum version.\n\tMinVersion uint16\n}\n") {
+		t.Errorf("doc srv.Server.TLS.MinVersion printed\n%s\nwant the field with its comment in its struct", out)
+	}
+}
+
+func TestPromoted(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"rw/rw.go": `package rw
+
+// Reader reads.
+type Reader struct{}
+
+// Read reads.
+func (r *Reader) Read(p []byte) (int, error) { return 0, nil }
+
+// ReadWriter reads and writes.
+type ReadWriter struct {
+	*Reader
+}
+
+// Outer wraps a ReadWriter.
+type Outer struct {
+	ReadWriter
+}
+
+// Flush flushes.
+func (o Outer) Flush() {}
+`,
+	})
+	tests := []struct {
+		args []string
+		want string // The first line printed.
+		syms []string
+	}{
+		{[]string{"-m", "rw.ReadWriter", "Read"}, "rw.ReadWriter.Read is method Read of rw.Reader, promoted through *Reader", []string{"example.com/p/rw#Reader.Read"}},
+		{[]string{"-m", "rw.ReadWriter", "read"}, "rw.ReadWriter.Read is method Read of rw.Reader, promoted through *Reader", []string{"example.com/p/rw#Reader.Read"}},
+		{[]string{"rw.Outer.Read"}, "rw.Outer.Read is method Read of rw.Reader, promoted through ReadWriter, then *Reader", []string{"example.com/p/rw#Reader.Read"}},
+		{[]string{"rw.Outer.Flush"}, "rw.Outer.Flush is method Flush of rw.Outer", []string{"example.com/p/rw#Outer.Flush"}},
+		// A package and a name are as before.
+		{[]string{"-m", "rw", "Read"}, "import \"example.com/p/rw\"", []string{"example.com/p/rw#Reader.Read"}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if first, _, _ := strings.Cut(out, "\n"); first != test.want {
+			t.Errorf("doc %q printed\n%s\nwant first %q", test.args, out, test.want)
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.syms) {
+			t.Errorf("doc %q showed %q, want %q", test.args, got, test.syms)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+)
+
+// How much -overview shows.
+const (
+	overviewTypes = 5  // The most used types.
+	overviewFuncs = 10 // The most used functions that construct none of those.
+)
+
+// overview prints, for each package with the name, a summary to start
+// reading it from: its synopsis, the exported types most used in the
+// package and the packages below it, each with its constructors, and its
+// most used other functions. Uses are counted syntactically: mentions of
+// the name within the package, and references to it from those below.
+func (s *session) overview(pkg string) {
+	found := false
+	for _, dir := range s.packageDirs(pkg) {
+		fset := token.NewFileSet()
+		notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+		pkgs, _ := parseDir(fset, dir, notTest, parser.ParseComments) // Ignore the error.
+		for _, p := range pkgs {
+			if p.Name == "main" {
+				continue
+			}
+			if found {
+				fmt.Fprintln(s.out)
+			}
+			found = true
+			s.overviewPackage(fset, dir, p)
+		}
+	}
+	if !found {
+		fmt.Fprintf(os.Stderr, "doc: -overview: no package %s\n", pkg)
+		exit(1)
+	}
+}
+
+// overviewPackage prints the overview of the package in the directory.
+func (s *session) overviewPackage(fset *token.FileSet, dir string, pkg *ast.Package) {
+	var names []string
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var (
+		doc   string
+		types []*ast.TypeSpec
+		funcs []*ast.FuncDecl
+		files = make(map[ast.Node]*File)
+	)
+	for _, name := range names {
+		astFile := pkg.Files[name]
+		file := s.newFile(fset, name, "", astFile)
+		if doc == "" {
+			doc = synopsis(astFile.Doc)
+		}
+		for _, decl := range astFile.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil && decl.Name.IsExported() {
+					funcs = append(funcs, decl)
+					files[decl] = file
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok && spec.Name.IsExported() {
+						types = append(types, spec)
+						files[spec] = file
+					}
+				}
+			}
+		}
+	}
+
+	uses := s.overviewUses(dir, pkg)
+	byUse := func(a, b string) bool {
+		if uses[a] != uses[b] {
+			return uses[a] > uses[b]
+		}
+		return a < b
+	}
+	sort.Slice(types, func(i, j int) bool { return byUse(types[i].Name.Name, types[j].Name.Name) })
+	sort.Slice(funcs, func(i, j int) bool { return byUse(funcs[i].Name.Name, funcs[j].Name.Name) })
+
+	fmt.Fprintf(s.out, "package %s // import %q\n\n", pkg.Name, importPath(dir))
+	if doc != "" {
+		fmt.Fprintf(s.out, "%s\n\n", doc)
+	}
+	constructed := make(map[*ast.FuncDecl]bool)
+	if len(types) > 0 {
+		fmt.Fprintf(s.out, "Types, most used first:\n")
+		for i, spec := range types {
+			if i == overviewTypes {
+				fmt.Fprintf(s.out, "\t... and %d more\n", len(types)-i)
+				break
+			}
+			fmt.Fprintf(s.out, "\t%s%s\n", files[spec].signature(spec, spec.Name), usesNote(uses[spec.Name.Name]))
+			for _, fn := range funcs {
+				if constructs(fn, spec.Name.Name) {
+					constructed[fn] = true
+					fmt.Fprintf(s.out, "\t\t%s\n", files[fn].signature(fn, fn.Name))
+				}
+			}
+		}
+		fmt.Fprintln(s.out)
+	}
+	var rest []*ast.FuncDecl
+	for _, fn := range funcs {
+		if !constructed[fn] {
+			rest = append(rest, fn)
+		}
+	}
+	if len(rest) > 0 {
+		fmt.Fprintf(s.out, "Functions, most used first:\n")
+		for i, fn := range rest {
+			if i == overviewFuncs {
+				fmt.Fprintf(s.out, "\t... and %d more\n", len(rest)-i)
+				break
+			}
+			fmt.Fprintf(s.out, "\t%s%s\n", files[fn].signature(fn, fn.Name), usesNote(uses[fn.Name.Name]))
+		}
+		fmt.Fprintln(s.out)
+	}
+}
+
+// overviewUses returns the number of uses of each exported package-level
+// type and function of the package in the directory: the identifiers
+// naming it in the package's own files, other than where it is declared,
+// and the references to it from the packages in the directories below.
+func (s *session) overviewUses(dir string, pkg *ast.Package) map[string]int {
+	decls := make(map[any]bool) // The declarations, as ast.Object.Decl holds them.
+	declaring := make(map[*ast.Ident]bool)
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					decls[decl], declaring[decl.Name] = true, true
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						decls[spec], declaring[spec.Name] = true, true
+					}
+				}
+			}
+		}
+	}
+	uses := make(map[string]int)
+	var count func(node ast.Node) bool
+	count = func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(node.X, count) // The selected name is a field, method or another package's.
+			return false
+		case *ast.Ident:
+			// A name declared in another file is unresolved; one declared
+			// locally resolves to its local declaration.
+			if node.IsExported() && !declaring[node] && (node.Obj == nil || decls[node.Obj.Decl]) {
+				uses[node.Name]++
+			}
+		}
+		return true
+	}
+	for _, file := range pkg.Files {
+		ast.Inspect(file, count)
+	}
+	pkgPath := importPath(dir)
+	var below []string
+	for _, sub := range s.dirsFor(dir, "") {
+		if sub != dir {
+			below = append(below, sub)
+		}
+	}
+	walkReferences(below, 0, func(ref reference) {
+		if ref.path == pkgPath {
+			uses[ref.name]++
+		}
+	})
+	return uses
+}
+
+// constructs reports whether the function returns a value of the named
+// type or a pointer to one.
+func constructs(fn *ast.FuncDecl, typeName string) bool {
+	if fn.Type.Results == nil {
+		return false
+	}
+	for _, field := range fn.Type.Results.List {
+		typ := field.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		if id, ok := typ.(*ast.Ident); ok && id.Name == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// usesNote returns the count of uses, if any, as printed by -overview.
+func usesNote(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return " // 1 use"
+	}
+	return fmt.Sprintf(" // %d uses", n)
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverview(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/s\n\ngo 1.22\n",
+		"shop/shop.go": `// Package shop sells things. It has a long synopsis.
+package shop
+
+// Item is sold.
+type Item struct{}
+
+// NewItem makes an Item.
+func NewItem() *Item { return &Item{} }
+
+// Cart holds Items.
+type Cart struct{ items []Item }
+
+// Total counts the items.
+func Total(c Cart) int { return len(c.items) }
+
+// Helper is never used.
+func Helper() {}
+
+func unexported() Cart { return Cart{} }
+`,
+		"shop/sub/sub.go": `package sub
+
+import "example.com/s/shop"
+
+var N = shop.Total(shop.Cart{})
+`,
+		"shop/main/main.go": "package main\n\nfunc main() {}\n",
+		"many/many.go":      "package many\n\ntype A int\ntype B int\ntype C int\ntype D int\ntype E int\ntype F int\n\nvar _ F\n",
+	})
+	tests := []struct {
+		pkg  string
+		want string
+	}{
+		{
+			filepath.Join(dir, "shop"),
+			`package shop // import "example.com/s/shop"
+
+Package shop sells things.
+
+Types, most used first:
+	type Cart struct{ ... } // 4 uses
+	type Item struct{ ... } // 3 uses
+		func NewItem() *Item
+
+Functions, most used first:
+	func Total(c Cart) int // 1 use
+	func Helper()
+
+`,
+		},
+		{
+			filepath.Join(dir, "shop", "sub"),
+			`package sub // import "example.com/s/shop/sub"
+
+`,
+		},
+		{
+			filepath.Join(dir, "many"),
+			`package many // import "example.com/s/many"
+
+Types, most used first:
+	type F int // 1 use
+	type A int
+	type B int
+	type C int
+	type D int
+	... and 1 more
+
+`,
+		},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		newSession(&out).overview(test.pkg)
+		if out.String() != test.want {
+			t.Errorf("-overview %s printed\n%s\nwant\n%s", test.pkg, out.String(), test.want)
+		}
+	}
+}
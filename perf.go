@@ -0,0 +1,101 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A compilerNote is a line of the compiler's optimization report.
+type compilerNote struct {
+	file string
+	line int
+	text string
+}
+
+// compilerLine matches a line of the report, such as
+//	./builder.go:46:6: can inline (*Builder).String with cost 8 as: ...
+// Lines whose text begins with a space explain the one before.
+var compilerLine = regexp.MustCompile(`^(.*\.go):(\d+):\d+: (\S.*)$`)
+
+// compilerNotes returns the optimization report of the compiler for the
+// package in the directory, from go build -gcflags=-m=2, or, if the build
+// fails, a single note describing the failure.
+func (s *session) compilerNotes(dir string) []compilerNote {
+	if notes, ok := s.perfNotes[dir]; ok {
+		return notes
+	}
+	cmd := exec.Command("go", "build", "-gcflags=-m=2", "-o", os.DevNull, ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	var notes []compilerNote
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if err != nil {
+			// The lines are errors, not notes; report the first.
+			if text := scanner.Text(); !strings.HasPrefix(text, "#") {
+				notes = []compilerNote{{text: "go build failed: " + text}}
+				break
+			}
+			continue
+		}
+		m := compilerLine.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(m[2])
+		notes = append(notes, compilerNote{filepath.Join(dir, m[1]), line, m[3]})
+	}
+	if err != nil && len(notes) == 0 {
+		notes = []compilerNote{{text: fmt.Sprintf("go build failed: %v", err)}}
+	}
+	s.perfNotes[dir] = notes
+	return notes
+}
+
+// perf prints, under the heading Performance notes, what the compiler
+// reports of fn: whether it can be inlined, and at what cost, and which
+// of its parameters escape.
+func (f *File) perf(fn *ast.FuncDecl) {
+	start := f.fset.Position(fn.Pos())
+	end := f.fset.Position(fn.Type.End())
+	var lines []string
+	for _, note := range f.s.compilerNotes(filepath.Dir(f.name)) {
+		if note.file == "" {
+			lines = append(lines, "\t"+note.text)
+			break
+		}
+		if note.file != start.Filename || note.line < start.Line || note.line > end.Line {
+			continue
+		}
+		text := note.text
+		switch {
+		case strings.HasPrefix(text, "can inline "):
+			text, _, _ = strings.Cut(text, " as: ")
+			if _, cost, ok := strings.Cut(text, " with cost "); ok {
+				text = "can inline, cost " + cost
+			}
+		case strings.HasPrefix(text, "cannot inline "):
+			if _, reason, ok := strings.Cut(text, ": "); ok {
+				text = "cannot inline: " + reason
+			}
+		case strings.HasPrefix(text, "leaking param"), strings.HasSuffix(text, " does not escape"),
+			strings.HasPrefix(text, "moved to heap: "):
+		default:
+			continue
+		}
+		lines = append(lines, "\t"+text)
+	}
+	printSection(f.output(nodeKind(fn)), "Performance notes", lines)
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// calcModule is a module of small functions that the compiler treats
+// differently, with a test that runs only Add.
+var calcModule = map[string]string{
+	"go.mod": "module example.com/calc\n\ngo 1.22\n",
+	"calc.go": `// Package calc calculates.
+package calc
+
+// Add adds.
+func Add(a, b int) int { return a + b }
+
+// Fact is a factorial.
+func Fact(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return n * Fact(n-1)
+}
+
+// Same returns p.
+func Same(p *int) *int { return p }
+
+// Deref reads p.
+func Deref(p *int) int { return *p }
+
+// Kept is never inlined.
+//
+//go:noinline
+func Kept() {}
+
+// Decl has no body.
+func Decl()
+`,
+	"calc_test.go": `package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if Add(1, 2) != 3 {
+		t.Fatal("Add(1, 2) != 3")
+	}
+}
+`,
+	"decl.s": "",
+}
+
+func TestPerf(t *testing.T) {
+	dir := writeModule(t, calcModule)
+	defaultFlags(t)
+	setFlag(t, perfFlag, true)
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOWORK", "off")
+	tests := []struct {
+		name    string
+		want    []string
+		notWant []string
+	}{
+		{name: "Add", want: []string{"Performance notes:\n\tcan inline, cost 4\n"}},
+		{name: "Same", want: []string{"\tcan inline, cost 2\n", "\tleaking param: p to result ~r0 level=0\n"}, notWant: []string{"flow:"}},
+		{name: "Deref", want: []string{"\tp does not escape\n"}},
+		{name: "Kept", want: []string{"\tcannot inline: marked go:noinline\n"}},
+		{name: "Decl", want: []string{"\tcannot inline: no function body\n"}},
+	}
+	s := newSession(nil)
+	for _, test := range tests {
+		var out bytes.Buffer
+		s.out = &out
+		s.lookInDirectory(dir, "", test.name)
+		for _, want := range test.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("-perf %s printed\n%s\nwant it to contain %q", test.name, out.String(), want)
+			}
+		}
+		for _, notWant := range test.notWant {
+			if strings.Contains(out.String(), notWant) {
+				t.Errorf("-perf %s printed\n%s\nwant it not to contain %q", test.name, out.String(), notWant)
+			}
+		}
+	}
+	if len(s.perfNotes) != 1 {
+		t.Errorf("the package was built %d times, want once", len(s.perfNotes))
+	}
+}
+
+func TestPerfBuildFailure(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/bad\n\ngo 1.22\n",
+		"bad.go": "package bad\n\n// F fails to build.\nfunc F() int { return \"\" }\n",
+	})
+	defaultFlags(t)
+	setFlag(t, perfFlag, true)
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOWORK", "off")
+	var out bytes.Buffer
+	newSession(&out).lookInDirectory(dir, "", "F")
+	if want := "Performance notes:\n\tgo build failed: ./bad.go:4:"; !strings.Contains(out.String(), want) {
+		t.Errorf("-perf of a package that does not build printed\n%s\nwant it to contain %q", out.String(), want)
+	}
+}
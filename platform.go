@@ -0,0 +1,120 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"path/filepath"
+	"strings"
+)
+
+// knownOS and knownArch hold the values of GOOS and GOARCH that, as a
+// suffix of a file's name, restrict the file to a platform, as described
+// by "go help buildconstraint".
+var (
+	knownOS = set("aix android darwin dragonfly freebsd hurd illumos ios js linux nacl netbsd openbsd plan9 solaris wasip1 windows zos")
+
+	knownArch = set("386 amd64 amd64p32 arm armbe arm64 arm64be loong64 mips mipsle mips64 mips64le mips64p32 mips64p32le " +
+		"ppc ppc64 ppc64le riscv riscv64 s390 s390x sparc sparc64 wasm")
+)
+
+// set returns the space-separated words as a set.
+func set(words string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		m[w] = true
+	}
+	return m
+}
+
+// platformNote returns, when the declaration of the identifier is one of
+// several in the package's files, a line naming the platforms for which
+// the file holding this one is built, so that variants for different
+// systems, whose documentation may differ, can be told apart.
+func (f *File) platformNote(node ast.Node, id *ast.Ident) string {
+	if id == nil || len(f.allFiles) < 2 {
+		return ""
+	}
+	recv := ""
+	if fn, ok := node.(*ast.FuncDecl); ok {
+		recv = recvTypeName(fn)
+	}
+	if !declares(f.file, recv, id.Name) {
+		return "" // Not at top level, such as a field.
+	}
+	label := f.platform()
+	if label == "" {
+		return ""
+	}
+	for _, other := range f.allFiles {
+		if other != f && declares(other.file, recv, id.Name) {
+			return "for " + label + "\n"
+		}
+	}
+	return ""
+}
+
+// platform returns the build constraint, from its name and any //go:build
+// line, of the file, such as "linux && amd64", or the empty string if
+// the file is built everywhere.
+func (f *File) platform() string {
+	var terms []string
+	elems := strings.Split(strings.TrimSuffix(strings.TrimSuffix(filepath.Base(f.name), ".go"), "_test"), "_")
+	if n := len(elems); n > 2 && knownOS[elems[n-2]] && knownArch[elems[n-1]] {
+		terms = append(terms, elems[n-2], elems[n-1])
+	} else if n > 1 && (knownOS[elems[n-1]] || knownArch[elems[n-1]]) {
+		terms = append(terms, elems[n-1])
+	}
+	var exprs []constraint.Expr
+	for _, group := range f.file.Comments {
+		if group.Pos() >= f.file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				continue
+			}
+			// Generated files often repeat the constraint of their name.
+			tags := make(map[string]bool)
+			expr.Eval(func(tag string) bool { tags[tag] = true; return true })
+			kept := terms[:0]
+			for _, t := range terms {
+				if !tags[t] {
+					kept = append(kept, t)
+				}
+			}
+			terms = kept
+			exprs = append(exprs, expr)
+		}
+	}
+	for _, expr := range exprs {
+		text := expr.String()
+		if _, ok := expr.(*constraint.OrExpr); ok && len(terms)+len(exprs) > 1 {
+			text = "(" + text + ")"
+		}
+		terms = append(terms, text)
+	}
+	return strings.Join(terms, " && ")
+}
+
+// declares reports whether the file declares the name at top level, as a
+// method of the receiver type if recv is not empty.
+func declares(file *ast.File, recv, name string) bool {
+	if recv == "" {
+		node, _ := findDecl(file, func(id *ast.Ident) bool { return id.Name == name })
+		return node != nil
+	}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == name && recvTypeName(fn) == recv {
+			return true
+		}
+	}
+	return false
+}
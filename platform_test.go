@@ -0,0 +1,77 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestPlatform(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"sys.go", "package sys\n", ""},
+		{"sys_linux.go", "package sys\n", "linux"},
+		{"sys_amd64.go", "package sys\n", "amd64"},
+		{"sys_linux_arm64.go", "package sys\n", "linux && arm64"},
+		{"sys_linux_test.go", "package sys\n", "linux"},
+		{"linux.go", "package sys\n", ""}, // A name that is all suffix is not a constraint.
+		{"bsd.go", "//go:build darwin || freebsd\n\npackage sys\n", "darwin || freebsd"},
+		{"zsys_linux.go", "//go:build linux\n\npackage sys\n", "linux"},
+		{"sys_linux.go", "//go:build 386 || amd64\n\npackage sys\n", "linux && (386 || amd64)"},
+		{"sys.go", "package sys\n\n//go:build linux\n", ""}, // Not a constraint after the package clause.
+	}
+	for _, test := range tests {
+		file, err := parser.ParseFile(token.NewFileSet(), test.name, test.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f := &File{name: "/src/sys/" + test.name, file: file}
+		if got := f.platform(); got != test.want {
+			t.Errorf("platform of %s with %q = %q, want %q", test.name, test.src, got, test.want)
+		}
+	}
+}
+
+func TestPlatformNote(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":             "module example.com/pl\n\ngo 1.22\n",
+		"sys/sys.go":         "package sys\n\n// Common is everywhere.\nfunc Common() {}\n",
+		"sys/sys_linux.go":   "package sys\n\n// Open opens on Linux.\nfunc Open() {}\n\n// Only is only here.\nfunc Only() {}\n",
+		"sys/sys_windows.go": "package sys\n\n// Open opens on Windows.\nfunc Open() {}\n",
+		"sys/bsd.go":         "//go:build darwin || freebsd\n\npackage sys\n\n// Open opens on BSD.\nfunc Open() {}\n",
+	})
+	tests := []struct {
+		arg  string
+		want []string // Platform lines and docs, in order.
+	}{
+		// Every variant is printed, labeled, in the order of the files' names.
+		{"sys.open", []string{"for darwin || freebsd", "// Open opens on BSD.", "for linux", "// Open opens on Linux.", "for windows", "// Open opens on Windows."}},
+		// A declaration with no variants has no label.
+		{"sys.only", []string{"// Only is only here."}},
+		{"sys.common", []string{"// Common is everywhere."}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.arg)
+		if status != 0 {
+			t.Errorf("doc %s exited with %d; stderr:\n%s", test.arg, status, stderr)
+			continue
+		}
+		var got []string
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, "for ") || strings.HasPrefix(line, "// ") {
+				got = append(got, line)
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("doc %s printed\n%s\nwant the lines\n%s", test.arg, out, strings.Join(test.want, "\n"))
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// defaultGOPROXY is the module proxy list used if GOPROXY is not set.
+const defaultGOPROXY = "https://proxy.golang.org,direct"
+
+// A proxyEntry is one entry of GOPROXY.
+type proxyEntry struct {
+	url      string // The proxy's URL, or direct or off.
+	fallBack bool   // Whether to go on to the next entry after any error, not just not found.
+}
+
+// proxyList returns the entries of GOPROXY, separated by commas or, to fall
+// back to the next entry whatever the error, by vertical bars.
+func proxyList() []proxyEntry {
+	env := os.Getenv("GOPROXY")
+	if env == "" {
+		env = defaultGOPROXY
+	}
+	var list []proxyEntry
+	for env != "" {
+		i := strings.IndexAny(env, ",|")
+		entry, fallBack := env, false
+		if i >= 0 {
+			entry, fallBack, env = env[:i], env[i] == '|', env[i+1:]
+		} else {
+			env = ""
+		}
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, proxyEntry{url: strings.TrimSuffix(entry, "/"), fallBack: fallBack})
+		}
+	}
+	return list
+}
+
+// fetchModule returns the file, such as @v/list or @v/v1.2.3.zip, of the
+// module from the module proxies, or exits if it cannot be retrieved.
+func fetchModule(modulePath, file string) []byte {
+	data, err := tryFetchModule(modulePath, file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+		exit(1)
+	}
+	return data
+}
+
+// tryFetchModule returns the file of the module from the module proxies in
+// GOPROXY, trying each in turn as the go command does. Modules matched by
+// GONOPROXY, or GOPRIVATE if it is not set, are fetched from no proxy. Doc
+// does not fetch directly from version control, so direct, like off, ends
+// the search.
+func tryFetchModule(modulePath, file string) ([]byte, error) {
+	noProxy := os.Getenv("GONOPROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("GOPRIVATE")
+	}
+	if module.MatchPrefixPatterns(noProxy, modulePath) {
+		return nil, fmt.Errorf("%s: matched by GONOPROXY; doc fetches modules only from a proxy", modulePath)
+	}
+	var err error // That of the last proxy tried.
+	for _, proxy := range proxyList() {
+		switch proxy.url {
+		case "off":
+			if err == nil {
+				err = fmt.Errorf("%s: module lookup disabled by GOPROXY=off", modulePath)
+			}
+			return nil, err
+		case "direct":
+			if err == nil {
+				err = fmt.Errorf("%s: GOPROXY=direct, but doc fetches modules only from a proxy", modulePath)
+			}
+			return nil, err
+		}
+		var escaped string
+		if escaped, err = module.EscapePath(modulePath); err != nil {
+			return nil, err
+		}
+		var data []byte
+		data, err = tryFetch(proxy.url + "/" + escaped + "/" + file)
+		if err == nil {
+			return data, nil
+		}
+		var notFound notFoundError
+		if !proxy.fallBack && !errors.As(err, &notFound) {
+			return nil, err
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("%s: GOPROXY lists no module proxy", modulePath)
+	}
+	return nil, err
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The weights of the terms in a package's score for -rank, which may be
+// set in the configuration file. See rankedSearch.
+var (
+	exactCaseWeight  = configFloat("weight.exactcase", 10)
+	stdlibWeight     = configFloat("weight.stdlib", 5)
+	pathLengthWeight = configFloat("weight.pathlength", 1)
+	popularityWeight = configFloat("weight.popularity", 2)
+	deprecatedWeight = configFloat("weight.deprecated", 10)
+)
+
+// rankedSearch looks in the directories for the name like lookInDirectory,
+// but gathers the results for each package and prints them in order of score:
+//	exactcase  if a result matches the name including case,
+//	+ stdlib   if the package is in the standard library,
+//	- pathlength × the number of elements in its import path,
+//	+ popularity × log(1 + the number of packages that import it),
+//	- deprecated if its module is deprecated or its version retracted.
+// Each term is scaled by the weight of the same name from the configuration
+// file, where it is written weight.exactcase and so on. With -why, each
+// package's results are preceded by its score and the terms that make it up.
+func (s *session) rankedSearch(dirs []string, pkg, name string) {
+	counts := importCounts(append(s.pathsFor(goRoot, ""), s.searchDirs()...))
+	goRootSrc := filepath.Join(goRoot, "src") + slash
+	type result struct {
+		path  string
+		score float64
+		terms []scoreTerm // For -why.
+		out   bytes.Buffer
+	}
+	var results []*result
+	out := s.out
+	for _, dir := range dirs {
+		r := new(result)
+		s.out, s.exactMatch = &r.out, false
+		s.lookInDirectory(dir, pkg, name)
+		if r.out.Len() == 0 {
+			continue
+		}
+		path := importPath(dir)
+		r.path = path
+		add := func(name string, value float64, note string) {
+			r.score += value
+			r.terms = append(r.terms, scoreTerm{name, value, note})
+		}
+		if s.exactMatch {
+			add("exactcase", exactCaseWeight, "")
+		}
+		if strings.HasPrefix(dir, goRootSrc) {
+			add("stdlib", stdlibWeight, "")
+		}
+		elems := strings.Count(path, "/") + 1
+		add("pathlength", -pathLengthWeight*float64(elems), fmt.Sprintf("path elements: %d", elems))
+		add("popularity", popularityWeight*math.Log1p(float64(counts[path])), fmt.Sprintf("importers: %d", counts[path]))
+		if status := s.moduleStatusOf(dir); status != nil {
+			add("deprecated", -deprecatedWeight, status.kind())
+		}
+		results = append(results, r)
+	}
+	s.out = out
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+	for _, r := range results {
+		if *whyFlag {
+			fmt.Fprint(s.out, rankWhy(r.path, r.score, r.terms))
+		}
+		s.out.Write(r.out.Bytes())
+	}
+}
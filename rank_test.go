@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// rankModule is a module with two packages declaring Thing: one imported
+// by two others, and one deep in the tree that nothing imports.
+var rankModule = map[string]string{
+	"go.mod":          "module example.com/r\n\ngo 1.22\n",
+	"popular/p.go":    "package popular\n\n// Thing is popular.\nfunc Thing() {}\n",
+	"a/b/c/deep/d.go": "package deep\n\n// THING is deep.\nfunc THING() {}\n",
+	"user1/u.go":      "package user1\n\nimport \"example.com/r/popular\"\n\nvar _ = popular.Thing\n",
+	"user2/u.go":      "package user2\n\nimport \"example.com/r/popular\"\n\nvar _ = popular.Thing\n",
+}
+
+func TestRankedSearch(t *testing.T) {
+	dir := writeModule(t, rankModule)
+	defaultFlags(t)
+	dirs := []string{filepath.Join(dir, "a", "b", "c", "deep"), filepath.Join(dir, "popular")}
+	tests := []struct {
+		name    string
+		query   string
+		weights map[*float64]float64
+		first   string // The package whose results come first.
+	}{
+		{name: "default", query: "thing", first: "popular"},
+		{name: "exact case", query: "THING", first: "deep"},
+		{name: "exact case ignored", query: "THING", weights: map[*float64]float64{&exactCaseWeight: 0}, first: "popular"},
+		{name: "long paths preferred", query: "thing", weights: map[*float64]float64{&pathLengthWeight: -10}, first: "deep"},
+		{name: "popularity ignored", query: "thing", weights: map[*float64]float64{&popularityWeight: 0, &pathLengthWeight: 0}, first: "deep"}, // A stable sort keeps the order of dirs.
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for w, value := range test.weights {
+				old := *w
+				*w = value
+				defer func() { *w = old }()
+			}
+			var out bytes.Buffer
+			newSession(&out).rankedSearch(dirs, "", test.query)
+			popular := strings.Index(out.String(), `import "example.com/r/popular"`)
+			deep := strings.Index(out.String(), `import "example.com/r/a/b/c/deep"`)
+			if popular < 0 || deep < 0 {
+				t.Fatalf("-rank printed\n%s\nwant both packages' results", out.String())
+			}
+			first := "deep"
+			if popular < deep {
+				first = "popular"
+			}
+			if first != test.first {
+				t.Errorf("-rank printed %s first, want %s; output:\n%s", first, test.first, out.String())
+			}
+		})
+	}
+}
+
+func TestConfigWeights(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config")
+	text := "# Weights for -rank.\nweight.stdlib 0.5\nweight.popularity 3 # More than the default.\nweight.popularity 4\nweight.pathlength x\n\nsearch ctx -r Context\n"
+	if err := os.WriteFile(name, []byte(text), 0666); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCCONFIG", name)
+	old := config
+	config = readConfig()
+	defer func() { config = old }()
+	tests := []struct {
+		key  string
+		def  float64
+		want float64
+	}{
+		{"weight.stdlib", 5, 0.5},
+		{"weight.popularity", 2, 4}, // The last setting wins.
+		{"weight.pathlength", 1, 1}, // Not a number.
+		{"weight.exactcase", 10, 10},
+	}
+	for _, test := range tests {
+		if got := configFloat(test.key, test.def); got != test.want {
+			t.Errorf("configFloat(%q, %v) = %v, want %v", test.key, test.def, got, test.want)
+		}
+	}
+	if got := config["search"]; len(got) != 1 || got[0] != "ctx -r Context" {
+		t.Errorf("search setting %q, want %q", got, "ctx -r Context")
+	}
+}
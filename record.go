@@ -0,0 +1,421 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/build"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// recordEnv lists the environment variables, besides GOROOT and GOPATH,
+// that affect a query, whose values -record keeps. A bundle is meant to be
+// attached to a bug report, so those naming servers, such as GOPROXY and
+// GOSUMDB, whose URLs may carry credentials, and those naming private
+// modules, such as GOPRIVATE and GONOSUMDB, are not kept.
+var recordEnv = []string{
+	"GO111MODULE", "GOFLAGS", "GOOS", "GOARCH", "CGO_ENABLED", "GOWORK", "NO_COLOR",
+}
+
+// replayEnv lists the variables of recordEnv that -replay restores. The
+// others could have the go command run programs, as GOFLAGS=-toolexec=prog
+// does, or fetch from a server of the bundle's choosing, and a bundle
+// comes from someone else.
+var replayEnv = []string{"GO111MODULE", "GOOS", "GOARCH", "NO_COLOR"}
+
+// replayRefused lists the flags a replayed query may not use, as they run
+// commands, use the network or write files.
+var replayRefused = map[string]bool{
+	"clip":      true,
+	"cover-run": true,
+	"force":     true,
+	"history":   true,
+	"manifest":  true,
+	"matrix":    true,
+	"migrate":   true,
+	"o":         true,
+	"out":       true,
+	"perf":      true,
+	"record":    true,
+	"replay":    true,
+	"repo":      true,
+	"snapshot":  true,
+	"stale":     true,
+	"update":    true,
+}
+
+// replaying reports whether this query is being run by -replay.
+var replaying = os.Getenv("DOCREPLAY") != ""
+
+// goRoot is the GOROOT whose source doc reads. When replaying, that is the
+// bundle's copy of it, named by $DOCGOROOT, but the go command, and so go
+// list and the export data it builds, keeps the real one: given another,
+// it would run that tree's tools and read that tree's go.env.
+var goRoot = cmp.Or(replayGOROOT(), runtime.GOROOT())
+
+// replayGOROOT returns the bundle's GOROOT if this query is being replayed.
+func replayGOROOT() string {
+	if !replaying {
+		return ""
+	}
+	return os.Getenv("DOCGOROOT")
+}
+
+// checkReplayFlags exits if the query is being replayed and sets a flag in
+// replayRefused or turns off -offline, which replay sets. It is called once
+// the arguments, with any saved searches from the bundle's configuration
+// expanded, have been parsed.
+func checkReplayFlags() {
+	if !replaying {
+		return
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if replayRefused[f.Name] {
+			fmt.Fprintf(os.Stderr, "doc: -replay: the recorded query sets -%s, which is not replayed\n", f.Name)
+			exit(2)
+		}
+	})
+	if !*offlineFlag {
+		fmt.Fprintf(os.Stderr, "doc: -replay: the recorded query sets -offline=false, which is not replayed\n")
+		exit(2)
+	}
+}
+
+// A recording is the query kept in a -record bundle, as query.json. The
+// bundle also holds what the query printed, as output.txt, and the files
+// it read, under files/ by their absolute names.
+type recording struct {
+	Args   []string          // The arguments, less -record.
+	Dir    string            // The working directory.
+	GOROOT string            // The GOROOT searched.
+	GOPATH []string          // The GOPATH searched.
+	Config string            `json:",omitempty"` // The configuration file, if there was one.
+	Env    map[string]string // The other variables of recordEnv that were set.
+	Status int               // The exit status.
+}
+
+// recordList is where, when a query runs under -record, the names of the
+// files it parses are written, one a line.
+var recordList = openRecordList()
+
+func openRecordList() io.Writer {
+	name := os.Getenv("DOCRECORD")
+	if name == "" {
+		return nil
+	}
+	fd, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return nil
+	}
+	return fd // Written unbuffered, so nothing is lost if the query exits.
+}
+
+// recordRead notes, for -record, that the file was read.
+func recordRead(name string) {
+	if recordList != nil {
+		if abs, err := filepath.Abs(name); err == nil {
+			fmt.Fprintln(recordList, abs)
+		}
+	}
+}
+
+// record runs the query given by the arguments, as usual, and writes to
+// the named zip file a bundle from which -replay runs it again elsewhere:
+// the arguments, the environment, the output, and the files the query read.
+// It exits with the query's status.
+func record(bundle string, args []string) {
+	list, err := os.CreateTemp("", "docrecord")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -record: %s\n", err)
+		exit(1)
+	}
+	list.Close()
+	defer os.Remove(list.Name())
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -record: %s\n", err)
+		exit(1)
+	}
+	var output bytes.Buffer
+	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(), "DOCRECORD="+list.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &output)
+	status := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			fmt.Fprintf(os.Stderr, "doc: -record: %s\n", err)
+			exit(1)
+		}
+		status = exitErr.ExitCode()
+	}
+
+	dir, _ := os.Getwd()
+	rec := recording{
+		Args:   args,
+		Dir:    dir,
+		GOROOT: runtime.GOROOT(),
+		GOPATH: filepath.SplitList(build.Default.GOPATH),
+		Env:    make(map[string]string),
+		Status: status,
+	}
+	for _, key := range recordEnv {
+		if value, ok := os.LookupEnv(key); ok {
+			rec.Env[key] = value
+		}
+	}
+	files := map[string]bool{filepath.Join(rec.GOROOT, "VERSION"): true}
+	if modDir != "" {
+		files[filepath.Join(modDir, "go.mod")] = true
+	}
+	if name := configPath(); name != "" {
+		if _, err := os.Stat(name); err == nil {
+			rec.Config = name
+			files[name] = true
+		}
+	}
+	if data, err := os.ReadFile(list.Name()); err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			files[scanner.Text()] = true
+		}
+	}
+	if err := writeBundle(bundle, rec, output.Bytes(), files); err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -record: %s\n", err)
+		exit(1)
+	}
+	exit(status)
+}
+
+// writeBundle writes the bundle for record. Files that cannot be read, such
+// as those that were removed, are left out.
+func writeBundle(bundle string, rec recording, output []byte, files map[string]bool) error {
+	fd, err := os.Create(bundle)
+	if err != nil {
+		return err
+	}
+	z := zip.NewWriter(fd)
+	add := func(name string, data []byte) error {
+		w, err := z.Create(name)
+		if err == nil {
+			_, err = w.Write(data)
+		}
+		return err
+	}
+	query, _ := json.MarshalIndent(rec, "", "\t")
+	err = add("query.json", append(query, '\n'))
+	if err == nil {
+		err = add("output.txt", output)
+	}
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, readErr := os.ReadFile(name)
+		if readErr != nil || err != nil {
+			continue
+		}
+		err = add(bundlePath(name), data)
+	}
+	if cerr := z.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := fd.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// bundlePath returns the name in a bundle of the file with the absolute name.
+func bundlePath(name string) string {
+	name = strings.TrimPrefix(name, filepath.VolumeName(name))
+	return "files/" + strings.TrimPrefix(filepath.ToSlash(name), "/")
+}
+
+// unpackedPath returns the name, in the directory a bundle was unpacked in,
+// of the file or directory with the recorded name. The recorded names come
+// from the bundle, so, like the names of its files, they may not lead out of
+// the directory.
+func unpackedPath(dir, name string) (string, error) {
+	path := filepath.FromSlash(bundlePath(name))
+	if !filepath.IsLocal(path) {
+		return "", fmt.Errorf("bad file name %q", name)
+	}
+	return filepath.Join(dir, path), nil
+}
+
+// replay runs again the query recorded in the bundle, with the recorded
+// files in place of the trees it searched. Only the files the query read are
+// there, and not the compiled standard library, so type information from
+// imported packages may be missing. The query runs offline, without cgo and
+// with the go command confined to the files there, and may not use the
+// flags in replayRefused.
+func replay(bundle string) {
+	tmp, err := os.MkdirTemp("", "docreplay")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -replay: %s\n", err)
+		exit(1)
+	}
+	rec, err := unpackBundle(bundle, tmp)
+	if err != nil {
+		os.RemoveAll(tmp)
+		fmt.Fprintf(os.Stderr, "doc: -replay: %s\n", err)
+		exit(1)
+	}
+	local := func(name string) string {
+		path, err := unpackedPath(tmp, name)
+		if err != nil {
+			os.RemoveAll(tmp)
+			fmt.Fprintf(os.Stderr, "doc: -replay: %s: %s\n", bundle, err)
+			exit(1)
+		}
+		return path
+	}
+	var gopath []string
+	for _, dir := range rec.GOPATH {
+		gopath = append(gopath, local(dir))
+	}
+	config := os.DevNull // Not the configuration of the one replaying.
+	if rec.Config != "" {
+		config = local(rec.Config)
+	}
+	set := map[string]string{
+		"GOROOT":      runtime.GOROOT(),
+		"DOCGOROOT":   local(rec.GOROOT),
+		"GOPATH":      strings.Join(gopath, string(filepath.ListSeparator)),
+		"DOCCONFIG":   config,
+		"DOCREPLAY":   "1",
+		"DOCRECORD":   "",
+		"GOENV":       "off",
+		"GOFLAGS":     "-buildvcs=false",
+		"GOPROXY":     "off",
+		"GOTOOLCHAIN": "local",
+		"GOWORK":      "off",
+		"CGO_ENABLED": "0",
+	}
+	for _, key := range replayEnv {
+		if value, ok := rec.Env[key]; ok {
+			set[key] = value
+		}
+	}
+	var env []string
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if _, ok := set[key]; !ok {
+			env = append(env, kv)
+		}
+	}
+	for key, value := range set {
+		env = append(env, key+"="+value)
+	}
+	dir := local(rec.Dir)
+	os.MkdirAll(dir, 0777)
+
+	exe, err := os.Executable()
+	if err != nil {
+		os.RemoveAll(tmp)
+		fmt.Fprintf(os.Stderr, "doc: -replay: %s\n", err)
+		exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "doc: replaying doc %s in %s; it exited with status %d\n", strings.Join(rec.Args, " "), rec.Dir, rec.Status)
+	cmd := exec.Command(exe, append([]string{"-offline"}, rec.Args...)...)
+	cmd.Env, cmd.Dir = env, dir
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	err = cmd.Run()
+	os.RemoveAll(tmp)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -replay: %s\n", err)
+		exit(1)
+	}
+}
+
+// unpackBundle extracts the files of the bundle into dir and returns its
+// query. Like a module zip file, the bundle may unpack to at most maxZipSize
+// bytes.
+func unpackBundle(bundle, dir string) (recording, error) {
+	var rec recording
+	z, err := zip.OpenReader(bundle)
+	if err != nil {
+		return rec, err
+	}
+	defer z.Close()
+	var size int64
+	for _, zf := range z.File {
+		name := filepath.FromSlash(zf.Name)
+		if !filepath.IsLocal(name) {
+			return rec, fmt.Errorf("%s: bad file name %q", bundle, zf.Name)
+		}
+		r, err := zf.Open()
+		if err != nil {
+			return rec, err
+		}
+		data, err := io.ReadAll(io.LimitReader(r, maxZipSize-size+1))
+		r.Close()
+		if err != nil {
+			return rec, err
+		}
+		if size += int64(len(data)); size > maxZipSize {
+			return rec, fmt.Errorf("%s: unpacks to more than %d bytes", bundle, maxZipSize)
+		}
+		if zf.Name == "query.json" {
+			if err := json.Unmarshal(data, &rec); err != nil {
+				return rec, fmt.Errorf("%s: query.json: %s", bundle, err)
+			}
+			continue
+		}
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			return rec, err
+		}
+		if err := os.WriteFile(name, data, 0666); err != nil {
+			return rec, err
+		}
+	}
+	if rec.Args == nil {
+		return rec, fmt.Errorf("%s: no query.json", bundle)
+	}
+	return rec, nil
+}
+
+// withoutFlag returns the arguments without the named string flag and its value.
+func withoutFlag(args []string, name string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return append(out, args[i:]...)
+		}
+		trimmed := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		switch {
+		case trimmed == name:
+			i++ // Skip the value too.
+		case strings.HasPrefix(trimmed, name+"="):
+		default:
+			out = append(out, arg)
+		}
+	}
+	return out
+}
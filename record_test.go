@@ -0,0 +1,143 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnpackedPath(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"/home/u/src/m", true},
+		{"/usr/local/go", true},
+		{"/", true},
+		{"/../../../../tmp/escaped", false},
+		{"/home/../../../x", false},
+		{"../../x", false},
+		{"/home/../x", true}, // Still within.
+	}
+	for _, test := range tests {
+		path, err := unpackedPath(dir, test.name)
+		if (err == nil) != test.ok {
+			t.Errorf("unpackedPath(%q) error %v; want ok %t", test.name, err, test.ok)
+			continue
+		}
+		if err == nil && !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			t.Errorf("unpackedPath(%q) = %q, outside %s", test.name, path, dir)
+		}
+	}
+}
+
+// writeTestBundle writes a bundle holding the named files and returns its name.
+func writeTestBundle(t *testing.T, files map[string]string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "bundle.zip")
+	fd, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := zip.NewWriter(fd)
+	for file, data := range files {
+		w, err := z.Create(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(data))
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	fd.Close()
+	return name
+}
+
+func TestUnpackBundle(t *testing.T) {
+	query := `{"Args":["fmt"],"Dir":"/src"}`
+	bundle := writeTestBundle(t, map[string]string{
+		"query.json":       query,
+		"files/src/x.go":   "package x\n",
+		"files/go/VERSION": "go1.99\n",
+	})
+	dir := t.TempDir()
+	rec, err := unpackBundle(bundle, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Args) != 1 || rec.Args[0] != "fmt" || rec.Dir != "/src" {
+		t.Errorf("unpackBundle query = %+v", rec)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "files", "src", "x.go")); err != nil || string(data) != "package x\n" {
+		t.Errorf("files/src/x.go = %q, %v", data, err)
+	}
+
+	for _, bad := range []string{"../x.go", "/etc/x.go", "files/../../x.go"} {
+		bundle := writeTestBundle(t, map[string]string{"query.json": query, bad: ""})
+		if _, err := unpackBundle(bundle, t.TempDir()); err == nil {
+			t.Errorf("unpackBundle with %q: no error", bad)
+		}
+	}
+	if _, err := unpackBundle(writeTestBundle(t, map[string]string{"files/x.go": ""}), t.TempDir()); err == nil {
+		t.Errorf("unpackBundle without query.json: no error")
+	}
+
+	// The limit is on the total size, not that of each file.
+	defer func(size int64) { maxZipSize = size }(maxZipSize)
+	maxZipSize = 100
+	files := map[string]string{"query.json": query}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		files["files/"+name+".go"] = strings.Repeat("x", 30)
+	}
+	if _, err := unpackBundle(writeTestBundle(t, files), t.TempDir()); err == nil {
+		t.Errorf("unpackBundle of %d bytes in files of 30: no error", 4*30+len(query))
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	goFile := filepath.Join(src, "x.go")
+	if err := os.WriteFile(goFile, []byte("package x\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	rec := recording{
+		Args:   []string{"x.X"},
+		Dir:    src,
+		GOROOT: filepath.Join(src, "goroot"),
+		Env:    map[string]string{"GOOS": "plan9"},
+		Status: 1,
+	}
+	files := map[string]bool{goFile: true, filepath.Join(src, "removed.go"): true}
+	bundle := filepath.Join(t.TempDir(), "bundle.zip")
+	if err := writeBundle(bundle, rec, []byte("output\n"), files); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	got, err := unpackBundle(bundle, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Dir != rec.Dir || got.GOROOT != rec.GOROOT || got.Status != 1 || got.Env["GOOS"] != "plan9" || len(got.Args) != 1 {
+		t.Errorf("unpacked query %+v, want %+v", got, rec)
+	}
+	// Replay finds the recorded files under their recorded names.
+	name, err := unpackedPath(dir, goFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(name); err != nil || string(data) != "package x\n" {
+		t.Errorf("replayed %s = %q, %v", goFile, data, err)
+	}
+	name, _ = unpackedPath(dir, filepath.Join(src, "removed.go"))
+	if _, err := os.Stat(name); err == nil {
+		t.Errorf("bundle holds %s, which could not be read", name)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// renameImpact prints every reference to the exported name, given as pkg.Name,
+// that would need updating if it were renamed: the uses within its own package
+// and the references from other packages in the module and GOPATH. They are
+// grouped by the module holding them, with counts.
+func (s *session) renameImpact(arg string) {
+	dot := strings.LastIndex(arg, ".")
+	if dot < 0 {
+		usage()
+	}
+	pkg, name := arg[:dot], arg[dot+1:]
+	byModule := make(map[string][]string)
+	add := func(dir string, pos token.Position) {
+		mod := moduleOf(dir)
+		byModule[mod] = append(byModule[mod], fmt.Sprintf("%s:%d", pos.Filename, pos.Line))
+	}
+	targets := make(map[string]bool) // Import paths of the packages declaring name.
+	for _, dir := range s.packageDirs(pkg) {
+		fset := token.NewFileSet()
+		notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+		pkgs, _ := parseDir(fset, dir, notTest, 0) // Ignore the error.
+		for _, astPkg := range pkgs {
+			typesPkg, info := typeCheck(fset, astPkg)
+			obj := typesPkg.Scope().Lookup(name)
+			if obj == nil {
+				continue
+			}
+			targets[importPath(dir)] = true
+			for id, use := range info.Uses {
+				if use == obj {
+					add(dir, fset.Position(id.Pos()))
+				}
+			}
+		}
+	}
+	if len(targets) == 0 {
+		fmt.Fprintf(os.Stderr, "doc: -rename-impact: no package %s declares %s\n", pkg, name)
+		exit(1)
+	}
+	walkReferences(s.searchDirs(), 0, func(ref reference) {
+		if targets[ref.path] && ref.name == name && ref.from != ref.path {
+			add(filepath.Dir(ref.pos.Filename), ref.pos)
+		}
+	})
+
+	var mods []string
+	for mod := range byModule {
+		mods = append(mods, mod)
+	}
+	sort.Strings(mods)
+	total := 0
+	for _, mod := range mods {
+		refs := byModule[mod]
+		sort.Strings(refs)
+		total += len(refs)
+		fmt.Fprintf(s.out, "%s (%d)\n", mod, len(refs))
+		for _, ref := range refs {
+			fmt.Fprintf(s.out, "\t%s\n", ref)
+		}
+	}
+	fmt.Fprintf(s.out, "%d references in %d modules\n", total, len(mods))
+}
+
+// moduleOf returns the path of the module holding the directory: "std" for
+// the standard library, or the import path of the directory itself for a
+// package in GOPATH that is in no module.
+func moduleOf(dir string) string {
+	goRootSrc := filepath.Join(goRoot, "src") + slash
+	if strings.HasPrefix(dir, goRootSrc) {
+		return "std"
+	}
+	for d := dir; ; {
+		if p := modulePathIn(filepath.Join(d, "go.mod")); p != "" {
+			return p
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return importPath(dir)
+		}
+		d = parent
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// lookInRepo looks for the named exported identifier in the packages named
+// pkg, or in every package if pkg is empty, of the repository at the URL,
+// which is searched instead of the module, GOROOT and GOPATH.
+func (s *session) lookInRepo(url, pkg, name string) {
+	_, modulePath, err := repoURL(url)
+	if err == nil {
+		err = module.CheckPath(modulePath)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -repo: %s\n", err)
+		exit(2)
+	}
+	root, err := repoSource(url, modulePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -repo: %s\n", err)
+		exit(1)
+	}
+	s.walkDirs(root, "", func(dir string) bool {
+		elem := filepath.Base(dir)
+		if dir == root {
+			elem = path.Base(modulePath) // The cache adds a version.
+		}
+		if pkg == "" || elem == pkg {
+			s.lookInDirectory(dir, pkg, name)
+		}
+		return true
+	})
+}
+
+// repoSource returns a directory in the cache holding the source of the
+// repository at the URL. The latest version of the module, whose path is
+// the URL's, is taken from the module cache or else from the module proxies
+// of GOPROXY; if none has it, the repository is cloned with git. A clone
+// is kept until it is removed from the cache.
+func repoSource(url, modulePath string) (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	cache = filepath.Join(cache, "doc", "repo")
+
+	var info struct{ Version string }
+	data, err := tryFetchModule(modulePath, "@latest")
+	if err == nil && json.Unmarshal(data, &info) == nil && info.Version != "" {
+		req := requirement{path: modulePath, version: info.Version}
+		dir, err := moduleDir(moduleCacheDir(), req.path, req.version)
+		if err != nil {
+			return "", err
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			stats.cacheHits.Add(1)
+			return dir, nil
+		}
+		dir, _ = moduleDir(cache, req.path, req.version)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			stats.cacheHits.Add(1)
+			return dir, nil
+		}
+		data, err := tryFetchModule(req.path, "@v/"+req.version+".zip")
+		if err != nil {
+			return "", err
+		}
+		if err := verifyZip(req.path, req.version, data, ""); err != nil {
+			return "", err
+		}
+		return dir, fill(dir, func(tmp string) error { return unpackModule(req, data, tmp) })
+	}
+
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cache, escaped+"@git")
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		stats.cacheHits.Add(1)
+		return dir, nil
+	}
+	if *offlineFlag {
+		return "", fmt.Errorf("%s: offline, and not in the cache", url)
+	}
+	scheme, err := cloneScheme(url)
+	if err != nil {
+		return "", err
+	}
+	return dir, fill(dir, func(tmp string) error {
+		cmd := exec.Command("git", "clone", "--quiet", "--depth", "1", "--", url, tmp)
+		cmd.Env = append(os.Environ(), "GIT_ALLOW_PROTOCOL="+scheme) // No redirection to other schemes.
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("git clone %s: %s", url, err)
+		}
+		return nil
+	})
+}
+
+// defaultRepoVCS is the rule for cloning that applies after those set in the
+// configuration file: public repositories may be cloned only over https, and
+// private ones, those matched by GOPRIVATE, over ssh as well.
+const defaultRepoVCS = "public:https,private:https|ssh"
+
+// cloneScheme returns the scheme of the URL, such as https, if the rules in
+// the configuration file's repo.vcs lines, followed by defaultRepoVCS, allow
+// the repository to be cloned with it. As in GOVCS, each rule is
+// pattern:schemes, where the pattern is a glob matching a prefix of the
+// repository's host and path, as in GOPRIVATE, or public or private, and the
+// schemes are separated by | or are all or off. The first rule whose pattern
+// matches decides; if none does, the repository may not be cloned.
+func cloneScheme(url string) (string, error) {
+	scheme, repoPath, err := repoURL(url)
+	if err != nil {
+		return "", err
+	}
+	private := module.MatchPrefixPatterns(os.Getenv("GOPRIVATE"), repoPath)
+	rules := strings.Join(append(config["repo.vcs"], defaultRepoVCS), ",")
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		i := strings.LastIndex(rule, ":")
+		if i < 0 {
+			if rule != "" {
+				return "", fmt.Errorf("repo.vcs: malformed rule %q", rule)
+			}
+			continue
+		}
+		pattern, schemes := rule[:i], rule[i+1:]
+		ok := false
+		switch pattern {
+		case "public":
+			ok = !private
+		case "private":
+			ok = private
+		default:
+			ok = module.MatchPrefixPatterns(pattern, repoPath)
+		}
+		if !ok {
+			continue
+		}
+		for _, s := range strings.Split(schemes, "|") {
+			if s == "all" || s == scheme {
+				return scheme, nil
+			}
+		}
+		break
+	}
+	return "", fmt.Errorf("%s: cloning %s over %s is not allowed by repo.vcs", url, repoPath, scheme)
+}
+
+// repoURL splits the URL of a repository into its scheme, in lower case,
+// and its host and path, without user, port or .git suffix, as in
+// github.com/user/repo. The scp-like form user@host:path is taken as ssh.
+// A URL beginning with - is refused, as git would take it for an option.
+func repoURL(url string) (scheme, repoPath string, err error) {
+	if strings.HasPrefix(url, "-") {
+		return "", "", fmt.Errorf("%s: not a URL", url)
+	}
+	scheme, rest, ok := strings.Cut(url, "://")
+	if ok {
+		host, path, _ := strings.Cut(rest, "/")
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i] // The port.
+		}
+		rest = host + "/" + path
+	} else if host, path, ok := strings.Cut(url, ":"); ok && !strings.Contains(host, "/") {
+		// The scp-like form, user@host:path.
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+		scheme, rest = "ssh", host+"/"+path
+	} else {
+		return "", "", fmt.Errorf("%s: not a URL", url)
+	}
+	return strings.ToLower(scheme), strings.TrimSuffix(strings.TrimSuffix(rest, "/"), ".git"), nil
+}
+
+// fill creates the directory by calling write to populate a temporary
+// directory beside it and renaming that into place, so the cache never
+// holds a partial copy.
+func fill(dir string, write func(tmp string) error) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0777); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), "tmp")
+	if err != nil {
+		return err
+	}
+	if err := write(tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	return nil
+}
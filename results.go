@@ -0,0 +1,290 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// A result records where a search result was found, so -show can find it
+// again, and what was printed for it, for -out json.
+type result struct {
+	Name         string
+	File         string
+	Line         int
+	Package      string `json:",omitempty"` // Import path.
+	Kind         string `json:",omitempty"` // "const", "var", "func", "method" or "type".
+	URL          string `json:",omitempty"`
+	Text         string `json:",omitempty"` // Declaration and doc comment, as printed.
+	Root         string `json:",omitempty"` // "std", "module", "workspace" or "vendored"; see rootCategory.
+	Visibility   string `json:",omitempty"` // "exported", "internal", "test" or "main"; see visibility.
+	ModuleStatus string `json:",omitempty"` // "deprecated" or "retracted"; see moduleStatusOf.
+	Stability    string `json:",omitempty"` // Such as "stable" or "experimental"; see File.stability.
+}
+
+// schemaVersion is the version of the format of the JSON that -out writes,
+// described by resultSchema. Within a version, fields are only ever added,
+// and a program reading the JSON should ignore those it does not know.
+// Renaming or removing a field, or changing its type or meaning, means a
+// new version.
+const schemaVersion = 1
+
+// A jsonResults is what -out json=file writes.
+type jsonResults struct {
+	SchemaVersion int      `json:"schemaVersion"`
+	Results       []result `json:"results"`
+}
+
+// resultSchema is the JSON Schema of the output of -out json=file, printed by -schema.
+const resultSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://robpike.io/cmd/doc/results.v1.json",
+	"title": "doc results",
+	"type": "object",
+	"required": ["schemaVersion", "results"],
+	"properties": {
+		"schemaVersion": {"const": 1},
+		"results": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"required": ["Name", "File", "Line"],
+				"properties": {
+					"Name": {"type": "string"},
+					"File": {"type": "string"},
+					"Line": {"type": "integer"},
+					"Package": {"type": "string", "description": "Import path."},
+					"Kind": {"enum": ["const", "var", "func", "method", "type"]},
+					"URL": {"type": "string"},
+					"Text": {"type": "string", "description": "Declaration and doc comment, as printed."},
+					"Root": {"enum": ["std", "module", "workspace", "vendored"], "description": "Category of the root holding the package."},
+					"Visibility": {"enum": ["exported", "internal", "test", "main"], "description": "Who can use the symbol: any importer, the importers an internal directory allows, only tests, or no one, being in a command."},
+					"ModuleStatus": {"enum": ["deprecated", "retracted"], "description": "Whether the module is deprecated, or the version holding the symbol retracted, by the go.mod file of the module's latest version."},
+					"Stability": {"type": "string", "description": "The stability tier given the symbol, its type or its package, such as stable, beta or experimental."}
+				}
+			}
+		}
+	}
+}
+`
+
+var kindName = [numKinds]string{"const", "var", "func", "type"}
+
+// resultKind returns the name of the kind of declaration the node represents.
+func resultKind(node ast.Node) string {
+	if fn, ok := node.(*ast.FuncDecl); ok && fn.Recv != nil {
+		return "method"
+	}
+	return kindName[nodeKind(node)]
+}
+
+// visibility returns who can use an exported symbol declared in the named
+// file of the package with the name and import path: "test" if only the
+// package's tests, "main" if it is in a command, which cannot be imported,
+// "internal" if only the packages an internal element of its import path
+// allows, and otherwise "exported". The second result, for "internal", is
+// the import path of the tree that may import it. A package with no import
+// path, given by its directory, has no internal elements to heed.
+func visibility(fileName, pkgName, pkgPath string) (string, string) {
+	switch {
+	case strings.HasSuffix(fileName, "_test.go"):
+		return "test", ""
+	case pkgName == "main":
+		return "main", ""
+	case filepath.IsAbs(filepath.FromSlash(pkgPath)):
+		return "exported", ""
+	}
+	switch i := strings.LastIndex("/"+pkgPath+"/", "/internal/"); {
+	case i == 0:
+		return "internal", "" // A top-level internal package, as in GOROOT.
+	case i > 0:
+		return "internal", pkgPath[:i-1]
+	}
+	return "exported", ""
+}
+
+// visibilityNote returns, for a symbol that not every program can use, a
+// line saying which can, to be printed with its documentation.
+func visibilityNote(fileName, pkgName, pkgPath string) string {
+	switch vis, tree := visibility(fileName, pkgName, pkgPath); vis {
+	case "test":
+		return "test only\n"
+	case "main":
+		return "in a command\n"
+	case "internal":
+		if tree == "" {
+			return "internal\n"
+		}
+		return fmt.Sprintf("internal to %s\n", tree)
+	}
+	return ""
+}
+
+// A gobHeader is the first value in the stream that -out gob=file writes.
+// The results follow it, each a gob of a result.
+type gobHeader struct {
+	SchemaVersion int
+}
+
+// setOutputs interprets the -out flag, a comma-separated list of targets:
+// stdout, for the usual rendering, json=file, for the results as JSON, and
+// gob=file, for the results as a stream of gobs, written as they are found.
+func (s *session) setOutputs(targets string) {
+	s.out = io.Discard
+	for _, target := range strings.Split(targets, ",") {
+		switch {
+		case target == "stdout":
+			s.out = os.Stdout
+		case strings.HasPrefix(target, "json="):
+			s.jsonOut = strings.TrimPrefix(target, "json=")
+		case strings.HasPrefix(target, "gob="):
+			fd, err := os.Create(strings.TrimPrefix(target, "gob="))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "doc: -out: %s\n", err)
+				exit(1)
+			}
+			// The file is written unbuffered, a gob at a time, so it is
+			// complete however the query ends.
+			s.gobFile, s.gobOut = fd, gob.NewEncoder(fd)
+			s.encode(gobHeader{SchemaVersion: schemaVersion})
+		default:
+			fmt.Fprintf(os.Stderr, "doc: -out: unknown target %q\n", target)
+			exit(2)
+		}
+	}
+}
+
+// closeOutputs closes the file of -out gob=file, if there is one, when the
+// query ends, however it ends. As it is deferred, it does not exit on
+// failure, which would skip main's other deferred calls, but reports the
+// error and sets the status with which recoverCrash ends doc.
+func (s *session) closeOutputs() {
+	if s.gobFile == nil {
+		return
+	}
+	err := s.gobFile.Close()
+	s.gobFile, s.gobOut = nil, nil
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -out: %s\n", err)
+		s.status = max(s.status, 1)
+	}
+}
+
+// record records the result and returns its number, formatted for
+// printing with the category of its root, if results are being numbered.
+func (s *session) record(r result) string {
+	if s.gobOut != nil {
+		s.encode(r)
+	}
+	if !s.numberResults && s.jsonOut == "" && s.compareTo == "" {
+		return ""
+	}
+	s.results = append(s.results, r)
+	if !s.numberResults {
+		return ""
+	}
+	if r.Root != "" {
+		return fmt.Sprintf("[%d] %s ", len(s.results), r.Root)
+	}
+	return fmt.Sprintf("[%d] ", len(s.results))
+}
+
+// encode writes the value to the gob stream of -out gob=file.
+func (s *session) encode(v any) {
+	if err := s.gobOut.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -out: %s\n", err)
+		exit(1)
+	}
+}
+
+// resultsPath returns the name of the file holding the results of the last numbered search.
+func resultsPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "doc", "results.json")
+}
+
+// saveResults writes the numbered results to the results file for -show,
+// and all the results to the JSON file named by -out or -snapshot, and
+// compares them with the snapshot named by -compare.
+func (s *session) saveResults() {
+	if s.jsonOut != "" {
+		out := jsonResults{SchemaVersion: schemaVersion, Results: s.results}
+		if out.Results == nil {
+			out.Results = []result{} // An empty list, not null.
+		}
+		data, _ := json.MarshalIndent(out, "", "\t")
+		if err := os.WriteFile(s.jsonOut, append(data, '\n'), 0666); err != nil {
+			fmt.Fprintf(os.Stderr, "doc: -out: %s\n", err)
+			exit(1)
+		}
+	}
+	if s.compareTo != "" {
+		s.compareResults()
+	}
+	name := resultsPath()
+	if !s.numberResults || name == "" || len(s.results) == 0 {
+		return
+	}
+	saved := make([]savedResult, len(s.results))
+	for i, r := range s.results {
+		saved[i] = savedResult{Name: r.Name, File: r.File, Line: r.Line, Source: s.source}
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(name), 0700) == nil {
+		os.WriteFile(name, data, 0600)
+	}
+}
+
+// A savedResult is a numbered result as saved for -show.
+type savedResult struct {
+	Name   string
+	File   string
+	Line   int
+	Source string `json:",omitempty"` // The flag, such as -archive x.zip, if File is not on disk; see session.source.
+}
+
+// show prints in full, with its body and examples, the result with the
+// number from the last numbered search. A result from files that are not
+// on disk, those of -archive and -stdin, or whose file is gone, as a copy
+// of -repo's may be, cannot be shown.
+func (s *session) show(arg string) {
+	var saved []savedResult
+	data, err := os.ReadFile(resultsPath())
+	if err == nil {
+		err = json.Unmarshal(data, &saved)
+	}
+	n, nerr := strconv.Atoi(arg)
+	if err != nil || nerr != nil || n < 1 || n > len(saved) {
+		fmt.Fprintf(os.Stderr, "doc: -show: no result %s from the last search\n", arg)
+		exit(2)
+	}
+	r := saved[n-1]
+	if _, err := os.Stat(r.File); err != nil {
+		if r.Source != "" {
+			fmt.Fprintf(os.Stderr, "doc: -show: result %d is from %s, whose files doc cannot read again; repeat that search\n", n, r.Source)
+		} else {
+			fmt.Fprintf(os.Stderr, "doc: -show: result %d is in %s, which is gone\n", n, r.File)
+		}
+		exit(1)
+	}
+	s.full, s.verboseDoc = true, true
+	s.showAt = token.Position{Filename: r.File, Line: r.Line}
+	s.lookInDirectory(filepath.Dir(r.File), "", r.Name)
+}
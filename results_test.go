@@ -0,0 +1,227 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOutFormats(t *testing.T) {
+	defaultFlags(t)
+	dir := writeModule(t, testModule)
+	tests := []struct {
+		name    string // Of the subtest, whose temporary directory must hold no comma.
+		targets string
+		stdout  bool
+		json    bool
+		gob     bool
+	}{
+		{name: "stdout", targets: "stdout", stdout: true},
+		{name: "json", targets: "json=RESULTS", json: true},
+		{name: "gob", targets: "gob=RESULTS", gob: true},
+		{name: "all", targets: "stdout,json=RESULTS.json,gob=RESULTS.gob", stdout: true, json: true, gob: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			s := newSession(new(bytes.Buffer))
+			s.setOutputs(strings.ReplaceAll(test.targets, "RESULTS", filepath.Join(tmp, "results")))
+			if got := s.out == os.Stdout; got != test.stdout {
+				t.Errorf("writes to stdout: %t, want %t", got, test.stdout)
+			}
+			s.out = io.Discard
+			s.lookInDirectory(dir, "", "Config")
+			s.saveResults()
+			s.closeOutputs()
+			if s.gobFile != nil || s.gobOut != nil {
+				t.Errorf("closeOutputs left the gob file open")
+			}
+			var results []result
+			if test.json {
+				name := filepath.Join(tmp, "results")
+				if test.gob {
+					name += ".json"
+				}
+				var out jsonResults
+				data, err := os.ReadFile(name)
+				if err == nil {
+					err = json.Unmarshal(data, &out)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if out.SchemaVersion != schemaVersion {
+					t.Errorf("JSON schema version %d, want %d", out.SchemaVersion, schemaVersion)
+				}
+				results = append(results, out.Results...)
+			}
+			if test.gob {
+				name := filepath.Join(tmp, "results")
+				if test.json {
+					name += ".gob"
+				}
+				f, err := os.Open(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+				dec := gob.NewDecoder(f)
+				var header gobHeader
+				if err := dec.Decode(&header); err != nil || header.SchemaVersion != schemaVersion {
+					t.Errorf("gob header %+v, %v; want schema version %d", header, err, schemaVersion)
+				}
+				for {
+					var r result
+					if err := dec.Decode(&r); err == io.EOF {
+						break
+					} else if err != nil {
+						t.Fatal(err)
+					}
+					results = append(results, r)
+				}
+			}
+			for _, r := range results {
+				if r.Name != "Config" || r.Kind != "type" || r.Line != 5 || r.Package != "example.com/m/v2" || !strings.Contains(r.Text, "type Config struct") {
+					t.Errorf("result %+v, want type Config of example.com/m/v2 at line 5", r)
+				}
+			}
+			want := 0
+			if test.json {
+				want++
+			}
+			if test.gob {
+				want++
+			}
+			if len(results) != want {
+				t.Errorf("%d results written, want %d", len(results), want)
+			}
+		})
+	}
+}
+
+func TestCloseOutputsFailure(t *testing.T) {
+	s := newSession(io.Discard)
+	s.setOutputs("gob=" + filepath.Join(t.TempDir(), "results"))
+	s.gobFile.Close() // So that closing it again fails.
+	s.closeOutputs()
+	if s.status != 1 {
+		t.Errorf("status after a failed close is %d, want 1", s.status)
+	}
+	if s.gobFile != nil || s.gobOut != nil {
+		t.Errorf("closeOutputs left the gob file set")
+	}
+}
+
+func TestShowSource(t *testing.T) {
+	defaultFlags(t)
+	dir := writeModule(t, testModule)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	file := filepath.Join(dir, "m.go")
+	tests := []struct {
+		file, source string
+		status       int // With which show exits, or 0 if it prints the result.
+	}{
+		{file, "", 0},
+		{file, "-repo https://example.com/m", 0},
+		{filepath.Join(dir, "gone.go"), "", 1},
+		{filepath.Join(dir, "m.zip", "m.go"), "-archive m.zip", 1},
+		{"<stdin>", "-stdin", 1},
+	}
+	exitUnwinds = true
+	defer func() { exitUnwinds = false }()
+	for _, test := range tests {
+		s := newSession(io.Discard)
+		s.numberResults, s.source = true, test.source
+		s.results = []result{{Name: "Config", File: test.file, Line: 5}}
+		s.saveResults()
+		var out bytes.Buffer
+		s = newSession(&out)
+		status := func() (status int) {
+			defer func() {
+				if code, ok := recover().(exitCode); ok {
+					status = int(code)
+				}
+			}()
+			s.show("1")
+			return 0
+		}()
+		if status != test.status {
+			t.Errorf("show of a result in %s from %q exited with %d, want %d", test.file, test.source, status, test.status)
+		}
+		if status == 0 && !strings.Contains(out.String(), "type Config struct") {
+			t.Errorf("show of a result in %s printed\n%s", test.file, out.String())
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	out, stderr, status := runDoc(t, t.TempDir(), "-schema")
+	if status != 0 || out != resultSchema {
+		t.Fatalf("doc -schema exited with %d, printed\n%s\nwant resultSchema; stderr:\n%s", status, out, stderr)
+	}
+	var schema struct {
+		Properties struct {
+			SchemaVersion struct{ Const int }
+			Results       struct {
+				Items struct {
+					Required   []string
+					Properties map[string]struct{ Enum []string }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(resultSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if got := schema.Properties.SchemaVersion.Const; got != schemaVersion {
+		t.Errorf("schema's schemaVersion is %d, want %d", got, schemaVersion)
+	}
+	// Every field of a result is described, and only those.
+	items := schema.Properties.Results.Items
+	var fields []string
+	for f := range reflect.TypeFor[result]().Fields() {
+		fields = append(fields, f.Name)
+	}
+	if got := slices.Sorted(maps.Keys(items.Properties)); !slices.Equal(got, slices.Sorted(slices.Values(fields))) {
+		t.Errorf("schema describes fields %q, want %q", got, fields)
+	}
+	for _, name := range items.Required {
+		if !slices.Contains(fields, name) {
+			t.Errorf("schema requires field %s, which results lack", name)
+		}
+	}
+	if got, want := items.Properties["Kind"].Enum, []string{"const", "var", "func", "method", "type"}; !slices.Equal(got, want) {
+		t.Errorf("schema's kinds are %q, want %q", got, want)
+	}
+	if _, _, status := runDoc(t, t.TempDir(), "-schema", "x"); status != 2 {
+		t.Errorf("doc -schema x exited with %d, want 2", status)
+	}
+}
+
+func TestEmptyResults(t *testing.T) {
+	dir := writeModule(t, testModule)
+	name := filepath.Join(t.TempDir(), "results.json")
+	if _, stderr, status := runDoc(t, dir, "-out", "json="+name, "m", "Missing"); status != 0 {
+		t.Fatalf("doc exited with %d; stderr:\n%s", status, stderr)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n\t\"schemaVersion\": 1,\n\t\"results\": []\n}"; strings.TrimSpace(string(data)) != want {
+		t.Errorf("-out wrote\n%s\nwant\n%s", data, want)
+	}
+}
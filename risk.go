@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// risk prints a line noting whether the body of fn refers to package unsafe
+// or, and how often, to package reflect, whose misuse the compiler cannot
+// catch.
+func (f *File) risk(fn *ast.FuncDecl) {
+	if fn.Body == nil {
+		return
+	}
+	counts := make(map[string]int)
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		if id, ok := node.(*ast.Ident); ok {
+			if obj := f.uses[id]; obj != nil && obj.Pkg() != nil && obj.Pkg() != f.types {
+				counts[obj.Pkg().Path()]++
+			}
+		}
+		return true
+	})
+	if notes := riskNotes(counts["unsafe"], counts["reflect"]); notes != "" {
+		fmt.Fprintf(f.output(nodeKind(fn)), "Risk: %s\n\n", notes)
+	}
+}
+
+// packageRisk returns a line noting how many of the files of the package
+// import unsafe, and how often the package refers to reflect.
+func (f *File) packageRisk() string {
+	unsafeFiles, reflectRefs := 0, 0
+	for _, file := range f.s.parsePackageFiles(filepath.Dir(f.name)) {
+		if file.Name.Name != f.file.Name.Name {
+			continue
+		}
+		for _, imp := range file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := path
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			switch path {
+			case "unsafe":
+				unsafeFiles++
+			case "reflect":
+				ast.Inspect(file, func(node ast.Node) bool {
+					if sel, ok := node.(*ast.SelectorExpr); ok {
+						if x, ok := sel.X.(*ast.Ident); ok && x.Name == name && x.Obj == nil {
+							reflectRefs++
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+	notes := riskNotes(unsafeFiles, reflectRefs)
+	if notes == "" {
+		return ""
+	}
+	if unsafeFiles > 0 {
+		notes = strings.Replace(notes, "uses unsafe", fmt.Sprintf("imports unsafe in %d files", unsafeFiles), 1)
+	}
+	return "Risk: " + notes + "\n\n"
+}
+
+// riskNotes returns the notes for the given numbers of uses of unsafe and
+// of reflect, if either is non-zero.
+func riskNotes(unsafeUses, reflectUses int) string {
+	var notes []string
+	if unsafeUses > 0 {
+		notes = append(notes, "uses unsafe")
+	}
+	if reflectUses > 0 {
+		notes = append(notes, fmt.Sprintf("%d references to reflect", reflectUses))
+	}
+	return strings.Join(notes, "; ")
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRiskNotes(t *testing.T) {
+	tests := []struct {
+		unsafeUses, reflectUses int
+		want                    string
+	}{
+		{0, 0, ""},
+		{1, 0, "uses unsafe"},
+		{3, 0, "uses unsafe"},
+		{0, 2, "2 references to reflect"},
+		{1, 4, "uses unsafe; 4 references to reflect"},
+	}
+	for _, test := range tests {
+		if got := riskNotes(test.unsafeUses, test.reflectUses); got != test.want {
+			t.Errorf("riskNotes(%d, %d) = %q, want %q", test.unsafeUses, test.reflectUses, got, test.want)
+		}
+	}
+}
+
+func TestRisk(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/rk\n\ngo 1.22\n",
+		"r/r.go": `// Package r is risky.
+package r
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Cast casts.
+func Cast(p *int) *float64 { return (*float64)(unsafe.Pointer(p)) }
+
+// Kind reports the kind.
+func Kind(x any) string { return reflect.TypeOf(x).String() }
+
+// Both uses both.
+func Both(x any) uintptr { return unsafe.Sizeof(x) + uintptr(reflect.ValueOf(x).Len()) }
+
+// Safe is safe.
+func Safe() int { return 1 }
+`,
+		"r/other.go": "package r\n\nimport \"unsafe\"\n\nvar _ = unsafe.Sizeof(0)\n",
+		// A renamed import of reflect counts too.
+		"r/third.go": "package r\n\nimport rf \"reflect\"\n\nvar _ = rf.TypeOf(0)\n",
+		"s/s.go":     "// Package s is safe.\npackage s\n\n// Safe is safe.\nfunc Safe() int { return 1 }\n",
+	})
+	tests := []struct {
+		args []string
+		want string // The risk line, if any.
+	}{
+		{[]string{"r.cast"}, "Risk: uses unsafe"},
+		{[]string{"r.kind"}, "Risk: 2 references to reflect"},
+		{[]string{"r.both"}, "Risk: uses unsafe; 2 references to reflect"},
+		{[]string{"r.safe"}, ""},
+		{[]string{"-pkg", "r"}, "Risk: imports unsafe in 2 files; 3 references to reflect"},
+		{[]string{"-pkg", "s"}, ""},
+	}
+	for _, test := range tests {
+		args := append([]string{"-risk"}, test.args...)
+		out, stderr, status := runDoc(t, dir, args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", args, status, stderr)
+			continue
+		}
+		got := ""
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, "Risk: ") {
+				got = line
+			}
+		}
+		if got != test.want {
+			t.Errorf("doc %q printed\n%s\nwant the line %q", args, out, test.want)
+		}
+	}
+	// Without -risk, nothing is noted.
+	out, _, _ := runDoc(t, dir, "r.cast")
+	if strings.Contains(out, "Risk:") {
+		t.Errorf("doc r.cast printed\n%s\nwant no risk note", out)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// rootCategories are the kinds of root a package may come from, as
+// rootCategory names them.
+var rootCategories = []string{"std", "module", "workspace", "vendored"}
+
+// setRoots interprets the -roots flag, a comma-separated list of categories.
+func (s *session) setRoots(list string) {
+	for _, category := range strings.Split(list, ",") {
+		found := false
+		for _, c := range rootCategories {
+			found = found || c == category
+		}
+		if !found {
+			fmt.Fprintf(os.Stderr, "doc: -roots: unknown category %q; want %s\n", category, strings.Join(rootCategories, ", "))
+			exit(2)
+		}
+		s.rootFilter = append(s.rootFilter, category)
+	}
+}
+
+// rootCategory returns the category of the root holding the directory:
+// vendored for a package in a vendor directory, std for the rest of GOROOT,
+// module for a module in the module cache, and workspace for the current
+// module and GOPATH. Other directories, such as those of an archive, have
+// no category.
+func rootCategory(dir string) string {
+	within := func(root string) bool {
+		return root != "" && (dir == root || strings.HasPrefix(dir, root+slash))
+	}
+	switch {
+	case strings.Contains(dir+slash, slash+"vendor"+slash):
+		return "vendored"
+	case within(filepath.Join(goRoot, "src")):
+		return "std"
+	case within(moduleCacheDir()):
+		return "module"
+	case within(modDir):
+		return "workspace"
+	}
+	for _, root := range goPaths {
+		if within(filepath.Join(root, "src")) {
+			return "workspace"
+		}
+	}
+	return ""
+}
+
+// rootAllowed reports whether -roots admits the directory.
+func (s *session) rootAllowed(dir string) bool {
+	return s.rootFilter == nil || s.rootRank(dir) < len(s.rootFilter)
+}
+
+// rootRank returns the position in -roots of the category of the
+// directory's root, or len(s.rootFilter) if it is not listed.
+func (s *session) rootRank(dir string) int {
+	category := rootCategory(dir)
+	for i, c := range s.rootFilter {
+		if c == category {
+			return i
+		}
+	}
+	return len(s.rootFilter)
+}
+
+// sortRoots orders the roots by the position of their categories in -roots.
+func (s *session) sortRoots(roots []string) {
+	sort.SliceStable(roots, func(i, j int) bool { return s.rootRank(roots[i]) < s.rootRank(roots[j]) })
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRootCategory(t *testing.T) {
+	fakeGOROOT(t, nil)
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	mod := writeModule(t, testModule)
+	goPath := t.TempDir()
+	old := goPaths
+	goPaths = []string{goPath}
+	defer func() { goPaths = old }()
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{filepath.Join(goRoot, "src", "strings"), "std"},
+		{filepath.Join(goRoot, "src", "vendor", "golang.org", "x", "net", "idna"), "vendored"},
+		{filepath.Join(cache, "golang.org", "x", "mod@v0.41.0", "semver"), "module"},
+		{filepath.Join(mod, "internal", "auth"), "workspace"},
+		{filepath.Join(mod, "vendor", "example.com", "dep"), "vendored"},
+		{filepath.Join(goPath, "src", "example.org", "p"), "workspace"},
+		{filepath.Join(goPath, "pkg"), ""},
+		{t.TempDir(), ""},
+	}
+	for _, test := range tests {
+		if got := rootCategory(test.dir); got != test.want {
+			t.Errorf("rootCategory(%s) = %q, want %q", test.dir, got, test.want)
+		}
+	}
+}
+
+func TestRoots(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":             "module example.com/l\n\ngo 1.22\n",
+		"strings/strings.go": "package strings\n\n// Cut is a local cut.\nfunc Cut() {}\n",
+	})
+	tests := []struct {
+		args   []string
+		want   []string
+		status int
+	}{
+		{args: []string{"-roots", "std", "strings.Cut"}, want: []string{"strings#Cut"}},
+		{args: []string{"-roots", "workspace", "strings.Cut"}, want: []string{"example.com/l/strings#Cut"}},
+		{args: []string{"-roots", "module", "strings.Cut"}},
+		{args: []string{"-roots", "std,workspace", "-all-roots", "strings.Cut"}, want: []string{"strings#Cut", "example.com/l/strings#Cut"}},
+		{args: []string{"-roots", "workspace,std", "-all-roots", "strings.Cut"}, want: []string{"example.com/l/strings#Cut", "strings#Cut"}},
+		{args: []string{"-roots", "bogus", "strings.Cut"}, status: 2},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != test.status {
+			t.Errorf("doc %q exited with %d, want %d; stderr:\n%s", test.args, status, test.status, stderr)
+			continue
+		}
+		if got := shownSymbols(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q showed %q, want %q", test.args, got, test.want)
+		}
+	}
+	// Numbered results are labeled with their root's category.
+	out, _, _ := runDoc(t, dir, "-roots", "workspace", "-r", "cut")
+	if !strings.Contains(out, "[1] workspace import \"example.com/l/strings\"\n") {
+		t.Errorf("doc -roots workspace -r cut printed\n%s\nwant the result labeled workspace", out)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"path/filepath"
+	"strings"
+)
+
+// screenReaderFragment returns, for -screen-reader, the documentation of the
+// declaration of id in node as labeled lines, each saying what it holds,
+// with no indentation or alignment by spaces for a screen reader to read
+// out or a braille display to spend cells on.
+func (f *File) screenReaderFragment(node ast.Node, id *ast.Ident, url string) string {
+	pos := f.fset.Position(id.Pos())
+	name := id.Name
+	if fn, ok := node.(*ast.FuncDecl); ok && fn.Recv != nil {
+		name = recvTypeName(fn) + "." + name
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Symbol: %s.%s\n", f.file.Name.Name, name)
+	fmt.Fprintf(&b, "Kind: %s\n", resultKind(node))
+	fmt.Fprintf(&b, "Package: %s\n", importPath(filepath.Dir(pos.Filename)))
+	screenReaderLines(&b, "Declaration", f.bareDeclaration(node))
+	screenReaderDoc(&b, docComment(node))
+	if url = strings.TrimSpace(url); url != "" {
+		fmt.Fprintf(&b, "Link: %s\n", url)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// screenReaderPackage returns, for -screen-reader, the package clause and
+// doc comment of the package as labeled lines.
+func screenReaderPackage(name, pkgPath string, doc *ast.CommentGroup) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Package: %s\n", name)
+	if pkgPath != "" {
+		fmt.Fprintf(&b, "Import path: %s\n", pkgPath)
+	}
+	screenReaderDoc(&b, doc)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// screenReaderDoc writes the doc comment, if any, as labeled lines: each
+// paragraph on one line, for the reader to wrap, each heading, list item
+// and line of code labeled as such.
+func screenReaderDoc(b *strings.Builder, doc *ast.CommentGroup) {
+	if doc == nil {
+		return
+	}
+	b.WriteString("Documentation:\n")
+	for _, block := range parseDoc(doc).Content {
+		switch block := block.(type) {
+		case *comment.Paragraph:
+			fmt.Fprintf(b, "%s\n", screenReaderText(block.Text))
+		case *comment.Heading:
+			fmt.Fprintf(b, "Heading: %s\n", screenReaderText(block.Text))
+		case *comment.Code:
+			screenReaderLines(b, "Code", block.Text)
+		case *comment.List:
+			for i, item := range block.Items {
+				label := fmt.Sprintf("Item %d", i+1)
+				if item.Number != "" {
+					label = "Item " + item.Number
+				}
+				var text []string
+				for _, c := range item.Content {
+					if p, ok := c.(*comment.Paragraph); ok {
+						text = append(text, screenReaderText(p.Text))
+					}
+				}
+				fmt.Fprintf(b, "%s: %s\n", label, strings.Join(text, " "))
+			}
+		}
+	}
+	b.WriteString("End of documentation.\n")
+}
+
+// screenReaderLines writes the labeled text: on the label's line if it is
+// one line, and otherwise one line to a line after it, each without the
+// indentation and alignment of code, and ended by saying so.
+func screenReaderLines(b *strings.Builder, label, text string) {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s: %s\n", label, strings.Join(strings.Fields(lines[0]), " "))
+		return
+	}
+	fmt.Fprintf(b, "%s, %d lines:\n", label, len(lines))
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			fmt.Fprintf(b, "%s\n", line)
+		}
+	}
+	fmt.Fprintf(b, "End of %s.\n", strings.ToLower(label))
+}
+
+// screenReaderText returns the text of a paragraph on one line. Emphasis
+// and code, which a reader does not voice, are plain, and a link is
+// followed by its address.
+func screenReaderText(text []comment.Text) string {
+	var b strings.Builder
+	for _, t := range text {
+		switch t := t.(type) {
+		case comment.Plain:
+			b.WriteString(string(t))
+		case comment.Italic:
+			b.WriteString(string(t))
+		case *comment.Link:
+			inner := screenReaderText(t.Text)
+			if inner == t.URL {
+				b.WriteString(t.URL)
+			} else {
+				fmt.Fprintf(&b, "%s (link: %s)", inner, t.URL)
+			}
+		case *comment.DocLink:
+			b.WriteString(screenReaderText(t.Text))
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
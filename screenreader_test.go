@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestScreenReader(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sr\n\ngo 1.22\n",
+		"page/page.go": `// Package page renders pages.
+package page
+
+// Render writes the page, as the [RFC] says,
+// using a [Template].
+//
+// # Steps
+//
+// The steps are:
+//  1. parse
+//  2. execute
+//
+// For example:
+//
+//	p := New()
+//	p.Render()
+//
+// [RFC]: https://example.com/rfc
+func Render(name string,
+	data any) error {
+	return nil
+}
+
+// Template is a template.
+type Template struct {
+	Name  string
+	Count int
+}
+
+// Size is the size.
+func (t *Template) Size() int { return 0 }
+`,
+	})
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{
+			[]string{"page.Render"},
+			`Symbol: page.Render
+Kind: func
+Package: example.com/sr/page
+Declaration, 2 lines:
+func Render(name string,
+data any) error
+End of declaration.
+Documentation:
+Render writes the page, as the RFC (link: https://example.com/rfc) says, using a Template.
+Heading: Steps
+The steps are:
+Item 1: parse
+Item 2: execute
+For example:
+Code, 2 lines:
+p := New()
+p.Render()
+End of code.
+End of documentation.
+Link: https://pkg.go.dev/example.com/sr/page#Render
+
+`,
+		},
+		{
+			[]string{"page.Template"},
+			`Symbol: page.Template
+Kind: type
+Package: example.com/sr/page
+Declaration, 4 lines:
+type Template struct {
+Name string
+Count int
+}
+End of declaration.
+Documentation:
+Template is a template.
+End of documentation.
+Link: https://pkg.go.dev/example.com/sr/page#Template
+
+`,
+		},
+		{
+			[]string{"page.Template.Size"},
+			`page.Template.Size is method Size of page.Template
+
+Symbol: page.Template.Size
+Kind: method
+Package: example.com/sr/page
+Declaration: func (t *Template) Size() int
+Documentation:
+Size is the size.
+End of documentation.
+Link: https://pkg.go.dev/example.com/sr/page#Template.Size
+
+`,
+		},
+	}
+	for _, test := range tests {
+		args := append([]string{"-screen-reader"}, test.args...)
+		out, stderr, status := runDoc(t, dir, args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", args, status, stderr)
+			continue
+		}
+		if out != test.want {
+			t.Errorf("doc %q printed\n%s\nwant\n%s", args, out, test.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/gob"
+	"go/ast"
+	"go/doc"
+	"go/token"
+	"io"
+	"os"
+	"regexp"
+
+	"golang.org/x/tools/cover"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// A session holds the state that a query changes as it runs: where its
+// output goes, what it has found, and what it has cached along the way,
+// and what is searched for and printed, which it settles from the flags
+// when it starts and some modes then change for the query they run. Every
+// File refers to the session it belongs to. Sessions run one at a time:
+// each still reads the flags, the configuration, the module holding the
+// current directory and the directory cache, which belong to the process
+// and which tests set between sessions.
+type session struct {
+	out            io.Writer      // Where results are written: stdout, except while ranking.
+	printed        bool           // Whether any result has been printed.
+	exactMatch     bool           // Whether a result printed since last cleared matched including case.
+	numberResults  bool           // Whether to number the results as they are printed.
+	results        []result       // The results printed, in order of their numbers.
+	showAt         token.Position // If set, print only the result declared here.
+	jsonOut        string         // If set, the file to which -out writes the results as JSON.
+	gobOut         *gob.Encoder   // If set, where -out streams the results as gobs.
+	gobFile        *os.File       // The file gobOut writes, closed by closeOutputs.
+	compareTo      string         // If set, the snapshot with which -compare compares the results.
+	compareOut     io.Writer      // Where -compare writes its report, as the results are not written.
+	searchFileSize int64          // If positive, lookInDirectory skips larger files.
+	examining      string         // The directory or file being examined, for reporting a crash.
+	imports        []importUse    // For -import, what would be printed for each match.
+	hovers         []hover        // For -hover, what would be printed for each match.
+	chatMore       string         // For -chat, the URL of the full documentation of the first match.
+	source         string         // If set, the flag, such as -archive x.zip, naming what is searched instead of the file system.
+	status         int            // If nonzero, the status with which recoverCrash ends doc, as set by a deferred call.
+
+	// What is searched and matched, settled from the flags before the search.
+	exclude       *regexp.Regexp // -exclude, compiled, or nil.
+	excludedDirs  []excludedDir  // -exclude-dir and the configuration's exclude.dir.
+	rootFilter    []string       // The categories named by -roots, in order, or nil for all.
+	filterExpr    ast.Expr       // -filter, or nil.
+	assignableKey string         // The signature given by -assignable-to, as signatureKey writes it, or empty.
+
+	// What is printed of each match, settled from the flags by newSession.
+	// Some modes change it for the query they run, as -manifest clears
+	// showURL and showSrc and sets pkgDoc for each package it writes.
+	pkgDoc                              bool // -package: only the package's documentation.
+	consts, vars, funcs, methods, types bool // -const and the rest: the kinds of declaration; all, but not pkgDoc, if no kind is set.
+	ifaces, structs                     bool // -interface and -struct: only those types.
+	showDoc, showSrc, showURL           bool // -doc, -src and -url: the parts of each match; all if none is set.
+	full                                bool // -full: function bodies and doc comments in full.
+	verboseDoc                          bool // -verbose-doc: examples, constructors, signature types and links.
+
+	methodSets       typeutil.MethodSetCache
+	moduleStatuses   map[string]*moduleStatus    // Result of moduleStatusOf, by module root.
+	generatedSkipped map[string]bool             // The generated SDK trees left out of the search, for generatedHint.
+	licenses         map[string]string           // Result of findLicense, by directory.
+	examples         map[string][]*doc.Example   // Examples in the test files, by directory.
+	parsedFiles      map[string][]*ast.File      // Result of parsePackageFiles, by directory.
+	sources          map[string][]byte           // Source of the files given by -files or -stdin, by name.
+	perfNotes        map[string][]compilerNote   // Result of compilerNotes, by directory.
+	coverage         map[string][]*cover.Profile // Result of coverProfiles, by directory or profile.
+}
+
+// newSession returns a session that writes its results to out.
+func newSession(out io.Writer) *session {
+	s := &session{
+		out:        out,
+		pkgDoc:     *packageFlag,
+		consts:     *constantFlag,
+		vars:       *variableFlag,
+		funcs:      *functionFlag,
+		methods:    *methodFlag,
+		types:      *typeFlag,
+		ifaces:     *interfaceFlag,
+		structs:    *structFlag,
+		showDoc:    *docFlag,
+		showSrc:    *srcFlag,
+		showURL:    *urlFlag,
+		full:       *fullFlag,
+		verboseDoc: *verboseDocFlag,
+
+		moduleStatuses:   make(map[string]*moduleStatus),
+		generatedSkipped: make(map[string]bool),
+		licenses:         make(map[string]string),
+		examples:         make(map[string][]*doc.Example),
+		parsedFiles:      make(map[string][]*ast.File),
+		sources:          make(map[string][]byte),
+		perfNotes:        make(map[string][]compilerNote),
+		coverage:         make(map[string][]*cover.Profile),
+	}
+	if !(s.consts || s.vars || s.funcs || s.methods || s.types || s.ifaces || s.structs || s.pkgDoc) { // none set
+		// Not package! It's special.
+		s.consts, s.vars, s.funcs, s.methods, s.types = true, true, true, true, true
+	}
+	if !(s.showDoc || s.showSrc || s.showURL) {
+		s.showDoc, s.showSrc, s.showURL = true, true, true
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+)
+
+// signatureWidth is the number of columns beyond which a function's
+// signature is shortened when results are listed.
+const signatureWidth = 100
+
+// shortSignature returns a copy of the function declaration whose signature,
+// if wider than signatureWidth, is shortened by eliding with "…" first the
+// constraints of its type parameters, then its parameters, then its results,
+// as far as needed to fit. It reports whether the signature was shortened.
+func (f *File) shortSignature(fn *ast.FuncDecl) (*ast.FuncDecl, bool) {
+	short := *fn
+	typ := *fn.Type
+	short.Type = &typ
+	steps := []func(){
+		func() { typ.TypeParams = elideConstraints(typ.TypeParams) },
+		func() { typ.Params = elideFields(typ.Params) },
+		func() { typ.Results = elideFields(typ.Results) },
+	}
+	shortened := false
+	for _, step := range steps {
+		if f.width(&short) <= signatureWidth {
+			break
+		}
+		step()
+		shortened = true
+	}
+	return &short, shortened
+}
+
+// width returns the number of columns the declaration's signature would
+// occupy on one line. One the printer breaks across lines, as it does an
+// interface constraint, counts as long as it would be unbroken.
+func (f *File) width(fn *ast.FuncDecl) int {
+	var b bytes.Buffer
+	printer.Fprint(&b, f.fset, &ast.FuncDecl{Recv: fn.Recv, Name: fn.Name, Type: fn.Type})
+	width := 0
+	for i, line := range strings.Split(b.String(), "\n") {
+		if i > 0 {
+			width++ // The space the line break becomes.
+		}
+		width += displayWidth(strings.TrimSpace(line))
+	}
+	return width
+}
+
+// elideConstraints returns a copy of the type parameter list with each
+// constraint, other than a plain name such as any, replaced by "…".
+func elideConstraints(list *ast.FieldList) *ast.FieldList {
+	if list == nil {
+		return nil
+	}
+	short := &ast.FieldList{Opening: list.Opening, Closing: list.Closing}
+	for _, field := range list.List {
+		field := *field
+		if _, ok := field.Type.(*ast.Ident); !ok {
+			field.Type = ast.NewIdent("…")
+		}
+		short.List = append(short.List, &field)
+	}
+	return short
+}
+
+// elideFields returns a parameter or result list reduced to "…",
+// or the list itself if it is empty.
+func elideFields(list *ast.FieldList) *ast.FieldList {
+	if list == nil || len(list.List) == 0 {
+		return list
+	}
+	return &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("…")}}}
+}
+
+// signature returns the declaration of the name, which node declares, on a
+// single line with no comments or body, such as
+//	func (b *Buffer) Len() int
+//	type Buffer struct{ ... }
+//	const MinRead = 512
+// A constant whose value is implicit, as in a list using iota, is given
+// the type and value computed by the type checker.
+func (f *File) signature(node ast.Node, id *ast.Ident) string {
+	var decl ast.Node
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		decl = &ast.FuncDecl{Recv: n.Recv, Name: n.Name, Type: n.Type}
+	case *ast.TypeSpec:
+		decl = &ast.GenDecl{Tok: token.TYPE, Specs: []ast.Spec{shortTypeSpec(n)}}
+	case *ast.GenDecl:
+		for _, spec := range n.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if spec.Name == id {
+					decl = &ast.GenDecl{Tok: token.TYPE, Specs: []ast.Spec{shortTypeSpec(spec)}}
+				}
+			case *ast.ValueSpec:
+				for i, name := range spec.Names {
+					if name != id {
+						continue
+					}
+					if c, ok := f.objs[id].(*types.Const); ok && spec.Type == nil && len(spec.Values) == 0 {
+						qualifier := func(p *types.Package) string {
+							if p == c.Pkg() {
+								return ""
+							}
+							return p.Name()
+						}
+						return fmt.Sprintf("const %s %s = %s", id.Name, types.TypeString(c.Type(), qualifier), c.Val().ExactString())
+					}
+					short := &ast.ValueSpec{Names: []*ast.Ident{id}, Type: spec.Type}
+					if len(spec.Values) == len(spec.Names) {
+						short.Values = []ast.Expr{spec.Values[i]}
+					}
+					decl = &ast.GenDecl{Tok: n.Tok, Specs: []ast.Spec{short}}
+				}
+			}
+		}
+	}
+	if decl == nil {
+		return id.Name
+	}
+	var b bytes.Buffer
+	printer.Fprint(&b, f.fset, decl)
+	text := openLines.ReplaceAllString(b.String(), "$1")
+	text = closeLines.ReplaceAllString(text, "$1")
+	text = commaLines.ReplaceAllString(text, ", ")
+	text = otherLines.ReplaceAllString(text, "; ")
+	return alignment.ReplaceAllString(text, " ")
+}
+
+// These match the line breaks of a declaration that is put on one line.
+// Those after an opening bracket or before a closing one vanish; those
+// after a comma become spaces; the rest separate fields or statements,
+// and become semicolons. The tabs that align fields become single spaces.
+var (
+	openLines  = regexp.MustCompile(`([({\[])[\t ]*\n\s*`)
+	closeLines = regexp.MustCompile(`,?[\t ]*\n\s*([)}\]])`)
+	commaLines = regexp.MustCompile(`,[\t ]*\n\s*`)
+	otherLines = regexp.MustCompile(`[\t ]*\n\s*`)
+	alignment  = regexp.MustCompile(`[\t ]*\t[\t ]*`)
+)
+
+// shortTypeSpec returns a copy of the type declaration without comments
+// and with the body of a struct or interface type elided.
+func shortTypeSpec(spec *ast.TypeSpec) *ast.TypeSpec {
+	short := *spec
+	short.Doc, short.Comment = nil, nil
+	switch spec.Type.(type) {
+	case *ast.StructType:
+		short.Type = ast.NewIdent("struct{ ... }")
+	case *ast.InterfaceType:
+		short.Type = ast.NewIdent("interface{ ... }")
+	}
+	return &short
+}
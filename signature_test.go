@@ -0,0 +1,61 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShortSignature(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/lg\n\ngo 1.22\n",
+		"g/g.go": `package g
+
+// Constrained has a long constraint.
+func Constrained[K interface{ ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 }, V any](k K, v V) {}
+
+// Params has many parameters.
+func Params(first, second, third string, fourth, fifth, sixth int, seventh, eighth, ninth []byte) error { return nil }
+
+// Results has many results.
+func Results() (first, second, third string, fourth, fifth, sixth int, seventh, eighth, ninth []byte, err error) { return }
+
+// Short is short.
+func Short(a int) int { return a }
+`,
+	})
+	const (
+		constrained = "func Constrained[K interface {\n\t~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16\n}, V any](k K, v V)\n"
+		params      = "func Params(first, second, third string, fourth, fifth, sixth int, seventh, eighth, ninth []byte) error\n"
+		results     = "func Results() (first, second, third string, fourth, fifth, sixth int, seventh, eighth, ninth []byte, err error)\n"
+		short       = "func Short(a int) int\n"
+	)
+	tests := []struct {
+		args []string
+		want []string // The declarations printed, in order.
+	}{
+		{[]string{"-r", "g", ".*"}, []string{"func Constrained[K …, V any](k K, v V)\n", "func Params(…) error\n", "func Results() …\n", short}},
+		{[]string{"-expand", "-r", "g", ".*"}, []string{constrained, params, results, short}},
+		{[]string{"-full", "-r", "g", "params"}, []string{"func Params(first, second, third string, fourth, fifth, sixth int, seventh, eighth, ninth []byte) error {\n\treturn nil\n}\n"}},
+		{[]string{"g", "Params"}, []string{params}}, // Not listed.
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		rest := out
+		for _, want := range test.want {
+			i := strings.Index(rest, want)
+			if i < 0 {
+				t.Errorf("doc %q printed\n%s\nwant, in order,\n%s", test.args, out, strings.Join(test.want, ""))
+				break
+			}
+			rest = rest[i+len(want):]
+		}
+	}
+}
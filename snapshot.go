@@ -0,0 +1,76 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// readSnapshot returns the results in the file written by -snapshot or
+// -out json=file, or exits if it cannot be read.
+func readSnapshot(name string) []result {
+	var snap jsonResults
+	data, err := os.ReadFile(name)
+	if err == nil {
+		err = json.Unmarshal(data, &snap)
+	}
+	if err == nil && snap.SchemaVersion != schemaVersion {
+		err = fmt.Errorf("schema version %d, not %d", snap.SchemaVersion, schemaVersion)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -compare: %s: %s\n", name, err)
+		exit(2)
+	}
+	return snap.Results
+}
+
+// compareResults prints, for -compare, the symbols among the results that
+// are not in the snapshot, marked +, and those in the snapshot that are
+// not among the results, marked -, each as its import path and name, in
+// order. It exits with status 1, as diff does, if there are any.
+func (s *session) compareResults() {
+	key := func(r result) string {
+		if r.Package == "" {
+			return r.Name
+		}
+		return r.Package + "." + r.Name
+	}
+	kinds := make(map[string]string)
+	before := make(map[string]bool)
+	for _, r := range readSnapshot(s.compareTo) {
+		before[key(r)] = true
+		kinds[key(r)] = r.Kind
+	}
+	after := make(map[string]bool)
+	for _, r := range s.results {
+		after[key(r)] = true
+		kinds[key(r)] = r.Kind
+	}
+	var lines []string
+	for k := range after {
+		if !before[k] {
+			lines = append(lines, "+ "+k)
+		}
+	}
+	for k := range before {
+		if !after[k] {
+			lines = append(lines, "- "+k)
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i][2:] < lines[j][2:] })
+	for _, line := range lines {
+		if kind := kinds[line[2:]]; kind != "" {
+			line += " (" + kind + ")"
+		}
+		fmt.Fprintln(s.compareOut, line)
+	}
+	exit(1)
+}
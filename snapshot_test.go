@@ -0,0 +1,46 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotCompare(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":     "module example.com/snap\n\ngo 1.22\n",
+		"lib/lib.go": "package lib\n\n// NewClient makes a client.\nfunc NewClient() {}\n\n// NewServer makes a server.\nfunc NewServer() {}\n",
+	})
+	snap := filepath.Join(t.TempDir(), "before.json")
+	if _, stderr, status := runDoc(t, dir, "-snapshot", snap, "-local", "-r", "new.*"); status != 0 {
+		t.Fatalf("-snapshot exited with %d; stderr:\n%s", status, stderr)
+	}
+	if out, stderr, status := runDoc(t, dir, "-compare", snap, "-local", "-r", "new.*"); status != 0 || out != "" {
+		t.Errorf("-compare with no change printed\n%s\nand exited with %d; stderr:\n%s", out, status, stderr)
+	}
+	err := os.WriteFile(filepath.Join(dir, "lib", "lib.go"), []byte("package lib\n\n// NewClient makes a client.\nfunc NewClient() {}\n\n// NewPool makes a pool.\ntype NewPool struct{}\n"), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, stderr, status := runDoc(t, dir, "-compare", snap, "-local", "-r", "new.*")
+	want := "+ example.com/snap/lib.NewPool (type)\n- example.com/snap/lib.NewServer (func)\n"
+	if status != 1 || out != want {
+		t.Errorf("-compare printed\n%s\nand exited with %d, want\n%s\nand 1; stderr:\n%s", out, status, want, stderr)
+	}
+
+	// A file that is not a snapshot is refused before searching.
+	bad := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"schemaVersion": 999}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if out, _, status := runDoc(t, dir, "-compare", bad, "-local", "-r", "new.*"); status != 2 || out != "" {
+		t.Errorf("-compare of a bad snapshot printed %q and exited with %d, want nothing and 2", out, status)
+	}
+	if _, _, status := runDoc(t, dir, "-snapshot", snap, "-out", "json="+bad, "-local", "-r", "new.*"); status != 2 {
+		t.Errorf("-snapshot with -out json=file exited with %d, want 2", status)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// snippet prints, after the documentation of fn, code to call it from
+// another package: a receiver variable for a method, the zero value of each
+// argument, marked with the parameter's name, variables for the results,
+// and a check of a final error result.
+func (f *File) snippet(fn *ast.FuncDecl) {
+	obj, ok := f.objs[fn.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	sig := obj.Type().(*types.Signature)
+	qual := func(p *types.Package) string {
+		if p == obj.Pkg() {
+			return f.file.Name.Name
+		}
+		return p.Name()
+	}
+	var lines []string
+	callee := f.file.Name.Name + "." + obj.Name()
+	if recv := sig.Recv(); recv != nil {
+		typ := recv.Type()
+		if ptr, ok := typ.(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		v := varName(receiverName(obj))
+		lines = append(lines, fmt.Sprintf("var %s %s", v, types.TypeString(typ, qual)))
+		callee = v + "." + obj.Name()
+	}
+	var args []string
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			break // None is a valid list of variadic arguments.
+		}
+		arg := zeroValue(param.Type(), qual)
+		if param.Name() != "" && param.Name() != "_" {
+			arg += " /* " + param.Name() + " */"
+		}
+		args = append(args, arg)
+	}
+	if len(args) > 0 {
+		lines = append(lines, "// TODO: Fill in the arguments.")
+	}
+	call := fmt.Sprintf("%s(%s)", callee, strings.Join(args, ", "))
+	results := sig.Results()
+	if results.Len() == 0 {
+		lines = append(lines, call)
+	} else {
+		var names []string
+		used := make(map[string]bool)
+		for i := 0; i < results.Len(); i++ {
+			name := resultName(results.At(i))
+			for n := 2; used[name]; n++ {
+				name = strings.TrimRight(name, "0123456789") + strconv.Itoa(n)
+			}
+			used[name] = true
+			names = append(names, name)
+		}
+		lines = append(lines, fmt.Sprintf("%s := %s", strings.Join(names, ", "), call))
+		if last := results.At(results.Len() - 1); isError(last.Type()) {
+			lines = append(lines, "if "+names[len(names)-1]+" != nil {", "\treturn "+names[len(names)-1], "}")
+		}
+	}
+	fmt.Fprintf(f.output(nodeKind(fn)), "Snippet:\n%s\n\n", indent(strings.Join(lines, "\n")))
+}
+
+// zeroValue returns the zero value of the type as it would be written
+// in a call, or context.TODO() for a context.Context.
+func zeroValue(typ types.Type, qual types.Qualifier) string {
+	if isContext(typ) {
+		return "context.TODO()"
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return "false"
+		case t.Info()&types.IsString != 0:
+			return `""`
+		case t.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+		return "nil" // Unsafe pointers, untyped nil.
+	case *types.Struct, *types.Array:
+		return types.TypeString(typ, qual) + "{}"
+	}
+	return "nil"
+}
+
+// resultName returns a name for a variable holding the result: its own
+// name, if it has one, err for an error, ok for a bool, and otherwise one
+// derived from its type.
+func resultName(result *types.Var) string {
+	typ := result.Type()
+	switch {
+	case result.Name() != "" && result.Name() != "_":
+		return result.Name()
+	case isError(typ):
+		return "err"
+	}
+	if basic, ok := typ.Underlying().(*types.Basic); ok && typ == typ.Underlying() {
+		switch {
+		case basic.Info()&types.IsBoolean != 0:
+			return "ok"
+		case basic.Info()&types.IsString != 0:
+			return "s"
+		case basic.Info()&types.IsNumeric != 0:
+			return "n"
+		}
+	}
+	for {
+		switch t := typ.(type) {
+		case *types.Pointer:
+			typ = t.Elem()
+			continue
+		case *types.Slice:
+			typ = t.Elem()
+			continue
+		case *types.Named:
+			return varName(t.Obj().Name())
+		case *types.Alias:
+			return varName(t.Obj().Name())
+		}
+		return "v"
+	}
+}
+
+// isError reports whether the type is the predeclared error type.
+func isError(typ types.Type) bool {
+	return typ == types.Universe.Lookup("error").Type()
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnippet(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sn\n\ngo 1.22\n",
+		"shop/shop.go": `package shop
+
+import (
+	"context"
+	"io"
+)
+
+// Point is a point.
+type Point struct{ X, Y int }
+
+// Cart holds items.
+type Cart struct{}
+
+// Add adds.
+func (c *Cart) Add(ctx context.Context, item string, n int, ok bool) error { return nil }
+
+// Open opens.
+func Open(name string, at Point, r io.Reader, grid [2]int) (*Cart, error) { return nil, nil }
+
+// Sum sums.
+func Sum(label string, xs ...int) (total int) { return 0 }
+
+// Pair pairs.
+func Pair() (int, int, bool) { return 0, 0, false }
+
+// Ping pings.
+func Ping() {}
+
+// Points lists points.
+func Points(_ int) []Point { return nil }
+`,
+	})
+	const add = "\tvar c shop.Cart\n" +
+		"\t// TODO: Fill in the arguments.\n" +
+		"\terr := c.Add(context.TODO() /* ctx */, \"\" /* item */, 0 /* n */, false /* ok */)\n" +
+		"\tif err != nil {\n\t\treturn err\n\t}\n"
+	tests := []struct {
+		arg  string
+		want string // The snippet, indented.
+	}{
+		{"shop.open", "\t// TODO: Fill in the arguments.\n" +
+			"\tc, err := shop.Open(\"\" /* name */, shop.Point{} /* at */, nil /* r */, [2]int{} /* grid */)\n" +
+			"\tif err != nil {\n\t\treturn err\n\t}\n"},
+		// No variadic arguments are needed.
+		{"shop.sum", "\t// TODO: Fill in the arguments.\n\ttotal := shop.Sum(\"\" /* label */)\n"},
+		{"shop.pair", "\tn, n2, ok := shop.Pair()\n"},
+		{"shop.ping", "\tshop.Ping()\n"},
+		{"shop.points", "\t// TODO: Fill in the arguments.\n\tp := shop.Points(0)\n"},
+		{"shop.add", add},
+		{"shop.cart.add", add},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, "-snippet", test.arg)
+		if status != 0 {
+			t.Errorf("doc -snippet %s exited with %d; stderr:\n%s", test.arg, status, stderr)
+			continue
+		}
+		_, got, _ := strings.Cut(out, "Snippet:\n")
+		if got != test.want+"\n" {
+			t.Errorf("doc -snippet %s printed\n%s\nwant the snippet\n%s", test.arg, out, test.want)
+		}
+	}
+	// A type has no snippet.
+	out, _, _ := runDoc(t, dir, "-snippet", "shop.point")
+	if strings.Contains(out, "Snippet:") {
+		t.Errorf("doc -snippet shop.point printed\n%s\nwant no snippet", out)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// parseDir is parser.ParseDir, but reads each file with readSource, so files
+// with byte order marks or unusual line endings are parsed rather than
+// dropped. Files that are not text it can decode are skipped with a notice.
+func parseDir(fset *token.FileSet, dir string, filter func(fs.FileInfo) bool, mode parser.Mode) (map[string]*ast.Package, error) {
+	list, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkgs := make(map[string]*ast.Package)
+	var first error
+	for _, d := range list {
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".go") {
+			continue
+		}
+		if filter != nil {
+			info, err := d.Info()
+			if err != nil || !filter(info) {
+				continue
+			}
+		}
+		name := filepath.Join(dir, d.Name())
+		src, err := readSource(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: skipping %s\n", err)
+			continue
+		}
+		file, err := parseFile(fset, name, src, mode)
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		pkg := pkgs[file.Name.Name]
+		if pkg == nil {
+			pkg = &ast.Package{Name: file.Name.Name, Files: make(map[string]*ast.File)}
+			pkgs[file.Name.Name] = pkg
+		}
+		pkg.Files[name] = file
+	}
+	return pkgs, first
+}
+
+// choosePackages returns the packages to document from those parsed in the
+// directory. A directory should hold one package, with perhaps its external
+// test package, but may hold strays too, such as a generator in package main
+// excluded by a build constraint. If so, the package named for the directory
+// is kept, or failing that the only one not named main; if neither settles
+// it, all are kept. With -strict, the conflict is reported.
+func choosePackages(dir string, pkgs map[string]*ast.Package) map[string]*ast.Package {
+	var names []string
+	for name := range pkgs {
+		if !strings.HasSuffix(name, "_test") {
+			names = append(names, name)
+		}
+	}
+	if len(names) < 2 {
+		return pkgs
+	}
+	sort.Strings(names)
+	chosen := ""
+	if pkgs[filepath.Base(dir)] != nil && !strings.HasSuffix(filepath.Base(dir), "_test") {
+		chosen = filepath.Base(dir)
+	} else {
+		for _, name := range names {
+			if name == "main" {
+				continue
+			}
+			if chosen != "" {
+				chosen = ""
+				break
+			}
+			chosen = name
+		}
+	}
+	if *strictFlag {
+		if chosen == "" {
+			fmt.Fprintf(os.Stderr, "doc: %s: packages %s; using all\n", dir, strings.Join(names, ", "))
+		} else {
+			fmt.Fprintf(os.Stderr, "doc: %s: packages %s; using %s\n", dir, strings.Join(names, ", "), chosen)
+		}
+	}
+	if chosen == "" {
+		return pkgs
+	}
+	kept := map[string]*ast.Package{chosen: pkgs[chosen]}
+	if test := pkgs[chosen+"_test"]; test != nil {
+		kept[chosen+"_test"] = test
+	}
+	return kept
+}
+
+// readSource returns the contents of the named Go source file as UTF-8 with
+// newline-terminated lines: a UTF-16 file is converted, a UTF-8 byte order
+// mark is removed, and \r\n and lone \r line endings become \n. Positions
+// within the file, as the parser reports them, refer to the result.
+func readSource(name string) ([]byte, error) {
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSource(name, src)
+}
+
+// decodeSource returns the source, read from the named file, converted
+// as readSource describes.
+func decodeSource(name string, src []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(src, []byte{0xFE, 0xFF}):
+		src = decodeUTF16(src[2:], true)
+	case bytes.HasPrefix(src, []byte{0xFF, 0xFE}):
+		src = decodeUTF16(src[2:], false)
+	}
+	src = bytes.TrimPrefix(src, []byte("\uFEFF"))
+	if bytes.IndexByte(src, '\r') >= 0 {
+		src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+		src = bytes.ReplaceAll(src, []byte("\r"), []byte("\n"))
+	}
+	if !utf8.Valid(src) {
+		return nil, fmt.Errorf("%s: not UTF-8 or UTF-16 text", name)
+	}
+	return src, nil
+}
+
+// decodeUTF16 returns the UTF-16 text, in big- or little-endian order, as UTF-8.
+func decodeUTF16(b []byte, bigEndian bool) []byte {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+		} else {
+			units[i] = uint16(b[2*i+1])<<8 | uint16(b[2*i])
+		}
+	}
+	return []byte(string(utf16.Decode(units)))
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSource(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+		err  bool
+	}{
+		{src: "package p\n", want: "package p\n"},
+		{src: "\uFEFFpackage p\n", want: "package p\n"},
+		{src: "package p\r\n\r\nvar x\r\n", want: "package p\n\nvar x\n"},
+		{src: "package p\rvar x\r", want: "package p\nvar x\n"},
+		{src: "\xFE\xFF\x00p\x00\r\x00\n\x00\xE9", want: "p\né"},
+		{src: "\xFF\xFEp\x00\r\x00\n\x00\xE9\x00", want: "p\né"},
+		{src: "package p\n// caf\xE9\n", err: true}, // Latin-1.
+	}
+	for _, test := range tests {
+		got, err := decodeSource("p.go", []byte(test.src))
+		if test.err {
+			if err == nil || !strings.Contains(err.Error(), "p.go: not UTF-8 or UTF-16 text") {
+				t.Errorf("decodeSource(%q) error = %v, want one for the encoding", test.src, err)
+			}
+			continue
+		}
+		if err != nil || string(got) != test.want {
+			t.Errorf("decodeSource(%q) = %q, %v; want %q", test.src, got, err, test.want)
+		}
+	}
+}
+
+func TestUnusualEncodings(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":   "module example.com/enc\n\ngo 1.22\n",
+		"e/bom.go": "\uFEFFpackage e\r\n\r\n// BOM has a byte order mark.\r\nfunc BOM() {}\r\n",
+		"e/cr.go":  "package e\r\r// CR ends lines with carriage returns.\rfunc CR() {}\r",
+		"e/bad.go": "package e\n\n// Latin is caf\xE9.\nfunc Latin() {}\n",
+	})
+	out, stderr, status := runDoc(t, dir, "-r", "e", ".*")
+	if status != 0 {
+		t.Fatalf("doc -r e .* exited with %d; stderr:\n%s", status, stderr)
+	}
+	if got, want := outline(out), "Functions\n#BOM\n#CR\n"; got != want {
+		t.Errorf("doc -r e .* listed\n%s\nwant\n%s", got, want)
+	}
+	if !strings.Contains(out, "// CR ends lines with carriage returns.\nfunc CR()") {
+		t.Errorf("doc -r e .* printed\n%s\nwant CR's comment and declaration on lines of their own", out)
+	}
+	if !strings.Contains(stderr, "doc: skipping ") || !strings.Contains(stderr, "bad.go: not UTF-8 or UTF-16 text") {
+		t.Errorf("doc -r e .* wrote\n%s\nto stderr, want a notice that bad.go is skipped", stderr)
+	}
+}
+
+func TestChoosePackages(t *testing.T) {
+	tests := []struct {
+		dir  string
+		pkgs []string
+		want []string
+	}{
+		{"shop", []string{"shop"}, []string{"shop"}},
+		{"shop", []string{"shop", "shop_test"}, []string{"shop", "shop_test"}},
+		{"shop", []string{"main", "shop", "shop_test"}, []string{"shop", "shop_test"}},
+		{"store", []string{"main", "shop"}, []string{"shop"}},
+		{"store", []string{"cart", "shop"}, []string{"cart", "shop"}},
+		{"store", []string{"cart", "main", "shop"}, []string{"cart", "main", "shop"}},
+	}
+	for _, test := range tests {
+		pkgs := make(map[string]*ast.Package)
+		for _, name := range test.pkgs {
+			pkgs[name] = &ast.Package{Name: name}
+		}
+		if got := slices.Sorted(maps.Keys(choosePackages(test.dir, pkgs))); !slices.Equal(got, test.want) {
+			t.Errorf("choosePackages(%s, %q) = %q, want %q", test.dir, test.pkgs, got, test.want)
+		}
+	}
+}
+
+func TestStrictPackages(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":       "module example.com/s\n\ngo 1.22\n",
+		"shop/shop.go": "package shop\n\n// Sell sells.\nfunc Sell() {}\n",
+		"shop/gen.go":  "//go:build ignore\n\npackage main\n\n// Sell generates.\nfunc Sell() {}\n",
+	})
+	for _, strict := range []bool{false, true} {
+		args := []string{"shop", "Sell"}
+		if strict {
+			args = append([]string{"-strict"}, args...)
+		}
+		out, stderr, status := runDoc(t, dir, args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", args, status, stderr)
+			continue
+		}
+		if got, want := shownSymbols(out), []string{"example.com/s/shop#Sell"}; !slices.Equal(got, want) || strings.Contains(out, "generates") {
+			t.Errorf("doc %q printed\n%s\nwant shop's Sell alone", args, out)
+		}
+		if got := strings.Contains(stderr, "shop: packages main, shop; using shop\n"); got != strict {
+			t.Errorf("doc %q wrote\n%s\nto stderr; reported the conflict: %t, want %t", args, stderr, got, strict)
+		}
+	}
+}
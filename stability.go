@@ -0,0 +1,123 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// stability returns the stability tier, such as stable, beta or
+// experimental, of the symbol declared by node, or the empty string if no
+// tier is given. The tier is given by a line of a doc comment such as
+//	// Stability: experimental
+// or by a line of the configuration file such as
+//	stability example.com/sdk/preview experimental
+//	stability example.com/sdk Client.Watch beta
+// whose pattern matches the import path of a package or a parent, as in
+// GOPRIVATE, or, when followed by the name of a symbol, that of the
+// symbol's package. A symbol takes the tier given for it, or
+// else for the type of a method, or else for its package, the doc comment
+// before the configuration at each step.
+func (f *File) stability(node ast.Node, id *ast.Ident) string {
+	pkgPath := importPath(filepath.Dir(f.name))
+	name, recv := "", ""
+	if id != nil {
+		name = id.Name
+		if fn, ok := node.(*ast.FuncDecl); ok && fn.Recv != nil {
+			recv = recvTypeName(fn)
+			name = recv + "." + name
+		}
+	}
+	if tier := stabilityIn(docComment(node)); tier != "" {
+		return tier
+	}
+	if name != "" {
+		if tier := configStability(pkgPath, name); tier != "" {
+			return tier
+		}
+	}
+	var files []*ast.File
+	for _, other := range f.allFiles {
+		files = append(files, other.file)
+	}
+	if files == nil {
+		files = []*ast.File{f.file}
+	}
+	if recv != "" {
+		if tier := stabilityIn(findDoc(files, "", recv)); tier != "" {
+			return tier
+		}
+		if tier := configStability(pkgPath, recv); tier != "" {
+			return tier
+		}
+	}
+	for _, file := range files {
+		if tier := stabilityIn(file.Doc); tier != "" {
+			return tier
+		}
+	}
+	return configStability(pkgPath, "")
+}
+
+// stabilityIn returns the tier named by the Stability: line of the doc comment.
+func stabilityIn(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if text, ok := strings.CutPrefix(strings.TrimSpace(line), "Stability:"); ok {
+			if fields := strings.Fields(text); len(fields) > 0 {
+				return strings.ToLower(strings.TrimSuffix(fields[0], "."))
+			}
+		}
+	}
+	return ""
+}
+
+// configStability returns the tier the configuration file gives the named
+// symbol of the package, or the package itself if the name is empty. The
+// last matching line wins, as for other settings.
+func configStability(pkgPath, name string) string {
+	tier := ""
+	for _, value := range config["stability"] {
+		fields := strings.Fields(value)
+		var pattern, symbol string
+		switch len(fields) {
+		case 2:
+			pattern = fields[0]
+		case 3:
+			pattern, symbol = fields[0], fields[1]
+		default:
+			continue
+		}
+		if symbol != name {
+			continue
+		}
+		if ok, _ := path.Match(pattern, pkgPath); ok || symbol == "" && module.MatchPrefixPatterns(pattern, pkgPath) {
+			tier = strings.ToLower(fields[len(fields)-1])
+		}
+	}
+	return tier
+}
+
+// stabilityNote returns the line, printed with the documentation of a
+// symbol, naming its tier, if it has one. It comes before the other notes.
+func stabilityNote(tier string) string {
+	if tier == "" {
+		return ""
+	}
+	return "stability: " + tier + "\n"
+}
+
+// stable reports whether the tier admits a symbol to the results of
+// -stable-only: it is stable, or none is given.
+func stable(tier string) bool {
+	return tier == "" || tier == "stable"
+}
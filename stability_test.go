@@ -0,0 +1,98 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var stabilityModule = map[string]string{
+	"go.mod": "module example.com/s\n\ngo 1.22\n",
+	"sdk/sdk.go": `package sdk
+
+// Client is a client.
+type Client struct{}
+
+// Watch watches.
+func (c *Client) Watch() {}
+
+// Get gets.
+func (c *Client) Get() {}
+
+// Fresh is new.
+//
+// Stability: Experimental.
+func Fresh() {}
+
+// Beta is in beta.
+//
+// Stability: beta
+type Beta struct{}
+
+// Run runs.
+func (b Beta) Run() {}
+`,
+	"sdk/preview/preview.go":   "package preview\n\n// Preview previews.\nfunc Preview() {}\n",
+	"sdk/preview/deep/deep.go": "package deep\n\n// Deep is deep.\nfunc Deep() {}\n",
+	"alpha/alpha.go": `// Package alpha is early.
+//
+// Stability: alpha
+package alpha
+
+// A is the first.
+func A() {}
+`,
+}
+
+func TestStability(t *testing.T) {
+	dir := writeModule(t, stabilityModule)
+	defaultFlags(t)
+	old := config
+	config = map[string][]string{"stability": {
+		"example.com/s/sdk Client.Watch beta",
+		"example.com/s/sdk/preview experimental",
+		"example.com/s/sdk Fresh stable", // The doc comment comes first.
+	}}
+	defer func() { config = old }()
+	tests := []struct {
+		pkg, name string
+		tier      string // Empty if none is printed.
+	}{
+		{"sdk", "Watch", "beta"},
+		{"sdk", "Get", ""},
+		{"sdk", "Client", ""},
+		{"sdk", "Fresh", "experimental"},
+		{"sdk", "Beta", "beta"},
+		{"sdk", "Run", "beta"}, // From the type of the method.
+		{"sdk/preview", "Preview", "experimental"},
+		{"sdk/preview/deep", "Deep", "experimental"}, // From a parent package.
+		{"alpha", "A", "alpha"},                      // From the package doc comment.
+	}
+	for _, test := range tests {
+		for _, stableOnly := range []bool{false, true} {
+			setFlag(t, stableOnlyFlag, stableOnly)
+			var out bytes.Buffer
+			newSession(&out).lookInDirectory(filepath.Join(dir, filepath.FromSlash(test.pkg)), "", test.name)
+			if stableOnly {
+				if printed, want := out.Len() > 0, stable(test.tier); printed != want {
+					t.Errorf("-stable-only %s.%s printed %t, want %t:\n%s", test.pkg, test.name, printed, want, out.String())
+				}
+				continue
+			}
+			tier := ""
+			for _, line := range strings.Split(out.String(), "\n") {
+				if name, ok := strings.CutPrefix(line, "stability: "); ok {
+					tier = name
+				}
+			}
+			if tier != test.tier {
+				t.Errorf("%s.%s has stability %q, want %q:\n%s", test.pkg, test.name, tier, test.tier, out.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// stale compares the doc comments of the current module with those of its
+// latest published version, which is what pkg.go.dev shows, as fetched from
+// the module proxy, and reports each exported symbol whose documentation
+// differs, is not yet published, or has been removed since. An empty version
+// means the latest. Where the documentation differs, the difference follows
+// as a diff, side by side if -width is set.
+func (s *session) stale(version string) {
+	if modDir == "" {
+		fmt.Fprintf(os.Stderr, "doc: -stale: not in a module\n")
+		exit(2)
+	}
+	if version == "" {
+		var info struct{ Version string }
+		if err := json.Unmarshal(fetchModule(modPath, "@latest"), &info); err != nil {
+			fmt.Fprintf(os.Stderr, "doc: -stale: %s: %s\n", modPath, err)
+			exit(1)
+		}
+		version = info.Version
+	}
+	data := fetchModule(modPath, "@v/"+version+".zip")
+	if err := verifyZip(modPath, version, data, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -stale: %s\n", err)
+		exit(1)
+	}
+	published := publishedDocs(data, modPath+"@"+version+"/")
+	local := localDocs(modDir)
+
+	var report []string
+	color := useColor()
+	for pkgPath, docs := range local {
+		for name, text := range docs {
+			old, ok := published[pkgPath][name]
+			switch {
+			case !ok:
+				report = append(report, fmt.Sprintf("%s.%s: not in %s", pkgPath, name, version))
+			case old != text:
+				report = append(report, fmt.Sprintf("%s.%s: documentation differs from %s\n%s", pkgPath, name, version,
+					textDiff(pkgPath+"."+name, old, text, *widthFlag, color)))
+			}
+		}
+	}
+	for pkgPath, docs := range published {
+		for name := range docs {
+			if _, ok := local[pkgPath][name]; !ok {
+				report = append(report, fmt.Sprintf("%s.%s: in %s but since removed", pkgPath, name, version))
+			}
+		}
+	}
+	sort.Strings(report)
+	for _, line := range report {
+		fmt.Fprintln(s.out, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+// publishedDocs returns the symbol documentation, by import path, of the
+// packages in a module zip file, whose names all begin with the prefix.
+func publishedDocs(data []byte, prefix string) map[string]map[string]string {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -stale: %s\n", err)
+		exit(1)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, zf := range r.File {
+		name := strings.TrimPrefix(zf.Name, prefix)
+		if !staleFile(name) {
+			continue
+		}
+		src, err := readZipFile(zf)
+		if err == nil {
+			var file *ast.File
+			file, err = parseUntrusted(fset, name, src)
+			if err == nil {
+				files = append(files, file)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: -stale: skipping %s\n", err)
+		}
+	}
+	return moduleDocs(fset, files)
+}
+
+// localDocs returns the symbol documentation, by import path, of the
+// packages of the module in the directory, read as publishedDocs reads
+// those of a published version: the packages of nested modules, which
+// are not published with it, are left out.
+func localDocs(root string) map[string]map[string]string {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, name)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if name == root {
+				return nil
+			}
+			if _, err := os.Stat(filepath.Join(name, "go.mod")); err == nil || !staleDir(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !staleFile(rel) {
+			return nil
+		}
+		src, err := os.ReadFile(name)
+		var file *ast.File
+		if err == nil {
+			file, err = parser.ParseFile(fset, rel, src, parser.ParseComments)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: -stale: skipping %s\n", err)
+			return nil
+		}
+		files = append(files, file)
+		return nil
+	})
+	return moduleDocs(fset, files)
+}
+
+// staleDir reports whether packages in the directory, named by its
+// slash-separated path within the module, are compared by -stale: not
+// those the go command ignores, in testdata or in directories beginning
+// with . or _, nor vendored ones.
+func staleDir(dir string) bool {
+	if dir == "." {
+		return true
+	}
+	for _, elem := range strings.Split(dir, "/") {
+		if elem == "testdata" || elem == "vendor" || strings.HasPrefix(elem, ".") || strings.HasPrefix(elem, "_") {
+			return false
+		}
+	}
+	return true
+}
+
+// staleFile reports whether the file, named by its slash-separated path
+// within the module, is one -stale reads: a Go file, not a test, in a
+// directory staleDir accepts.
+func staleFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") && staleDir(path.Dir(name))
+}
+
+// moduleDocs returns the symbol documentation, by import path, of the
+// packages in the module's files, which are named by their slash-separated
+// paths within the module. Commands, in package main, and external test
+// packages are left out. Should a directory hold other packages besides,
+// as a file excluded by a build constraint may put there, the one named for
+// the directory is taken, or else the first by name, so the two sides of a
+// comparison agree.
+func moduleDocs(fset *token.FileSet, files []*ast.File) map[string]map[string]string {
+	byDir := make(map[string]map[string][]*ast.File) // Files by package name by directory.
+	for _, file := range files {
+		name := file.Name.Name
+		if name == "main" || strings.HasSuffix(name, "_test") {
+			continue
+		}
+		dir := path.Dir(fset.Position(file.Package).Filename)
+		if byDir[dir] == nil {
+			byDir[dir] = make(map[string][]*ast.File)
+		}
+		byDir[dir][name] = append(byDir[dir][name], file)
+	}
+	docs := make(map[string]map[string]string)
+	for dir, pkgs := range byDir {
+		pkgPath := path.Join(modPath, dir)
+		names := slices.Sorted(maps.Keys(pkgs))
+		chosen := names[0]
+		if pkgs[path.Base(pkgPath)] != nil {
+			chosen = path.Base(pkgPath)
+		}
+		docs[pkgPath] = symbolDocs(fset, pkgs[chosen], pkgPath)
+	}
+	return docs
+}
+
+// symbolDocs returns the doc comments of the package's exported symbols,
+// keyed by name, or by Type.Method for methods.
+func symbolDocs(fset *token.FileSet, files []*ast.File, pkgPath string) map[string]string {
+	docs := make(map[string]string)
+	p, err := doc.NewFromFiles(fset, files, pkgPath)
+	if err != nil {
+		return docs
+	}
+	values := func(values []*doc.Value) {
+		for _, v := range values {
+			for _, name := range v.Names {
+				docs[name] = v.Doc
+			}
+		}
+	}
+	funcs := func(funcs []*doc.Func) {
+		for _, fn := range funcs {
+			name := fn.Name
+			if fn.Recv != "" {
+				name = strings.TrimPrefix(fn.Recv, "*") + "." + name
+			}
+			docs[name] = fn.Doc
+		}
+	}
+	values(p.Consts)
+	values(p.Vars)
+	funcs(p.Funcs)
+	for _, t := range p.Types {
+		docs[t.Name] = t.Doc
+		values(t.Consts)
+		values(t.Vars)
+		funcs(t.Funcs)
+		funcs(t.Methods)
+	}
+	return docs
+}
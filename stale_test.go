@@ -0,0 +1,120 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStaleDocs(t *testing.T) {
+	files := map[string]string{
+		"go.mod":              "module example.com/m\n\ngo 1.22\n",
+		"m.go":                "package m\n\n// F does f.\nfunc F() {}\n",
+		"m_test.go":           "package m\n\n// T is for tests.\nfunc T() {}\n",
+		"x/x.go":              "package x\n\n// X is x.\nvar X int\n",
+		"x/gen.go":            "//go:build ignore\n\npackage main\n\nfunc Gen() {}\n",
+		"x/stray.go":          "//go:build ignore\n\npackage stray\n\nfunc Stray() {}\n",
+		"cmd/tool/main.go":    "package main\n\n// Run runs.\nfunc Run() {}\n",
+		"testdata/td/td.go":   "package td\n\nfunc TD() {}\n",
+		"_old/old.go":         "package old\n\nfunc Old() {}\n",
+		"vendor/v.com/v/v.go": "package v\n\nfunc V() {}\n",
+		"sub/go.mod":          "module example.com/m/sub\n\ngo 1.22\n",
+		"sub/sub.go":          "package sub\n\nfunc Sub() {}\n",
+	}
+	dir := writeModule(t, files)
+	local := localDocs(dir)
+	want := map[string]map[string]string{
+		"example.com/m":   {"F": "F does f.\n"},
+		"example.com/m/x": {"X": "X is x.\n"},
+	}
+	if !reflect.DeepEqual(local, want) {
+		t.Errorf("localDocs = %v, want %v", local, want)
+	}
+
+	// The published zip holds what the go command puts there: neither the
+	// nested module nor the vendor directory.
+	const prefix = "example.com/m@v1.0.0/"
+	published := maps.Clone(files)
+	maps.DeleteFunc(published, func(name, _ string) bool {
+		return strings.HasPrefix(name, "sub/") || strings.HasPrefix(name, "vendor/")
+	})
+	if docs := publishedDocs(moduleZip(prefix, published), prefix); !reflect.DeepEqual(docs, local) {
+		t.Errorf("publishedDocs = %v, want the same as localDocs, %v", docs, local)
+	}
+}
+
+// moduleZip returns a module zip file holding the files, keyed by
+// slash-separated name, each under the prefix.
+func moduleZip(prefix string, files map[string]string) []byte {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		w, _ := z.Create(prefix + name)
+		w.Write([]byte(files[name]))
+	}
+	z.Close()
+	return buf.Bytes()
+}
+
+func TestStale(t *testing.T) {
+	const goMod = "module example.com/m\n\ngo 1.22\n"
+	published := map[string]string{
+		"go.mod": goMod,
+		"m.go":   "package m\n\n// F does f.\nfunc F() {}\n\n// G does g.\nfunc G() {}\n\n// Gone is gone.\nfunc Gone() {}\n",
+	}
+	writeModule(t, map[string]string{
+		"go.mod": goMod,
+		"m.go":   "package m\n\n// F does f.\nfunc F() {}\n\n// G does g twice.\nfunc G() {}\n\n// New is new.\nfunc New() {}\n",
+	})
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/m/@latest":
+			fmt.Fprint(w, `{"Version":"v1.1.0"}`)
+		case "/example.com/m/@v/v1.0.0.zip", "/example.com/m/@v/v1.1.0.zip":
+			version := strings.TrimSuffix(path.Base(r.URL.Path), ".zip")
+			w.Write(moduleZip("example.com/m@"+version+"/", published))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer proxy.Close()
+	t.Setenv("GOPROXY", proxy.URL)
+	t.Setenv("GOSUMDB", "off")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NO_COLOR", "1")
+
+	for _, version := range []string{"", "v1.0.0"} {
+		want := cmp.Or(version, "v1.1.0")
+		var out bytes.Buffer
+		newSession(&out).stale(version)
+		got := out.String()
+		for _, line := range []string{
+			"example.com/m.G: documentation differs from " + want + "\n",
+			"-G does g.\n",
+			"+G does g twice.\n",
+			"example.com/m.Gone: in " + want + " but since removed\n",
+			"example.com/m.New: not in " + want + "\n",
+		} {
+			if !strings.Contains(got, line) {
+				t.Errorf("-stale %s output lacks %q:\n%s", version, line, got)
+			}
+		}
+		if strings.Contains(got, "m.F:") {
+			t.Errorf("-stale %s reports F, whose documentation is unchanged:\n%s", version, got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// queryStats counts the work done to answer a query, for -time. The counts
+// are atomic, as packages may be parsed and checked in parallel.
+type queryStats struct {
+	start     time.Time
+	dirs      atomic.Int64 // Directories walked.
+	files     atomic.Int64 // Files parsed.
+	packages  atomic.Int64 // Packages type-checked.
+	cacheHits atomic.Int64 // Lookups answered from a cache: parsed files, examples, licenses, fetched source.
+	parse     atomic.Int64 // Time spent parsing, in nanoseconds.
+	typeCheck atomic.Int64 // Time spent type checking, in nanoseconds.
+}
+
+var stats = queryStats{start: time.Now()}
+
+// since returns the time since start, for adding to parse or typeCheck.
+func since(start time.Time) int64 {
+	return int64(time.Since(start))
+}
+
+// print writes the statistics as a trailer, with the time taken by each
+// phase; the rest is walking directories, searching and printing.
+func (q *queryStats) print(w io.Writer) {
+	elapsed := time.Since(q.start)
+	parse, typeCheck := time.Duration(q.parse.Load()), time.Duration(q.typeCheck.Load())
+	rest := elapsed - parse - typeCheck
+	fmt.Fprintf(w, "doc: %d directories scanned, %d files parsed, %d packages type-checked, %d cache hits\n",
+		q.dirs.Load(), q.files.Load(), q.packages.Load(), q.cacheHits.Load())
+	fmt.Fprintf(w, "doc: %s parsing, %s type checking, %s walking, searching and printing; %s in all\n",
+		round(parse), round(typeCheck), round(rest), round(elapsed))
+}
+
+// round rounds the duration for printing.
+func round(d time.Duration) time.Duration {
+	return d.Round(100 * time.Microsecond)
+}
+
+// parseFile is parser.ParseFile, counted for -time.
+func parseFile(fset *token.FileSet, name string, src any, mode parser.Mode) (*ast.File, error) {
+	start := time.Now()
+	file, err := parser.ParseFile(fset, name, src, mode)
+	recordRead(name)
+	stats.files.Add(1)
+	stats.parse.Add(since(start))
+	return file, err
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a.go": "// Package s is a script.\npackage s\n\n// Run runs.\nfunc Run() {}\n",
+		"b.go": "package s\n\n// Config configures Run.\ntype Config struct{}\n",
+	})
+	tests := []struct {
+		args []string
+		want string // The first line of the report.
+	}{
+		{[]string{"-time", "-files", "a.go", "b.go", "Run"}, "doc: 0 directories scanned, 2 files parsed, 1 packages type-checked, 0 cache hits"},
+		{[]string{"-time", "-files", "a.go", "Missing"}, "doc: 0 directories scanned, 1 files parsed, 0 packages type-checked, 0 cache hits"}, // No match to check.
+		{[]string{"-time", "-pkg", "-files", "a.go"}, "doc: 0 directories scanned, 1 files parsed, 0 packages type-checked, 0 cache hits"},
+		{[]string{"-files", "a.go", "Run"}, ""},
+	}
+	phases := regexp.MustCompile(`^doc: \S+ parsing, \S+ type checking, \S+ walking, searching and printing; \S+ in all$`)
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if test.want == "" {
+			if stderr != "" {
+				t.Errorf("doc %q wrote\n%s\nto stderr, want nothing without -time", test.args, stderr)
+			}
+			continue
+		}
+		counts, times, _ := strings.Cut(strings.TrimSuffix(stderr, "\n"), "\n")
+		if counts != test.want || !phases.MatchString(times) {
+			t.Errorf("doc %q wrote\n%s\nto stderr, want\n%s\nand the time of each phase", test.args, stderr, test.want)
+		}
+		if strings.Contains(out, "directories scanned") {
+			t.Errorf("doc %q printed the report on standard output", test.args)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	for _, test := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{49_999, "0s"},
+		{50_000, "100µs"},
+		{12_345_678, "12.3ms"},
+		{2_000_049_999, "2s"},
+	} {
+		if got := fmt.Sprint(round(test.d)); got != test.want {
+			t.Errorf("round(%d) = %s, want %s", test.d, got, test.want)
+		}
+	}
+}
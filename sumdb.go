@@ -0,0 +1,283 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/dirhash"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// verifyZip checks the module zip file downloaded from a proxy against its
+// expected hash: the one recorded in the go.sum file, if it is named and
+// lists the version, or else the one the checksum database named by GOSUMDB
+// gives, whose answer is verified against the database's signed tree, as
+// the go command verifies it. As for the go command, modules matched by
+// GONOSUMDB or GOPRIVATE, or all modules if GOSUMDB is off, need be in no
+// checksum database. If the hash cannot be had, verifyZip fails.
+func verifyZip(modulePath, version string, data []byte, goSum string) error {
+	got, err := zipHash(data)
+	if err != nil {
+		return err
+	}
+	want := ""
+	if goSum != "" {
+		if sums, err := os.ReadFile(goSum); err == nil {
+			want = sumFor(sums, modulePath, version)
+		}
+	}
+	if want == "" {
+		if noSumDB(modulePath) {
+			return nil
+		}
+		lines, err := checksumDB().lookup(modulePath, version)
+		if err != nil {
+			return fmt.Errorf("%s@%s: verifying with the checksum database: %v", modulePath, version, err)
+		}
+		want = sumFor([]byte(strings.Join(lines, "\n")), modulePath, version)
+	}
+	if want == "" {
+		return fmt.Errorf("%s@%s: no checksum found", modulePath, version)
+	}
+	if got != want {
+		return fmt.Errorf("%s@%s: checksum mismatch: downloaded %s, expected %s", modulePath, version, got, want)
+	}
+	return nil
+}
+
+// noSumDB reports whether the module need not be in a checksum database.
+func noSumDB(modulePath string) bool {
+	if os.Getenv("GOSUMDB") == "off" {
+		return true
+	}
+	noSum := os.Getenv("GONOSUMDB")
+	if noSum == "" {
+		noSum = os.Getenv("GOPRIVATE")
+	}
+	return module.MatchPrefixPatterns(noSum, modulePath)
+}
+
+// knownSumDBKeys holds the verifier keys of the checksum databases GOSUMDB
+// may name by name alone, as the go command knows them.
+var knownSumDBKeys = map[string]string{
+	"sum.golang.org": "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8",
+}
+
+// A sumDB is the client of the checksum database named by GOSUMDB, or why
+// there can be none.
+type sumDB struct {
+	client *sumdb.Client
+	err    error
+}
+
+var (
+	sumDBOnce   sync.Once
+	sumDBClient *sumDB
+)
+
+// checksumDB returns the client of the checksum database named by GOSUMDB,
+// which is sum.golang.org if it is not set. As in the go command, GOSUMDB
+// is the database's verifier key, or a name with a known key, optionally
+// followed by the URL at which to reach it.
+func checksumDB() *sumDB {
+	sumDBOnce.Do(func() {
+		sumDBClient = new(sumDB)
+		env := os.Getenv("GOSUMDB")
+		switch env {
+		case "":
+			env = "sum.golang.org"
+		case "sum.golang.google.cn":
+			env = "sum.golang.org https://sum.golang.google.cn"
+		}
+		fields := strings.Fields(env)
+		if len(fields) == 0 || len(fields) > 2 {
+			sumDBClient.err = fmt.Errorf("invalid GOSUMDB %q", env)
+			return
+		}
+		key := fields[0]
+		if known := knownSumDBKeys[key]; known != "" {
+			key = known
+		}
+		verifier, err := note.NewVerifier(key)
+		if err != nil {
+			sumDBClient.err = fmt.Errorf("invalid GOSUMDB: %v", err)
+			return
+		}
+		ops := &sumDBOps{key: key, name: verifier.Name()}
+		if len(fields) == 2 {
+			ops.base = strings.TrimSuffix(fields[1], "/")
+		}
+		if dir, err := os.UserCacheDir(); err == nil {
+			ops.dir = filepath.Join(dir, "doc", "sumdb")
+		}
+		sumDBClient.client = sumdb.NewClient(ops)
+	})
+	return sumDBClient
+}
+
+// lookup returns the go.sum lines of the module version from the database.
+func (db *sumDB) lookup(modulePath, version string) ([]string, error) {
+	if db.err != nil {
+		return nil, db.err
+	}
+	return db.client.Lookup(modulePath, version)
+}
+
+// sumDBOps is how the checksum database client reaches the database,
+// through tryFetch, and keeps what it has verified, in doc's cache directory.
+type sumDBOps struct {
+	key, name string
+	dir       string // Where the latest signed tree and the tiles are kept, or empty.
+
+	baseOnce sync.Once
+	base     string // The URL of the database, given or found by findBase.
+	mu       sync.Mutex
+}
+
+func (o *sumDBOps) ReadRemote(path string) ([]byte, error) {
+	o.baseOnce.Do(o.findBase)
+	return tryFetch(o.base + path)
+}
+
+// findBase sets the URL of the database, if it was not given: a module
+// proxy of GOPROXY that serves the database, as it reports at
+// /sumdb/name/supported, or else the database itself.
+func (o *sumDBOps) findBase() {
+	if o.base != "" {
+		return
+	}
+	o.base = "https://" + o.name
+	for _, proxy := range proxyList() {
+		if proxy.url == "direct" || proxy.url == "off" {
+			return
+		}
+		_, err := tryFetch(proxy.url + "/sumdb/" + o.name + "/supported")
+		if err == nil {
+			o.base = proxy.url + "/sumdb/" + o.name
+			return
+		}
+		var notFound notFoundError
+		if !proxy.fallBack && !errors.As(err, &notFound) {
+			return
+		}
+	}
+}
+
+func (o *sumDBOps) ReadConfig(file string) ([]byte, error) {
+	if file == "key" {
+		return []byte(o.key), nil
+	}
+	if o.dir == "" {
+		return nil, nil // Start from an empty tree.
+	}
+	data, err := os.ReadFile(filepath.Join(o.dir, filepath.FromSlash(file)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (o *sumDBOps) WriteConfig(file string, old, new []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.dir == "" {
+		return nil
+	}
+	current, err := o.ReadConfig(file)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current, old) {
+		return sumdb.ErrWriteConflict
+	}
+	return writeFileAtomic(filepath.Join(o.dir, filepath.FromSlash(file)), new)
+}
+
+func (o *sumDBOps) ReadCache(file string) ([]byte, error) {
+	if o.dir == "" {
+		return nil, fs.ErrNotExist
+	}
+	return os.ReadFile(filepath.Join(o.dir, "cache", filepath.FromSlash(file)))
+}
+
+func (o *sumDBOps) WriteCache(file string, data []byte) {
+	if o.dir != "" {
+		writeFileAtomic(filepath.Join(o.dir, "cache", filepath.FromSlash(file)), data) // Failure to write the cache is not an error.
+	}
+}
+
+func (o *sumDBOps) Log(msg string) {}
+
+func (o *sumDBOps) SecurityError(msg string) {
+	fmt.Fprintf(os.Stderr, "doc: SECURITY ERROR\n%s\n", msg)
+	exit(1)
+}
+
+// writeFileAtomic writes the file, making its directory if need be, by way
+// of a temporary file renamed into place, so no reader sees it partial.
+func writeFileAtomic(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// sumFor returns the hash of the module version's zip file in go.sum
+// format text, or the empty string if it is not listed.
+func sumFor(text []byte, modulePath, version string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == modulePath && fields[1] == version {
+			return fields[2]
+		}
+	}
+	return ""
+}
+
+// zipHash returns the "h1:" hash of the module zip file, as it appears in
+// go.sum, computed as dirhash.HashZip computes it for a file on disk.
+func zipHash(data []byte) (string, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	files := make(map[string]*zip.File)
+	for _, zf := range r.File {
+		names = append(names, zf.Name)
+		files[zf.Name] = zf
+	}
+	return dirhash.Hash1(names, func(name string) (io.ReadCloser, error) {
+		return files[name].Open()
+	})
+}
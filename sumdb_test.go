@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cachedModZip returns the name and contents of the zip file of
+// golang.org/x/mod v0.41.0, a dependency of doc's own module, so in the
+// module cache with its hash in go.sum.
+func cachedModZip(t *testing.T) (string, []byte) {
+	t.Helper()
+	zipFile := filepath.Join(moduleCacheDir(), "cache", "download", "golang.org", "x", "mod", "@v", "v0.41.0.zip")
+	data, err := os.ReadFile(zipFile)
+	if err != nil {
+		t.Skip(err)
+	}
+	return zipFile, data
+}
+
+func TestZipHash(t *testing.T) {
+	zipFile, data := cachedModZip(t)
+	sums, err := os.ReadFile("go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sumFor(sums, "golang.org/x/mod", "v0.41.0")
+	if got, err := zipHash(data); err != nil || got != want {
+		t.Errorf("zipHash(%s) = %q, %v; want %q", zipFile, got, err, want)
+	}
+	if _, err := zipHash([]byte("not a zip file")); err == nil {
+		t.Errorf("zipHash of a bad zip file: no error")
+	}
+}
+
+func TestVerifyZip(t *testing.T) {
+	_, data := cachedModZip(t)
+	sums, err := os.ReadFile("go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sumFor(sums, "golang.org/x/mod", "v0.41.0")
+	writeSum := func(sum string) string {
+		name := filepath.Join(t.TempDir(), "go.sum")
+		line := "golang.org/x/mod v0.41.0 " + sum + "\n"
+		if err := os.WriteFile(name, []byte(line), 0666); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+	bad := "h1:" + strings.Repeat("A", 43) + "="
+	if err := verifyZip("golang.org/x/mod", "v0.41.0", data, writeSum(want)); err != nil {
+		t.Errorf("verifyZip with the go.sum hash: %v", err)
+	}
+	if err := verifyZip("golang.org/x/mod", "v0.41.0", data, writeSum(bad)); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("verifyZip with a wrong go.sum hash: %v, want a checksum mismatch", err)
+	}
+	if err := verifyZip("golang.org/x/mod", "v0.41.0", data[:len(data)/2], writeSum(want)); err == nil {
+		t.Errorf("verifyZip of a truncated zip file: no error")
+	}
+
+	// Without a go.sum line, the checksum database decides, unless the
+	// module is exempt from it. The database is not consulted here.
+	t.Setenv("GOSUMDB", "off")
+	if err := verifyZip("golang.org/x/mod", "v0.41.0", data, ""); err != nil {
+		t.Errorf("verifyZip with GOSUMDB=off: %v", err)
+	}
+	t.Setenv("GOSUMDB", "")
+	t.Setenv("GONOSUMDB", "")
+	t.Setenv("GOPRIVATE", "golang.org/x")
+	if err := verifyZip("golang.org/x/mod", "v0.41.0", data, ""); err != nil {
+		t.Errorf("verifyZip with GOPRIVATE=golang.org/x: %v", err)
+	}
+	// A go.sum line is checked even for an exempt module.
+	if err := verifyZip("golang.org/x/mod", "v0.41.0", data, writeSum(bad)); err == nil {
+		t.Errorf("verifyZip of an exempt module with a wrong go.sum hash: no error")
+	}
+}
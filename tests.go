@@ -0,0 +1,114 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// listTests prints the declarations, with doc comments and positions, of the
+// test functions in the packages in the directories whose names begin with
+// one of the prefixes, such as "Benchmark" or "Fuzz", each followed by the
+// subtests that it names with string literals.
+func (s *session) listTests(dirs []string, prefixes ...string) {
+	isTest := func(info os.FileInfo) bool { return strings.HasSuffix(info.Name(), "_test.go") }
+	for _, dir := range dirs {
+		fset := token.NewFileSet()
+		pkgs, _ := parseDir(fset, dir, isTest, parser.ParseComments) // Ignore the error.
+		// The package before its external test package.
+		for _, pkgName := range slices.Sorted(maps.Keys(pkgs)) {
+			pkg := pkgs[pkgName]
+			var names []string
+			for name := range pkg.Files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				astFile := pkg.Files[name]
+				file := s.newFile(fset, name, "", astFile)
+				file.doPrint = true
+				for _, decl := range astFile.Decls {
+					fn, ok := decl.(*ast.FuncDecl)
+					if !ok || fn.Recv != nil || !isTestFunc(fn.Name.Name, prefixes) {
+						continue
+					}
+					body := fn.Body
+					if !s.full {
+						fn.Body = nil // Do not print the function body.
+					}
+					file.printNode(fn, fn.Name, "")
+					fn.Body = body
+					if subtests := subtests(fn.Body, ""); len(subtests) > 0 {
+						printSection(s.out, "Subtests", subtests)
+					}
+				}
+			}
+		}
+	}
+}
+
+// subtests returns the names, as go test reports them after the test's
+// own, of the subtests started in the body by calls of the form
+// t.Run("name", func...), including those they start in turn. Subtests
+// whose names are not string literals cannot be known without running
+// the test and are omitted.
+func subtests(body *ast.BlockStmt, parent string) []string {
+	if body == nil {
+		return nil
+	}
+	var names []string
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		lit, isLit := call.Args[0].(*ast.BasicLit)
+		if !ok || sel.Sel.Name != "Run" || !isLit || lit.Kind != token.STRING {
+			return true
+		}
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		name = parent + "/" + strings.ReplaceAll(name, " ", "_")
+		names = append(names, "\t"+strings.TrimPrefix(name, "/"))
+		if fn, ok := call.Args[1].(*ast.FuncLit); ok {
+			names = append(names, subtests(fn.Body, name)...)
+		}
+		return false
+	})
+	return names
+}
+
+// isTestFunc reports whether the name is that of a test function with one
+// of the prefixes, following the rule of go test: the prefix must not be
+// followed by a lower-case letter.
+func isTestFunc(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := name[len(prefix):]
+		if rest == "" {
+			return true
+		}
+		r, _ := utf8.DecodeRuneInString(rest)
+		if !unicode.IsLower(r) {
+			return true
+		}
+	}
+	return false
+}
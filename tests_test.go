@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// testsModule is a module whose package p has tests, benchmarks and fuzz
+// targets, with subtests, and functions that only look like them.
+var testsModule = map[string]string{
+	"go.mod": "module example.com/p\n\ngo 1.22\n",
+	"p/p.go": "package p\n\n// TestLike is not in a test file.\nfunc TestLike() {}\n",
+	"p/p_test.go": `package p
+
+import "testing"
+
+// TestParse tests parsing.
+func TestParse(t *testing.T) {
+	t.Run("empty input", func(t *testing.T) {
+		t.Run("nil", func(t *testing.T) {})
+	})
+	name := "computed"
+	t.Run(name, func(t *testing.T) {})
+	t.Run("long", func(t *testing.T) {})
+}
+
+func Testify(t *testing.T) {}
+
+func Test(t *testing.T) {}
+
+// BenchmarkParse measures parsing.
+func BenchmarkParse(b *testing.B) {
+	b.Run("small", func(b *testing.B) {})
+}
+
+func FuzzParse(f *testing.F) {}
+
+type suite struct{}
+
+func (suite) TestMethod(t *testing.T) {}
+`,
+	"p/ext_test.go": "package p_test\n\nimport \"testing\"\n\nfunc TestExternal(t *testing.T) {}\n",
+}
+
+// listedFuncs returns the names of the functions declared in doc's output,
+// and the subtests listed after them, in order.
+func listedFuncs(out string) []string {
+	re := regexp.MustCompile(`(?m)^func (\w+)\(|^\t(\S+)$`)
+	var names []string
+	for _, m := range re.FindAllStringSubmatch(out, -1) {
+		names = append(names, m[1]+m[2])
+	}
+	return names
+}
+
+func TestListTests(t *testing.T) {
+	dir := writeModule(t, testsModule)
+	tests := []struct {
+		flags []string
+		want  []string
+	}{
+		{[]string{"-bench"}, []string{"BenchmarkParse", "small"}},
+		{[]string{"-fuzz"}, []string{"FuzzParse"}},
+		{[]string{"-bench", "-fuzz"}, []string{"BenchmarkParse", "small", "FuzzParse"}},
+		{[]string{"-tests"}, []string{"TestParse", "empty_input", "empty_input/nil", "long", "Test", "TestExternal"}},
+		{[]string{"-tests", "-fuzz"}, []string{"TestParse", "empty_input", "empty_input/nil", "long", "Test", "FuzzParse", "TestExternal"}},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, append(test.flags, "p")...)
+		if status != 0 {
+			t.Errorf("doc %q p exited with %d; stderr:\n%s", test.flags, status, stderr)
+			continue
+		}
+		if got := listedFuncs(out); !slices.Equal(got, test.want) {
+			t.Errorf("doc %q p listed %q, want %q; output:\n%s", test.flags, got, test.want, out)
+		}
+	}
+}
+
+func TestIsTestFunc(t *testing.T) {
+	prefixes := []string{"Test", "Benchmark"}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"TestParse", true},
+		{"Test", true},
+		{"Test_parse", true},
+		{"TestÉté", true},
+		{"Testify", false},
+		{"Testété", false},
+		{"BenchmarkX", true},
+		{"Fuzz", false},
+		{"Parse", false},
+	}
+	for _, test := range tests {
+		if got := isTestFunc(test.name, prefixes); got != test.want {
+			t.Errorf("isTestFunc(%q) = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
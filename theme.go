@@ -0,0 +1,98 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A theme is how colored output looks: the ANSI escapes coloring diffs on a
+// terminal, which use only the 16 standard colors every terminal has, and
+// the style sheet for -html-fragment.
+type theme struct {
+	del, add, header string // Escapes for deleted and added lines and headers.
+	css              string
+}
+
+// themes are the built-in themes, selected by theme in the configuration
+// file. Without one, diffs are colored as by defaultTheme and HTML is
+// left unstyled, for the page it is embedded in to style.
+var themes = map[string]theme{
+	"light": {
+		del: "\x1b[31m", add: "\x1b[32m", header: "\x1b[34m",
+		css: `.doc { color: #1f2328; background: #ffffff; }
+.doc pre { background: #f6f8fa; padding: 0.5em; }
+.doc a { color: #0969da; }
+`,
+	},
+	"dark": {
+		del: "\x1b[91m", add: "\x1b[92m", header: "\x1b[96m",
+		css: `.doc { color: #e6edf3; background: #0d1117; }
+.doc pre { background: #161b22; padding: 0.5em; }
+.doc a { color: #58a6ff; }
+`,
+	},
+	"high-contrast": {
+		del: "\x1b[1;97;41m", add: "\x1b[1;30;42m", header: "\x1b[1;4m",
+		css: `.doc { color: #000000; background: #ffffff; }
+.doc pre { background: #ffffff; border: 2px solid #000000; padding: 0.5em; }
+.doc a { color: #0000ee; text-decoration: underline; }
+`,
+	},
+}
+
+// defaultTheme colors diffs when no theme is configured.
+var defaultTheme = theme{del: "\x1b[31m", add: "\x1b[32m", header: "\x1b[36m"}
+
+// colorReset ends a colored span.
+const colorReset = "\x1b[0m"
+
+// reportTheme reports an unknown theme, just once in the process.
+var reportTheme sync.Once
+
+// currentTheme returns the theme named by theme in the configuration file,
+// or defaultTheme if there is none or it is unknown, which is reported once.
+func currentTheme() theme {
+	name := configValue("theme")
+	if name == "" {
+		return defaultTheme
+	}
+	t, ok := themes[name]
+	if !ok {
+		reportTheme.Do(func() {
+			var names []string
+			for name := range themes {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			fmt.Fprintf(os.Stderr, "doc: unknown theme %q; want %s\n", name, strings.Join(names, ", "))
+		})
+		return defaultTheme
+	}
+	return t
+}
+
+// htmlStyle returns, for -html-fragment, a style element holding the style
+// sheet of the configured theme followed by the user's own, from the file
+// named by theme.css in the configuration file, or nothing if neither is
+// configured.
+func htmlStyle() string {
+	css := currentTheme().css
+	if name := configValue("theme.css"); name != "" {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "doc: theme.css: %s\n", err)
+		}
+		css += string(data)
+	}
+	if css == "" {
+		return ""
+	}
+	return "<style>\n" + css + "</style>\n"
+}
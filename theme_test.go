@@ -0,0 +1,67 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestThemeDiff(t *testing.T) {
+	old := config
+	defer func() { config = old }()
+	for _, name := range []string{"", "light", "dark", "high-contrast", "unknown"} {
+		config = map[string][]string{}
+		want := defaultTheme
+		if name != "" {
+			config["theme"] = []string{name}
+		}
+		if th, ok := themes[name]; ok {
+			want = th
+		}
+		got := textDiff("F", "a\nb\n", "a\nc\n", 0, true)
+		for _, line := range []string{
+			want.header + "@@ F @@" + colorReset,
+			want.del + "-b" + colorReset,
+			want.add + "+c" + colorReset,
+		} {
+			if !strings.Contains(got, line+"\n") {
+				t.Errorf("theme %q: diff\n%q\nlacks %q", name, got, line)
+			}
+		}
+		if got := textDiff("F", "a\nb\n", "a\nc\n", 40, true); !strings.Contains(got, want.del) || !strings.Contains(got, want.add) {
+			t.Errorf("theme %q: side-by-side diff\n%q\nis not colored by it", name, got)
+		}
+		if got := textDiff("F", "a\nb\n", "a\nc\n", 0, false); strings.Contains(got, "\x1b") {
+			t.Errorf("theme %q: uncolored diff %q holds escapes", name, got)
+		}
+	}
+}
+
+func TestHTMLStyle(t *testing.T) {
+	old := config
+	defer func() { config = old }()
+	css := filepath.Join(t.TempDir(), "doc.css")
+	if err := os.WriteFile(css, []byte(".doc { font-size: 120%; }\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		config map[string][]string
+		want   string
+	}{
+		{map[string][]string{}, ""},
+		{map[string][]string{"theme": {"dark"}}, "<style>\n" + themes["dark"].css + "</style>\n"},
+		{map[string][]string{"theme.css": {css}}, "<style>\n.doc { font-size: 120%; }\n</style>\n"},
+		{map[string][]string{"theme": {"light", "high-contrast"}, "theme.css": {css}}, "<style>\n" + themes["high-contrast"].css + ".doc { font-size: 120%; }\n</style>\n"},
+	}
+	for _, test := range tests {
+		config = test.config
+		if got := htmlStyle(); got != test.want {
+			t.Errorf("htmlStyle with %v = %q, want %q", test.config, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// A translator provides translated doc comments. The symbol is the name of
+// a package-level declaration, Type.Method for a method, or empty for the
+// package comment. Providers that keep their translations elsewhere, such as
+// in a service or in .po files, need only implement this interface and be
+// added to translators.
+type translator interface {
+	translate(dir, symbol, lang string) (text string, ok bool)
+}
+
+// translators are consulted in order for a translation.
+var translators = []translator{&sidecarTranslator{files: make(map[string]map[string]string)}}
+
+// sidecarTranslator reads translations from a JSON file alongside the
+// package's source, doc.<lang>.json, holding an object that maps symbols
+// to translated text:
+//	{"": "Package fmt は…", "Printf": "Printf は…"}
+type sidecarTranslator struct {
+	mu    sync.Mutex
+	files map[string]map[string]string // The parsed sidecar files, by name.
+}
+
+func (t *sidecarTranslator) translate(dir, symbol, lang string) (string, bool) {
+	name := filepath.Join(dir, "doc."+lang+".json")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	texts, ok := t.files[name]
+	if !ok {
+		if data, err := os.ReadFile(name); err == nil {
+			json.Unmarshal(data, &texts) // A malformed file has no translations.
+		}
+		t.files[name] = texts
+	}
+	text, ok := texts[symbol]
+	return text, ok
+}
+
+// translation returns the translation, in the language set by -lang, of the
+// doc comment for the symbol declared in the file, if there is one.
+func (f *File) translation(symbol string) (string, bool) {
+	if *langFlag == "" {
+		return "", false
+	}
+	for _, t := range translators {
+		if text, ok := t.translate(filepath.Dir(f.name), symbol, *langFlag); ok {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// symbolKey returns the name under which the declaration's doc comment is translated.
+func symbolKey(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if recv := recvTypeName(n); recv != "" {
+			return recv + "." + n.Name.Name
+		}
+		return n.Name.Name
+	case *ast.TypeSpec:
+		return n.Name.Name
+	case *ast.GenDecl:
+		for _, spec := range n.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				return spec.Name.Name
+			case *ast.ValueSpec:
+				return spec.Names[0].Name
+			}
+		}
+	}
+	return ""
+}
+
+// docComment returns the doc comment of the declaration.
+func docComment(node ast.Node) *ast.CommentGroup {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		return n.Doc
+	case *ast.TypeSpec:
+		return n.Doc
+	case *ast.GenDecl:
+		return n.Doc
+	}
+	return nil
+}
+
+// setDocComment sets the doc comment of the declaration and returns the
+// one it replaces.
+func setDocComment(node ast.Node, doc *ast.CommentGroup) *ast.CommentGroup {
+	var old *ast.CommentGroup
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		old, n.Doc = n.Doc, doc
+	case *ast.TypeSpec:
+		old, n.Doc = n.Doc, doc
+	case *ast.GenDecl:
+		old, n.Doc = n.Doc, doc
+	}
+	return old
+}
+
+// commentLines returns the text formatted as a // comment.
+func commentLines(text string) string {
+	text = strings.TrimRight(text, "\n")
+	lines := "// " + strings.ReplaceAll(text, "\n", "\n// ") + "\n"
+	return strings.ReplaceAll(lines, "// \n", "//\n") // No trailing space on blank lines.
+}
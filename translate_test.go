@@ -0,0 +1,62 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslation(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/tr\n\ngo 1.22\n",
+		"tr/tr.go": `// Package tr is translated.
+package tr
+
+// Hello greets.
+func Hello() {}
+
+// Greeter greets.
+type Greeter struct{}
+
+// Greet greets.
+func (Greeter) Greet() {}
+
+// Untranslated is left alone.
+const Untranslated = 1
+`,
+		"tr/doc.fr.json": `{
+	"": "Le paquet tr est traduit.",
+	"Hello": "Hello salue.\n\nDeux paragraphes.",
+	"Greeter": "Greeter salue.",
+	"Greeter.Greet": "Greet salue."
+}`,
+		"tr/doc.xx.json": "not JSON",
+	})
+	tests := []struct {
+		args []string
+		want string // The doc comment printed.
+	}{
+		{[]string{"-lang", "fr", "tr", "Hello"}, "// Hello salue.\n//\n// Deux paragraphes.\nfunc Hello()\n"},
+		{[]string{"-lang", "fr", "-r", "h.*"}, "// Hello salue.\n//\n// Deux paragraphes.\nfunc Hello()\n"}, // Numbered.
+		{[]string{"-lang", "fr", "tr", "Greeter"}, "// Greeter salue.\ntype Greeter struct{}\n"},
+		{[]string{"-lang", "fr", "-m", "tr", "Greet"}, "// Greet salue.\nfunc (Greeter) Greet()\n"},
+		{[]string{"-lang", "fr", "tr", "Untranslated"}, "// Untranslated is left alone.\nconst Untranslated = 1\n"},
+		{[]string{"-lang", "fr", "-pkg", "tr"}, "Le paquet tr est traduit.\n"},
+		{[]string{"-lang", "de", "tr", "Hello"}, "// Hello greets.\nfunc Hello()\n"},
+		{[]string{"-lang", "xx", "tr", "Hello"}, "// Hello greets.\nfunc Hello()\n"}, // A malformed file.
+		{[]string{"tr", "Hello"}, "// Hello greets.\nfunc Hello()\n"},
+	}
+	for _, test := range tests {
+		out, stderr, status := runDoc(t, dir, test.args...)
+		if status != 0 {
+			t.Errorf("doc %q exited with %d; stderr:\n%s", test.args, status, stderr)
+			continue
+		}
+		if !strings.Contains(out, test.want) {
+			t.Errorf("doc %q printed\n%s\nwant it to contain\n%s", test.args, out, test.want)
+		}
+	}
+}
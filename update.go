@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// docPackage is the import path of this command.
+const docPackage = "robpike.io/cmd/doc"
+
+// update installs, with go install, the newest release of doc on the
+// channel, if it is newer than the running binary. The module, and the
+// command within it, are those the binary was built from, which for a fork
+// or a renamed module are not docPackage. On the stable channel,
+// the default, pre-releases are ignored; on the latest channel they are not.
+// Retracted versions are ignored on both.
+// The channel may also be set in the configuration file as update.channel.
+// With -offline, update reports the version running and does nothing else.
+func (s *session) update(channel string) {
+	if channel == "" {
+		channel = configValue("update.channel")
+	}
+	if channel == "" {
+		channel = "stable"
+	}
+	if channel != "stable" && channel != "latest" {
+		fmt.Fprintf(os.Stderr, "doc: -update: unknown channel %s; want stable or latest\n", channel)
+		exit(2)
+	}
+	modulePath, pkgPath, current := docPackage, docPackage, "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Path != "" {
+		modulePath, pkgPath, current = info.Main.Path, info.Main.Path, info.Main.Version
+		if info.Path != "" {
+			pkgPath = info.Path
+		}
+	}
+	if *offlineFlag {
+		fmt.Fprintf(s.out, "doc %s; not checking for updates while offline\n", current)
+		return
+	}
+	list := strings.Fields(string(fetchModule(modulePath, "@v/list")))
+	newest := newestVersion(list, channel, latestGoMod(modulePath))
+	if newest == "" {
+		fmt.Fprintf(os.Stderr, "doc: -update: no %s release of %s\n", channel, modulePath)
+		exit(1)
+	}
+	if current != "(devel)" && semver.Compare(newest, current) <= 0 {
+		fmt.Fprintf(s.out, "doc %s is up to date\n", current)
+		return
+	}
+	cmd := exec.Command("go", "install", pkgPath+"@"+newest)
+	cmd.Stdout, cmd.Stderr = s.out, os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -update: go install %s@%s: %s\n", pkgPath, newest, err)
+		exit(1)
+	}
+	fmt.Fprintf(s.out, "doc updated from %s to %s\n", current, newest)
+}
+
+// newestVersion returns the newest of the listed versions on the channel,
+// or the empty string if there is none: a pre-release is only on the
+// latest channel, and a version retracted by the go.mod file of the
+// module's latest version, as the go command reads retractions, is on
+// neither.
+func newestVersion(list []string, channel string, goMod []byte) string {
+	newest := ""
+	for _, v := range list {
+		switch {
+		case !semver.IsValid(v):
+			continue
+		case channel == "stable" && semver.Prerelease(v) != "":
+			continue
+		case retractionIn(goMod, v) != "":
+			continue
+		}
+		if newest == "" || semver.Compare(v, newest) > 0 {
+			newest = v
+		}
+	}
+	return newest
+}
+
+// printVersion prints the version of doc, the revision it was built from
+// and its build settings, as recorded in the binary, and the GOROOT that
+// doc searches with the version of Go it holds, which may differ from the
+// version doc was built with.
+func (s *session) printVersion() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		fmt.Fprintln(s.out, "doc: no build information")
+	} else {
+		fmt.Fprintf(s.out, "doc %s %s\n", info.Main.Path, info.Main.Version)
+		fmt.Fprintf(s.out, "\tbuilt with %s\n", info.GoVersion)
+		for _, setting := range info.Settings {
+			fmt.Fprintf(s.out, "\t%s=%s\n", setting.Key, setting.Value)
+		}
+	}
+	version := gorootVersion()
+	if version == "" {
+		version = "unknown version"
+	}
+	fmt.Fprintf(s.out, "searching GOROOT %s (%s)\n", goRoot, version)
+}
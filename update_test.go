@@ -0,0 +1,60 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNewestVersion(t *testing.T) {
+	const goMod = "module example.com/m\n\nretract [v1.3.0, v1.4.0]\n"
+	tests := []struct {
+		list    string
+		channel string
+		goMod   string
+		want    string
+	}{
+		{"", "stable", "", ""},
+		{"v1.0.0 v1.10.0 v1.9.0", "stable", "", "v1.10.0"},
+		{"v1.0.0 v1.1.0-rc.1", "stable", "", "v1.0.0"},
+		{"v1.0.0 v1.1.0-rc.1", "latest", "", "v1.1.0-rc.1"},
+		{"v1.1.0-rc.2 v1.1.0-rc.10 v1.1.0-rc.9", "latest", "", "v1.1.0-rc.10"},
+		{"v1.1.0-beta v1.1.0-alpha.1", "latest", "", "v1.1.0-beta"},
+		{"v1.0.0 v2.0.0+incompatible", "stable", "", "v2.0.0+incompatible"},
+		{"v1.0.0 latest v1..0", "stable", "", "v1.0.0"},
+		{"v1.2.0 v1.3.0 v1.4.0", "stable", goMod, "v1.2.0"},
+		{"v1.2.0 v1.3.0 v1.4.0 v1.4.1", "stable", goMod, "v1.4.1"},
+		{"v1.3.0 v1.4.0", "stable", goMod, ""},
+	}
+	for _, test := range tests {
+		got := newestVersion(strings.Fields(test.list), test.channel, []byte(test.goMod))
+		if got != test.want {
+			t.Errorf("newestVersion(%q, %s) with %q = %q, want %q", test.list, test.channel, test.goMod, got, test.want)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	out, stderr, status := runDoc(t, t.TempDir(), "-version")
+	if status != 0 {
+		t.Fatalf("doc -version exited with %d; stderr:\n%s", status, stderr)
+	}
+	goVersion, _ := exec.Command("go", "env", "GOVERSION").Output()
+	for _, want := range []string{
+		"\n\tbuilt with " + runtime.Version() + "\n",
+		"\n\t-compiler=gc\n",
+		"\nsearching GOROOT " + goRoot + " (" + strings.TrimSpace(string(goVersion)) + ")\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("doc -version printed\n%s\nwant it to contain %q", out, want)
+		}
+	}
+	if _, _, status := runDoc(t, t.TempDir(), "-version", "strings"); status != 2 {
+		t.Errorf("doc -version strings exited with %d, want 2", status)
+	}
+}
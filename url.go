@@ -0,0 +1,219 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// A urlScheme builds the links doc prints, for one kind of documentation
+// or source server. Servers with other layouts need only implement this
+// interface and be added to urlSchemes.
+type urlScheme interface {
+	// url returns the URL of the declaration, or of its package if the
+	// declaration's name is empty.
+	url(d declaration) string
+}
+
+// A declaration is what a link points to.
+type declaration struct {
+	importPath string // Of the package.
+	kind       int    // As returned by nodeKind.
+	name       string // Name, Type.Method for a method, or empty for the package.
+	file       string // Base name of the file holding it.
+	line       int
+	rev        string // Revision of the source, such as a tag, if known.
+}
+
+// urlSchemes holds the schemes that may be chosen in the configuration file
+// with a line such as
+//	url.scheme pkgsite
+// Each is made for a host, its default or, for a self-hosted server, the
+// one set in the configuration file:
+//	url.host https://godoc.example.com
+// The source schemes, gitea and sourcegraph, link to the line declaring the
+// symbol. Gitea links are to the branch set by url.branch, main by default;
+// Sourcegraph links are to the revision of the source, where doc knows it,
+// or that set by url.rev.
+var urlSchemes = map[string]struct {
+	host string // The default.
+	make func(host string) urlScheme
+}{
+	"pkgsite":       {"https://pkg.go.dev", func(host string) urlScheme { return pkgsite{host} }},
+	"classic-godoc": {"http://localhost:6060", func(host string) urlScheme { return classicGodoc{host} }},
+	"gitea":         {"https://gitea.com", func(host string) urlScheme { return gitea{host} }},
+	"sourcegraph":   {"https://sourcegraph.com", func(host string) urlScheme { return sourcegraph{host} }},
+}
+
+// scheme is the scheme chosen in the configuration file, or nil for the
+// links doc has always printed, once to golang.org and godoc.org and now,
+// by way of modernURL, to pkg.go.dev.
+var scheme = chooseScheme()
+
+func chooseScheme() urlScheme {
+	name := configValue("url.scheme")
+	if name == "" {
+		return nil
+	}
+	preset, ok := urlSchemes[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "doc: config: unknown url.scheme %s\n", name)
+		return nil
+	}
+	host := strings.TrimSuffix(configValue("url.host"), "/")
+	if host == "" {
+		host = preset.host
+	}
+	if modern := modernURL(host); modern != host {
+		// The retired sites redirect only some pages, and not reliably.
+		fmt.Fprintf(os.Stderr, "doc: config: url.host %s is retired; using %s (doc -migrate updates the configuration)\n", host, modern)
+		return pkgsite{modern}
+	}
+	return preset.make(host)
+}
+
+// modernURL returns the URL on pkg.go.dev of the page at a URL on godoc.org
+// or under golang.org/pkg or golang.org/cmd, the sites it replaced, and
+// other URLs unchanged. Links into packages' pages keep their fragments,
+// which pkg.go.dev names as those sites did.
+func modernURL(u string) string {
+	rest, ok := strings.CutPrefix(u, "https://")
+	if !ok {
+		rest, ok = strings.CutPrefix(u, "http://")
+	}
+	if !ok {
+		return u
+	}
+	host, p, _ := strings.Cut(rest, "/")
+	switch strings.TrimPrefix(host, "www.") {
+	case "godoc.org":
+		p = strings.TrimPrefix(p, "pkg/")
+	case "golang.org":
+		if !strings.HasPrefix(p, "pkg/") && p != "pkg" && !strings.HasPrefix(p, "cmd/") {
+			return u
+		}
+		p = strings.TrimPrefix(strings.TrimPrefix(p, "pkg"), "/")
+	default:
+		return u
+	}
+	p, fragment, ok := strings.Cut(p, "#")
+	u = strings.TrimSuffix("https://pkg.go.dev/"+p, "/")
+	if ok {
+		u += "#" + fragment
+	}
+	return u
+}
+
+// pkgsite links to pages like those of pkg.go.dev.
+type pkgsite struct{ host string }
+
+func (p pkgsite) url(d declaration) string {
+	if d.name == "" {
+		return p.host + "/" + d.importPath
+	}
+	return p.host + "/" + d.importPath + "#" + d.name
+}
+
+// classicGodoc links to pages served by the godoc command, which
+// gathers constants and variables into sections of their own.
+type classicGodoc struct{ host string }
+
+func (g classicGodoc) url(d declaration) string {
+	u := g.host + "/pkg/" + d.importPath + "/"
+	switch {
+	case d.name == "":
+		return u
+	case d.kind == constKind:
+		return u + "#pkg-constants"
+	case d.kind == varKind:
+		return u + "#pkg-variables"
+	}
+	return u + "#" + d.name
+}
+
+// gitea links to the source in a Gitea server, whose repositories are
+// named owner/repo, as the second and third elements of the import path.
+type gitea struct{ host string }
+
+func (g gitea) url(d declaration) string {
+	repo, dir := splitRepo(d.importPath)
+	_, ownerRepo, _ := strings.Cut(repo, "/")
+	u := g.host + "/" + ownerRepo + "/src/branch/"
+	if d.name == "" {
+		return u + path.Join(branch(), dir)
+	}
+	return fmt.Sprintf("%s%s#L%d", u, path.Join(branch(), dir, d.file), d.line)
+}
+
+// sourcegraph links to the source as Sourcegraph shows it, at the
+// revision of the source if it is known or else the default branch.
+type sourcegraph struct{ host string }
+
+func (s sourcegraph) url(d declaration) string {
+	repo, dir := splitRepo(d.importPath)
+	if d.rev != "" {
+		repo += "@" + d.rev
+	}
+	if d.name == "" {
+		return s.host + "/" + repo + "/-/tree/" + dir
+	}
+	return fmt.Sprintf("%s/%s/-/blob/%s#L%d", s.host, repo, path.Join(dir, d.file), d.line)
+}
+
+// splitRepo returns the repository holding the package with the import
+// path, taken to be its first three elements as on most hosts, and the
+// package's directory within it. The standard library is in the Go repository.
+func splitRepo(importPath string) (repo, dir string) {
+	elems := strings.Split(importPath, "/")
+	if !strings.Contains(elems[0], ".") {
+		return "github.com/golang/go", path.Join("src", importPath)
+	}
+	if len(elems) < 3 {
+		return importPath, ""
+	}
+	return strings.Join(elems[:3], "/"), strings.Join(elems[3:], "/")
+}
+
+// branch returns the branch that source links refer to.
+func branch() string {
+	if b := configValue("url.branch"); b != "" {
+		return b
+	}
+	return "main"
+}
+
+// declURL returns the link, ending in a newline, for the declaration of the
+// kind with the name, Type.Method for a method, at the line of the file.
+func (f *File) declURL(kind int, name string, line int) string {
+	if scheme == nil {
+		return fmt.Sprintf("%s#%s\n", f.packageURL(), name)
+	}
+	d := f.declaration()
+	d.kind, d.name, d.line = kind, name, line
+	return scheme.url(d) + "\n"
+}
+
+// declaration returns the declaration of the file's package, with its
+// import path and the revision of its source: the version of the module
+// for a package in the module cache, the release of Go for one in GOROOT,
+// or else the revision set by url.rev.
+func (f *File) declaration() declaration {
+	dir := filepath.Dir(f.name)
+	d := declaration{
+		importPath: importPath(dir),
+		file:       filepath.Base(f.name),
+		rev:        configValue("url.rev"),
+	}
+	if pkgPath, version, ok := cachedPackage(dir); ok {
+		d.importPath, d.rev = pkgPath, version
+	} else if strings.HasPrefix(dir, filepath.Join(goRoot, "src")+slash) && strings.HasPrefix(gorootVersion(), "go") {
+		d.rev = gorootVersion()
+	}
+	return d
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestURLSchemes(t *testing.T) {
+	pkg := declaration{importPath: "example.com/owner/repo/sub"}
+	fn := declaration{importPath: "example.com/owner/repo/sub", kind: funcKind, name: "F", file: "f.go", line: 12}
+	method := declaration{importPath: "example.com/owner/repo/sub", kind: funcKind, name: "T.M", file: "t.go", line: 3}
+	constant := declaration{importPath: "example.com/owner/repo/sub", kind: constKind, name: "C", file: "c.go", line: 7}
+	variable := declaration{importPath: "strings", kind: varKind, name: "V", file: "v.go", line: 9}
+	tests := []struct {
+		scheme string
+		d      declaration
+		want   string
+	}{
+		{"pkgsite", pkg, "HOST/example.com/owner/repo/sub"},
+		{"pkgsite", fn, "HOST/example.com/owner/repo/sub#F"},
+		{"pkgsite", method, "HOST/example.com/owner/repo/sub#T.M"},
+		{"classic-godoc", pkg, "HOST/pkg/example.com/owner/repo/sub/"},
+		{"classic-godoc", method, "HOST/pkg/example.com/owner/repo/sub/#T.M"},
+		{"classic-godoc", constant, "HOST/pkg/example.com/owner/repo/sub/#pkg-constants"},
+		{"classic-godoc", variable, "HOST/pkg/strings/#pkg-variables"},
+		{"gitea", pkg, "HOST/owner/repo/src/branch/main/sub"},
+		{"gitea", fn, "HOST/owner/repo/src/branch/main/sub/f.go#L12"},
+		{"sourcegraph", pkg, "HOST/example.com/owner/repo/-/tree/sub"},
+		{"sourcegraph", fn, "HOST/example.com/owner/repo/-/blob/sub/f.go#L12"},
+		{"sourcegraph", variable, "HOST/github.com/golang/go/-/blob/src/strings/v.go#L9"},
+	}
+	for _, test := range tests {
+		if got := urlSchemes[test.scheme].make("HOST").url(test.d); got != test.want {
+			t.Errorf("%s url of %+v = %q, want %q", test.scheme, test.d, got, test.want)
+		}
+	}
+}
+
+func TestChooseScheme(t *testing.T) {
+	tests := []struct {
+		config map[string][]string
+		want   urlScheme
+	}{
+		{nil, nil},
+		{map[string][]string{"url.scheme": {"pkgsite"}}, pkgsite{"https://pkg.go.dev"}},
+		{map[string][]string{"url.scheme": {"classic-godoc"}, "url.host": {"https://godoc.example.com/"}}, classicGodoc{"https://godoc.example.com"}},
+		{map[string][]string{"url.scheme": {"sourcegraph"}}, sourcegraph{"https://sourcegraph.com"}},
+		{map[string][]string{"url.scheme": {"classic-godoc"}, "url.host": {"https://godoc.org"}}, pkgsite{"https://pkg.go.dev"}}, // Retired.
+		{map[string][]string{"url.scheme": {"unknown"}}, nil},
+	}
+	old := config
+	defer func() { config = old }()
+	for _, test := range tests {
+		config = test.config
+		if got := chooseScheme(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("chooseScheme with configuration %v = %#v, want %#v", test.config, got, test.want)
+		}
+	}
+}
+
+func TestModernURL(t *testing.T) {
+	tests := []struct {
+		url, want string
+	}{
+		{"https://godoc.org/golang.org/x/mod/semver", "https://pkg.go.dev/golang.org/x/mod/semver"},
+		{"http://godoc.org/pkg/strings#Cut", "https://pkg.go.dev/strings#Cut"},
+		{"https://www.godoc.org/", "https://pkg.go.dev"},
+		{"http://golang.org/pkg/strings", "https://pkg.go.dev/strings"},
+		{"http://golang.org/pkg", "https://pkg.go.dev"},
+		{"https://golang.org/cmd/go#hdr-Build", "https://pkg.go.dev/cmd/go#hdr-Build"},
+		{"https://golang.org/doc/effective_go", "https://golang.org/doc/effective_go"},
+		{"https://gitea.com/owner/repo", "https://gitea.com/owner/repo"},
+		{"godoc.org/strings", "godoc.org/strings"},
+	}
+	for _, test := range tests {
+		if got := modernURL(test.url); got != test.want {
+			t.Errorf("modernURL(%q) = %q, want %q", test.url, got, test.want)
+		}
+	}
+}
+
+func TestDeclarationRevision(t *testing.T) {
+	fakeGOROOT(t, map[string]string{"VERSION": "go1.99.1\ntime 2026-01-01T00:00:00Z\n"})
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	dir := writeModule(t, testModule)
+	old := config
+	defer func() { config = old }()
+	tests := []struct {
+		file string
+		rev  string // Set by url.rev.
+		want declaration
+	}{
+		{
+			file: filepath.Join(cache, "github.com", "!big!co", "lib@v1.2.3", "sub", "s.go"),
+			want: declaration{importPath: "github.com/BigCo/lib/sub", file: "s.go", rev: "v1.2.3"},
+		},
+		{
+			file: filepath.Join(goRoot, "src", "strings", "v.go"),
+			want: declaration{importPath: "strings", file: "v.go", rev: "go1.99.1"},
+		},
+		{
+			file: filepath.Join(dir, "internal", "auth", "auth.go"),
+			rev:  "v2.0.0",
+			want: declaration{importPath: "example.com/m/v2/internal/auth", file: "auth.go", rev: "v2.0.0"},
+		},
+		{
+			file: filepath.Join(dir, "m.go"),
+			want: declaration{importPath: "example.com/m/v2", file: "m.go"},
+		},
+	}
+	for _, test := range tests {
+		config = map[string][]string{}
+		if test.rev != "" {
+			config["url.rev"] = []string{test.rev}
+		}
+		f := &File{name: test.file}
+		if got := f.declaration(); got != test.want {
+			t.Errorf("declaration of %s = %+v, want %+v", test.file, got, test.want)
+		}
+	}
+	// The revision goes into Sourcegraph links.
+	f := &File{name: filepath.Join(goRoot, "src", "strings", "v.go")}
+	d := f.declaration()
+	d.kind, d.name, d.line = funcKind, "F", 4
+	if got, want := (sourcegraph{"https://sourcegraph.com"}).url(d), "https://sourcegraph.com/github.com/golang/go@go1.99.1/-/blob/src/strings/v.go#L4"; got != want {
+		t.Errorf("Sourcegraph link %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A reference is a use of an exported name through a qualified identifier,
+// pkg.Name, in a file that imports the package, or unqualified, in a file
+// that imports it with a dot. References are found syntactically, so
+// methods and fields, which are selected through values rather than
+// package names, are not references.
+type reference struct {
+	from string         // Import path of the referring package.
+	path string         // Import path of the referenced package.
+	name string         // The name referenced.
+	pos  token.Position // Where the reference appears.
+	fn   *ast.FuncDecl  // The function holding the reference, if any.
+}
+
+// walkReferences calls fn for every reference in the non-test Go files in
+// the directories, parsed with the mode, which must include
+// parser.ParseComments if fn needs the doc comments of the functions
+// holding the references.
+func walkReferences(dirs []string, mode parser.Mode, fn func(reference)) {
+	for _, dir := range dirs {
+		fset := token.NewFileSet()
+		notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+		pkgs, _ := parseDir(fset, dir, notTest, mode) // Ignore the error.
+		from := importPath(dir)
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				fileReferences(fset, from, file, fn)
+			}
+		}
+	}
+}
+
+// fileReferences calls fn for every reference in the file.
+func fileReferences(fset *token.FileSet, from string, file *ast.File, fn func(reference)) {
+	imports := make(map[string]string) // Local name to import path.
+	dotted := make(map[string]string)  // Name exported by a dot-imported package to its import path.
+	for _, imp := range file.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		switch name := importName(imp); name {
+		case "_":
+		case ".":
+			for name := range exportedNames(p) {
+				dotted[name] = p
+			}
+		default:
+			imports[name] = p
+		}
+	}
+	for _, decl := range file.Decls {
+		funcDecl, _ := decl.(*ast.FuncDecl)
+		selected := make(map[*ast.Ident]bool) // Names selected from something, not referring to a package's.
+		if funcDecl != nil {
+			selected[funcDecl.Name] = true // A method's name is resolved to nothing.
+		}
+		ast.Inspect(decl, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.SelectorExpr:
+				selected[node.Sel] = true
+				x, ok := node.X.(*ast.Ident)
+				if !ok || x.Obj != nil { // A local declaration shadows the import.
+					return true
+				}
+				if p, ok := imports[x.Name]; ok {
+					fn(reference{from, p, node.Sel.Name, fset.Position(node.Sel.Pos()), funcDecl})
+					return false
+				}
+			case *ast.Ident:
+				// An unresolved name is declared in another file of the
+				// package, in the universe, or in a dot-imported package.
+				if p, ok := dotted[node.Name]; ok && node.Obj == nil && !selected[node] {
+					fn(reference{from, p, node.Name, fset.Position(node.Pos()), funcDecl})
+				}
+			}
+			return true
+		})
+	}
+}
+
+// packageNames caches importedName and exportedNames, by import path.
+var packageNames = struct {
+	sync.Mutex
+	name     map[string]string
+	exported map[string]map[string]bool
+}{name: make(map[string]string), exported: make(map[string]map[string]bool)}
+
+// importedName returns the name the package with the import path declares
+// in its package clause, if its source is found, or else the name it most
+// likely declares, as packageNameOf guesses it: the name by which a file
+// that imports it without naming it refers to it.
+func importedName(pkgPath string) string {
+	packageNames.Lock()
+	defer packageNames.Unlock()
+	if name, ok := packageNames.name[pkgPath]; ok {
+		return name
+	}
+	name := packageNameOf(pkgPath)
+	if dir := dirForImport(pkgPath, ""); dir != "" {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			fileName := entry.Name()
+			if !strings.HasSuffix(fileName, ".go") || strings.HasSuffix(fileName, "_test.go") {
+				continue
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, fileName), nil, parser.PackageClauseOnly)
+			if err == nil && file.Name.Name != "documentation" { // As go/build ignores it.
+				name = file.Name.Name
+				break
+			}
+		}
+	}
+	packageNames.name[pkgPath] = name
+	return name
+}
+
+// exportedNames returns the set of exported package-level names of the
+// package with the import path, if its source is found.
+func exportedNames(pkgPath string) map[string]bool {
+	packageNames.Lock()
+	names, ok := packageNames.exported[pkgPath]
+	packageNames.Unlock()
+	if ok {
+		return names
+	}
+	names = make(map[string]bool)
+	if dir := dirForImport(pkgPath, ""); dir != "" {
+		for _, sym := range exportedSymbols(dir) {
+			names[sym.name] = true
+		}
+	}
+	packageNames.Lock()
+	packageNames.exported[pkgPath] = names
+	packageNames.Unlock()
+	return names
+}
+
+// searchDirs returns all the package directories in the current module and in GOPATH,
+// the places code that refers to a package being examined might live.
+func (s *session) searchDirs() []string {
+	var dirs []string
+	if modDir != "" {
+		dirs = s.dirsFor(modDir, "")
+	}
+	for _, root := range goPaths {
+		dirs = append(dirs, s.pathsFor(root, "")...)
+	}
+	return dirs
+}
+
+// treeDirs returns the directories named by a command-line pattern: a directory,
+// or one followed by /... for it and all directories below it. The empty
+// pattern means the current module.
+func (s *session) treeDirs(pattern string) []string {
+	if pattern == "" {
+		if modDir == "" {
+			fmt.Fprintf(os.Stderr, "doc: not in a module\n")
+			exit(2)
+		}
+		return s.dirsFor(modDir, "")
+	}
+	dir, all := strings.CutSuffix(pattern, "/...")
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doc: %s\n", err)
+		exit(2)
+	}
+	if all {
+		return s.dirsFor(dir, "")
+	}
+	return []string{dir}
+}
+
+// A symbol is an exported package-level declaration.
+type symbol struct {
+	path string // Import path of the package.
+	pkg  string // Package name.
+	name string
+	pos  token.Position
+	doc  string // Synopsis of its doc comment.
+}
+
+// exportedSymbols returns the exported package-level declarations of the
+// importable (non-main, non-test) package in the directory.
+func exportedSymbols(dir string) []symbol {
+	fset := token.NewFileSet()
+	notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+	pkgs, _ := parseDir(fset, dir, notTest, parser.ParseComments) // Ignore the error.
+	var syms []symbol
+	for _, pkg := range pkgs {
+		if pkg.Name == "main" {
+			continue
+		}
+		add := func(id *ast.Ident, doc *ast.CommentGroup) {
+			if id.IsExported() {
+				syms = append(syms, symbol{importPath(dir), pkg.Name, id.Name, fset.Position(id.Pos()), synopsis(doc)})
+			}
+		}
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					if decl.Recv == nil {
+						add(decl.Name, decl.Doc)
+					}
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						switch spec := spec.(type) {
+						case *ast.TypeSpec:
+							doc := spec.Doc
+							if doc == nil {
+								doc = decl.Doc
+							}
+							add(spec.Name, doc)
+						case *ast.ValueSpec:
+							doc := spec.Doc
+							if doc == nil {
+								doc = decl.Doc
+							}
+							for _, id := range spec.Names {
+								add(id, doc)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(syms, func(i, j int) bool {
+		if syms[i].pos.Filename != syms[j].pos.Filename {
+			return syms[i].pos.Filename < syms[j].pos.Filename
+		}
+		return syms[i].pos.Line < syms[j].pos.Line
+	})
+	return syms
+}
+
+// unused prints the exported package-level symbols of the packages named by
+// the pattern that no other package in the module or GOPATH refers to.
+func (s *session) unused(pattern string) {
+	var syms []symbol
+	for _, dir := range s.treeDirs(pattern) {
+		syms = append(syms, exportedSymbols(dir)...)
+	}
+	used := make(map[string]bool) // Keyed by path.name.
+	walkReferences(s.searchDirs(), 0, func(ref reference) {
+		if ref.from != ref.path {
+			used[ref.path+"."+ref.name] = true
+		}
+	})
+	for _, sym := range syms {
+		if !used[sym.path+"."+sym.name] {
+			fmt.Fprintf(s.out, "%s:%d: %s.%s\n", sym.pos.Filename, sym.pos.Line, sym.pkg, sym.name)
+		}
+	}
+}
+
+// collisions prints the exported package-level names declared in more
+// than one of the packages named by the pattern, most widespread first,
+// each with where it is declared and the synopsis of its doc comment.
+func (s *session) collisions(pattern string) {
+	byName := make(map[string][]symbol)
+	for _, dir := range s.treeDirs(pattern) {
+		for _, sym := range exportedSymbols(dir) {
+			byName[sym.name] = append(byName[sym.name], sym)
+		}
+	}
+	var names []string
+	count := make(map[string]int) // Packages declaring the name.
+	for name, syms := range byName {
+		paths := make(map[string]bool)
+		for _, sym := range syms {
+			paths[sym.path] = true
+		}
+		if len(paths) > 1 {
+			names = append(names, name)
+			count[name] = len(paths)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ni, nj := count[names[i]], count[names[j]]
+		if ni != nj {
+			return ni > nj
+		}
+		return lessFold(names[i], names[j])
+	})
+	for i, name := range names {
+		if i > 0 {
+			fmt.Fprintln(s.out)
+		}
+		syms := byName[name]
+		fmt.Fprintf(s.out, "%s: declared in %d packages\n", name, count[name])
+		for _, sym := range syms {
+			fmt.Fprintf(s.out, "\t%s:%d: %s.%s", sym.pos.Filename, sym.pos.Line, sym.pkg, sym.name)
+			if sym.doc != "" {
+				fmt.Fprintf(s.out, " // %s", sym.doc)
+			}
+			fmt.Fprintln(s.out)
+		}
+	}
+}
+
+// importCounts returns, for each import path, the number of packages in the
+// directories whose non-test files import it.
+func importCounts(dirs []string) map[string]int {
+	counts := make(map[string]int)
+	for _, dir := range dirs {
+		fset := token.NewFileSet()
+		notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+		pkgs, _ := parseDir(fset, dir, notTest, parser.ImportsOnly) // Ignore the error.
+		seen := make(map[string]bool)
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				for _, imp := range file.Imports {
+					p, err := strconv.Unquote(imp.Path.Value)
+					if err == nil && !seen[p] {
+						seen[p] = true
+						counts[p]++
+					}
+				}
+			}
+		}
+	}
+	return counts
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnusedImportNames(t *testing.T) {
+	writeModule(t, testModule)
+	var out bytes.Buffer
+	newSession(&out).unused("")
+	tests := []struct {
+		name   string
+		unused bool
+	}{
+		{"m.Config", false},  // Through a module path ending in /v2.
+		{"auth.Make", false}, // Through a dot import.
+		{"m.Alias", true},
+		{"auth.Token", true},
+	}
+	for _, test := range tests {
+		if got := strings.Contains(out.String(), ": "+test.name+"\n"); got != test.unused {
+			t.Errorf("-unused reports %s: %t, want %t; output:\n%s", test.name, got, test.unused, out.String())
+		}
+	}
+}
+
+func TestRenameImpactImportNames(t *testing.T) {
+	dir := writeModule(t, testModule)
+	tests := []struct {
+		arg  string
+		want string // A reference that must be listed.
+	}{
+		{dir + "/internal/auth.Make", dir + "/v/v.go:5"},    // Through a dot import.
+		{dir + ".Config", dir + "/internal/auth/auth.go:7"}, // Through a module path ending in /v2.
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		newSession(&out).renameImpact(test.arg)
+		if !strings.Contains(out.String(), "\t"+test.want+"\n") {
+			t.Errorf("-rename-impact %s printed\n%s\nwant it to list %s", test.arg, out.String(), test.want)
+		}
+	}
+}
+
+func TestRenameImpact(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/r\n\ngo 1.22\n",
+		"r.go": `package r
+
+func Name() {}
+
+func use() {
+	Name()
+	Name()
+}
+
+func Other() {}
+`,
+		"r_test.go": "package r\n\nfunc useInTest() { Name() }\n",
+		"a/a.go": `package a
+
+import "example.com/r"
+
+var f = r.Name
+
+func g() { r.Name(); r.Other() }
+`,
+		"b/b.go": `package b
+
+import alias "example.com/r"
+
+var f = alias.Name
+`,
+	})
+	tests := []struct {
+		arg    string
+		want   string // With the module directory as $DIR.
+		status int
+	}{
+		{
+			arg: dir + ".Name",
+			want: `example.com/r (5)
+	$DIR/a/a.go:5
+	$DIR/a/a.go:7
+	$DIR/b/b.go:5
+	$DIR/r.go:6
+	$DIR/r.go:7
+5 references in 1 modules
+`,
+		},
+		{
+			arg: dir + ".Other",
+			want: `example.com/r (1)
+	$DIR/a/a.go:7
+1 references in 1 modules
+`,
+		},
+		{arg: dir + ".Missing", status: 1},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).renameImpact(test.arg) })
+		if status != test.status {
+			t.Errorf("-rename-impact %s exited with %d, want %d", test.arg, status, test.status)
+			continue
+		}
+		if got := strings.ReplaceAll(out.String(), dir, "$DIR"); status == 0 && got != test.want {
+			t.Errorf("-rename-impact %s printed\n%s\nwant\n%s", test.arg, got, test.want)
+		}
+	}
+}
+
+func TestCollisions(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":          "module example.com/col\n\ngo 1.22\n",
+		"a/a.go":          "package a\n\n// Config configures a.\ntype Config struct{}\n\n// New makes an a.\nfunc New() {}\n\n// Only is only in a.\nfunc Only() {}\n",
+		"b/b.go":          "package b\n\n// Config configures b.\ntype Config struct{}\n\n// New makes a b.\nfunc New() {}\n",
+		"b/b2.go":         "package b\n\nconst (\n\t// Max is the most.\n\tMax = 1\n)\n",
+		"c/c.go":          "package c\n\nvar Max = 2\n\n// New makes a c.\nfunc New() {}\n\nfunc Config() {}\n",
+		"c/c_test.go":     "package c\n\nfunc Only() {}\n",
+		"d/internal/x.go": "package internal\n\n// config is unexported.\nfunc config() {}\n",
+	})
+	var out bytes.Buffer
+	newSession(&out).collisions("")
+	want := strings.ReplaceAll(`Config: declared in 3 packages
+	DIR/a/a.go:4: a.Config // Config configures a.
+	DIR/b/b.go:4: b.Config // Config configures b.
+	DIR/c/c.go:8: c.Config
+
+New: declared in 3 packages
+	DIR/a/a.go:7: a.New // New makes an a.
+	DIR/b/b.go:7: b.New // New makes a b.
+	DIR/c/c.go:6: c.New // New makes a c.
+
+Max: declared in 2 packages
+	DIR/b/b2.go:5: b.Max // Max is the most.
+	DIR/c/c.go:3: c.Max
+`, "DIR", dir)
+	if out.String() != want {
+		t.Errorf("-collisions printed\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	newSession(&out).collisions(filepath.Join(dir, "a") + "/...")
+	if out.String() != "" {
+		t.Errorf("-collisions in one package printed\n%s\nwant nothing", out.String())
+	}
+}
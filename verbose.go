@@ -0,0 +1,368 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/doc/comment"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// verbose prints, after the documentation for the declaration of ident,
+// the material a learner needs to use it: its examples, the functions
+// that construct it (for a type), one-line synopses of the types in its
+// signature (for a function), and synopses of the symbols its comment links to.
+func (f *File) verbose(node ast.Node, ident *ast.Ident) {
+	w := f.output(nodeKind(node))
+	recv := ""
+	if fn, ok := node.(*ast.FuncDecl); ok {
+		recv = recvTypeName(fn)
+	}
+	f.printExamples(w, recv, ident.Name)
+	switch obj := f.objs[ident].(type) {
+	case *types.TypeName:
+		f.printConstructors(w, obj)
+	case *types.Func:
+		f.printSignatureTypes(w, obj)
+	}
+	f.printSeeAlso(w, node)
+}
+
+// printExamples prints the examples for the symbol, which is a method if recv is set.
+func (f *File) printExamples(w io.Writer, recv, name string) {
+	for _, ex := range f.examplesOf(recv, name) {
+		fmt.Fprintf(w, "Example%s:\n%s\n", ex.Name, indent(ex.Doc))
+		if ex.Output != "" {
+			fmt.Fprintf(w, "Output:\n%s\n", indent(strings.TrimSuffix(ex.Output, "\n")))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// examplesOf returns the examples for the symbol, which is a method if
+// recv is set, from the tests of its package. Their Doc fields hold their
+// code, as printed.
+func (f *File) examplesOf(recv, name string) []*doc.Example {
+	dir := filepath.Dir(f.name)
+	examples, ok := f.s.examples[dir]
+	if ok {
+		stats.cacheHits.Add(1)
+	} else {
+		fset := token.NewFileSet()
+		isTest := func(info os.FileInfo) bool { return strings.HasSuffix(info.Name(), "_test.go") }
+		pkgs, _ := parseDir(fset, dir, isTest, parser.ParseComments) // Ignore the error.
+		var files []*ast.File
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				files = append(files, file)
+			}
+		}
+		for _, ex := range doc.Examples(files...) {
+			ex.Doc = exampleCode(fset, ex.Code) // Keep the printed code; the AST needs its FileSet.
+			examples = append(examples, ex)
+		}
+		f.s.examples[dir] = examples
+	}
+	key := name
+	if recv != "" {
+		key = recv + "_" + name
+	}
+	var matches []*doc.Example
+	for _, ex := range examples {
+		// Examples are named Example, or Example_suffix with a lower-case suffix.
+		suffix, ok := strings.CutPrefix(ex.Name, key)
+		if !ok || suffix != "" && !(strings.HasPrefix(suffix, "_") && len(suffix) > 1 && unicode.IsLower(rune(suffix[1]))) {
+			continue
+		}
+		matches = append(matches, ex)
+	}
+	return matches
+}
+
+// exampleCode returns the source of an example's code, without the braces
+// if it is a block.
+func exampleCode(fset *token.FileSet, code ast.Node) string {
+	var b bytes.Buffer
+	if block, ok := code.(*ast.BlockStmt); ok {
+		for _, stmt := range block.List {
+			printer.Fprint(&b, fset, stmt)
+			b.WriteByte('\n')
+		}
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+	printer.Fprint(&b, fset, code)
+	return b.String()
+}
+
+// printConstructors prints the package-level functions that return the type or a pointer to it.
+func (f *File) printConstructors(w io.Writer, obj *types.TypeName) {
+	if obj.Pkg() == nil {
+		return
+	}
+	scope := obj.Pkg().Scope()
+	var lines []string
+	for _, name := range scope.Names() {
+		fn, ok := scope.Lookup(name).(*types.Func)
+		if !ok || !fn.Exported() {
+			continue
+		}
+		results := fn.Type().(*types.Signature).Results()
+		for i := 0; i < results.Len(); i++ {
+			typ := results.At(i).Type()
+			if ptr, ok := typ.(*types.Pointer); ok {
+				typ = ptr.Elem()
+			}
+			if named, ok := typ.(*types.Named); ok && named.Obj() == obj {
+				lines = append(lines, f.synopsisLine(fn))
+				break
+			}
+		}
+	}
+	printSection(w, "Constructors", lines)
+}
+
+// printSignatureTypes prints a synopsis of each named type in the function's signature.
+func (f *File) printSignatureTypes(w io.Writer, fn *types.Func) {
+	sig := fn.Type().(*types.Signature)
+	seen := make(map[*types.TypeName]bool)
+	var lines []string
+	var visit func(types.Type)
+	visit = func(typ types.Type) {
+		switch t := typ.(type) {
+		case *types.Pointer:
+			visit(t.Elem())
+		case *types.Slice:
+			visit(t.Elem())
+		case *types.Array:
+			visit(t.Elem())
+		case *types.Map:
+			visit(t.Key())
+			visit(t.Elem())
+		case *types.Chan:
+			visit(t.Elem())
+		case *types.Named:
+			obj := t.Obj()
+			if obj.Pkg() != nil && obj.Exported() && !seen[obj] {
+				seen[obj] = true
+				lines = append(lines, f.synopsisLine(obj))
+			}
+		}
+	}
+	for _, tuple := range []*types.Tuple{sig.Params(), sig.Results()} {
+		for i := 0; i < tuple.Len(); i++ {
+			visit(tuple.At(i).Type())
+		}
+	}
+	printSection(w, "Types in signature", lines)
+}
+
+// printSeeAlso prints a synopsis of each symbol linked to, as in [io.Reader],
+// from the doc comment of the node.
+func (f *File) printSeeAlso(w io.Writer, node ast.Node) {
+	var cg *ast.CommentGroup
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		cg = n.Doc
+	case *ast.GenDecl:
+		cg = n.Doc
+	case *ast.TypeSpec:
+		cg = n.Doc
+	}
+	if cg == nil {
+		return
+	}
+	var lines []string
+	seen := make(map[string]bool)
+	for _, link := range docLinks(cg.Text(), nil) {
+		name := link.Name
+		if link.Recv != "" {
+			name = link.Recv + "." + name
+		}
+		if link.ImportPath != "" {
+			name = link.ImportPath + "." + name
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		line := "\t" + name
+		if s := f.linkSynopsis(link); s != "" {
+			line += "\n\t\t" + s
+		}
+		lines = append(lines, line)
+	}
+	printSection(w, "See also", lines)
+}
+
+// docLinks returns the doc links, such as [bytes.Buffer], in the comment text.
+// If lookupPackage is non-nil, it resolves package names, as in comment.Parser.
+func docLinks(text string, lookupPackage func(name string) (importPath string, ok bool)) []*comment.DocLink {
+	var links []*comment.DocLink
+	var walk func([]comment.Text)
+	walk = func(texts []comment.Text) {
+		for _, t := range texts {
+			switch t := t.(type) {
+			case *comment.DocLink:
+				links = append(links, t)
+			case *comment.Link:
+				walk(t.Text)
+			case *comment.Italic, comment.Plain:
+			}
+		}
+	}
+	var blocks func([]comment.Block)
+	blocks = func(list []comment.Block) {
+		for _, b := range list {
+			switch b := b.(type) {
+			case *comment.Paragraph:
+				walk(b.Text)
+			case *comment.Heading:
+				walk(b.Text)
+			case *comment.List:
+				for _, item := range b.Items {
+					blocks(item.Content)
+				}
+			}
+		}
+	}
+	p := &comment.Parser{
+		LookupPackage: lookupPackage,
+		LookupSym:     func(recv, name string) bool { return true },
+	}
+	blocks(p.Parse(text).Content)
+	return links
+}
+
+// linkSynopsis returns the first sentence of the documentation for the doc link's target.
+func (f *File) linkSynopsis(link *comment.DocLink) string {
+	var files []*ast.File
+	if link.ImportPath == "" {
+		for _, file := range f.allFiles {
+			files = append(files, file.file)
+		}
+	} else {
+		dir := dirForImport(link.ImportPath, filepath.Dir(f.name))
+		if dir == "" {
+			// Perhaps vendored: look among the directories named for the
+			// path's last element for the one it is imported as. This is
+			// the directory's name, even a major version such as v2, not
+			// the package's, so importedName does not apply.
+			for _, d := range f.s.paths(path.Base(link.ImportPath)) {
+				if importPath(d) == link.ImportPath {
+					dir = d
+					break
+				}
+			}
+		}
+		if dir == "" {
+			return ""
+		}
+		files = f.s.parsePackageFiles(dir)
+	}
+	return synopsis(findDoc(files, link.Recv, link.Name))
+}
+
+// synopsisLine returns a line, for a section, naming the object and giving its synopsis.
+func (f *File) synopsisLine(obj types.Object) string {
+	qual := func(p *types.Package) string { return p.Name() }
+	line := "\t" + types.ObjectString(obj, qual)
+	if _, ok := obj.(*types.TypeName); ok {
+		line = "\t" + obj.Pkg().Name() + "." + obj.Name()
+	}
+	var files []*ast.File
+	if obj.Pkg() == f.types {
+		for _, file := range f.allFiles {
+			files = append(files, file.file)
+		}
+	} else if dir := dirForImport(obj.Pkg().Path(), filepath.Dir(f.name)); dir != "" {
+		files = f.s.parsePackageFiles(dir)
+	}
+	if s := synopsis(findDoc(files, "", obj.Name())); s != "" {
+		line += "\n\t\t" + s
+	}
+	return line
+}
+
+// parsePackageFiles returns the parsed non-test files, with comments, in the directory.
+func (s *session) parsePackageFiles(dir string) []*ast.File {
+	if files, ok := s.parsedFiles[dir]; ok {
+		stats.cacheHits.Add(1)
+		return files
+	}
+	fset := token.NewFileSet()
+	notTest := func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+	pkgs, _ := parseDir(fset, dir, notTest, parser.ParseComments) // Ignore the error.
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+	s.parsedFiles[dir] = files
+	return files
+}
+
+// findDoc returns the doc comment of the package-level declaration, or method
+// if recv is set, with the name in the files.
+func findDoc(files []*ast.File, recv, name string) *ast.CommentGroup {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Name.Name == name && recvTypeName(decl) == recv {
+					return decl.Doc
+				}
+			case *ast.GenDecl:
+				if recv != "" {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					var names []*ast.Ident
+					var specDoc *ast.CommentGroup
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						names, specDoc = []*ast.Ident{spec.Name}, spec.Doc
+					case *ast.ValueSpec:
+						names, specDoc = spec.Names, spec.Doc
+					}
+					for _, id := range names {
+						if id.Name == name {
+							if specDoc != nil {
+								return specDoc
+							}
+							return decl.Doc
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// printSection prints the lines under the heading, if there are any.
+func printSection(w io.Writer, heading string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s:\n%s\n\n", heading, strings.Join(lines, "\n"))
+}
+
+// indent returns the text with each line indented by a tab.
+func indent(text string) string {
+	return "\t" + strings.ReplaceAll(text, "\n", "\n\t")
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/doc/comment"
+	"io"
+	"testing"
+)
+
+func TestLinkSynopsisVendored(t *testing.T) {
+	writeModule(t, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.22\n",
+		"vendor/example.com/dep/sub/sub.go": `package sub
+
+// Thing is the vendored thing.
+type Thing int
+`,
+		"vendor/example.com/other/sub/sub.go": `package sub
+
+// Thing is another package's thing.
+type Thing int
+`,
+	})
+	f := &File{s: newSession(io.Discard)}
+	link := &comment.DocLink{ImportPath: "example.com/dep/sub", Name: "Thing"}
+	if got, want := f.linkSynopsis(link), "Thing is the vendored thing."; got != want {
+		t.Errorf("synopsis of [example.com/dep/sub.Thing] = %q, want %q", got, want)
+	}
+}
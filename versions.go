@@ -0,0 +1,221 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// An apiEntry is a line of the API files in GOROOT/api, which record,
+// for each release of Go, the exported API of the standard library that
+// it added.
+type apiEntry struct {
+	version string // Release that added the line, such as go1.20.
+	minor   int    // Its minor version, for sorting.
+	pkgPath string
+	symbol  string // Name, Type.Method, Type.Field or Interface.Method.
+	decl    string // Declaration as recorded, such as "func CutPrefix(string, string) (string, bool)".
+}
+
+// apiIssue matches the issue number that ends some lines of the API files.
+var apiIssue = regexp.MustCompile(` #\d+$`)
+
+// apiVersions prints, from the API files of GOROOT, the release of Go that
+// added the standard library symbol, given as pkg.Name or pkg.Type.Method,
+// and each release that changed its declaration or deprecated it.
+func (s *session) apiVersions(arg string) {
+	if !strings.Contains(arg, ".") {
+		fmt.Fprintf(os.Stderr, "doc: -versions: want pkg.Name, not %s\n", arg)
+		exit(2)
+	}
+	pkg, name := split(arg)
+	dir := filepath.Join(goRoot, "api")
+	names, _ := filepath.Glob(filepath.Join(dir, "go1*.txt"))
+	if len(names) == 0 {
+		fmt.Fprintf(os.Stderr, "doc: -versions: no API files in %s\n", dir)
+		exit(1)
+	}
+	var entries []apiEntry
+	for _, file := range names {
+		version := strings.TrimSuffix(filepath.Base(file), ".txt")
+		minor := 0
+		if _, m, ok := strings.Cut(version, "."); ok {
+			minor, _ = strconv.Atoi(m)
+		}
+		for _, e := range readAPI(file) {
+			if importedName(e.pkgPath) == pkg && equalFold(e.symbol, name) {
+				e.version, e.minor = version, minor
+				entries = append(entries, e)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "doc: -versions: %s is not in the API of any release of Go in %s\n", arg, dir)
+		exit(1)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].minor < entries[j].minor })
+	changed := make(map[string]bool) // Lines since changed or removed.
+	for _, e := range readAPI(filepath.Join(dir, "except.txt")) {
+		changed[e.pkgPath+", "+e.decl] = true
+	}
+	for _, pkgPath := range packagesOf(entries) {
+		fmt.Fprintf(s.out, "%s.%s\n", pkgPath, entries[0].symbol)
+		seen := make(map[string]bool)
+		for _, e := range entries {
+			if e.pkgPath != pkgPath || seen[e.decl] {
+				continue
+			}
+			seen[e.decl] = true
+			note := ""
+			if changed[e.pkgPath+", "+e.decl] {
+				note = " (since changed or removed)"
+			}
+			if decl, ok := strings.CutSuffix(e.decl, " //deprecated"); ok {
+				fmt.Fprintf(s.out, "\t%s\tdeprecated: %s\n", e.version, decl)
+				continue
+			}
+			fmt.Fprintf(s.out, "\t%s\t%s%s\n", e.version, e.decl, note)
+		}
+	}
+	if v := gorootVersion(); v != "" {
+		fmt.Fprintf(s.out, "GOROOT holds %s\n", v)
+	}
+}
+
+// packagesOf returns the import paths of the entries, in order of first appearance.
+func packagesOf(entries []apiEntry) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if !seen[e.pkgPath] {
+			seen[e.pkgPath] = true
+			paths = append(paths, e.pkgPath)
+		}
+	}
+	return paths
+}
+
+// readAPI returns the entries of the API file, without versions. Entries
+// for particular systems, such as those of package syscall, are kept once.
+func readAPI(file string) []apiEntry {
+	fd, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer fd.Close()
+	var entries []apiEntry
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		line, ok := strings.CutPrefix(scanner.Text(), "pkg ")
+		if !ok {
+			continue
+		}
+		pkgPath, decl, ok := strings.Cut(apiIssue.ReplaceAllString(line, ""), ", ")
+		if !ok {
+			continue
+		}
+		pkgPath, _, _ = strings.Cut(pkgPath, " ") // Drop a system, such as (linux-386).
+		entries = append(entries, apiEntry{pkgPath: pkgPath, symbol: apiSymbol(decl), decl: decl})
+	}
+	return entries
+}
+
+// apiSymbol returns the name of the symbol the declaration from an API file declares:
+//	func Name(...)			Name
+//	method (*Type) Name(...)	Type.Name
+//	type Type struct, Field T	Type.Field
+//	type Type interface, M(...)	Type.M
+//	type Name ..., var Name ..., const Name ...	Name
+func apiSymbol(decl string) string {
+	kind, rest, _ := strings.Cut(decl, " ")
+	if kind == "method" {
+		recv, rest, _ := strings.Cut(rest, ") ")
+		recv = strings.TrimLeft(recv, "(*")
+		recv, _, _ = strings.Cut(recv, "[") // Type parameters.
+		return recv + "." + apiName(rest)
+	}
+	if kind == "type" && !strings.Contains(rest, " { ") { // Not the list of an interface's methods.
+		if typ, member, ok := strings.Cut(rest, ", "); ok {
+			return apiName(typ) + "." + apiName(member)
+		}
+	}
+	return apiName(rest)
+}
+
+// apiName returns the identifier that begins the text.
+func apiName(text string) string {
+	if i := strings.IndexAny(text, " ([,"); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
+
+// addedAPI prints the standard library symbols that the release of Go,
+// such as go1.22, added, with their synopses, grouped by package. If pkg
+// is not empty, it prints only the symbols of that package.
+func (s *session) addedAPI(version, pkg string) {
+	version = strings.TrimSuffix(version, ".0")
+	if !strings.HasPrefix(version, "go") {
+		version = "go" + version
+	}
+	file := filepath.Join(goRoot, "api", version+".txt")
+	if _, err := os.Stat(file); err != nil {
+		fmt.Fprintf(os.Stderr, "doc: -added: no API file for %s in %s\n", version, filepath.Dir(file))
+		exit(1)
+	}
+	var entries []apiEntry
+	seen := make(map[string]bool)
+	for _, e := range readAPI(file) {
+		if pkg != "" && e.pkgPath != pkg && importedName(e.pkgPath) != pkg {
+			continue
+		}
+		if !seen[e.pkgPath+", "+e.decl] {
+			seen[e.pkgPath+", "+e.decl] = true
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "doc: -added: %s added nothing to package %s\n", version, pkg)
+		exit(1)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].pkgPath < entries[j].pkgPath })
+	for i, e := range entries {
+		if i > 0 && e.pkgPath != entries[i-1].pkgPath {
+			fmt.Fprintln(s.out)
+		}
+		if i == 0 || e.pkgPath != entries[i-1].pkgPath {
+			fmt.Fprintf(s.out, "%s\n", e.pkgPath)
+		}
+		decl, deprecated := strings.CutSuffix(e.decl, " //deprecated")
+		if deprecated {
+			fmt.Fprintf(s.out, "\tdeprecated: %s\n", decl)
+			continue
+		}
+		fmt.Fprintf(s.out, "\t%s\n", decl)
+		if syn := s.apiSynopsis(e); syn != "" {
+			fmt.Fprintf(s.out, "\t\t%s\n", syn)
+		}
+	}
+}
+
+// apiSynopsis returns the first sentence of the documentation, in GOROOT,
+// for the symbol of the entry. Fields and interface methods have none.
+func (s *session) apiSynopsis(e apiEntry) string {
+	recv, name := "", e.symbol
+	if strings.HasPrefix(e.decl, "method ") {
+		recv, name, _ = strings.Cut(e.symbol, ".")
+	} else if strings.Contains(e.symbol, ".") {
+		return ""
+	}
+	dir := filepath.Join(goRoot, "src", filepath.FromSlash(e.pkgPath))
+	return synopsis(findDoc(s.parsePackageFiles(dir), recv, name))
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVersionsMajorVersion(t *testing.T) {
+	var out bytes.Buffer
+	newSession(&out).apiVersions("rand.N")
+	// math/rand/v2 is package rand, not v2.
+	if want := "math/rand/v2.N\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("-versions rand.N printed\n%s\nwant %q", out.String(), want)
+	}
+}
+
+// testAPI holds the API files of a GOROOT in which package strings grew
+// over three releases.
+var testAPI = map[string]string{
+	"api/go1.1.txt": `pkg strings, func Cut(string) string
+pkg strings, func Old() int
+pkg strings, type Builder struct
+pkg syscall (linux-386), func Sys() int
+pkg syscall (linux-amd64), func Sys() int
+`,
+	"api/go1.2.txt": `pkg strings, func Cut(string, string) (string, string, bool) #123
+pkg strings, method (*Builder) Len() int
+pkg strings, type Builder struct, Size int
+pkg strings/v2, func Cut() int
+`,
+	"api/go1.10.txt": `pkg strings, func Old() int //deprecated
+pkg strings, func New() int
+`,
+	"api/except.txt": `pkg strings, func Cut(string) string
+`,
+	"src/strings/strings.go": `package strings
+
+// Cut cuts the string.
+func Cut(s, sep string) (string, string, bool)
+
+// Old is old.
+//
+// Deprecated: Use New.
+func Old() int
+
+// New is new. It replaces Old.
+func New() int
+
+type Builder struct{ Size int }
+
+// Len returns the length.
+func (b *Builder) Len() int
+`,
+}
+
+// fakeGOROOT writes the files, keyed by slash-separated name, to a
+// temporary directory and makes it GOROOT for the test.
+func fakeGOROOT(t *testing.T, files map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, text := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(text), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := goRoot
+	goRoot = dir
+	t.Cleanup(func() { goRoot = old })
+}
+
+func TestVersions(t *testing.T) {
+	fakeGOROOT(t, testAPI)
+	tests := []struct {
+		arg    string
+		want   []string // Lines printed, before the version of GOROOT.
+		status int
+	}{
+		{
+			arg: "strings.Cut",
+			want: []string{
+				"strings.Cut",
+				"\tgo1.1\tfunc Cut(string) string (since changed or removed)",
+				"\tgo1.2\tfunc Cut(string, string) (string, string, bool)",
+				"strings/v2.Cut",
+				"\tgo1.2\tfunc Cut() int",
+			},
+		},
+		{
+			arg: "strings.old", // Case is ignored.
+			want: []string{
+				"strings.Old",
+				"\tgo1.1\tfunc Old() int",
+				"\tgo1.10\tdeprecated: func Old() int",
+			},
+		},
+		{
+			arg:  "strings.Builder.Len",
+			want: []string{"strings.Builder.Len", "\tgo1.2\tmethod (*Builder) Len() int"},
+		},
+		{
+			arg:  "strings.Builder.Size",
+			want: []string{"strings.Builder.Size", "\tgo1.2\ttype Builder struct, Size int"},
+		},
+		{
+			arg:  "syscall.Sys", // Listed once, though for two systems.
+			want: []string{"syscall.Sys", "\tgo1.1\tfunc Sys() int"},
+		},
+		{arg: "strings.Missing", status: 1},
+		{arg: "Cut", status: 2},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).apiVersions(test.arg) })
+		if status != test.status {
+			t.Errorf("-versions %s exited with %d, want %d", test.arg, status, test.status)
+			continue
+		}
+		if status != 0 {
+			continue
+		}
+		got, _, _ := strings.Cut(out.String(), "GOROOT holds")
+		if want := strings.Join(test.want, "\n") + "\n"; got != want {
+			t.Errorf("-versions %s printed\n%s\nwant\n%s", test.arg, got, want)
+		}
+	}
+}
+
+func TestAdded(t *testing.T) {
+	fakeGOROOT(t, testAPI)
+	tests := []struct {
+		version, pkg string
+		want         []string
+		status       int
+	}{
+		{
+			version: "1.10",
+			want: []string{
+				"strings",
+				"\tdeprecated: func Old() int",
+				"\tfunc New() int",
+				"\t\tNew is new.",
+			},
+		},
+		{
+			version: "go1.2.0",
+			want: []string{
+				"strings",
+				"\tfunc Cut(string, string) (string, string, bool)",
+				"\t\tCut cuts the string.",
+				"\tmethod (*Builder) Len() int",
+				"\t\tLen returns the length.",
+				"\ttype Builder struct, Size int",
+				"",
+				"strings/v2",
+				"\tfunc Cut() int",
+			},
+		},
+		{
+			version: "1.2",
+			pkg:     "strings/v2",
+			want:    []string{"strings/v2", "\tfunc Cut() int"},
+		},
+		{
+			version: "1.1",
+			pkg:     "syscall",
+			want:    []string{"syscall", "\tfunc Sys() int"},
+		},
+		{version: "1.10", pkg: "syscall", status: 1},
+		{version: "1.99", status: 1},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		status := exitStatus(func() { newSession(&out).addedAPI(test.version, test.pkg) })
+		if status != test.status {
+			t.Errorf("-added %s %s exited with %d, want %d", test.version, test.pkg, status, test.status)
+			continue
+		}
+		if status != 0 {
+			continue
+		}
+		if want := strings.Join(test.want, "\n") + "\n"; out.String() != want {
+			t.Errorf("-added %s %s printed\n%s\nwant\n%s", test.version, test.pkg, out.String(), want)
+		}
+	}
+}
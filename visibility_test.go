@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVisibility(t *testing.T) {
+	abs, err := filepath.Abs(filepath.Join("internal", "scratch"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		file, pkgName, pkgPath string
+		vis, note              string
+	}{
+		{"a.go", "a", "example.com/a", "exported", ""},
+		{"a_test.go", "a", "example.com/a", "test", "test only\n"},
+		{"a_test.go", "a_test", "example.com/a", "test", "test only\n"},
+		{"main.go", "main", "example.com/cmd/tool", "main", "in a command\n"},
+		{"a.go", "a", "example.com/m/internal/a", "internal", "internal to example.com/m\n"},
+		{"a.go", "a", "example.com/m/internal", "internal", "internal to example.com/m\n"},
+		{"a.go", "a", "example.com/m/internal/x/internal/y", "internal", "internal to example.com/m/internal/x\n"},
+		{"a.go", "poll", "internal/poll", "internal", "internal\n"},
+		{"a.go", "a", "example.com/internalized", "exported", ""},
+		// A directory with no import path has no internal elements to heed.
+		{"a.go", "scratch", filepath.ToSlash(abs), "exported", ""},
+	}
+	for _, test := range tests {
+		if vis, _ := visibility(test.file, test.pkgName, test.pkgPath); vis != test.vis {
+			t.Errorf("visibility(%s, %s, %s) = %q, want %q", test.file, test.pkgName, test.pkgPath, vis, test.vis)
+		}
+		if note := visibilityNote(test.file, test.pkgName, test.pkgPath); note != test.note {
+			t.Errorf("visibilityNote(%s, %s, %s) = %q, want %q", test.file, test.pkgName, test.pkgPath, note, test.note)
+		}
+	}
+}
+
+func TestVisibilityJSON(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":           "module example.com/vis\n\ngo 1.22\n",
+		"a/a.go":           "package a\n\n// Pub is public.\nfunc Pub() {}\n",
+		"a/a_test.go":      "package a\n\n// Helper helps tests.\nfunc Helper() {}\n",
+		"internal/b/b.go":  "package b\n\n// Hidden is internal.\nfunc Hidden() {}\n",
+		"cmd/tool/main.go": "package main\n\n// Run runs.\nfunc Run() {}\n",
+	})
+	out := filepath.Join(t.TempDir(), "results.json")
+	if _, stderr, status := runDoc(t, dir, "-out", "json="+out, "-local", "-r", "pub|helper|hidden|run"); status != 0 {
+		t.Fatalf("doc -out json exited with %d; stderr:\n%s", status, stderr)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results struct{ Results []result }
+	if err := json.Unmarshal(data, &results); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Pub": "exported", "Helper": "test", "Hidden": "internal", "Run": "main"}
+	got := make(map[string]string)
+	for _, r := range results.Results {
+		got[r.Name] = r.Visibility
+	}
+	for name, vis := range want {
+		if got[name] != vis {
+			t.Errorf("%s has visibility %q, want %q", name, got[name], vis)
+		}
+	}
+}
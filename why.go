@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"strings"
+)
+
+// whyNote returns, for -why, a line saying how the name id matched the
+// query: as asked for, ignoring case, or by regular expression, and then
+// admitted by -filter and -stable-only if they are set, and the root of
+// the package it was found in.
+func (f *File) whyNote(id *ast.Ident) string {
+	if !*whyFlag || id == nil {
+		return ""
+	}
+	var why string
+	switch {
+	case f.ident == "":
+		why = "every exported name is listed"
+	case f.regexp != nil:
+		why = fmt.Sprintf("%s matches the regular expression %s, ignoring case", id.Name, f.ident)
+	case id.Name == f.ident:
+		why = fmt.Sprintf("%s is the name asked for", id.Name)
+	default:
+		why = fmt.Sprintf("%s is %s, ignoring case", id.Name, f.ident)
+	}
+	var admitted []string
+	if *filterFlag != "" {
+		admitted = append(admitted, "-filter")
+	}
+	if *stableOnlyFlag {
+		admitted = append(admitted, "-stable-only")
+	}
+	if len(admitted) > 0 {
+		why += "; admitted by " + strings.Join(admitted, " and ")
+	}
+	if category := rootCategory(filepath.Dir(f.name)); category != "" {
+		why += "; found in " + category
+	}
+	return "why: " + why + "\n"
+}
+
+// A scoreTerm is one term of a package's score for -rank.
+type scoreTerm struct {
+	name  string  // As in the configuration file, without weight.
+	value float64 // Weighted.
+	note  string  // What it counts, if not evident.
+}
+
+// rankWhy returns, for -why, a line giving the package's score for -rank
+// and the terms that make it up, largest first as added.
+func rankWhy(pkgPath string, score float64, terms []scoreTerm) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "why: %s scores %.2f:", pkgPath, score)
+	for i, t := range terms {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, " %s %+.2f", t.name, t.value)
+		if t.note != "" {
+			fmt.Fprintf(&b, " (%s)", t.note)
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
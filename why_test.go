@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWhy(t *testing.T) {
+	dir := writeModule(t, rankModule)
+	popular := filepath.Join(dir, "popular")
+	tests := []struct {
+		name   string
+		regexp bool
+		filter string
+		lookup func(s *session)
+		want   string
+	}{
+		{
+			name:   "exact",
+			lookup: func(s *session) { s.lookInDirectory(popular, "", "Thing") },
+			want:   "why: Thing is the name asked for; found in workspace\n",
+		},
+		{
+			name:   "ignoring case",
+			lookup: func(s *session) { s.lookInDirectory(popular, "", "thing") },
+			want:   "why: Thing is thing, ignoring case; found in workspace\n",
+		},
+		{
+			name:   "regexp",
+			regexp: true,
+			lookup: func(s *session) { s.lookInDirectory(popular, "", "th.*") },
+			want:   "why: Thing matches the regular expression th.*, ignoring case; found in workspace\n",
+		},
+		{
+			name:   "filter",
+			filter: `kind == "func"`,
+			lookup: func(s *session) { s.lookInDirectory(popular, "", "Thing") },
+			want:   "why: Thing is the name asked for; admitted by -filter; found in workspace\n",
+		},
+		{
+			name:   "rank",
+			lookup: func(s *session) { s.rankedSearch([]string{popular}, "", "Thing") },
+			want:   "why: example.com/r/popular scores 9.20: exactcase +10.00, pathlength -3.00 (path elements: 3), popularity +2.20 (importers: 2)\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defaultFlags(t)
+			setFlag(t, whyFlag, true)
+			setFlag(t, regexpFlag, test.regexp)
+			old := *filterFlag
+			*filterFlag = test.filter
+			defer func() { *filterFlag = old }()
+			var out bytes.Buffer
+			s := newSession(&out)
+			if test.filter != "" {
+				s.setFilter(test.filter)
+			}
+			test.lookup(s)
+			if !strings.Contains(out.String(), test.want) {
+				t.Errorf("printed\n%s\nwant it to contain %q", out.String(), test.want)
+			}
+		})
+	}
+	// Without -why, nothing is explained.
+	defaultFlags(t)
+	var out bytes.Buffer
+	newSession(&out).lookInDirectory(popular, "", "Thing")
+	if strings.Contains(out.String(), "why:") {
+		t.Errorf("without -why, printed\n%s", out.String())
+	}
+}
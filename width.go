@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// tabWidth is the distance between the tab stops of a terminal.
+const tabWidth = 8
+
+// runeWidth returns the number of columns a terminal gives the character,
+// other than a tab: two for one of East Asian width W or F, such as CJK
+// ideographs, kana, Hangul and most emoji, none for a combining mark, a
+// format character such as a zero-width joiner, or a control character,
+// and otherwise one.
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Cc) {
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// displayWidth returns the number of columns the line of text occupies on a
+// terminal, with tabs advancing to the next tab stop.
+func displayWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		if r == '\t' {
+			width += tabWidth - width%tabWidth
+			continue
+		}
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// expandTabs returns the line of text with each tab replaced by the spaces
+// that reach the next tab stop, so that it keeps its look wherever it is
+// placed on the line.
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		if r == '\t' {
+			n := tabWidth - width%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			width += n
+			continue
+		}
+		b.WriteRune(r)
+		width += runeWidth(r)
+	}
+	return b.String()
+}
+
+// fitWidth returns the line of text, its tabs expanded, padded with spaces
+// to exactly width columns, or cut short with "…" to fit. A wide character
+// that would straddle the edge is left out.
+func fitWidth(line string, width int) string {
+	line = expandTabs(line)
+	if w := displayWidth(line); w <= width {
+		return line + strings.Repeat(" ", width-w)
+	}
+	var b strings.Builder
+	w := 0
+	for _, r := range line {
+		rw := runeWidth(r)
+		if w+rw > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		w += rw
+	}
+	b.WriteString("…")
+	return b.String() + strings.Repeat(" ", width-1-w)
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		line  string
+		width int
+	}{
+		{"", 0},
+		{"Reader", 6},
+		{"日本語", 6},
+		{"ｆｕｌｌ", 8}, // Fullwidth forms.
+		{"한국어", 6},
+		{"e\u0301te\u0301", 3}, // Combining acute accents.
+		{"a\u200db", 2},        // Zero-width joiner.
+		{"🙂 ok", 5},
+		{"\tx", 9},
+		{"ab\tx", 9},
+		{"日本\tx", 9},
+		{"12345678\tx", 17},
+	}
+	for _, test := range tests {
+		if got := displayWidth(test.line); got != test.width {
+			t.Errorf("displayWidth(%q) = %d, want %d", test.line, got, test.width)
+		}
+	}
+}
+
+func TestFitWidth(t *testing.T) {
+	tests := []struct {
+		line  string
+		width int
+		want  string
+	}{
+		{"abc", 5, "abc  "},
+		{"abcdef", 5, "abcd…"},
+		{"日本語", 7, "日本語 "},
+		{"日本語", 6, "日本語"},
+		{"日本語", 5, "日本…"}, // 語 would straddle the edge.
+		{"日本語テキスト", 6, "日本… "},
+		{"\tx", 10, "        x "},
+		{"e\u0301te\u0301", 4, "e\u0301te\u0301 "},
+		{"abc", 1, "…"},
+	}
+	for _, test := range tests {
+		got := fitWidth(test.line, test.width)
+		if got != test.want {
+			t.Errorf("fitWidth(%q, %d) = %q, want %q", test.line, test.width, got, test.want)
+		}
+		if w := displayWidth(got); w != test.width {
+			t.Errorf("fitWidth(%q, %d) is %d columns wide", test.line, test.width, w)
+		}
+	}
+}